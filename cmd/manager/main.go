@@ -4,12 +4,15 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"github.com/mrtazz/checkmake/logger"
 	"os"
 	"runtime"
 
 	"github.com/VirtusLab/jenkins-operator/pkg/apis"
+	routev1 "github.com/VirtusLab/jenkins-operator/pkg/apis/route/v1"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/health"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/metrics"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/tracing"
 	"github.com/VirtusLab/jenkins-operator/pkg/log"
 	"github.com/VirtusLab/jenkins-operator/version"
 
@@ -17,7 +20,11 @@ import (
 	"github.com/operator-framework/operator-sdk/pkg/leader"
 	"github.com/operator-framework/operator-sdk/pkg/ready"
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
@@ -33,18 +40,50 @@ func printInfo() {
 
 func main() {
 	minikube := flag.Bool("minikube", false, "Use minikube as a Kubernetes platform")
+	openshift := flag.Bool("openshift", false, "Use OpenShift as a Kubernetes platform, detected automatically when not set")
 	local := flag.Bool("local", false, "Run operator locally")
 	debug := flag.Bool("debug", false, "Set log level to debug")
+	logLevels := flag.String("log-levels", "", "Log levels of components, e.g. 'base=debug,client=warn', components: default, base, user, client, levels: debug, info, warn, error")
+	logLevelsConfigMap := flag.String("log-levels-configmap", "", "Name of ConfigMap in the watch namespace with log levels of components overriding --log-levels at runtime")
+	healthAddress := flag.String("health-address", health.DefaultAddress, "Address of liveness and readiness endpoints")
+	metricsAddress := flag.String("metrics-address", metrics.DefaultAddress, "Address of Prometheus metrics endpoint")
+	tracingEndpoint := flag.String("tracing-endpoint", "", "OTLP/HTTP endpoint of OpenTelemetry traces, e.g. 'otel-collector:4318', tracing is disabled when not set")
+	tracingInsecure := flag.Bool("tracing-insecure", false, "Export OpenTelemetry traces without TLS")
+	tracingSampleRatio := flag.Float64("tracing-sample-ratio", 1, "Fraction of reconciliation loops traced, from 0 to 1")
+	serviceMonitor := flag.Bool("service-monitor", false, "Create Prometheus Operator ServiceMonitor of the operator metrics in the watch namespace")
 	flag.Parse()
 
-	log.SetupLogger(debug)
+	if err := log.SetupLogger(*debug, *logLevels); err != nil {
+		fatal(err, "invalid log levels")
+	}
 	printInfo()
 
+	if len(*tracingEndpoint) > 0 {
+		shutdownTracing, err := tracing.Setup(*tracingEndpoint, *tracingInsecure, *tracingSampleRatio)
+		if err != nil {
+			fatal(err, "failed to setup tracing")
+		}
+		defer func() {
+			_ = shutdownTracing(context.Background())
+		}()
+		log.Log.Info(fmt.Sprintf("Exporting traces to %s", *tracingEndpoint))
+	}
+
+	// the endpoints are served before the leader election, so the operator pods waiting for the leadership are alive
+	readiness := health.NewChecks(health.CheckLeader, health.CheckManager, health.CheckCaches)
+	go func() {
+		if err := health.Serve(*healthAddress, readiness); err != nil {
+			fatal(err, "failed to serve health endpoints")
+		}
+	}()
+	log.Log.Info(fmt.Sprintf("Serving health endpoints on %s%s and %s%s", *healthAddress, health.LivenessPath,
+		*healthAddress, health.ReadinessPath))
+
 	namespace, err := k8sutil.GetWatchNamespace()
 	if err != nil {
 		fatal(err, "failed to get watch namespace")
 	}
-	logger.Info(fmt.Sprintf("watch namespace: %v", namespace))
+	log.Log.Info(fmt.Sprintf("watch namespace: %v", namespace))
 
 	// get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
@@ -52,11 +91,20 @@ func main() {
 		fatal(err, "failed to get config")
 	}
 
+	if !*openshift {
+		*openshift, err = isOpenShift(cfg)
+		if err != nil {
+			fatal(err, "failed to detect OpenShift")
+		}
+	}
+	log.Log.Info(fmt.Sprintf("OpenShift: %v", *openshift))
+
 	// become the leader before proceeding
 	err = leader.Become(context.TODO(), "jenkins-operator-lock")
 	if err != nil {
 		fatal(err, "failed to become leader")
 	}
+	readiness.Pass(health.CheckLeader)
 
 	r := ready.NewFileReady()
 	err = r.Set()
@@ -73,7 +121,7 @@ func main() {
 		fatal(err, "failed to create manager")
 	}
 
-	logger.Info("Registering Components.")
+	log.Log.Info("Registering Components.")
 
 	// setup Scheme for all resources
 	if err := apis.AddToScheme(mgr.GetScheme()); err != nil {
@@ -81,18 +129,89 @@ func main() {
 	}
 
 	// setup Jenkins controller
-	if err := jenkins.Add(mgr, *local, *minikube); err != nil {
+	if err := jenkins.Add(mgr, *local, *minikube, *openshift); err != nil {
 		fatal(err, "failed to setup controllers")
 	}
 
-	logger.Info("Starting the Cmd.")
+	if *serviceMonitor {
+		if err := ensureServiceMonitor(cfg, mgr, namespace, *metricsAddress); err != nil {
+			fatal(err, "failed to create metrics ServiceMonitor")
+		}
+		log.Log.Info("Metrics ServiceMonitor is up to date")
+	}
+
+	go func() {
+		if err := metrics.Serve(*metricsAddress); err != nil {
+			fatal(err, "failed to serve metrics")
+		}
+	}()
+	log.Log.Info(fmt.Sprintf("Serving metrics on %s%s", *metricsAddress, metrics.Path))
+
+	log.Log.Info("Starting the Cmd.")
+
+	// runnables are started by the manager, so the manager is started once the runnable is
+	err = mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		readiness.Pass(health.CheckManager)
+		if mgr.GetCache().WaitForCacheSync(stop) {
+			readiness.Pass(health.CheckCaches)
+			log.Log.Info("Caches are synced, the operator is ready")
+		}
+		<-stop
+		return nil
+	}))
+	if err != nil {
+		fatal(err, "failed to add readiness checks")
+	}
+
+	stop := signals.SetupSignalHandler()
+	if len(*logLevelsConfigMap) > 0 {
+		k8sClient, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme()})
+		if err != nil {
+			fatal(err, "failed to create log levels ConfigMap client")
+		}
+		go log.WatchLevelsConfigMap(k8sClient, namespace, *logLevelsConfigMap, log.LevelsConfigMapPeriod, stop)
+		log.Log.Info(fmt.Sprintf("Watching log levels ConfigMap '%s'", *logLevelsConfigMap))
+	}
 
 	// start the Cmd
-	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(stop); err != nil {
 		fatal(err, "failed to start cmd")
 	}
 }
 
+// isOpenShift tells if OpenShift Route API is served by the cluster
+func isOpenShift(cfg *rest.Config) (bool, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return false, err
+	}
+	_, err = discoveryClient.ServerResourcesForGroupVersion(routev1.SchemeGroupVersion.String())
+	if err != nil && apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ensureServiceMonitor creates the Service and ServiceMonitor of the operator metrics, the manager client can't be
+// used before the manager is started, so a direct client is created
+func ensureServiceMonitor(cfg *rest.Config, mgr manager.Manager, namespace, metricsAddress string) error {
+	operatorName, err := k8sutil.GetOperatorName()
+	if err != nil {
+		return err
+	}
+	port, err := metrics.GetPort(metricsAddress)
+	if err != nil {
+		return err
+	}
+	k8sClient, err := client.New(cfg, client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return err
+	}
+	return metrics.EnsureServiceMonitor(k8sClient, namespace, operatorName, port)
+}
+
 func fatal(err error, message string) {
 	log.Log.Error(err, message)
 	os.Exit(-1)
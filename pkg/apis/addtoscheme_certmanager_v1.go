@@ -0,0 +1,10 @@
+package apis
+
+import (
+	"github.com/VirtusLab/jenkins-operator/pkg/apis/certmanager/v1"
+)
+
+func init() {
+	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
+	AddToSchemes = append(AddToSchemes, v1.SchemeBuilder.AddToScheme)
+}
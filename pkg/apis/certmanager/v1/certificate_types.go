@@ -0,0 +1,60 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IssuerKind is the kind of namespaced cert-manager issuer
+	IssuerKind = "Issuer"
+	// ClusterIssuerKind is the kind of cluster scoped cert-manager issuer
+	ClusterIssuerKind = "ClusterIssuer"
+	// CertificateKind is the kind of cert-manager certificate request
+	CertificateKind = "Certificate"
+)
+
+// CertificateSpec defines the desired state of Certificate
+type CertificateSpec struct {
+	DNSNames []string `json:"dnsNames,omitempty"`
+	// SecretName is the name of kubernetes.io/tls Secret where cert-manager stores the issued certificate
+	SecretName string `json:"secretName"`
+	// SecretTemplate defines labels and annotations copied to the Secret
+	SecretTemplate *CertificateSecretTemplate `json:"secretTemplate,omitempty"`
+	IssuerRef      ObjectReference            `json:"issuerRef"`
+}
+
+// CertificateSecretTemplate defines labels and annotations of the certificate Secret
+type CertificateSecretTemplate struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// ObjectReference references cert-manager Issuer or ClusterIssuer
+type ObjectReference struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Certificate is the cert-manager request for a certificate issued by the referenced issuer
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CertificateSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CertificateList contains a list of Certificate
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Certificate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Certificate{}, &CertificateList{})
+}
@@ -0,0 +1,5 @@
+// Package v1 contains the subset of cert-manager.io/v1 API used by the operator to request Jenkins master certificates,
+// cert-manager has to be installed in the cluster
+// +k8s:deepcopy-gen=package,register
+// +groupName=cert-manager.io
+package v1
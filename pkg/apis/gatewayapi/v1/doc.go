@@ -0,0 +1,5 @@
+// Package v1 contains the subset of Kubernetes Gateway API gateway.networking.k8s.io/v1 used by the operator to
+// expose Jenkins UI, the API is available when Gateway API CRDs are installed in the cluster
+// +k8s:deepcopy-gen=package,register
+// +groupName=gateway.networking.k8s.io
+package v1
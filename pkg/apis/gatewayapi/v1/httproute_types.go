@@ -0,0 +1,85 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// HTTPRouteKind is the kind of Gateway API HTTP route
+	HTTPRouteKind = "HTTPRoute"
+	// GatewayKind is the kind of Gateway API gateway
+	GatewayKind = "Gateway"
+	// ServiceKind is the kind of route backend
+	ServiceKind = "Service"
+
+	// PathMatchPathPrefix matches request paths by path elements prefix
+	PathMatchPathPrefix = "PathPrefix"
+)
+
+// HTTPRouteSpec defines the desired state of HTTPRoute, the fields defaulted by Gateway API CRDs are always set, so
+// the spec read from the API server is equal to the built one
+type HTTPRouteSpec struct {
+	// ParentRefs contains gateways the route attaches to
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `json:"rules,omitempty"`
+}
+
+// ParentReference references the gateway and optionally its listener
+type ParentReference struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// SectionName is the name of the gateway listener, the route attaches to all listeners when empty
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// HTTPRouteRule defines requests matched by the rule and backends receiving them
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch `json:"matches,omitempty"`
+	BackendRefs []HTTPBackendRef `json:"backendRefs,omitempty"`
+}
+
+// HTTPRouteMatch defines the request matching the rule
+type HTTPRouteMatch struct {
+	Path *HTTPPathMatch `json:"path,omitempty"`
+}
+
+// HTTPPathMatch defines the request path matching the rule
+type HTTPPathMatch struct {
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// HTTPBackendRef references the backend Service port
+type HTTPBackendRef struct {
+	Group  string `json:"group"`
+	Kind   string `json:"kind,omitempty"`
+	Name   string `json:"name"`
+	Port   int32  `json:"port,omitempty"`
+	Weight int32  `json:"weight,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRoute routes HTTP requests received by gateways to backends
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HTTPRouteSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteList contains a list of HTTPRoute
+type HTTPRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HTTPRoute `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HTTPRoute{}, &HTTPRouteList{})
+}
@@ -0,0 +1,5 @@
+// Package v1beta1 contains the subset of Istio networking.istio.io/v1beta1 API used by the operator to expose Jenkins UI
+// through Istio ingress gateway, Istio has to be installed in the cluster
+// +k8s:deepcopy-gen=package,register
+// +groupName=networking.istio.io
+package v1beta1
@@ -0,0 +1,121 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GatewayKind is the kind of Istio gateway
+	GatewayKind = "Gateway"
+	// VirtualServiceKind is the kind of Istio virtual service
+	VirtualServiceKind = "VirtualService"
+
+	// ProtocolHTTP is the plain HTTP protocol of gateway server
+	ProtocolHTTP = "HTTP"
+	// ProtocolHTTPS is the HTTPS protocol of gateway server
+	ProtocolHTTPS = "HTTPS"
+
+	// TLSModeSimple terminates TLS on the gateway with the certificate from the credential
+	TLSModeSimple = "SIMPLE"
+)
+
+// GatewaySpec defines the desired state of Gateway
+type GatewaySpec struct {
+	// Selector selects Istio ingress gateway pods which apply the configuration
+	Selector map[string]string `json:"selector,omitempty"`
+	Servers  []Server          `json:"servers"`
+}
+
+// Server defines port exposed by the gateway
+type Server struct {
+	Port  Port               `json:"port"`
+	Hosts []string           `json:"hosts"`
+	TLS   *ServerTLSSettings `json:"tls,omitempty"`
+}
+
+// Port defines gateway port
+type Port struct {
+	Number   uint32 `json:"number"`
+	Protocol string `json:"protocol"`
+	Name     string `json:"name"`
+}
+
+// ServerTLSSettings defines TLS settings of gateway server
+type ServerTLSSettings struct {
+	// HTTPSRedirect redirects plain HTTP requests to HTTPS
+	HTTPSRedirect bool   `json:"httpsRedirect,omitempty"`
+	Mode          string `json:"mode,omitempty"`
+	// CredentialName is the name of kubernetes.io/tls Secret in the ingress gateway namespace
+	CredentialName string `json:"credentialName,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Gateway configures Istio ingress gateway ports and hosts
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GatewaySpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayList contains a list of Gateway
+type GatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Gateway `json:"items"`
+}
+
+// VirtualServiceSpec defines the desired state of VirtualService
+type VirtualServiceSpec struct {
+	Hosts []string `json:"hosts"`
+	// Gateways contains gateways as <namespace>/<name> which apply the routes
+	Gateways []string    `json:"gateways,omitempty"`
+	HTTP     []HTTPRoute `json:"http,omitempty"`
+}
+
+// HTTPRoute defines destinations of HTTP traffic
+type HTTPRoute struct {
+	Route []HTTPRouteDestination `json:"route"`
+}
+
+// HTTPRouteDestination defines destination of HTTP route
+type HTTPRouteDestination struct {
+	Destination Destination `json:"destination"`
+}
+
+// Destination references the Service receiving the traffic
+type Destination struct {
+	Host string        `json:"host"`
+	Port *PortSelector `json:"port,omitempty"`
+}
+
+// PortSelector selects the Service port receiving the traffic
+type PortSelector struct {
+	Number uint32 `json:"number"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualService routes traffic of hosts to Services
+type VirtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec VirtualServiceSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualServiceList contains a list of VirtualService
+type VirtualServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VirtualService `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Gateway{}, &GatewayList{}, &VirtualService{}, &VirtualServiceList{})
+}
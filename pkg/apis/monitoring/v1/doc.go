@@ -0,0 +1,5 @@
+// Package v1 contains the subset of Prometheus Operator monitoring.coreos.com/v1 API used by the operator to scrape
+// metrics of the operator and Jenkins, Prometheus Operator has to be installed in the cluster
+// +k8s:deepcopy-gen=package,register
+// +groupName=monitoring.coreos.com
+package v1
@@ -0,0 +1,144 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ServiceMonitorKind is the kind of Prometheus Operator service monitor
+	ServiceMonitorKind = "ServiceMonitor"
+	// PodMonitorKind is the kind of Prometheus Operator pod monitor
+	PodMonitorKind = "PodMonitor"
+	// PrometheusRuleKind is the kind of Prometheus Operator alerting and recording rules
+	PrometheusRuleKind = "PrometheusRule"
+)
+
+// ServiceMonitorSpec defines Services scraped by Prometheus
+type ServiceMonitorSpec struct {
+	// Selector selects Services in the ServiceMonitor namespace
+	Selector  metav1.LabelSelector `json:"selector"`
+	Endpoints []Endpoint           `json:"endpoints"`
+}
+
+// Endpoint defines the scraped port of the Service
+type Endpoint struct {
+	// Port is the name of the Service port
+	Port string `json:"port,omitempty"`
+	// Path is the HTTP path of the metrics, /metrics by default
+	Path string `json:"path,omitempty"`
+	// Interval is the scrape interval, e.g. 30s, the Prometheus global interval is used when empty
+	Interval string `json:"interval,omitempty"`
+	// HonorLabels keeps labels of the scraped metrics when they collide with the target labels, e.g. namespace
+	HonorLabels bool `json:"honorLabels,omitempty"`
+}
+
+// PodMonitorSpec defines pods scraped by Prometheus
+type PodMonitorSpec struct {
+	// Selector selects pods in the PodMonitor namespace
+	Selector            metav1.LabelSelector `json:"selector"`
+	PodMetricsEndpoints []PodMetricsEndpoint `json:"podMetricsEndpoints"`
+}
+
+// PodMetricsEndpoint defines the scraped port of the pod
+type PodMetricsEndpoint struct {
+	// Port is the name of the container port
+	Port string `json:"port,omitempty"`
+	// Path is the HTTP path of the metrics, /metrics by default
+	Path string `json:"path,omitempty"`
+	// Scheme is http or https
+	Scheme string `json:"scheme,omitempty"`
+	// Interval is the scrape interval, e.g. 30s, the Prometheus global interval is used when empty
+	Interval  string     `json:"interval,omitempty"`
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+}
+
+// TLSConfig defines TLS connection to the scraped pod
+type TLSConfig struct {
+	// InsecureSkipVerify disables verification of the certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// PrometheusRuleSpec defines rule groups loaded by Prometheus
+type PrometheusRuleSpec struct {
+	Groups []RuleGroup `json:"groups,omitempty"`
+}
+
+// RuleGroup defines a group of rules evaluated together
+type RuleGroup struct {
+	Name  string `json:"name"`
+	Rules []Rule `json:"rules"`
+}
+
+// Rule defines an alerting rule, recording rules aren't used by the operator
+type Rule struct {
+	// Alert is the name of the alert
+	Alert string `json:"alert,omitempty"`
+	// Expr is PromQL expression of the alert
+	Expr string `json:"expr"`
+	// For is how long the expression has to be true before the alert fires, e.g. 5m
+	For         string            `json:"for,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceMonitor makes Prometheus scrape endpoints of the selected Services
+type ServiceMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceMonitorSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceMonitorList contains a list of ServiceMonitor
+type ServiceMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceMonitor `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PodMonitor makes Prometheus scrape the selected pods
+type PodMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PodMonitorSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PodMonitorList contains a list of PodMonitor
+type PodMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PodMonitor `json:"items"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PrometheusRule defines alerting rules loaded by Prometheus
+type PrometheusRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PrometheusRuleSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// PrometheusRuleList contains a list of PrometheusRule
+type PrometheusRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PrometheusRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceMonitor{}, &ServiceMonitorList{}, &PodMonitor{}, &PodMonitorList{},
+		&PrometheusRule{}, &PrometheusRuleList{})
+}
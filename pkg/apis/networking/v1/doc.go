@@ -0,0 +1,5 @@
+// Package v1 contains the subset of Kubernetes networking.k8s.io/v1 API missing in the vendored client, it's used
+// to look up IngressClass selected by Jenkins Ingress, the API is available since Kubernetes 1.18
+// +k8s:deepcopy-gen=package,register
+// +groupName=networking.k8s.io
+package v1
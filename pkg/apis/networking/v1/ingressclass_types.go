@@ -0,0 +1,36 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IngressClassKind is the kind of Kubernetes ingress class
+const IngressClassKind = "IngressClass"
+
+// IngressClassSpec defines the ingress controller implementing the class
+type IngressClassSpec struct {
+	Controller string `json:"controller,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IngressClass represents the class of the Ingress, it's cluster scoped
+type IngressClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec IngressClassSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IngressClassList contains a list of IngressClass
+type IngressClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IngressClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&IngressClass{}, &IngressClassList{})
+}
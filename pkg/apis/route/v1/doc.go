@@ -0,0 +1,5 @@
+// Package v1 contains the subset of OpenShift route.openshift.io/v1 API used by the operator to expose Jenkins UI,
+// the API is available only on OpenShift
+// +k8s:deepcopy-gen=package,register
+// +groupName=route.openshift.io
+package v1
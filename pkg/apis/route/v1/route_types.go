@@ -0,0 +1,90 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// RouteKind is the kind of OpenShift route
+	RouteKind = "Route"
+	// ServiceKind is the kind of route target
+	ServiceKind = "Service"
+)
+
+// TLSTerminationType defines where TLS connection is terminated
+type TLSTerminationType string
+
+const (
+	// TLSTerminationEdge terminates TLS on the router, the router connects to the Service over plain HTTP
+	TLSTerminationEdge TLSTerminationType = "edge"
+	// TLSTerminationReencrypt terminates TLS on the router which opens a new TLS connection to the Service
+	TLSTerminationReencrypt TLSTerminationType = "reencrypt"
+	// TLSTerminationPassthrough passes TLS connection to the Service without decrypting it
+	TLSTerminationPassthrough TLSTerminationType = "passthrough"
+)
+
+// InsecureEdgeTerminationPolicyType defines how the router handles plain HTTP requests of TLS route
+type InsecureEdgeTerminationPolicyType string
+
+const (
+	// InsecureEdgeTerminationPolicyNone rejects plain HTTP requests
+	InsecureEdgeTerminationPolicyNone InsecureEdgeTerminationPolicyType = "None"
+	// InsecureEdgeTerminationPolicyRedirect redirects plain HTTP requests to HTTPS
+	InsecureEdgeTerminationPolicyRedirect InsecureEdgeTerminationPolicyType = "Redirect"
+)
+
+// RouteSpec defines the desired state of Route
+type RouteSpec struct {
+	// Host is the route host name, OpenShift router generates it when empty
+	Host string `json:"host,omitempty"`
+	// Path is the path prefix routed to the Service, it can't be used with passthrough termination
+	Path string               `json:"path,omitempty"`
+	To   RouteTargetReference `json:"to"`
+	Port *RoutePort           `json:"port,omitempty"`
+	TLS  *TLSConfig           `json:"tls,omitempty"`
+}
+
+// RouteTargetReference references the Service receiving the route traffic
+type RouteTargetReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// RoutePort selects the Service port receiving the route traffic
+type RoutePort struct {
+	TargetPort intstr.IntOrString `json:"targetPort"`
+}
+
+// TLSConfig defines TLS termination of the route, certificates are PEM encoded
+type TLSConfig struct {
+	Termination                   TLSTerminationType                `json:"termination"`
+	Certificate                   string                            `json:"certificate,omitempty"`
+	Key                           string                            `json:"key,omitempty"`
+	CACertificate                 string                            `json:"caCertificate,omitempty"`
+	DestinationCACertificate      string                            `json:"destinationCACertificate,omitempty"`
+	InsecureEdgeTerminationPolicy InsecureEdgeTerminationPolicyType `json:"insecureEdgeTerminationPolicy,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Route exposes a Service at a host name through OpenShift router
+type Route struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RouteSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RouteList contains a list of Route
+type RouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Route `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Route{}, &RouteList{})
+}
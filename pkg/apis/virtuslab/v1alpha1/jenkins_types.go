@@ -2,6 +2,9 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -16,6 +19,936 @@ type JenkinsSpec struct {
 	BackupAmazonS3 JenkinsBackupAmazonS3 `json:"backupAmazonS3,omitempty"`
 	Master         JenkinsMaster         `json:"master,omitempty"`
 	SeedJobs       []SeedJob             `json:"seedJobs,omitempty"`
+	Configuration  JenkinsConfiguration  `json:"configuration,omitempty"`
+	Vault          *Vault                `json:"vault,omitempty"`
+	// ArtifactManager defines external storage of build artifacts, artifacts are kept on master disk when not set
+	ArtifactManager *ArtifactManager `json:"artifactManager,omitempty"`
+	// Security defines how users authenticate to Jenkins, Jenkins own user database is used when not set
+	Security Security `json:"security,omitempty"`
+	// NetworkPolicy restricts ingress traffic of Jenkins master pod, all traffic is allowed when not set
+	NetworkPolicy *NetworkPolicy `json:"networkPolicy,omitempty"`
+	// Service defines how Jenkins master is exposed, the operator always creates Service jenkins-operator-<cr_name>
+	Service JenkinsService `json:"service,omitempty"`
+	// ExternalURL is the URL of Jenkins UI used by users, it's set as Jenkins root URL used in links of e-mails,
+	// build statuses and webhooks, derived from spec.service.ingress or spec.service.route host when not set
+	ExternalURL string `json:"externalURL,omitempty"`
+	// MaintenanceMode puts Jenkins into quiet mode, so new builds aren't started, and stops applying base and user
+	// configuration, including seed jobs, until it's disabled, Jenkins UI is still reachable
+	MaintenanceMode bool `json:"maintenanceMode,omitempty"`
+	// Agents defines Kubernetes plugin agents of Jenkins
+	Agents Agents `json:"agents,omitempty"`
+	// Exposure is external (default) or internal which guarantees that the operator doesn't expose Jenkins outside of
+	// the cluster, resources exposing the Jenkins Services created by others are reported by ExternallyExposed condition
+	Exposure Exposure `json:"exposure,omitempty"`
+	// Monitoring installs and configures the prometheus plugin and makes Prometheus scrape Jenkins metrics, Jenkins
+	// isn't scraped when not set
+	Monitoring *Monitoring `json:"monitoring,omitempty"`
+}
+
+// Agents defines Kubernetes plugin agents of Jenkins
+type Agents struct {
+	// Namespace runs agent pods in a separate namespace, the operator creates there the ServiceAccount used by agent pod
+	// templates and the Role and RoleBinding which let Jenkins master manage agent pods, Jenkins namespace by default
+	Namespace string `json:"namespace,omitempty"`
+	// Templates are pod templates of the Kubernetes cloud, they replace templates configured in Jenkins UI
+	Templates []AgentPodTemplate `json:"templates,omitempty"`
+	// Static are permanent agents outside of the cluster launched over SSH, e.g. bare-metal or VM build machines
+	Static []StaticAgent `json:"static,omitempty"`
+	// DefaultImage is the jnlp container image of pod templates which don't set it, including templates defined by
+	// pipelines, e.g. 'registry.example.com/jenkins/inbound-agent:4.3-4', Kubernetes plugin default image when empty
+	DefaultImage string `json:"defaultImage,omitempty"`
+	// MaxConcurrent limits the number of agent pods running at the same time in the Kubernetes cloud, unlimited when 0
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+	// AdditionalNamespaces run agent pods in other namespaces as well, each of them gets Kubernetes cloud
+	// 'kubernetes-<namespace>' with Templates labeled '<namespace>-<label>' and the same RBAC resources as Namespace
+	AdditionalNamespaces []AgentsNamespace `json:"additionalNamespaces,omitempty"`
+	// Clusters run agent pods in other Kubernetes clusters, e.g. a dedicated build cluster, each of them gets
+	// Kubernetes cloud '<name>' with Templates labeled '<name>-<label>'
+	Clusters []AgentsCluster `json:"clusters,omitempty"`
+	// ImagePullSecrets are added to every agent pod template, e.g. to pull agent images from private registry, the
+	// Secrets have to exist in the namespace of agent pods
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// OrphanedPodsGracePeriod enables deleting agent pods in Namespace and AdditionalNamespaces which have no Jenkins
+	// agent, e.g. after Jenkins master restart, when they are older than the period, agent pods are left to Kubernetes
+	// plugin when not set
+	OrphanedPodsGracePeriod *metav1.Duration `json:"orphanedPodsGracePeriod,omitempty"`
+	// VMClouds provision agents on virtual machines of cloud providers, e.g. for builds which can't run in containers,
+	// they replace the clouds configured in Jenkins UI with the same names
+	VMClouds []VMCloud `json:"vmClouds,omitempty"`
+}
+
+// VMCloudProvider defines the cloud provider of virtual machine agents
+type VMCloudProvider string
+
+const (
+	// VMCloudProviderEC2 is Amazon EC2, it requires 'ec2' plugin
+	VMCloudProviderEC2 VMCloudProvider = "ec2"
+	// VMCloudProviderGCE is Google Compute Engine, it requires 'google-compute-engine' plugin
+	VMCloudProviderGCE VMCloudProvider = "gce"
+)
+
+// AllowedVMCloudProviders consists allowed virtual machine cloud providers
+var AllowedVMCloudProviders = []VMCloudProvider{VMCloudProviderEC2, VMCloudProviderGCE}
+
+// VMCloud defines Jenkins cloud provisioning agents on virtual machines
+type VMCloud struct {
+	// Name is the name of the Jenkins cloud
+	Name string `json:"name"`
+	// Provider is ec2 or gce
+	Provider VMCloudProvider `json:"provider"`
+	// Region is AWS region of ec2 cloud, e.g. 'eu-west-1'
+	Region string `json:"region,omitempty"`
+	// Project is Google Cloud project ID of gce cloud
+	Project string `json:"project,omitempty"`
+	// CredentialsSecretName is the name of the Secret with 'accessKeyId', 'secretAccessKey' and 'privateKey' (private
+	// key of EC2 key pair) keys of ec2 cloud or with 'serviceAccountKey' key (service account JSON key) of gce cloud
+	CredentialsSecretName string `json:"credentialsSecretName"`
+	// MaxInstances limits the number of instances running at the same time in the cloud, unlimited when 0
+	MaxInstances int `json:"maxInstances,omitempty"`
+	// Templates are the virtual machine templates of the cloud
+	Templates []VMAgentTemplate `json:"templates"`
+}
+
+// VMAgentTemplate defines virtual machines provisioned as Jenkins agents
+type VMAgentTemplate struct {
+	// Name identifies the template, it's the description of ec2 templates and the instance name prefix of gce templates
+	Name string `json:"name"`
+	// Image is AMI ID of ec2 template, e.g. 'ami-0abcdef1234567890', or image of gce template, e.g.
+	// 'projects/debian-cloud/global/images/family/debian-12'
+	Image string `json:"image"`
+	// MachineType is the instance type, e.g. 't3.large' or 'n2-standard-4'
+	MachineType string `json:"machineType"`
+	// Zone is the availability zone of ec2 template, any zone of the region when empty, or the zone of gce template
+	Zone string `json:"zone,omitempty"`
+	// Subnet is the subnet ID of ec2 template or the subnetwork of the default network of gce template
+	Subnet string `json:"subnet,omitempty"`
+	// Labels select the agents by jobs
+	Labels []string `json:"labels,omitempty"`
+	// Executors is the number of executors of the agent, 1 by default
+	Executors int `json:"executors,omitempty"`
+	// MaxInstances limits the number of instances of the template, unlimited when 0
+	MaxInstances int `json:"maxInstances,omitempty"`
+	// IdleMinutes is the time idle instances are kept before termination, 30 minutes by default
+	IdleMinutes int `json:"idleMinutes,omitempty"`
+	// RemoteUser is the user running the agent, 'ec2-user' for ec2 and 'jenkins' for gce templates by default
+	RemoteUser string `json:"remoteUser,omitempty"`
+	// WorkDir is the remote root directory of the agent, home directory of RemoteUser by default
+	WorkDir string `json:"workDir,omitempty"`
+}
+
+// AgentsCluster defines Kubernetes cloud running agent pods in other cluster
+type AgentsCluster struct {
+	// Name is the name of the Kubernetes cloud
+	Name string `json:"name"`
+	// KubeconfigSecretName is the name of the Secret with 'kubeconfig' key used to manage agent pods in the cluster
+	KubeconfigSecretName string `json:"kubeconfigSecretName"`
+	// Namespace is the namespace of agent pods in the cluster
+	Namespace string `json:"namespace"`
+	// JenkinsURL is Jenkins URL reachable from the cluster
+	JenkinsURL string `json:"jenkinsURL"`
+	// JenkinsTunnel is 'host:port' of Jenkins agent listener reachable from the cluster, agents connect to the host and
+	// port from JenkinsURL when empty
+	JenkinsTunnel string `json:"jenkinsTunnel,omitempty"`
+	// ServiceAccountName is the service account of agent pods in the cluster, the default service account of
+	// the namespace when empty
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// MaxConcurrent limits the number of agent pods running at the same time in the cluster, unlimited when 0
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+}
+
+// AgentsNamespace defines additional namespace of agent pods
+type AgentsNamespace struct {
+	// Name is the name of the namespace
+	Name string `json:"name"`
+	// MaxConcurrent limits the number of agent pods running at the same time in the namespace, unlimited when 0
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+}
+
+// StaticAgent defines permanent Jenkins node launched by SSH plugin, it requires 'ssh-slaves' plugin
+type StaticAgent struct {
+	// Name is the name of the Jenkins node
+	Name string `json:"name"`
+	// Host is the host name or IP address of the machine
+	Host string `json:"host"`
+	// Port is the SSH port, 22 by default
+	Port int `json:"port,omitempty"`
+	// CredentialsSecretName is the name of the Secret with 'username' and 'privateKey' keys used to log in
+	CredentialsSecretName string `json:"credentialsSecretName"`
+	// HostKey is the expected SSH host key, e.g. 'ssh-ed25519 AAAA...', the key presented by the first connection is
+	// trusted when empty
+	HostKey string `json:"hostKey,omitempty"`
+	// Labels select the agent by jobs
+	Labels []string `json:"labels,omitempty"`
+	// Executors is the number of executors, 1 by default
+	Executors int `json:"executors,omitempty"`
+	// WorkDir is the remote root directory of the agent, /home/jenkins/agent by default
+	WorkDir string `json:"workDir,omitempty"`
+}
+
+// AgentPodTemplate defines Kubernetes plugin pod template, the agent runs in the jnlp container
+type AgentPodTemplate struct {
+	// Name identifies the template, it's the prefix of agent pod names
+	Name string `json:"name"`
+	// Label contains space separated labels selecting the template by jobs, e.g. 'maven jdk11', the template is used
+	// only by pipelines referencing it when empty
+	Label string `json:"label,omitempty"`
+	// Image is the image of the jnlp container, Agents.DefaultImage is used when not set, Windows templates use
+	// the Windows Server Core inbound agent image instead
+	Image string `json:"image,omitempty"`
+	// Resources are resource requests and limits of the jnlp container
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Env contains environment variables of the jnlp container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Volumes are added to the agent pod
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts are mounted into the jnlp container, they can reference Volumes and volumes from Yaml
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Yaml is the agent pod definition the other fields are merged into, e.g. with additional containers, node selector
+	// or tolerations
+	Yaml string `json:"yaml,omitempty"`
+	// YamlConfigMap is the ConfigMap with the agent pod definition in 'pod.yaml' key, for pods which are too large or
+	// complex to be inlined in Yaml
+	YamlConfigMap *ConfigMapReference `json:"yamlConfigMap,omitempty"`
+	// YamlMergeStrategy defines how the pod from YamlConfigMap is combined with Yaml, merge by default
+	YamlMergeStrategy YamlMergeStrategy `json:"yamlMergeStrategy,omitempty"`
+	// Permissions are granted in the agents namespace to the dedicated service account of the template, templates
+	// without permissions use the shared agents service account which doesn't mount Kubernetes API token
+	Permissions []rbacv1.PolicyRule `json:"permissions,omitempty"`
+	// MaxConcurrent limits the number of agent pods of the template running at the same time, Agents.MaxConcurrent
+	// applies when 0
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+	// NodeSelector schedules agent pods on nodes with the labels, e.g. a big-memory node pool, it's merged into
+	// the node selector from Yaml
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations allow scheduling agent pods on tainted nodes, they're added to the tolerations from Yaml
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity replaces the affinity from Yaml, e.g. to prefer nodes with cached images or spread agent pods
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// Spot schedules agent pods of the template on spot or preemptible nodes
+	Spot *SpotNodes `json:"spot,omitempty"`
+	// OS is the operating system of nodes running agent pods of the template, linux or windows, the pods aren't
+	// restricted to any operating system when empty
+	OS AgentOS `json:"os,omitempty"`
+	// ImageBuilder adds container building images, e.g. Docker in Docker, with its volumes and security context
+	ImageBuilder *ImageBuilder `json:"imageBuilder,omitempty"`
+	// IdleMinutes keeps the agent pod running for the next builds after the build finishes, the pod is removed right
+	// after the build when 0
+	IdleMinutes int `json:"idleMinutes,omitempty"`
+	// ConnectionTimeoutSeconds is the time the agent has to connect to Jenkins after the pod is created, Kubernetes
+	// plugin default 1000 seconds applies when 0
+	ConnectionTimeoutSeconds int `json:"connectionTimeoutSeconds,omitempty"`
+	// PodRetention defines when the agent pod is kept after the build, never, on-failure or always, Kubernetes cloud
+	// setting applies when empty
+	PodRetention PodRetention `json:"podRetention,omitempty"`
+	// GPU requests GPUs or other device plugin resources for the agent pod
+	GPU *AgentGPU `json:"gpu,omitempty"`
+	// WorkspaceVolume is the volume of the agent workspace, Kubernetes plugin emptyDir applies when not set
+	WorkspaceVolume *WorkspaceVolume `json:"workspaceVolume,omitempty"`
+}
+
+// WorkspaceVolumeType defines the volume type of agent workspace
+type WorkspaceVolumeType string
+
+const (
+	// WorkspaceVolumeTypeEmptyDir is emptyDir volume removed with the agent pod
+	WorkspaceVolumeTypeEmptyDir WorkspaceVolumeType = "emptyDir"
+	// WorkspaceVolumeTypeEphemeral is generic ephemeral volume, PVC created and deleted with the agent pod
+	WorkspaceVolumeTypeEphemeral WorkspaceVolumeType = "ephemeral"
+	// WorkspaceVolumeTypeHostPath is directory of the node kept between agent pods, e.g. for build caches
+	WorkspaceVolumeTypeHostPath WorkspaceVolumeType = "hostPath"
+)
+
+// AllowedWorkspaceVolumeTypes consists allowed workspace volume types
+var AllowedWorkspaceVolumeTypes = []WorkspaceVolumeType{WorkspaceVolumeTypeEmptyDir, WorkspaceVolumeTypeEphemeral, WorkspaceVolumeTypeHostPath}
+
+// WorkspaceVolume defines the workspace volume of agent pods
+type WorkspaceVolume struct {
+	// Type is emptyDir, ephemeral or hostPath
+	Type WorkspaceVolumeType `json:"type"`
+	// Memory keeps emptyDir workspace in memory, it counts to memory limits of the containers
+	Memory bool `json:"memory,omitempty"`
+	// SizeLimit is the maximum size of emptyDir workspace, the agent pod is evicted when it's exceeded
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+	// StorageClassName is the storage class of ephemeral workspace PVC, the default storage class applies when empty
+	StorageClassName string `json:"storageClassName,omitempty"`
+	// Size is the requested size of ephemeral workspace PVC
+	Size *resource.Quantity `json:"size,omitempty"`
+	// AccessMode is the access mode of ephemeral workspace PVC, ReadWriteOnce by default
+	AccessMode corev1.PersistentVolumeAccessMode `json:"accessMode,omitempty"`
+	// Path is the absolute directory of hostPath workspace on the node
+	Path string `json:"path,omitempty"`
+}
+
+// AgentGPU defines device plugin resources of agent pods, e.g. NVIDIA GPUs
+type AgentGPU struct {
+	// ResourceName is the extended resource advertised by the device plugin, 'nvidia.com/gpu' by default
+	ResourceName corev1.ResourceName `json:"resourceName,omitempty"`
+	// Count is the number of devices requested, device plugin resources can't be shared so requests equal limits
+	Count int64 `json:"count"`
+	// Container is the name of the container getting the devices, e.g. CUDA container from Yaml, the jnlp container
+	// by default
+	Container string `json:"container,omitempty"`
+	// RuntimeClassName selects container runtime with device support, e.g. 'nvidia'
+	RuntimeClassName string `json:"runtimeClassName,omitempty"`
+	// DriverCapabilities set NVIDIA_DRIVER_CAPABILITIES of the container, e.g. compute and utility, NVIDIA container
+	// runtime defaults apply when empty
+	DriverCapabilities []string `json:"driverCapabilities,omitempty"`
+}
+
+// PodRetention defines when agent pods are kept after the build
+type PodRetention string
+
+const (
+	// PodRetentionNever removes agent pods after the build
+	PodRetentionNever PodRetention = "never"
+	// PodRetentionOnFailure keeps agent pods which failed, e.g. for debugging, the others are removed
+	PodRetentionOnFailure PodRetention = "on-failure"
+	// PodRetentionAlways keeps all agent pods, they have to be removed manually
+	PodRetentionAlways PodRetention = "always"
+)
+
+// AllowedPodRetentions consists allowed agent pod retention policies
+var AllowedPodRetentions = []PodRetention{PodRetentionNever, PodRetentionOnFailure, PodRetentionAlways}
+
+// ImageBuilder defines the container building images in agent pods
+type ImageBuilder struct {
+	// Type is dind, buildkit or kaniko
+	Type ImageBuilderType `json:"type"`
+	// Image replaces the default image of the image builder container
+	Image string `json:"image,omitempty"`
+}
+
+// ImageBuilderType defines the tool building images in agent pods
+type ImageBuilderType string
+
+const (
+	// ImageBuilderTypeDind runs privileged Docker daemon, builds use docker CLI with DOCKER_HOST
+	ImageBuilderTypeDind ImageBuilderType = "dind"
+	// ImageBuilderTypeBuildKit runs rootless BuildKit daemon, builds use buildctl with BUILDKIT_HOST
+	ImageBuilderTypeBuildKit ImageBuilderType = "buildkit"
+	// ImageBuilderTypeKaniko runs Kaniko executor container, builds call /kaniko/executor in it
+	ImageBuilderTypeKaniko ImageBuilderType = "kaniko"
+)
+
+// AllowedImageBuilderTypes consists allowed tools building images in agent pods
+var AllowedImageBuilderTypes = []ImageBuilderType{ImageBuilderTypeDind, ImageBuilderTypeBuildKit, ImageBuilderTypeKaniko}
+
+// YamlMergeStrategy defines how agent pod definitions are combined
+type YamlMergeStrategy string
+
+const (
+	// YamlMergeStrategyMerge merges AgentPodTemplate.Yaml into the pod from the ConfigMap with strategic merge patch,
+	// e.g. containers with the same name are merged
+	YamlMergeStrategyMerge YamlMergeStrategy = "merge"
+	// YamlMergeStrategyOverride uses the pod from the ConfigMap instead of AgentPodTemplate.Yaml
+	YamlMergeStrategyOverride YamlMergeStrategy = "override"
+)
+
+// AllowedYamlMergeStrategies consists allowed strategies of combining agent pod definitions
+var AllowedYamlMergeStrategies = []YamlMergeStrategy{YamlMergeStrategyMerge, YamlMergeStrategyOverride}
+
+// AgentOS defines the operating system of agent nodes
+type AgentOS string
+
+const (
+	// AgentOSLinux schedules agent pods on Linux nodes
+	AgentOSLinux AgentOS = "linux"
+	// AgentOSWindows schedules agent pods on Windows nodes
+	AgentOSWindows AgentOS = "windows"
+)
+
+// AllowedAgentOSs consists allowed operating systems of agent nodes
+var AllowedAgentOSs = []AgentOS{AgentOSLinux, AgentOSWindows}
+
+// SpotNodes schedules agent pods on spot or preemptible nodes and retries builds which lost their agent
+type SpotNodes struct {
+	// Provider is aws, gcp or azure, it sets node selector and tolerations of the cloud provider spot nodes, NodeSelector
+	// and Tolerations are added to them
+	Provider SpotProvider `json:"provider,omitempty"`
+	// NodeSelector selects spot nodes, e.g. labels of self-managed spot node pools
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations allow scheduling on tainted spot nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Retries is the number of times a build is scheduled again when it fails after its agent was removed, e.g. because
+	// the spot node was reclaimed, builds aren't retried when 0
+	Retries int `json:"retries,omitempty"`
+}
+
+// SpotProvider defines the cloud provider of spot nodes
+type SpotProvider string
+
+const (
+	// SpotProviderAWS selects EKS managed node groups with Spot capacity
+	SpotProviderAWS SpotProvider = "aws"
+	// SpotProviderGCP selects GKE Spot VMs
+	SpotProviderGCP SpotProvider = "gcp"
+	// SpotProviderAzure selects AKS Spot node pools
+	SpotProviderAzure SpotProvider = "azure"
+)
+
+// AllowedSpotProviders consists allowed cloud providers of spot nodes
+var AllowedSpotProviders = []SpotProvider{SpotProviderAWS, SpotProviderGCP, SpotProviderAzure}
+
+// Exposure defines whether Jenkins can be reachable from outside of the cluster
+type Exposure string
+
+const (
+	// ExposureExternal allows exposing Jenkins through Ingress, OpenShift Route, Istio, Gateway API and NodePort or
+	// LoadBalancer Services
+	ExposureExternal Exposure = "external"
+	// ExposureInternal allows only ClusterIP Services, the operator doesn't create any resource exposing Jenkins outside
+	// of the cluster
+	ExposureInternal Exposure = "internal"
+)
+
+// AllowedExposures consists allowed Jenkins exposures
+var AllowedExposures = []Exposure{ExposureExternal, ExposureInternal}
+
+// NetworkPolicy defines NetworkPolicy of Jenkins master pod, the operator and Kubernetes plugin agents
+// are always allowed to connect, all other ingress traffic is denied unless allowed here
+type NetworkPolicy struct {
+	// IngressControllers contains ingress controller pods allowed to connect to Jenkins HTTP port
+	IngressControllers []networkingv1.NetworkPolicyPeer `json:"ingressControllers,omitempty"`
+	// AdditionalRules contains ingress rules appended to the generated ones
+	AdditionalRules []networkingv1.NetworkPolicyIngressRule `json:"additionalRules,omitempty"`
+}
+
+// Monitoring defines Prometheus Operator PodMonitor jenkins-operator-<cr_name> of Jenkins master pod, the metrics are
+// exposed by the prometheus plugin installed and configured by the operator
+type Monitoring struct {
+	// Path is the path of the prometheus plugin endpoint relative to Jenkins prefix, 'prometheus' by default
+	Path string `json:"path,omitempty"`
+	// Interval is the scrape interval, e.g. 30s, the Prometheus global interval is used when empty
+	Interval string `json:"interval,omitempty"`
+	// Labels are added to the PodMonitor, e.g. to match podMonitorSelector of the Prometheus
+	Labels map[string]string `json:"labels,omitempty"`
+	// GrafanaDashboard creates ConfigMap jenkins-operator-grafana-dashboard-<cr_name> with Grafana dashboard of
+	// Jenkins and the operator metrics of the Jenkins CR, the ConfigMap isn't created when not set
+	GrafanaDashboard *GrafanaDashboard `json:"grafanaDashboard,omitempty"`
+	// PrometheusRule creates PrometheusRule jenkins-operator-<cr_name> with alerts of the Jenkins CR, the PrometheusRule
+	// isn't created when not set
+	PrometheusRule *PrometheusRule `json:"prometheusRule,omitempty"`
+}
+
+// GrafanaDashboard defines ConfigMap with Grafana dashboard loaded by Grafana dashboards sidecar
+type GrafanaDashboard struct {
+	// Labels of the ConfigMap, grafana_dashboard: "1" when not set, e.g. matching the label of the sidecar
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// PrometheusRule defines alerts of the Jenkins CR loaded by Prometheus
+type PrometheusRule struct {
+	// Labels of the PrometheusRule, e.g. matching the ruleSelector of Prometheus
+	Labels map[string]string `json:"labels,omitempty"`
+	// Severity is the severity label of the alerts, 'warning' by default
+	Severity string `json:"severity,omitempty"`
+	// QueueLength is the build queue length which fires the JenkinsQueueLengthHigh alert when sustained for 15 minutes,
+	// 10 by default
+	QueueLength int `json:"queueLength,omitempty"`
+}
+
+// JenkinsService defines how Jenkins master is exposed outside of the cluster
+type JenkinsService struct {
+	// Type is ClusterIP (default), NodePort or LoadBalancer, ClusterIP is replaced by NodePort when the operator
+	// runs with minikube
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// NodePort is the node port of Jenkins HTTP port, allocated by Kubernetes when not set
+	NodePort int32 `json:"nodePort,omitempty"`
+	// HTTPSNodePort is the node port of Jenkins HTTPS port, used only when spec.master.tls is set
+	HTTPSNodePort int32 `json:"httpsNodePort,omitempty"`
+	// AgentListenerNodePort is the node port of Jenkins TCP agent listener port
+	AgentListenerNodePort int32 `json:"agentListenerNodePort,omitempty"`
+	// LoadBalancerIP is the IP address requested from the cloud provider for LoadBalancer type
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+	// LoadBalancerSourceRanges restricts client CIDR ranges allowed by the cloud provider load balancer of
+	// LoadBalancer type, all clients are allowed when empty
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// InternalLoadBalancer is aws, gcp or azure, it sets the cloud provider annotations which make load balancer of
+	// LoadBalancer type reachable only from the cluster network, Annotations take precedence over them
+	InternalLoadBalancer InternalLoadBalancerProvider `json:"internalLoadBalancer,omitempty"`
+	// Annotations are added to the Service, e.g. cloud provider load balancer settings
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// SessionAffinity is None (default) or ClientIP which routes requests of a client to the same endpoint
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+	// SessionAffinityTimeoutSeconds is the maximum session sticky time with ClientIP session affinity, 10800 by default
+	SessionAffinityTimeoutSeconds int32 `json:"sessionAffinityTimeoutSeconds,omitempty"`
+	// ExternalTrafficPolicy is Cluster (default) or Local which preserves client source IP addresses of NodePort and
+	// LoadBalancer Service traffic, nodes without Jenkins master pod don't receive the traffic then
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	// PortNames overrides names of the Service ports, service meshes and ingress controllers detect protocols by them
+	PortNames ServicePortNames `json:"portNames,omitempty"`
+	// Ingress exposes Jenkins UI through Ingress, Jenkins root URL is set to the Ingress URL
+	Ingress *Ingress `json:"ingress,omitempty"`
+	// Route exposes Jenkins UI through OpenShift Route instead of Ingress, Jenkins root URL is set to the Route URL,
+	// requires the operator running on OpenShift
+	Route *Route `json:"route,omitempty"`
+	// Istio exposes Jenkins UI through Istio ingress gateway and adjusts Jenkins master to Istio sidecar injection,
+	// requires Istio installed in the cluster
+	Istio *Istio `json:"istio,omitempty"`
+	// HTTPRoute exposes Jenkins UI through Gateway API HTTPRoute attached to an existing Gateway instead of Ingress,
+	// Jenkins root URL is set to the route URL, requires Gateway API CRDs installed in the cluster
+	HTTPRoute *HTTPRoute `json:"httpRoute,omitempty"`
+	// ExternalDNS makes external-dns publish DNS records of Jenkins master, it's set as external-dns annotations of
+	// the Ingress or the Service
+	ExternalDNS *ExternalDNS `json:"externalDNS,omitempty"`
+	// Agent exposes TCP agent listener through a dedicated Service jenkins-operator-agent-<cr_name>, so agents outside
+	// of the cluster can connect without exposing Jenkins UI
+	Agent *AgentService `json:"agent,omitempty"`
+	// Headless creates headless Service jenkins-operator-headless-<cr_name> resolving directly to Jenkins master pod
+	// IP, the pod is addressable as jenkins-operator-<cr_name>.jenkins-operator-headless-<cr_name>
+	Headless *HeadlessService `json:"headless,omitempty"`
+}
+
+// InternalLoadBalancerProvider defines the cloud provider of internal load balancer
+type InternalLoadBalancerProvider string
+
+const (
+	// InternalLoadBalancerProviderAWS creates internal AWS Elastic Load Balancer
+	InternalLoadBalancerProviderAWS InternalLoadBalancerProvider = "aws"
+	// InternalLoadBalancerProviderGCP creates internal Google Cloud TCP/UDP load balancer
+	InternalLoadBalancerProviderGCP InternalLoadBalancerProvider = "gcp"
+	// InternalLoadBalancerProviderAzure creates internal Azure load balancer
+	InternalLoadBalancerProviderAzure InternalLoadBalancerProvider = "azure"
+)
+
+// AllowedInternalLoadBalancerProviders consists allowed cloud providers of internal load balancer
+var AllowedInternalLoadBalancerProviders = []InternalLoadBalancerProvider{InternalLoadBalancerProviderAWS,
+	InternalLoadBalancerProviderGCP, InternalLoadBalancerProviderAzure}
+
+// HeadlessService defines the headless Service of Jenkins master pod
+type HeadlessService struct {
+	// PublishNotReadyAddresses resolves the pod address before Jenkins master is ready, e.g. for peer discovery
+	PublishNotReadyAddresses bool `json:"publishNotReadyAddresses,omitempty"`
+	// Annotations are added to the Service
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ServicePortNames defines names of Jenkins Service ports, the names have to be valid IANA service names
+type ServicePortNames struct {
+	// HTTP is the name of Jenkins HTTP port, 'http' by default
+	HTTP string `json:"http,omitempty"`
+	// HTTPS is the name of Jenkins HTTPS port, 'https' by default
+	HTTPS string `json:"https,omitempty"`
+	// AgentListener is the name of TCP agent listener port, 'tcp-jnlp' by default
+	AgentListener string `json:"agentListener,omitempty"`
+	// SSHD is the name of SSH server port, 'tcp-sshd' by default
+	SSHD string `json:"sshd,omitempty"`
+}
+
+// AgentService defines the Service exposing Jenkins TCP agent listener
+type AgentService struct {
+	// Type is ClusterIP (default), NodePort or LoadBalancer
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// NodePort is the node port of the agent listener port, allocated by Kubernetes when not set
+	NodePort int32 `json:"nodePort,omitempty"`
+	// LoadBalancerIP is the IP address requested from the cloud provider for LoadBalancer type
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+	// Annotations are added to the Service, e.g. internal load balancer settings of the cloud provider
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ExternalDNS defines DNS records published by external-dns
+type ExternalDNS struct {
+	// Hostname contains comma separated fully qualified domain names of Jenkins master, the Ingress host is always
+	// published by external-dns
+	Hostname string `json:"hostname,omitempty"`
+	// TTL is the time to live of the DNS records in seconds, external-dns default is used when not set
+	TTL int32 `json:"ttl,omitempty"`
+}
+
+// Istio defines Istio VirtualService jenkins-operator-<cr_name> routing the host to Jenkins master HTTP port
+type Istio struct {
+	// Host is the fully qualified domain name of Jenkins UI
+	Host string `json:"host"`
+	// Gateway references an existing Istio Gateway as <namespace>/<name> or <name> in the Jenkins CR namespace,
+	// the operator creates Gateway jenkins-operator-<cr_name> when empty
+	Gateway string `json:"gateway,omitempty"`
+	// GatewaySelector selects Istio ingress gateway pods of the created Gateway, 'istio: ingressgateway' by default
+	GatewaySelector map[string]string `json:"gatewaySelector,omitempty"`
+	// TLSSecretName is the name of kubernetes.io/tls Secret with the host certificate in the ingress gateway
+	// namespace, the created Gateway serves HTTPS and redirects plain HTTP requests when set
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+}
+
+// HTTPRoute defines Gateway API HTTPRoute jenkins-operator-<cr_name> routing the host to Jenkins master HTTP port
+type HTTPRoute struct {
+	// Host is the fully qualified domain name of Jenkins UI
+	Host string `json:"host"`
+	// Gateway references the Gateway as <namespace>/<name> or <name> in the Jenkins CR namespace, the Gateway has to
+	// allow routes from the Jenkins CR namespace
+	Gateway string `json:"gateway"`
+	// SectionName attaches the route only to the named Gateway listener, all matching listeners are used when empty
+	SectionName string `json:"sectionName,omitempty"`
+	// HTTPS tells that the Gateway listener terminates TLS, Jenkins root URL uses http scheme when not set
+	HTTPS bool `json:"https,omitempty"`
+	// Annotations are added to the HTTPRoute
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// RouteTermination defines where OpenShift router terminates TLS connection to Jenkins UI
+type RouteTermination string
+
+const (
+	// RouteTerminationEdge terminates TLS on the router which connects to Jenkins master HTTP port
+	RouteTerminationEdge RouteTermination = "edge"
+	// RouteTerminationReencrypt terminates TLS on the router which connects to Jenkins master HTTPS port,
+	// requires spec.master.tls
+	RouteTerminationReencrypt RouteTermination = "reencrypt"
+	// RouteTerminationPassthrough passes TLS connection to Jenkins master HTTPS port, requires spec.master.tls
+	RouteTerminationPassthrough RouteTermination = "passthrough"
+)
+
+// AllowedRouteTerminations consists allowed OpenShift Route TLS terminations
+var AllowedRouteTerminations = []RouteTermination{RouteTerminationEdge, RouteTerminationReencrypt, RouteTerminationPassthrough}
+
+// Route defines OpenShift Route jenkins-operator-<cr_name> routing the host to Jenkins master
+type Route struct {
+	// Host is the fully qualified domain name of Jenkins UI, OpenShift router generates it when empty
+	Host string `json:"host,omitempty"`
+	// Termination is edge (default), reencrypt or passthrough
+	Termination RouteTermination `json:"termination,omitempty"`
+	// SecretName is the name of kubernetes.io/tls Secret in the Jenkins CR namespace with the host certificate
+	// copied to the Route, the router default certificate is used when empty, not used with passthrough termination
+	SecretName string `json:"secretName,omitempty"`
+	// Annotations are added to the Route, e.g. router specific settings
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Ingress defines Ingress jenkins-operator-<cr_name> routing the host to Jenkins master HTTP port
+type Ingress struct {
+	// Host is the fully qualified domain name of Jenkins UI
+	Host string `json:"host"`
+	// IngressClassName selects the ingress controller, it's set as 'kubernetes.io/ingress.class' annotation
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// Annotations are added to the Ingress, e.g. ingress controller specific settings
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// TLS enables HTTPS of the host, Jenkins root URL uses http scheme when not set
+	TLS *IngressTLS `json:"tls,omitempty"`
+	// AllowSourceRanges contains CIDRs of clients allowed to access Jenkins UI through the Ingress, all clients are
+	// allowed when empty, requires ingress-nginx
+	AllowSourceRanges []string `json:"allowSourceRanges,omitempty"`
+	// Auth makes the ingress controller authenticate requests before they reach Jenkins, requires ingress-nginx
+	Auth *IngressAuth `json:"auth,omitempty"`
+	// ResponseHeaders are HTTP headers added by the ingress controller to all responses of Jenkins UI, they replace the
+	// same headers sent by Jenkins, requires ingress-nginx with snippet annotations allowed
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+}
+
+// IngressAuth defines authentication done by ingress-nginx, only one method can be set
+type IngressAuth struct {
+	// OAuth2ProxyURL is the URL of oauth2-proxy endpoints, e.g. https://auth.example.com/oauth2
+	OAuth2ProxyURL string `json:"oauth2ProxyURL,omitempty"`
+	// BasicAuthSecretName is the name of Secret in the Jenkins CR namespace with htpasswd file in 'auth' key
+	BasicAuthSecretName string `json:"basicAuthSecretName,omitempty"`
+	// BasicAuthRealm is the realm displayed by browsers with basic authentication
+	BasicAuthRealm string `json:"basicAuthRealm,omitempty"`
+}
+
+// IngressTLS defines certificate of Jenkins host served by the ingress controller
+type IngressTLS struct {
+	// SecretName is the name of kubernetes.io/tls Secret in the Jenkins CR namespace with the certificate
+	SecretName string `json:"secretName"`
+	// IssuerRef references cert-manager issuer, when set the operator creates cert-manager Certificate which stores
+	// the certificate valid for the host in SecretName Secret
+	IssuerRef *CertManagerIssuerReference `json:"issuerRef,omitempty"`
+}
+
+// Security defines Jenkins security realm, the operator user has to be able to authenticate in the configured realm
+type Security struct {
+	LDAP *LDAP `json:"ldap,omitempty"`
+	OIDC *OIDC `json:"oidc,omitempty"`
+	SAML *SAML `json:"saml,omitempty"`
+	// GitHubOAuth replaces authorization strategy with GitHub organization and team based one
+	GitHubOAuth *GitHubOAuth `json:"githubOAuth,omitempty"`
+	// Authorization defines Jenkins authorization strategy, all logged in users have full control when not set
+	Authorization *Authorization `json:"authorization,omitempty"`
+	// PodSecurityProfile is the Pod Security Standards level which pods generated by the operator comply with,
+	// pods aren't changed when not set
+	PodSecurityProfile PodSecurityProfile `json:"podSecurityProfile,omitempty"`
+	// FIPSMode restricts Jenkins and the operator to FIPS 140 approved cryptography, Jenkins master image has to
+	// provide FIPS validated JVM security providers, requires spec.master.tls
+	FIPSMode bool `json:"fipsMode,omitempty"`
+	// ServiceUsers contains Jenkins accounts created by the operator for external systems, their API tokens are
+	// published in Kubernetes Secrets
+	ServiceUsers []ServiceUser `json:"serviceUsers,omitempty"`
+	// LoginThrottling locks out users after too many failed logins with Jenkins login form, logins aren't
+	// throttled when not set
+	LoginThrottling *LoginThrottling `json:"loginThrottling,omitempty"`
+	// Profile is the security hardening preset which provides defaults of CSRF protection, content security policy,
+	// agent protocols, markup formatter and script console, the settings set explicitly take precedence, default none
+	Profile SecurityProfile `json:"profile,omitempty"`
+	// ScriptConsoleEnabled allows users other than the operator to use Groovy script console, when false the script
+	// console is rejected for everyone except the operator, default true unless strict security profile is selected
+	ScriptConsoleEnabled *bool `json:"scriptConsoleEnabled,omitempty"`
+}
+
+// SecurityProfile defines security hardening preset
+type SecurityProfile string
+
+const (
+	// SecurityProfileNone keeps Jenkins defaults and settings configured manually in Jenkins
+	SecurityProfileNone SecurityProfile = "none"
+	// SecurityProfileRecommended enforces CSRF protection, Jenkins default content security policy, agent protocols
+	// without deprecated ones and plain text markup formatter
+	SecurityProfileRecommended SecurityProfile = "recommended"
+	// SecurityProfileStrict is the recommended profile with restrictive content security policy, JNLP4-connect
+	// as the only agent protocol and script console available only to the operator
+	SecurityProfileStrict SecurityProfile = "strict"
+)
+
+// AllowedSecurityProfiles consists allowed security hardening presets
+var AllowedSecurityProfiles = []SecurityProfile{SecurityProfileNone, SecurityProfileRecommended, SecurityProfileStrict}
+
+// LoginThrottling defines brute-force protection of Jenkins login form
+type LoginThrottling struct {
+	// MaxFailedAttempts is the number of failed logins of a user within the window after which further logins
+	// of the user are rejected
+	MaxFailedAttempts int32 `json:"maxFailedAttempts"`
+	// Window is the period in which failed logins are counted, the user is locked out until the oldest counted
+	// failed login is older than the window
+	Window metav1.Duration `json:"window"`
+}
+
+// ServiceUser defines Jenkins account used by an external system, e.g. a deploy bot or a dashboard, its permissions
+// are granted by the authorization strategy
+type ServiceUser struct {
+	// Name is the Jenkins user ID, the user has to exist in the security realm unless Jenkins own user database is used
+	Name string `json:"name"`
+	// SecretName is the name of Secret created by the operator in the Jenkins CR namespace with 'user' and 'token' keys
+	SecretName string `json:"secretName"`
+}
+
+// PodSecurityProfile defines Pod Security Standards level enforced by Pod Security Admission
+type PodSecurityProfile string
+
+const (
+	// PodSecurityProfilePrivileged doesn't restrict pods
+	PodSecurityProfilePrivileged PodSecurityProfile = "privileged"
+	// PodSecurityProfileBaseline prevents known privilege escalations, e.g. unconfined seccomp and AppArmor profiles
+	PodSecurityProfileBaseline PodSecurityProfile = "baseline"
+	// PodSecurityProfileRestricted requires non-root user, dropped capabilities and runtime default seccomp profile
+	PodSecurityProfileRestricted PodSecurityProfile = "restricted"
+)
+
+// AllowedPodSecurityProfiles consists allowed Pod Security Standards levels
+var AllowedPodSecurityProfiles = []PodSecurityProfile{PodSecurityProfilePrivileged, PodSecurityProfileBaseline, PodSecurityProfileRestricted}
+
+// Authorization defines Jenkins authorization strategy, the operator user is always administrator,
+// only one of the strategies can be set
+type Authorization struct {
+	Matrix    *MatrixAuthorization    `json:"matrix,omitempty"`
+	RoleBased *RoleBasedAuthorization `json:"roleBased,omitempty"`
+}
+
+// RoleBasedAuthorization defines role-based authorization strategy, requires role-strategy plugin,
+// roles and assignments created manually in Jenkins are overwritten
+type RoleBasedAuthorization struct {
+	// GlobalRoles contains roles with permissions granted in whole Jenkins
+	GlobalRoles []Role `json:"globalRoles,omitempty"`
+	// ItemRoles contains roles with permissions granted in folders and jobs matching the role pattern
+	ItemRoles []Role `json:"itemRoles,omitempty"`
+}
+
+// Role defines named set of permissions assigned to users and groups
+type Role struct {
+	Name string `json:"name"`
+	// Pattern is regular expression matching full names of folders and jobs, e.g. 'team-a(/.*)?', used only by item roles
+	Pattern string `json:"pattern,omitempty"`
+	// Permissions contains permissions in 'group/name' format, e.g. Overall/Read or Job/Build
+	Permissions []string `json:"permissions"`
+	// Assignments contains user and group names assigned to the role
+	Assignments []string `json:"assignments,omitempty"`
+}
+
+// MatrixAuthorization defines matrix-based authorization strategy, requires matrix-auth plugin,
+// permissions granted manually in Jenkins are overwritten
+type MatrixAuthorization struct {
+	// ProjectBased enables project-based matrix, jobs and folders can grant additional permissions
+	ProjectBased bool `json:"projectBased,omitempty"`
+	// Grants contains permissions granted to users and groups
+	Grants []MatrixGrant `json:"grants,omitempty"`
+}
+
+// MatrixGrant defines permissions granted to user or group
+type MatrixGrant struct {
+	// Sid is the user or group name, 'authenticated' means all logged in users and 'anonymous' not logged in users
+	Sid string `json:"sid"`
+	// Permissions contains permissions in 'group/name' format, e.g. Overall/Read or Job/Build
+	Permissions []string `json:"permissions"`
+}
+
+// GitHubOAuth defines GitHub or GitHub Enterprise OAuth security realm, requires github-oauth plugin
+type GitHubOAuth struct {
+	// WebURL is GitHub URL, default https://github.com
+	WebURL string `json:"webURL,omitempty"`
+	// APIURL is GitHub API URL, default https://api.github.com for GitHub and WebURL/api/v3 for GitHub Enterprise
+	APIURL string `json:"apiURL,omitempty"`
+	// ClientSecretName is the name of Kubernetes Secret in the Jenkins CR namespace with OAuth application
+	// 'clientId' and 'clientSecret' keys
+	ClientSecretName string `json:"clientSecretName"`
+	// Organizations contains GitHub organizations whose members can read Jenkins and build jobs
+	Organizations []string `json:"organizations,omitempty"`
+	// Teams contains GitHub teams in 'organization/team' format whose members can read Jenkins and build jobs
+	Teams []string `json:"teams,omitempty"`
+	// Admins contains GitHub user names with Jenkins administrator permission
+	Admins []string `json:"admins,omitempty"`
+}
+
+// SAML defines SAML 2.0 security realm, requires saml and configuration-as-code plugins
+type SAML struct {
+	IdPMetadata SAMLIdPMetadata `json:"idpMetadata"`
+	// UsernameAttribute is the attribute with Jenkins user name, SAML NameID is used when empty
+	UsernameAttribute    string `json:"usernameAttribute,omitempty"`
+	DisplayNameAttribute string `json:"displayNameAttribute,omitempty"`
+	EmailAttribute       string `json:"emailAttribute,omitempty"`
+	GroupsAttribute      string `json:"groupsAttribute,omitempty"`
+	// KeystoreSecretName is the name of Kubernetes Secret in the Jenkins CR namespace with 'keystore.jks',
+	// 'keystorePassword' and 'privateKeyPassword' keys used to sign and encrypt SAML messages
+	KeystoreSecretName string `json:"keystoreSecretName,omitempty"`
+	// PrivateKeyAlias is the alias of the private key in the keystore
+	PrivateKeyAlias string `json:"privateKeyAlias,omitempty"`
+}
+
+// SAMLIdPMetadata defines source of the identity provider metadata, exactly one of them has to be set
+type SAMLIdPMetadata struct {
+	// XML is the inline metadata
+	XML string `json:"xml,omitempty"`
+	// URL is the metadata URL periodically fetched by Jenkins
+	URL string `json:"url,omitempty"`
+	// ConfigMap is the ConfigMap with the metadata in 'idp-metadata.xml' key
+	ConfigMap *ConfigMapReference `json:"configMap,omitempty"`
+}
+
+// OIDC defines OpenID Connect security realm, requires oic-auth and configuration-as-code plugins
+type OIDC struct {
+	// IssuerURL is the OpenID Provider URL, the provider configuration is discovered from
+	// IssuerURL/.well-known/openid-configuration
+	IssuerURL string `json:"issuerURL"`
+	// ClientSecretName is the name of Kubernetes Secret in the Jenkins CR namespace with 'clientId' and 'clientSecret' keys
+	ClientSecretName string `json:"clientSecretName"`
+	// Scopes are requested from the OpenID Provider, default openid, email and profile
+	Scopes []string `json:"scopes,omitempty"`
+	// UserNameClaim is the claim with Jenkins user name, default sub
+	UserNameClaim string `json:"userNameClaim,omitempty"`
+	FullNameClaim string `json:"fullNameClaim,omitempty"`
+	EmailClaim    string `json:"emailClaim,omitempty"`
+	// GroupsClaim is the claim with user groups, groups aren't mapped when empty
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+	// BreakGlassSecretName is the name of Kubernetes Secret in the Jenkins CR namespace with 'username' and 'password'
+	// keys of local account which can log in when the OpenID Provider isn't available
+	BreakGlassSecretName string `json:"breakGlassSecretName,omitempty"`
+}
+
+// LDAP defines LDAP security realm, requires ldap plugin
+type LDAP struct {
+	// Server is the LDAP server URL, e.g. ldaps://ldap.example.com:636
+	Server string `json:"server"`
+	RootDN string `json:"rootDN,omitempty"`
+	// UserSearchBase is relative to RootDN
+	UserSearchBase string `json:"userSearchBase,omitempty"`
+	// UserSearchFilter is the user search filter, {0} is replaced by the user name, default uid={0}
+	UserSearchFilter string `json:"userSearchFilter,omitempty"`
+	// GroupSearchBase is relative to RootDN
+	GroupSearchBase string `json:"groupSearchBase,omitempty"`
+	// GroupSearchFilter is the group search filter, {0} is replaced by the group name
+	GroupSearchFilter string `json:"groupSearchFilter,omitempty"`
+	// BindSecretName is the name of Kubernetes Secret in the Jenkins CR namespace with 'bindDN' and 'bindPassword'
+	// keys used to search the directory, anonymous bind is used when empty
+	BindSecretName string `json:"bindSecretName,omitempty"`
+}
+
+// ArtifactManager defines object storage where build artifacts and stashes are stored instead of Jenkins master disk
+type ArtifactManager struct {
+	AmazonS3 *ArtifactManagerAmazonS3 `json:"amazonS3,omitempty"`
+}
+
+// ArtifactManagerAmazonS3 defines AWS S3 bucket used to store build artifacts, requires artifact-manager-s3 plugin
+type ArtifactManagerAmazonS3 struct {
+	BucketName string `json:"bucketName"`
+	// Prefix is the bucket path prefix of stored artifacts, it has to end with '/'
+	Prefix string `json:"prefix,omitempty"`
+	Region string `json:"region,omitempty"`
+	// CredentialsSecretName is the name of Kubernetes Secret in the Jenkins CR namespace with 'accessKeyId'
+	// and 'secretAccessKey' keys, when empty the AWS default credentials chain is used (e.g. instance profile)
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// Vault defines HashiCorp Vault used by Jenkins to resolve credentials and configuration as code secrets,
+// requires hashicorp-vault-plugin
+type Vault struct {
+	// Address is the Vault server URL
+	Address    string          `json:"address"`
+	AuthMethod VaultAuthMethod `json:"authMethod"`
+	// SecretName is the name of Kubernetes Secret in the Jenkins CR namespace with the Vault credentials,
+	// key 'token' for token auth method, keys 'roleId' and 'secretId' for appRole auth method
+	SecretName string `json:"secretName,omitempty"`
+	// Role is the Vault role used by kubernetes auth method
+	Role string `json:"role,omitempty"`
+	// Paths contains Vault KV secret paths used to resolve secrets in configuration as code files, requires
+	// configuration-as-code plugin
+	Paths []string `json:"paths,omitempty"`
+	// EngineVersion is the Vault KV secrets engine version, default 2
+	EngineVersion int `json:"engineVersion,omitempty"`
+}
+
+// VaultAuthMethod defines how Jenkins authenticates to Vault
+type VaultAuthMethod string
+
+const (
+	// VaultAuthMethodToken uses Vault token from Kubernetes Secret
+	VaultAuthMethodToken VaultAuthMethod = "token"
+	// VaultAuthMethodAppRole uses Vault AppRole role ID and secret ID from Kubernetes Secret
+	VaultAuthMethodAppRole VaultAuthMethod = "appRole"
+	// VaultAuthMethodKubernetes uses Jenkins master pod service account token
+	VaultAuthMethodKubernetes VaultAuthMethod = "kubernetes"
+)
+
+// AllowedVaultAuthMethods consists allowed Vault auth methods
+var AllowedVaultAuthMethods = []VaultAuthMethod{VaultAuthMethodToken, VaultAuthMethodAppRole, VaultAuthMethodKubernetes}
+
+// JenkinsConfiguration defines sources of user provided Jenkins configuration
+type JenkinsConfiguration struct {
+	Repository *ConfigurationRepository `json:"repository,omitempty"`
+	// Jobs contains references to ConfigMaps with Jenkins job config.xml files, every ConfigMap key
+	// in format <job-name>.xml is created or updated as Jenkins job
+	Jobs []ConfigMapReference `json:"jobs,omitempty"`
+	// DisabledBaseScripts contains names of built-in base configuration groovy scripts which won't be applied
+	DisabledBaseScripts []string `json:"disabledBaseScripts,omitempty"`
+	// BaseScriptsOverride is reference to ConfigMap which replaces built-in base configuration groovy scripts,
+	// the ConfigMap keys are names of the replaced scripts
+	BaseScriptsOverride *ConfigMapReference `json:"baseScriptsOverride,omitempty"`
+	// SharedLibraries contains Pipeline shared libraries configured by the operator through configuration as code,
+	// requires configuration-as-code plugin
+	SharedLibraries []SharedLibrary `json:"sharedLibraries,omitempty"`
+}
+
+// SharedLibrary defines Pipeline shared library retrieved from Git repository
+type SharedLibrary struct {
+	Name          string `json:"name"`
+	RepositoryURL string `json:"repositoryUrl"`
+	// CredentialsID is ID of Jenkins credentials used to check out the repository
+	CredentialsID  string `json:"credentialsId,omitempty"`
+	DefaultVersion string `json:"defaultVersion,omitempty"`
+	// Implicit tells that the library is loaded by every Pipeline without @Library annotation
+	Implicit bool `json:"implicit,omitempty"`
+	// AllowVersionOverride tells that Pipelines can select other version than DefaultVersion
+	AllowVersionOverride bool `json:"allowVersionOverride,omitempty"`
+	// Folder is the full name of Jenkins folder where the library is available, global library when empty
+	Folder string `json:"folder,omitempty"`
+}
+
+// ConfigMapReference is reference to Kubernetes ConfigMap in the Jenkins CR namespace
+type ConfigMapReference struct {
+	Name string `json:"name"`
+}
+
+// ConfigurationRepository defines Git repository with groovy scripts and configuration as code files,
+// which are applied in lexical order every time the repository changes
+type ConfigurationRepository struct {
+	URL        string     `json:"url"`
+	Ref        string     `json:"ref,omitempty"`
+	Path       string     `json:"path,omitempty"`
+	PrivateKey PrivateKey `json:"privateKey,omitempty"`
 }
 
 // JenkinsBackup defines type of Jenkins backup
@@ -38,22 +971,368 @@ type JenkinsBackupAmazonS3 struct {
 	Region     string `json:"region,omitempty"`
 }
 
-// JenkinsMaster defines the Jenkins master pod attributes and plugins,
-// every single change requires Jenkins master pod restart
+// JenkinsMaster defines the Jenkins master pod attributes, plugins and global configuration,
+// changes of the pod attributes and plugins require Jenkins master pod restart
 type JenkinsMaster struct {
 	Image       string                      `json:"image,omitempty"`
 	Annotations map[string]string           `json:"masterAnnotations,omitempty"`
 	Resources   corev1.ResourceRequirements `json:"resources,omitempty"`
 	Plugins     map[string][]string         `json:"plugins,omitempty"`
+	// Tools contains tool installations configured in Jenkins global tool configuration
+	Tools []Tool `json:"tools,omitempty"`
+	// ScriptApprovals contains script security approvals configured in Jenkins
+	ScriptApprovals ScriptApprovals `json:"scriptApprovals,omitempty"`
+	// GlobalEnv contains environment variables configured in Jenkins global properties and visible to all builds,
+	// when set it replaces all global environment variables configured in Jenkins
+	GlobalEnv map[string]string `json:"globalEnv,omitempty"`
+	// Executors is the number of executors on Jenkins master node, at least one is required by the operator jobs,
+	// default 3
+	Executors *int32 `json:"executors,omitempty"`
+	// Mode defines which builds can run on Jenkins master node, default exclusive
+	Mode MasterMode `json:"mode,omitempty"`
+	// BuildSettings contains global build settings, Jenkins defaults are kept for the settings which aren't set
+	BuildSettings BuildSettings `json:"buildSettings,omitempty"`
+	// Remoting contains Jenkins CLI and agent connection settings, insecure features are disabled by default
+	Remoting Remoting `json:"remoting,omitempty"`
+	// CSRF contains Jenkins CSRF protection settings
+	CSRF CSRF `json:"csrf,omitempty"`
+	// MarkupFormatter defines how descriptions are rendered in Jenkins, Jenkins default is kept when not set
+	MarkupFormatter MarkupFormatter `json:"markupFormatter,omitempty"`
+	// ContentSecurityPolicy is the Content-Security-Policy header of workspace files and archived artifacts served
+	// by Jenkins, empty string disables the policy, Jenkins default is kept when not set
+	ContentSecurityPolicy *string `json:"contentSecurityPolicy,omitempty"`
+	// ResponseHeaders are HTTP headers added by Jenkins to all its responses, e.g. Strict-Transport-Security, they
+	// replace the same headers set by Jenkins, like X-Frame-Options
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	// ResourceRootURL is an alternative URL of Jenkins used to serve workspace files and archived artifacts from
+	// another origin, so HTML reports work without relaxing the content security policy, requires Jenkins URL
+	ResourceRootURL string `json:"resourceRootURL,omitempty"`
+	// Prefix is the context path of Jenkins, e.g. /jenkins, used by path based routing, Jenkins is served from the root
+	// path when empty
+	Prefix string `json:"prefix,omitempty"`
+	// SessionTimeout is the HTTP session timeout of Jenkins UI in minutes, Jenkins default is used when not set
+	SessionTimeout int32 `json:"sessionTimeout,omitempty"`
+	// AdminEmail is the sender address of e-mails sent by Jenkins, e.g. 'Jenkins <jenkins@example.com>'
+	AdminEmail string `json:"adminEmail,omitempty"`
+	// SystemMessage is the message displayed at the top of Jenkins dashboard, formatted by the markup formatter
+	SystemMessage string `json:"systemMessage,omitempty"`
+	// LoginDisclaimer is the plain text displayed above the Jenkins login form
+	LoginDisclaimer string `json:"loginDisclaimer,omitempty"`
+	// Theme contains Jenkins look customizations applied by simple-theme-plugin
+	Theme Theme `json:"theme,omitempty"`
+	// UsageStatisticsEnabled enables submitting anonymous usage statistics to the Jenkins project, disabled by default
+	UsageStatisticsEnabled bool `json:"usageStatisticsEnabled,omitempty"`
+	// AdminPasswordRotationPeriod is how often the operator generates a new password of the operator user, which is
+	// the Jenkins administrator, in jenkins-operator-credentials-<cr_name> Secret, the password isn't rotated when not set
+	AdminPasswordRotationPeriod *metav1.Duration `json:"adminPasswordRotationPeriod,omitempty"`
+	// TLS enables Jenkins HTTPS listener used by the operator to communicate with Jenkins, HTTP listener is kept
+	// for agents and the probes
+	TLS *MasterTLS `json:"tls,omitempty"`
+	// HTTPSKeystore enables Jenkins HTTPS listener like TLS, but the certificate is read from Java keystore in the
+	// Secret, it can't be used together with TLS
+	HTTPSKeystore *HTTPSKeystore `json:"httpsKeystore,omitempty"`
+	// ServiceAccountToken replaces the legacy service account token of Jenkins master pod, used by Kubernetes plugin
+	// to launch agents, with a projected token with limited lifetime rotated by kubelet
+	ServiceAccountToken *ServiceAccountToken `json:"serviceAccountToken,omitempty"`
+}
+
+// ServiceAccountToken defines projected service account token of Jenkins master pod
+type ServiceAccountToken struct {
+	// Audience is the intended audience of the token, Kubernetes API server audience is used when not set
+	Audience string `json:"audience,omitempty"`
+	// ExpirationSeconds is the requested lifetime of the token, kubelet refreshes the token when 80% of the lifetime
+	// passes, minimum 600, default 3600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+}
+
+// MasterTLS defines Jenkins master certificate and how the operator verifies it
+type MasterTLS struct {
+	// SecretName is the name of kubernetes.io/tls Secret in the Jenkins CR namespace with 'tls.crt' and 'tls.key' keys,
+	// e.g. issued by cert-manager, the certificate has to be valid for the Jenkins master Service DNS name
+	// jenkins-operator-<cr_name>.<namespace>.svc
+	SecretName string `json:"secretName"`
+	// CASecretName is the name of Secret in the Jenkins CR namespace with 'ca.crt' key used to verify the certificate,
+	// when empty 'ca.crt' key of SecretName Secret is used if present, system root certificates otherwise
+	CASecretName string `json:"caSecretName,omitempty"`
+	// InsecureSkipVerify disables verification of Jenkins certificate, traffic is encrypted but not authenticated
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// IssuerRef references cert-manager issuer, when set the operator creates cert-manager Certificate which stores
+	// the certificate valid for the Jenkins master Service DNS names in SecretName Secret
+	IssuerRef *CertManagerIssuerReference `json:"issuerRef,omitempty"`
+}
+
+// HTTPSKeystore defines Java keystore with Jenkins master certificate and private key served by Jenkins HTTPS listener
+type HTTPSKeystore struct {
+	// SecretName is the name of Secret in the Jenkins CR namespace with the keystore and its password, the certificate
+	// has to be valid for the Jenkins master Service DNS name jenkins-operator-<cr_name>.<namespace>.svc
+	SecretName string `json:"secretName"`
+	// KeystoreKey is the Secret key with JKS or PKCS12 keystore, 'keystore.jks' by default
+	KeystoreKey string `json:"keystoreKey,omitempty"`
+	// PasswordKey is the Secret key with the keystore password, 'password' by default
+	PasswordKey string `json:"passwordKey,omitempty"`
+	// CASecretName is the name of Secret in the Jenkins CR namespace with 'ca.crt' key used to verify the certificate,
+	// system root certificates are used when empty
+	CASecretName string `json:"caSecretName,omitempty"`
+	// InsecureSkipVerify disables verification of Jenkins certificate, traffic is encrypted but not authenticated
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// CertManagerIssuerReference references cert-manager Issuer, ClusterIssuer or an external issuer
+type CertManagerIssuerReference struct {
+	Name string `json:"name"`
+	// Kind is Issuer (default) or ClusterIssuer for cert-manager issuers
+	Kind string `json:"kind,omitempty"`
+	// Group is the API group of external issuers, cert-manager.io when not set
+	Group string `json:"group,omitempty"`
+}
+
+// Theme defines Jenkins look customizations, all URLs have to be accessible from users browsers
+type Theme struct {
+	// DisplayName is the Jenkins instance name displayed in the page header instead of Jenkins logo text
+	DisplayName string `json:"displayName,omitempty"`
+	// LogoURL is the URL of image displayed in the page header instead of Jenkins logo
+	LogoURL    string `json:"logoURL,omitempty"`
+	FaviconURL string `json:"faviconURL,omitempty"`
+	CSSURL     string `json:"cssURL,omitempty"`
+	JSURL      string `json:"jsURL,omitempty"`
+	// CSS is custom CSS included in every Jenkins page
+	CSS string `json:"css,omitempty"`
+}
+
+// MarkupFormatter defines Jenkins markup formatter
+type MarkupFormatter string
+
+const (
+	// MarkupFormatterPlainText escapes all HTML in descriptions
+	MarkupFormatterPlainText MarkupFormatter = "plainText"
+	// MarkupFormatterSafeHTML allows basic HTML in descriptions, requires antisamy-markup-formatter plugin
+	MarkupFormatterSafeHTML MarkupFormatter = "safeHTML"
+)
+
+// AllowedMarkupFormatters consists allowed Jenkins markup formatters
+var AllowedMarkupFormatters = []MarkupFormatter{MarkupFormatterPlainText, MarkupFormatterSafeHTML}
+
+// CSRF defines Jenkins CSRF protection crumb issuer
+type CSRF struct {
+	// ExcludeClientIPFromCrumb enables proxy compatibility of the crumb issuer, required when Jenkins is accessed
+	// through a proxy which changes the client IP address, default true. When not set the crumb issuer configured
+	// manually in Jenkins is kept
+	ExcludeClientIPFromCrumb *bool `json:"excludeClientIPFromCrumb,omitempty"`
+}
+
+// Remoting defines Jenkins CLI and agent connection settings
+type Remoting struct {
+	// CLIEnabled enables Jenkins CLI at /cli URL, disabled by default
+	CLIEnabled bool `json:"cliEnabled,omitempty"`
+	// CLIRemotingEnabled enables deprecated Jenkins CLI over remoting, requires cliEnabled
+	CLIRemotingEnabled bool `json:"cliRemotingEnabled,omitempty"`
+	// AgentProtocols contains names of enabled agent protocols, by default all protocols except deprecated
+	// JNLP-connect, JNLP2-connect, JNLP3-connect and CLI-connect are enabled
+	AgentProtocols []string `json:"agentProtocols,omitempty"`
+	// AgentListenerDisabled disables TCP agent listener, Kubernetes plugin agents require it unless webSocket is set
+	AgentListenerDisabled bool `json:"agentListenerDisabled,omitempty"`
+	// WebSocket makes Kubernetes plugin agents connect over WebSocket through Jenkins HTTP port, the TCP agent
+	// listener port isn't exposed by the Service then
+	WebSocket bool `json:"webSocket,omitempty"`
+	// SSHD enables Jenkins built-in SSH server used by Jenkins CLI over SSH, disabled when not set
+	SSHD *SSHD `json:"sshd,omitempty"`
+}
+
+// SSHD defines Jenkins built-in SSH server settings
+type SSHD struct {
+	// Port is the SSH server port, 50022 by default
+	Port int32 `json:"port,omitempty"`
+	// ServiceEnabled creates a dedicated Service jenkins-operator-sshd-<cr_name> exposing the SSH server port
+	ServiceEnabled bool `json:"serviceEnabled,omitempty"`
+}
+
+// AllowedAgentProtocols consists allowed Jenkins agent protocols
+var AllowedAgentProtocols = []string{"JNLP-connect", "JNLP2-connect", "JNLP3-connect", "JNLP4-connect", "JNLP4-plaintext",
+	"CLI-connect", "CLI2-connect", "Ping"}
+
+// BuildSettings defines Jenkins global build settings
+type BuildSettings struct {
+	// QuietPeriod is the number of seconds Jenkins waits before starting a triggered build
+	QuietPeriod *int32 `json:"quietPeriod,omitempty"`
+	// SCMCheckoutRetryCount is the number of retries of failed SCM checkout
+	SCMCheckoutRetryCount *int32 `json:"scmCheckoutRetryCount,omitempty"`
+	// BuildDiscarder is the global build discarder applied to all jobs, requires Jenkins 2.221 or newer
+	BuildDiscarder *BuildDiscarder `json:"buildDiscarder,omitempty"`
+}
+
+// BuildDiscarder defines which builds and artifacts are kept, zero means no limit
+type BuildDiscarder struct {
+	DaysToKeep         int32 `json:"daysToKeep,omitempty"`
+	NumToKeep          int32 `json:"numToKeep,omitempty"`
+	ArtifactDaysToKeep int32 `json:"artifactDaysToKeep,omitempty"`
+	ArtifactNumToKeep  int32 `json:"artifactNumToKeep,omitempty"`
+}
+
+// MasterMode defines usage of Jenkins master node executors
+type MasterMode string
+
+const (
+	// MasterModeNormal allows to run any build on Jenkins master node
+	MasterModeNormal MasterMode = "normal"
+	// MasterModeExclusive allows to run only builds with label expression matching Jenkins master node
+	MasterModeExclusive MasterMode = "exclusive"
+)
+
+// AllowedMasterModes consists allowed Jenkins master node modes
+var AllowedMasterModes = []MasterMode{MasterModeNormal, MasterModeExclusive}
+
+// ScriptApprovals defines approved script security sandbox signatures and whole scripts, approvals made manually
+// in Jenkins are kept
+type ScriptApprovals struct {
+	// Signatures contains approved method, constructor and field signatures,
+	// e.g. 'method java.lang.String trim'
+	Signatures []string `json:"signatures,omitempty"`
+	// ScriptHashes contains hashes of approved scripts as shown in Jenkins In-process Script Approval page
+	ScriptHashes []string `json:"scriptHashes,omitempty"`
+}
+
+// ToolType defines type of Jenkins tool installation
+type ToolType string
+
+const (
+	// ToolTypeJDK is JDK installation
+	ToolTypeJDK ToolType = "jdk"
+	// ToolTypeMaven is Maven installation
+	ToolTypeMaven ToolType = "maven"
+	// ToolTypeGradle is Gradle installation, requires gradle plugin
+	ToolTypeGradle ToolType = "gradle"
+	// ToolTypeNodeJS is NodeJS installation, requires nodejs plugin
+	ToolTypeNodeJS ToolType = "nodejs"
+)
+
+// AllowedToolTypes consists allowed Jenkins tool installation types
+var AllowedToolTypes = []ToolType{ToolTypeJDK, ToolTypeMaven, ToolTypeGradle, ToolTypeNodeJS}
+
+// Tool defines Jenkins tool installation, the tool is installed automatically in Version
+// or it's already present in Home directory
+type Tool struct {
+	Type    ToolType `json:"type"`
+	Name    string   `json:"name"`
+	Version string   `json:"version,omitempty"`
+	Home    string   `json:"home,omitempty"`
 }
 
 // JenkinsStatus defines the observed state of Jenkins
 type JenkinsStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
+	// Phase summarizes the conditions, e.g. Provisioning, Configuring or Ready
+	Phase JenkinsPhase `json:"phase,omitempty"`
+	// JenkinsVersion is the version of Jenkins running in the current Jenkins master pod
+	JenkinsVersion string `json:"jenkinsVersion,omitempty"`
+	// URL is Jenkins root URL or URL of Jenkins Service inside the cluster when Jenkins isn't exposed
+	URL string `json:"url,omitempty"`
+	// ObservedGeneration is the generation of Jenkins spec which was completely reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// ProvisionStartTime is the time when the current Jenkins master pod was created
+	ProvisionStartTime             *metav1.Time `json:"provisionStartTime,omitempty"`
 	BaseConfigurationCompletedTime *metav1.Time `json:"baseConfigurationCompletedTime,omitempty"`
+	// SeedJobsCompletedTime is the time when seed jobs of the current Jenkins master pod were built
+	SeedJobsCompletedTime          *metav1.Time `json:"seedJobsCompletedTime,omitempty"`
 	UserConfigurationCompletedTime *metav1.Time `json:"userConfigurationCompletedTime,omitempty"`
-	Builds                         []Build      `json:"builds,omitempty"`
+	// LastBackupTime is the time of the last successful backup of Jenkins jobs, it's empty until the first backup
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+	Builds         []Build      `json:"builds,omitempty"`
+	// MasterPodSpecHash is the hash of the rendered Jenkins master pod spec, plugins and base configuration,
+	// the Jenkins master pod is recreated only when this hash changes
+	MasterPodSpecHash string             `json:"masterPodSpecHash,omitempty"`
+	Conditions        []JenkinsCondition `json:"conditions,omitempty"`
+	// MaintenanceQuietDown tells that the operator has put Jenkins into quiet mode because of Jenkins.Spec.MaintenanceMode,
+	// only such quiet mode is canceled when the maintenance mode is disabled
+	MaintenanceQuietDown bool `json:"maintenanceQuietDown,omitempty"`
+	// CredentialsHash is the hash of Jenkins credentials IDs and settings synchronized from Kubernetes Secrets and
+	// the Secrets resource versions, the credentials data isn't hashed
+	CredentialsHash string `json:"credentialsHash,omitempty"`
+	// JobsHash is the hash of Jenkins jobs config.xml files imported from ConfigMaps, the jobs are posted to Jenkins
+	// only when this hash changes
+	JobsHash string `json:"jobsHash,omitempty"`
+	// OperatorCredentialsResourceVersion is the resource version of the operator credentials Secret which was
+	// successfully used to authenticate in Jenkins, change of the Secret is applied to Jenkins
+	OperatorCredentialsResourceVersion string `json:"operatorCredentialsResourceVersion,omitempty"`
+	// SpecHistory is the history of Jenkins spec generations applied by the operator, the oldest first, at most 10
+	// generations are kept
+	SpecHistory []SpecChange `json:"specHistory,omitempty"`
+}
+
+// SpecChange describes a generation of Jenkins spec applied by the operator
+type SpecChange struct {
+	// Generation is the generation of Jenkins spec
+	Generation int64 `json:"generation"`
+	// Hash is the hash of Jenkins spec
+	Hash string `json:"hash"`
+	// Time is the time when the generation was completely reconciled
+	Time metav1.Time `json:"time"`
+	// Changes are the spec fields changed since the previous generation, e.g. master.image, master.plugins or
+	// configuration, they're empty for the first recorded generation
+	Changes []string `json:"changes,omitempty"`
+	// FieldHashes are hashes of the spec fields, they're kept only for the newest generation to find the changes of
+	// the next one
+	FieldHashes map[string]string `json:"fieldHashes,omitempty"`
+}
+
+// JenkinsPhase is a summary of Jenkins status conditions shown by kubectl get jenkins
+type JenkinsPhase string
+
+const (
+	// JenkinsPhaseProvisioning tells that Jenkins master pod is being created or started
+	JenkinsPhaseProvisioning JenkinsPhase = "Provisioning"
+	// JenkinsPhaseConfiguring tells that base or user configuration of Jenkins is in progress
+	JenkinsPhaseConfiguring JenkinsPhase = "Configuring"
+	// JenkinsPhaseReady tells that Jenkins is provisioned and configured
+	JenkinsPhaseReady JenkinsPhase = "Ready"
+	// JenkinsPhaseInvalid tells that Jenkins CR is rejected by the validation
+	JenkinsPhaseInvalid JenkinsPhase = "Invalid"
+	// JenkinsPhaseRestarting tells that Jenkins master pod is being restarted by the operator
+	JenkinsPhaseRestarting JenkinsPhase = "Restarting"
+	// JenkinsPhaseMaintenance tells that Jenkins is in maintenance mode
+	JenkinsPhaseMaintenance JenkinsPhase = "Maintenance"
+	// JenkinsPhasePaused tells that reconciliation of Jenkins is paused
+	JenkinsPhasePaused JenkinsPhase = "Paused"
+)
+
+// JenkinsConditionType defines type of Jenkins status condition
+type JenkinsConditionType string
+
+const (
+	// JenkinsConditionRestarting tells that Jenkins master pod is being restarted by the operator
+	JenkinsConditionRestarting JenkinsConditionType = "Restarting"
+	// JenkinsConditionPaused tells that reconciliation of Jenkins is paused by the jenkins-operator/paused annotation
+	JenkinsConditionPaused JenkinsConditionType = "Paused"
+	// JenkinsConditionMaintenance tells that Jenkins is in quiet mode enabled by the spec.maintenanceMode field
+	JenkinsConditionMaintenance JenkinsConditionType = "Maintenance"
+	// JenkinsConditionExternallyExposed tells that Jenkins Services are exposed outside of the cluster by resources not
+	// managed by the operator although spec.exposure is internal
+	JenkinsConditionExternallyExposed JenkinsConditionType = "ExternallyExposed"
+	// JenkinsConditionAgentTemplatesInvalid tells that agent pod templates are rejected by the validation, the message
+	// lists the errors of every invalid template
+	JenkinsConditionAgentTemplatesInvalid JenkinsConditionType = "AgentTemplatesInvalid"
+	// JenkinsConditionProvisioned tells that Jenkins master pod is ready and Jenkins API is reachable
+	JenkinsConditionProvisioned JenkinsConditionType = "Provisioned"
+	// JenkinsConditionBaseConfigured tells that base configuration of the current Jenkins master pod is completed
+	JenkinsConditionBaseConfigured JenkinsConditionType = "BaseConfigured"
+	// JenkinsConditionUserConfigured tells that seed jobs and user configuration of the current Jenkins master pod
+	// are completed
+	JenkinsConditionUserConfigured JenkinsConditionType = "UserConfigured"
+	// JenkinsConditionReady tells that Jenkins is provisioned and configured according to the observed generation of
+	// the spec, e.g. kubectl wait --for=condition=Ready jenkins/example
+	JenkinsConditionReady JenkinsConditionType = "Ready"
+	// JenkinsConditionBackupHealthy tells that the backup strategy of spec.backup is configured correctly, it's
+	// Unknown when backup is disabled
+	JenkinsConditionBackupHealthy JenkinsConditionType = "BackupHealthy"
+)
+
+// JenkinsCondition describes the state of Jenkins at a certain point
+type JenkinsCondition struct {
+	Type               JenkinsConditionType   `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
 }
 
 // BuildStatus defines type of Jenkins build job status
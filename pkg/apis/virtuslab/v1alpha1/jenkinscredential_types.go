@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// JenkinsCredentialSpec defines Jenkins credentials synchronized from Kubernetes Secret
+type JenkinsCredentialSpec struct {
+	// Jenkins contains names of Jenkins CRs in the same namespace where the credentials are configured
+	Jenkins []string `json:"jenkins"`
+	// Type is type of the credentials: usernamePassword, secretText, sshUserPrivateKey or certificate
+	Type  string                 `json:"type"`
+	Scope JenkinsCredentialScope `json:"scope,omitempty"`
+	// Folder is the full name of Jenkins folder where the credentials are stored, system store when empty
+	Folder      string `json:"folder,omitempty"`
+	Description string `json:"description,omitempty"`
+	// SecretName is the name of Kubernetes Secret in the same namespace which contains the credentials data
+	SecretName string `json:"secretName"`
+}
+
+// JenkinsCredentialScope defines scope of Jenkins credentials
+type JenkinsCredentialScope string
+
+const (
+	// JenkinsCredentialScopeGlobal tells that credentials are available for Jenkins and all jobs
+	JenkinsCredentialScopeGlobal JenkinsCredentialScope = "global"
+	// JenkinsCredentialScopeSystem tells that credentials are available only for Jenkins itself
+	JenkinsCredentialScopeSystem JenkinsCredentialScope = "system"
+)
+
+// AllowedJenkinsCredentialScopes consists allowed Jenkins credentials scopes
+var AllowedJenkinsCredentialScopes = []JenkinsCredentialScope{JenkinsCredentialScopeGlobal, JenkinsCredentialScopeSystem}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsCredential is the Schema for the jenkinscredentials API
+// +k8s:openapi-gen=true
+type JenkinsCredential struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec JenkinsCredentialSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// JenkinsCredentialList contains a list of JenkinsCredential
+type JenkinsCredentialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []JenkinsCredential `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&JenkinsCredential{}, &JenkinsCredentialList{})
+}
@@ -22,188 +22,1867 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentGPU) DeepCopyInto(out *AgentGPU) {
+	*out = *in
+	if in.DriverCapabilities != nil {
+		in, out := &in.DriverCapabilities, &out.DriverCapabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentGPU.
+func (in *AgentGPU) DeepCopy() *AgentGPU {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentGPU)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentPodTemplate) DeepCopyInto(out *AgentPodTemplate) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]v1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]v1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.YamlConfigMap != nil {
+		in, out := &in.YamlConfigMap, &out.YamlConfigMap
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Spot != nil {
+		in, out := &in.Spot, &out.Spot
+		*out = new(SpotNodes)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageBuilder != nil {
+		in, out := &in.ImageBuilder, &out.ImageBuilder
+		*out = new(ImageBuilder)
+		**out = **in
+	}
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(AgentGPU)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkspaceVolume != nil {
+		in, out := &in.WorkspaceVolume, &out.WorkspaceVolume
+		*out = new(WorkspaceVolume)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentPodTemplate.
+func (in *AgentPodTemplate) DeepCopy() *AgentPodTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentPodTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentService) DeepCopyInto(out *AgentService) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentService.
+func (in *AgentService) DeepCopy() *AgentService {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Agents) DeepCopyInto(out *Agents) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]AgentPodTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Static != nil {
+		in, out := &in.Static, &out.Static
+		*out = make([]StaticAgent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalNamespaces != nil {
+		in, out := &in.AdditionalNamespaces, &out.AdditionalNamespaces
+		*out = make([]AgentsNamespace, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]AgentsCluster, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrphanedPodsGracePeriod != nil {
+		in, out := &in.OrphanedPodsGracePeriod, &out.OrphanedPodsGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.VMClouds != nil {
+		in, out := &in.VMClouds, &out.VMClouds
+		*out = make([]VMCloud, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Agents.
+func (in *Agents) DeepCopy() *Agents {
+	if in == nil {
+		return nil
+	}
+	out := new(Agents)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentsCluster) DeepCopyInto(out *AgentsCluster) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentsCluster.
+func (in *AgentsCluster) DeepCopy() *AgentsCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentsCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentsNamespace) DeepCopyInto(out *AgentsNamespace) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentsNamespace.
+func (in *AgentsNamespace) DeepCopy() *AgentsNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentsNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactManager) DeepCopyInto(out *ArtifactManager) {
+	*out = *in
+	if in.AmazonS3 != nil {
+		in, out := &in.AmazonS3, &out.AmazonS3
+		*out = new(ArtifactManagerAmazonS3)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactManager.
+func (in *ArtifactManager) DeepCopy() *ArtifactManager {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactManagerAmazonS3) DeepCopyInto(out *ArtifactManagerAmazonS3) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactManagerAmazonS3.
+func (in *ArtifactManagerAmazonS3) DeepCopy() *ArtifactManagerAmazonS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactManagerAmazonS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Authorization) DeepCopyInto(out *Authorization) {
+	*out = *in
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = new(MatrixAuthorization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RoleBased != nil {
+		in, out := &in.RoleBased, &out.RoleBased
+		*out = new(RoleBasedAuthorization)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Authorization.
+func (in *Authorization) DeepCopy() *Authorization {
+	if in == nil {
+		return nil
+	}
+	out := new(Authorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Build) DeepCopyInto(out *Build) {
 	*out = *in
-	if in.CreateTime != nil {
-		in, out := &in.CreateTime, &out.CreateTime
-		*out = (*in).DeepCopy()
+	if in.CreateTime != nil {
+		in, out := &in.CreateTime, &out.CreateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Build.
+func (in *Build) DeepCopy() *Build {
+	if in == nil {
+		return nil
+	}
+	out := new(Build)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildDiscarder) DeepCopyInto(out *BuildDiscarder) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildDiscarder.
+func (in *BuildDiscarder) DeepCopy() *BuildDiscarder {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildDiscarder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildSettings) DeepCopyInto(out *BuildSettings) {
+	*out = *in
+	if in.QuietPeriod != nil {
+		in, out := &in.QuietPeriod, &out.QuietPeriod
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SCMCheckoutRetryCount != nil {
+		in, out := &in.SCMCheckoutRetryCount, &out.SCMCheckoutRetryCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BuildDiscarder != nil {
+		in, out := &in.BuildDiscarder, &out.BuildDiscarder
+		*out = new(BuildDiscarder)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildSettings.
+func (in *BuildSettings) DeepCopy() *BuildSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSRF) DeepCopyInto(out *CSRF) {
+	*out = *in
+	if in.ExcludeClientIPFromCrumb != nil {
+		in, out := &in.ExcludeClientIPFromCrumb, &out.ExcludeClientIPFromCrumb
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSRF.
+func (in *CSRF) DeepCopy() *CSRF {
+	if in == nil {
+		return nil
+	}
+	out := new(CSRF)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapReference) DeepCopyInto(out *ConfigMapReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapReference.
+func (in *ConfigMapReference) DeepCopy() *ConfigMapReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationRepository) DeepCopyInto(out *ConfigurationRepository) {
+	*out = *in
+	in.PrivateKey.DeepCopyInto(&out.PrivateKey)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationRepository.
+func (in *ConfigurationRepository) DeepCopy() *ConfigurationRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalDNS) DeepCopyInto(out *ExternalDNS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNS.
+func (in *ExternalDNS) DeepCopy() *ExternalDNS {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalDNS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitHubOAuth) DeepCopyInto(out *GitHubOAuth) {
+	*out = *in
+	if in.Organizations != nil {
+		in, out := &in.Organizations, &out.Organizations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Teams != nil {
+		in, out := &in.Teams, &out.Teams
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Admins != nil {
+		in, out := &in.Admins, &out.Admins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitHubOAuth.
+func (in *GitHubOAuth) DeepCopy() *GitHubOAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(GitHubOAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaDashboard) DeepCopyInto(out *GrafanaDashboard) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaDashboard.
+func (in *GrafanaDashboard) DeepCopy() *GrafanaDashboard {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaDashboard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRoute) DeepCopyInto(out *HTTPRoute) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRoute.
+func (in *HTTPRoute) DeepCopy() *HTTPRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSKeystore) DeepCopyInto(out *HTTPSKeystore) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSKeystore.
+func (in *HTTPSKeystore) DeepCopy() *HTTPSKeystore {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSKeystore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeadlessService) DeepCopyInto(out *HeadlessService) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeadlessService.
+func (in *HeadlessService) DeepCopy() *HeadlessService {
+	if in == nil {
+		return nil
+	}
+	out := new(HeadlessService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBuilder) DeepCopyInto(out *ImageBuilder) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBuilder.
+func (in *ImageBuilder) DeepCopy() *ImageBuilder {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBuilder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ingress) DeepCopyInto(out *Ingress) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(IngressTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowSourceRanges != nil {
+		in, out := &in.AllowSourceRanges, &out.AllowSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(IngressAuth)
+		**out = **in
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
+func (in *Ingress) DeepCopy() *Ingress {
+	if in == nil {
+		return nil
+	}
+	out := new(Ingress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressAuth) DeepCopyInto(out *IngressAuth) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressAuth.
+func (in *IngressAuth) DeepCopy() *IngressAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressTLS) DeepCopyInto(out *IngressTLS) {
+	*out = *in
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressTLS.
+func (in *IngressTLS) DeepCopy() *IngressTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Istio) DeepCopyInto(out *Istio) {
+	*out = *in
+	if in.GatewaySelector != nil {
+		in, out := &in.GatewaySelector, &out.GatewaySelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Istio.
+func (in *Istio) DeepCopy() *Istio {
+	if in == nil {
+		return nil
+	}
+	out := new(Istio)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Jenkins) DeepCopyInto(out *Jenkins) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Jenkins.
+func (in *Jenkins) DeepCopy() *Jenkins {
+	if in == nil {
+		return nil
+	}
+	out := new(Jenkins)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Jenkins) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsBackupAmazonS3) DeepCopyInto(out *JenkinsBackupAmazonS3) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsBackupAmazonS3.
+func (in *JenkinsBackupAmazonS3) DeepCopy() *JenkinsBackupAmazonS3 {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsBackupAmazonS3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsCondition) DeepCopyInto(out *JenkinsCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsCondition.
+func (in *JenkinsCondition) DeepCopy() *JenkinsCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsConfiguration) DeepCopyInto(out *JenkinsConfiguration) {
+	*out = *in
+	if in.Repository != nil {
+		in, out := &in.Repository, &out.Repository
+		*out = new(ConfigurationRepository)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]ConfigMapReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.DisabledBaseScripts != nil {
+		in, out := &in.DisabledBaseScripts, &out.DisabledBaseScripts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BaseScriptsOverride != nil {
+		in, out := &in.BaseScriptsOverride, &out.BaseScriptsOverride
+		*out = new(ConfigMapReference)
+		**out = **in
+	}
+	if in.SharedLibraries != nil {
+		in, out := &in.SharedLibraries, &out.SharedLibraries
+		*out = make([]SharedLibrary, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsConfiguration.
+func (in *JenkinsConfiguration) DeepCopy() *JenkinsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsCredential) DeepCopyInto(out *JenkinsCredential) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsCredential.
+func (in *JenkinsCredential) DeepCopy() *JenkinsCredential {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsCredential)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JenkinsCredential) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsCredentialList) DeepCopyInto(out *JenkinsCredentialList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]JenkinsCredential, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsCredentialList.
+func (in *JenkinsCredentialList) DeepCopy() *JenkinsCredentialList {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsCredentialList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JenkinsCredentialList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsCredentialSpec) DeepCopyInto(out *JenkinsCredentialSpec) {
+	*out = *in
+	if in.Jenkins != nil {
+		in, out := &in.Jenkins, &out.Jenkins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsCredentialSpec.
+func (in *JenkinsCredentialSpec) DeepCopy() *JenkinsCredentialSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsCredentialSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsList) DeepCopyInto(out *JenkinsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Jenkins, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsList.
+func (in *JenkinsList) DeepCopy() *JenkinsList {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *JenkinsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsMaster) DeepCopyInto(out *JenkinsMaster) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]Tool, len(*in))
+		copy(*out, *in)
+	}
+	in.ScriptApprovals.DeepCopyInto(&out.ScriptApprovals)
+	if in.GlobalEnv != nil {
+		in, out := &in.GlobalEnv, &out.GlobalEnv
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Executors != nil {
+		in, out := &in.Executors, &out.Executors
+		*out = new(int32)
+		**out = **in
+	}
+	in.BuildSettings.DeepCopyInto(&out.BuildSettings)
+	in.Remoting.DeepCopyInto(&out.Remoting)
+	in.CSRF.DeepCopyInto(&out.CSRF)
+	if in.ContentSecurityPolicy != nil {
+		in, out := &in.ContentSecurityPolicy, &out.ContentSecurityPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.Theme = in.Theme
+	if in.AdminPasswordRotationPeriod != nil {
+		in, out := &in.AdminPasswordRotationPeriod, &out.AdminPasswordRotationPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(MasterTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPSKeystore != nil {
+		in, out := &in.HTTPSKeystore, &out.HTTPSKeystore
+		*out = new(HTTPSKeystore)
+		**out = **in
+	}
+	if in.ServiceAccountToken != nil {
+		in, out := &in.ServiceAccountToken, &out.ServiceAccountToken
+		*out = new(ServiceAccountToken)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsMaster.
+func (in *JenkinsMaster) DeepCopy() *JenkinsMaster {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsMaster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsService) DeepCopyInto(out *JenkinsService) {
+	*out = *in
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.PortNames = in.PortNames
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(Ingress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Route != nil {
+		in, out := &in.Route, &out.Route
+		*out = new(Route)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(Istio)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPRoute != nil {
+		in, out := &in.HTTPRoute, &out.HTTPRoute
+		*out = new(HTTPRoute)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(ExternalDNS)
+		**out = **in
+	}
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(AgentService)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headless != nil {
+		in, out := &in.Headless, &out.Headless
+		*out = new(HeadlessService)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsService.
+func (in *JenkinsService) DeepCopy() *JenkinsService {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsSpec) DeepCopyInto(out *JenkinsSpec) {
+	*out = *in
+	out.BackupAmazonS3 = in.BackupAmazonS3
+	in.Master.DeepCopyInto(&out.Master)
+	if in.SeedJobs != nil {
+		in, out := &in.SeedJobs, &out.SeedJobs
+		*out = make([]SeedJob, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Configuration.DeepCopyInto(&out.Configuration)
+	if in.Vault != nil {
+		in, out := &in.Vault, &out.Vault
+		*out = new(Vault)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArtifactManager != nil {
+		in, out := &in.ArtifactManager, &out.ArtifactManager
+		*out = new(ArtifactManager)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Security.DeepCopyInto(&out.Security)
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Service.DeepCopyInto(&out.Service)
+	in.Agents.DeepCopyInto(&out.Agents)
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(Monitoring)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsSpec.
+func (in *JenkinsSpec) DeepCopy() *JenkinsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JenkinsStatus) DeepCopyInto(out *JenkinsStatus) {
+	*out = *in
+	if in.ProvisionStartTime != nil {
+		in, out := &in.ProvisionStartTime, &out.ProvisionStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BaseConfigurationCompletedTime != nil {
+		in, out := &in.BaseConfigurationCompletedTime, &out.BaseConfigurationCompletedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SeedJobsCompletedTime != nil {
+		in, out := &in.SeedJobsCompletedTime, &out.SeedJobsCompletedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.UserConfigurationCompletedTime != nil {
+		in, out := &in.UserConfigurationCompletedTime, &out.UserConfigurationCompletedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Builds != nil {
+		in, out := &in.Builds, &out.Builds
+		*out = make([]Build, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]JenkinsCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SpecHistory != nil {
+		in, out := &in.SpecHistory, &out.SpecHistory
+		*out = make([]SpecChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsStatus.
+func (in *JenkinsStatus) DeepCopy() *JenkinsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JenkinsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAP) DeepCopyInto(out *LDAP) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAP.
+func (in *LDAP) DeepCopy() *LDAP {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoginThrottling) DeepCopyInto(out *LoginThrottling) {
+	*out = *in
+	out.Window = in.Window
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginThrottling.
+func (in *LoginThrottling) DeepCopy() *LoginThrottling {
+	if in == nil {
+		return nil
+	}
+	out := new(LoginThrottling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MasterTLS) DeepCopyInto(out *MasterTLS) {
+	*out = *in
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MasterTLS.
+func (in *MasterTLS) DeepCopy() *MasterTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(MasterTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatrixAuthorization) DeepCopyInto(out *MatrixAuthorization) {
+	*out = *in
+	if in.Grants != nil {
+		in, out := &in.Grants, &out.Grants
+		*out = make([]MatrixGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatrixAuthorization.
+func (in *MatrixAuthorization) DeepCopy() *MatrixAuthorization {
+	if in == nil {
+		return nil
+	}
+	out := new(MatrixAuthorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatrixGrant) DeepCopyInto(out *MatrixGrant) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatrixGrant.
+func (in *MatrixGrant) DeepCopy() *MatrixGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(MatrixGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Monitoring) DeepCopyInto(out *Monitoring) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GrafanaDashboard != nil {
+		in, out := &in.GrafanaDashboard, &out.GrafanaDashboard
+		*out = new(GrafanaDashboard)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(PrometheusRule)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Monitoring.
+func (in *Monitoring) DeepCopy() *Monitoring {
+	if in == nil {
+		return nil
+	}
+	out := new(Monitoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicy) DeepCopyInto(out *NetworkPolicy) {
+	*out = *in
+	if in.IngressControllers != nil {
+		in, out := &in.IngressControllers, &out.IngressControllers
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalRules != nil {
+		in, out := &in.AdditionalRules, &out.AdditionalRules
+		*out = make([]networkingv1.NetworkPolicyIngressRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicy.
+func (in *NetworkPolicy) DeepCopy() *NetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDC) DeepCopyInto(out *OIDC) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDC.
+func (in *OIDC) DeepCopy() *OIDC {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateKey) DeepCopyInto(out *PrivateKey) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateKey.
+func (in *PrivateKey) DeepCopy() *PrivateKey {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRule) DeepCopyInto(out *PrometheusRule) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRule.
+func (in *PrometheusRule) DeepCopy() *PrometheusRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Remoting) DeepCopyInto(out *Remoting) {
+	*out = *in
+	if in.AgentProtocols != nil {
+		in, out := &in.AgentProtocols, &out.AgentProtocols
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SSHD != nil {
+		in, out := &in.SSHD, &out.SSHD
+		*out = new(SSHD)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Remoting.
+func (in *Remoting) DeepCopy() *Remoting {
+	if in == nil {
+		return nil
+	}
+	out := new(Remoting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Role) DeepCopyInto(out *Role) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Assignments != nil {
+		in, out := &in.Assignments, &out.Assignments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Role.
+func (in *Role) DeepCopy() *Role {
+	if in == nil {
+		return nil
+	}
+	out := new(Role)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleBasedAuthorization) DeepCopyInto(out *RoleBasedAuthorization) {
+	*out = *in
+	if in.GlobalRoles != nil {
+		in, out := &in.GlobalRoles, &out.GlobalRoles
+		*out = make([]Role, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ItemRoles != nil {
+		in, out := &in.ItemRoles, &out.ItemRoles
+		*out = make([]Role, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleBasedAuthorization.
+func (in *RoleBasedAuthorization) DeepCopy() *RoleBasedAuthorization {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleBasedAuthorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Route) DeepCopyInto(out *Route) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Route.
+func (in *Route) DeepCopy() *Route {
+	if in == nil {
+		return nil
+	}
+	out := new(Route)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAML) DeepCopyInto(out *SAML) {
+	*out = *in
+	in.IdPMetadata.DeepCopyInto(&out.IdPMetadata)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAML.
+func (in *SAML) DeepCopy() *SAML {
+	if in == nil {
+		return nil
 	}
-	if in.LastUpdateTime != nil {
-		in, out := &in.LastUpdateTime, &out.LastUpdateTime
-		*out = (*in).DeepCopy()
+	out := new(SAML)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SAMLIdPMetadata) DeepCopyInto(out *SAMLIdPMetadata) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(ConfigMapReference)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Build.
-func (in *Build) DeepCopy() *Build {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SAMLIdPMetadata.
+func (in *SAMLIdPMetadata) DeepCopy() *SAMLIdPMetadata {
 	if in == nil {
 		return nil
 	}
-	out := new(Build)
+	out := new(SAMLIdPMetadata)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Jenkins) DeepCopyInto(out *Jenkins) {
+func (in *SSHD) DeepCopyInto(out *SSHD) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Jenkins.
-func (in *Jenkins) DeepCopy() *Jenkins {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHD.
+func (in *SSHD) DeepCopy() *SSHD {
 	if in == nil {
 		return nil
 	}
-	out := new(Jenkins)
+	out := new(SSHD)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Jenkins) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScriptApprovals) DeepCopyInto(out *ScriptApprovals) {
+	*out = *in
+	if in.Signatures != nil {
+		in, out := &in.Signatures, &out.Signatures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	return nil
+	if in.ScriptHashes != nil {
+		in, out := &in.ScriptHashes, &out.ScriptHashes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScriptApprovals.
+func (in *ScriptApprovals) DeepCopy() *ScriptApprovals {
+	if in == nil {
+		return nil
+	}
+	out := new(ScriptApprovals)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JenkinsBackupAmazonS3) DeepCopyInto(out *JenkinsBackupAmazonS3) {
+func (in *Security) DeepCopyInto(out *Security) {
+	*out = *in
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAP)
+		**out = **in
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDC)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SAML != nil {
+		in, out := &in.SAML, &out.SAML
+		*out = new(SAML)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GitHubOAuth != nil {
+		in, out := &in.GitHubOAuth, &out.GitHubOAuth
+		*out = new(GitHubOAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(Authorization)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceUsers != nil {
+		in, out := &in.ServiceUsers, &out.ServiceUsers
+		*out = make([]ServiceUser, len(*in))
+		copy(*out, *in)
+	}
+	if in.LoginThrottling != nil {
+		in, out := &in.LoginThrottling, &out.LoginThrottling
+		*out = new(LoginThrottling)
+		**out = **in
+	}
+	if in.ScriptConsoleEnabled != nil {
+		in, out := &in.ScriptConsoleEnabled, &out.ScriptConsoleEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Security.
+func (in *Security) DeepCopy() *Security {
+	if in == nil {
+		return nil
+	}
+	out := new(Security)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SeedJob) DeepCopyInto(out *SeedJob) {
 	*out = *in
+	in.PrivateKey.DeepCopyInto(&out.PrivateKey)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsBackupAmazonS3.
-func (in *JenkinsBackupAmazonS3) DeepCopy() *JenkinsBackupAmazonS3 {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJob.
+func (in *SeedJob) DeepCopy() *SeedJob {
 	if in == nil {
 		return nil
 	}
-	out := new(JenkinsBackupAmazonS3)
+	out := new(SeedJob)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JenkinsList) DeepCopyInto(out *JenkinsList) {
+func (in *ServiceAccountToken) DeepCopyInto(out *ServiceAccountToken) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Jenkins, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsList.
-func (in *JenkinsList) DeepCopy() *JenkinsList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountToken.
+func (in *ServiceAccountToken) DeepCopy() *ServiceAccountToken {
 	if in == nil {
 		return nil
 	}
-	out := new(JenkinsList)
+	out := new(ServiceAccountToken)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *JenkinsList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServicePortNames) DeepCopyInto(out *ServicePortNames) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServicePortNames.
+func (in *ServicePortNames) DeepCopy() *ServicePortNames {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(ServicePortNames)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JenkinsMaster) DeepCopyInto(out *JenkinsMaster) {
+func (in *ServiceUser) DeepCopyInto(out *ServiceUser) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceUser.
+func (in *ServiceUser) DeepCopy() *ServiceUser {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedLibrary) DeepCopyInto(out *SharedLibrary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedLibrary.
+func (in *SharedLibrary) DeepCopy() *SharedLibrary {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedLibrary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecChange) DeepCopyInto(out *SpecChange) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FieldHashes != nil {
+		in, out := &in.FieldHashes, &out.FieldHashes
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Plugins != nil {
-		in, out := &in.Plugins, &out.Plugins
-		*out = make(map[string][]string, len(*in))
-		for key, val := range *in {
-			var outVal []string
-			if val == nil {
-				(*out)[key] = nil
-			} else {
-				in, out := &val, &outVal
-				*out = make([]string, len(*in))
-				copy(*out, *in)
-			}
-			(*out)[key] = outVal
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsMaster.
-func (in *JenkinsMaster) DeepCopy() *JenkinsMaster {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecChange.
+func (in *SpecChange) DeepCopy() *SpecChange {
 	if in == nil {
 		return nil
 	}
-	out := new(JenkinsMaster)
+	out := new(SpecChange)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JenkinsSpec) DeepCopyInto(out *JenkinsSpec) {
+func (in *SpotNodes) DeepCopyInto(out *SpotNodes) {
 	*out = *in
-	out.BackupAmazonS3 = in.BackupAmazonS3
-	in.Master.DeepCopyInto(&out.Master)
-	if in.SeedJobs != nil {
-		in, out := &in.SeedJobs, &out.SeedJobs
-		*out = make([]SeedJob, len(*in))
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	return
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsSpec.
-func (in *JenkinsSpec) DeepCopy() *JenkinsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotNodes.
+func (in *SpotNodes) DeepCopy() *SpotNodes {
 	if in == nil {
 		return nil
 	}
-	out := new(JenkinsSpec)
+	out := new(SpotNodes)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JenkinsStatus) DeepCopyInto(out *JenkinsStatus) {
+func (in *StaticAgent) DeepCopyInto(out *StaticAgent) {
 	*out = *in
-	if in.BaseConfigurationCompletedTime != nil {
-		in, out := &in.BaseConfigurationCompletedTime, &out.BaseConfigurationCompletedTime
-		*out = (*in).DeepCopy()
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.UserConfigurationCompletedTime != nil {
-		in, out := &in.UserConfigurationCompletedTime, &out.UserConfigurationCompletedTime
-		*out = (*in).DeepCopy()
+	return
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaticAgent.
+func (in *StaticAgent) DeepCopy() *StaticAgent {
+	if in == nil {
+		return nil
 	}
-	if in.Builds != nil {
-		in, out := &in.Builds, &out.Builds
-		*out = make([]Build, len(*in))
+	out := new(StaticAgent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Theme) DeepCopyInto(out *Theme) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Theme.
+func (in *Theme) DeepCopy() *Theme {
+	if in == nil {
+		return nil
+	}
+	out := new(Theme)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tool) DeepCopyInto(out *Tool) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tool.
+func (in *Tool) DeepCopy() *Tool {
+	if in == nil {
+		return nil
+	}
+	out := new(Tool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMAgentTemplate) DeepCopyInto(out *VMAgentTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMAgentTemplate.
+func (in *VMAgentTemplate) DeepCopy() *VMAgentTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(VMAgentTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VMCloud) DeepCopyInto(out *VMCloud) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]VMAgentTemplate, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -211,50 +1890,59 @@ func (in *JenkinsStatus) DeepCopyInto(out *JenkinsStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JenkinsStatus.
-func (in *JenkinsStatus) DeepCopy() *JenkinsStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VMCloud.
+func (in *VMCloud) DeepCopy() *VMCloud {
 	if in == nil {
 		return nil
 	}
-	out := new(JenkinsStatus)
+	out := new(VMCloud)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PrivateKey) DeepCopyInto(out *PrivateKey) {
+func (in *Vault) DeepCopyInto(out *Vault) {
 	*out = *in
-	if in.SecretKeyRef != nil {
-		in, out := &in.SecretKeyRef, &out.SecretKeyRef
-		*out = new(v1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateKey.
-func (in *PrivateKey) DeepCopy() *PrivateKey {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Vault.
+func (in *Vault) DeepCopy() *Vault {
 	if in == nil {
 		return nil
 	}
-	out := new(PrivateKey)
+	out := new(Vault)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SeedJob) DeepCopyInto(out *SeedJob) {
+func (in *WorkspaceVolume) DeepCopyInto(out *WorkspaceVolume) {
 	*out = *in
-	in.PrivateKey.DeepCopyInto(&out.PrivateKey)
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SeedJob.
-func (in *SeedJob) DeepCopy() *SeedJob {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceVolume.
+func (in *WorkspaceVolume) DeepCopy() *WorkspaceVolume {
 	if in == nil {
 		return nil
 	}
-	out := new(SeedJob)
+	out := new(WorkspaceVolume)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2,11 +2,17 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"net/http/cookiejar"
 	"os/exec"
 	"strings"
 
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/metrics"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/tracing"
+
 	"github.com/bndr/gojenkins"
 	"github.com/pkg/errors"
 )
@@ -51,10 +57,16 @@ type Jenkins interface {
 	GetAllViews() ([]*gojenkins.View, error)
 	CreateView(name string, viewType string) (*gojenkins.View, error)
 	Poll() (int, error)
+	QuietDown() error
+	GetBusyExecutors() (int, error)
+	ExecuteScript(script string) (string, error)
+	GetVersion() string
 }
 
 type jenkins struct {
 	gojenkins.Jenkins
+	// transport sends the requests, WithContext instruments it with another tracing context
+	transport http.RoundTripper
 }
 
 // CreateOrUpdateJob creates or updates a job from config
@@ -73,6 +85,11 @@ func (jenkins *jenkins) CreateOrUpdateJob(config, jobName string) (job *gojenkin
 	return
 }
 
+// GetVersion returns Jenkins version reported by the X-Jenkins header when the client was initialized
+func (jenkins *jenkins) GetVersion() string {
+	return jenkins.Version
+}
+
 func isNotFoundError(err error) bool {
 	if err != nil {
 		return err.Error() == errorNotFound.Error()
@@ -80,8 +97,13 @@ func isNotFoundError(err error) bool {
 	return false
 }
 
-// BuildJenkinsAPIUrl returns Jenkins API URL
-func BuildJenkinsAPIUrl(namespace, serviceName string, portNumber int, local, minikube bool) (string, error) {
+// BuildJenkinsAPIUrl returns Jenkins API URL, https is used when Jenkins master serves TLS on the given port
+func BuildJenkinsAPIUrl(namespace, serviceName string, portNumber int, https, local, minikube bool) (string, error) {
+	scheme := "http"
+	if https {
+		scheme = "https"
+	}
+
 	// Get Jenkins URL from minikube command
 	if local && minikube {
 		cmd := exec.Command("minikube", "service", "--url", "-n", namespace, serviceName)
@@ -91,34 +113,48 @@ func BuildJenkinsAPIUrl(namespace, serviceName string, portNumber int, local, mi
 		if err != nil {
 			return "", err
 		}
-		lines := strings.Split(out.String(), "\n")
-		// First is for http, the second one is for Jenkins slaves communication
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		// First is for http, then for Jenkins slaves communication unless it isn't exposed and the last one for https
 		// see pkg/controller/jenkins/configuration/base/resources/service.go
+		if https {
+			if len(lines) < 2 {
+				return "", errors.Errorf("couldn't find Jenkins https port in minikube service '%s' URLs", serviceName)
+			}
+			// minikube always returns URLs with http scheme
+			return strings.Replace(lines[len(lines)-1], "http://", "https://", 1), nil
+		}
 		url := lines[0]
 		return url, nil
 	}
 
 	if local {
 		// When run locally make port-forward to jenkins pod ('kubectl -n default port-forward jenkins-operator-example 8080')
-		return fmt.Sprintf("http://localhost:%d", portNumber), nil
+		return fmt.Sprintf("%s://localhost:%d", scheme, portNumber), nil
 	}
 
 	// Connect through Kubernetes service, operator has to be run inside cluster
-	return fmt.Sprintf("http://%s:%d", serviceName, portNumber), nil
+	return fmt.Sprintf("%s://%s:%d", scheme, serviceName, portNumber), nil
 }
 
-// New creates Jenkins API client
-func New(url, user, passwordOrToken string) (Jenkins, error) {
+// New creates Jenkins API client, tlsConfig is used to verify Jenkins certificate when connecting over https,
+// the default configuration is used when it's nil, spans of the client requests are children of the span of ctx
+func New(ctx context.Context, url, user, passwordOrToken string, tlsConfig *tls.Config) (Jenkins, error) {
 	if strings.HasSuffix(url, "/") {
 		url = url[:len(url)-1]
 	}
 
-	jenkinsClient := &jenkins{}
+	// crumbs issued by Jenkins are bound to the web session, the cookie jar keeps the session between requests
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't create Jenkins API client cookie jar")
+	}
+
+	jenkinsClient := &jenkins{transport: newLoggingTransport(getTransport(tlsConfig))}
 	jenkinsClient.Server = url
 	jenkinsClient.Requester = &gojenkins.Requester{
 		Base:      url,
 		SslVerify: true,
-		Client:    http.DefaultClient,
+		Client:    &http.Client{Jar: jar, Transport: instrumentTransport(ctx, jenkinsClient.transport)},
 		BasicAuth: &gojenkins.BasicAuth{Username: user, Password: passwordOrToken},
 	}
 	if _, err := jenkinsClient.Init(); err != nil {
@@ -135,3 +171,22 @@ func New(url, user, passwordOrToken string) (Jenkins, error) {
 
 	return jenkinsClient, nil
 }
+
+// WithContext returns Jenkins API client whose spans of requests are children of the span of ctx, it shares the session
+// with jenkinsClient, clients not created by New, e.g. mocks, are returned as they are
+func WithContext(ctx context.Context, jenkinsClient Jenkins) Jenkins {
+	current, ok := jenkinsClient.(*jenkins)
+	if !ok {
+		return jenkinsClient
+	}
+
+	requester := *current.Requester
+	requester.Client = &http.Client{Jar: current.Requester.Client.Jar, Transport: instrumentTransport(ctx, current.transport)}
+	withContext := &jenkins{Jenkins: current.Jenkins, transport: current.transport}
+	withContext.Requester = &requester
+	return withContext
+}
+
+func instrumentTransport(ctx context.Context, transport http.RoundTripper) http.RoundTripper {
+	return metrics.InstrumentJenkinsAPI(tracing.InstrumentJenkinsAPI(ctx, transport))
+}
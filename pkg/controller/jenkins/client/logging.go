@@ -0,0 +1,35 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/VirtusLab/jenkins-operator/pkg/log"
+)
+
+// loggingTransport logs Jenkins API requests at the debug level of the client component
+type loggingTransport struct {
+	transport http.RoundTripper
+}
+
+func newLoggingTransport(transport http.RoundTripper) http.RoundTripper {
+	return &loggingTransport{transport: transport}
+}
+
+// RoundTrip sends the request and logs its method, path, status code and duration, the query isn't logged because
+// it can contain credentials
+func (t *loggingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	logger := log.ForComponent(log.Log, log.ComponentClient).V(log.VDebug)
+	start := time.Now()
+	response, err := t.transport.RoundTrip(request)
+	duration := time.Since(start)
+	if err != nil {
+		logger.Info(fmt.Sprintf("Jenkins API request '%s %s' failed: %s", request.Method, request.URL.Path, err),
+			"duration", duration.String())
+		return response, err
+	}
+	logger.Info(fmt.Sprintf("Jenkins API request '%s %s' returned %d", request.Method, request.URL.Path, response.StatusCode),
+		"duration", duration.String())
+	return response, nil
+}
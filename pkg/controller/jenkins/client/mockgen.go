@@ -603,3 +603,61 @@ func (mr *MockJenkinsMockRecorder) Poll() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poll", reflect.TypeOf((*MockJenkins)(nil).Poll))
 }
+
+// QuietDown mocks base method
+func (m *MockJenkins) QuietDown() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuietDown")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QuietDown indicates an expected call of QuietDown
+func (mr *MockJenkinsMockRecorder) QuietDown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuietDown", reflect.TypeOf((*MockJenkins)(nil).QuietDown))
+}
+
+// GetBusyExecutors mocks base method
+func (m *MockJenkins) GetBusyExecutors() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBusyExecutors")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBusyExecutors indicates an expected call of GetBusyExecutors
+func (mr *MockJenkinsMockRecorder) GetBusyExecutors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBusyExecutors", reflect.TypeOf((*MockJenkins)(nil).GetBusyExecutors))
+}
+
+// ExecuteScript mocks base method
+func (m *MockJenkins) ExecuteScript(script string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteScript", script)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteScript indicates an expected call of ExecuteScript
+func (mr *MockJenkinsMockRecorder) ExecuteScript(script interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteScript", reflect.TypeOf((*MockJenkins)(nil).ExecuteScript), script)
+}
+
+// GetVersion mocks base method
+func (m *MockJenkins) GetVersion() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetVersion indicates an expected call of GetVersion
+func (mr *MockJenkinsMockRecorder) GetVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockJenkins)(nil).GetVersion))
+}
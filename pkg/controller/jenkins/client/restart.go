@@ -0,0 +1,41 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type computersResponse struct {
+	BusyExecutors int `json:"busyExecutors"`
+}
+
+// QuietDown puts Jenkins into the quiet mode, Jenkins doesn't start any new builds in this mode
+func (jenkins *jenkins) QuietDown() error {
+	r, err := jenkins.Requester.Post("/quietDown", strings.NewReader(""), struct{}{}, map[string]string{})
+	if err != nil {
+		return errors.Wrap(err, "couldn't put Jenkins into quiet mode")
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return errors.Errorf("couldn't put Jenkins into quiet mode: %d", r.StatusCode)
+	}
+
+	return nil
+}
+
+// GetBusyExecutors returns amount of executors which are running builds on all Jenkins nodes
+func (jenkins *jenkins) GetBusyExecutors() (int, error) {
+	computers := &computersResponse{}
+	r, err := jenkins.Requester.GetJSON("/computer", computers, map[string]string{"tree": "busyExecutors"})
+	if err != nil {
+		return 0, errors.Wrap(err, "couldn't get busy executors")
+	}
+
+	if r.StatusCode != http.StatusOK {
+		return 0, errors.Errorf("couldn't get busy executors: %d", r.StatusCode)
+	}
+
+	return computers.BusyExecutors, nil
+}
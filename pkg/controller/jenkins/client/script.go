@@ -0,0 +1,77 @@
+package client
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ExecuteScript executes groovy script in Jenkins script console and returns its output,
+// the script is considered as failed when it doesn't finish, for example due to an exception
+func (jenkins *jenkins) ExecuteScript(script string) (string, error) {
+	verifier := fmt.Sprintf("script-finished-%d", time.Now().UnixNano())
+	data := url.Values{}
+	data.Set("script", fmt.Sprintf("%s\nprintln '%s'\n", script, verifier))
+
+	request, err := http.NewRequest(http.MethodPost, jenkins.Server+"/scriptText", strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", errors.Wrap(err, "couldn't execute groovy script")
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if jenkins.Requester.BasicAuth != nil {
+		request.SetBasicAuth(jenkins.Requester.BasicAuth.Username, jenkins.Requester.BasicAuth.Password)
+	}
+	if err := jenkins.setCrumb(request); err != nil {
+		return "", err
+	}
+
+	response, err := jenkins.Requester.Client.Do(request)
+	if err != nil {
+		return "", errors.Wrap(err, "couldn't execute groovy script")
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", errors.Wrap(err, "couldn't read groovy script output")
+	}
+	output := string(body)
+
+	if response.StatusCode != http.StatusOK {
+		return output, errors.Errorf("couldn't execute groovy script: %d", response.StatusCode)
+	}
+	if !strings.Contains(output, verifier) {
+		return output, errors.Errorf("groovy script execution failed, output: %s", output)
+	}
+
+	return strings.Replace(output, verifier+"\n", "", 1), nil
+}
+
+type crumbResponse struct {
+	CrumbRequestField string `json:"crumbRequestField"`
+	Crumb             string `json:"crumb"`
+}
+
+// setCrumb adds CSRF protection crumb to the request, whatever crumb issuer is configured in Jenkins,
+// the request is left unchanged when CSRF protection is disabled
+func (jenkins *jenkins) setCrumb(request *http.Request) error {
+	crumb := &crumbResponse{}
+	response, err := jenkins.Requester.GetJSON("/crumbIssuer/api/json", crumb, nil)
+	if response != nil && response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "couldn't get CSRF protection crumb")
+	}
+	if response.StatusCode != http.StatusOK {
+		return errors.Errorf("couldn't get CSRF protection crumb: %d", response.StatusCode)
+	}
+
+	request.Header.Set(crumb.CrumbRequestField, crumb.Crumb)
+	return nil
+}
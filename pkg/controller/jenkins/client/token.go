@@ -29,6 +29,11 @@ func (token *UserToken) GetToken() string {
 	return token.raw.Data.Value
 }
 
+// GetUUID returns user token UUID
+func (token *UserToken) GetUUID() string {
+	return token.raw.Data.UUID
+}
+
 func (jenkins *jenkins) GenerateToken(userName, tokenName string) (*UserToken, error) {
 	token := &UserToken{raw: new(userTokenResponse),
 		base: fmt.Sprintf("/user/%s/descriptorByName/jenkins.security.ApiTokenProperty/generateNewToken", userName)}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// transports keeps one transport per Jenkins master, so connections are reused across clients created by New
+// in the following reconciliation loops, the key is the server name of TLS configuration which is unique per Jenkins CR
+var transports = struct {
+	sync.Mutex
+	byServerName map[string]*cachedTransport
+}{byServerName: map[string]*cachedTransport{}}
+
+type cachedTransport struct {
+	tlsConfig *tls.Config
+	transport *http.Transport
+}
+
+// getTransport returns transport verifying Jenkins certificate with tlsConfig, the transport is created again when
+// the TLS configuration changes, e.g. after the CA rotation, http.DefaultTransport is returned when tlsConfig is nil
+func getTransport(tlsConfig *tls.Config) http.RoundTripper {
+	if tlsConfig == nil {
+		return http.DefaultTransport
+	}
+
+	transports.Lock()
+	defer transports.Unlock()
+	cached, found := transports.byServerName[tlsConfig.ServerName]
+	if found && isSameTLSConfig(cached.tlsConfig, tlsConfig) {
+		return cached.transport
+	}
+	if found {
+		cached.transport.CloseIdleConnections()
+	}
+
+	transport := newTransport()
+	transport.TLSClientConfig = tlsConfig
+	transports.byServerName[tlsConfig.ServerName] = &cachedTransport{tlsConfig: tlsConfig, transport: transport}
+	return transport
+}
+
+// isSameTLSConfig compares settings of TLS configuration built by the operator, TLS configuration is built in every
+// reconciliation loop, so they're compared by value
+func isSameTLSConfig(a, b *tls.Config) bool {
+	return a.ServerName == b.ServerName &&
+		a.InsecureSkipVerify == b.InsecureSkipVerify &&
+		a.MinVersion == b.MinVersion &&
+		reflect.DeepEqual(a.CipherSuites, b.CipherSuites) &&
+		reflect.DeepEqual(a.RootCAs, b.RootCAs)
+}
+
+// newTransport returns transport with the same settings as http.DefaultTransport, so the proxy and timeouts
+// carry over
+func newTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
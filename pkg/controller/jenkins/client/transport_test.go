@@ -0,0 +1,38 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTransport(t *testing.T) {
+	t.Run("TLS isn't enabled", func(t *testing.T) {
+		assert.Equal(t, http.DefaultTransport, getTransport(nil))
+	})
+	t.Run("transport is reused", func(t *testing.T) {
+		first := getTransport(&tls.Config{ServerName: "jenkins-operator-http-example.default.svc", RootCAs: x509.NewCertPool()})
+		second := getTransport(&tls.Config{ServerName: "jenkins-operator-http-example.default.svc", RootCAs: x509.NewCertPool()})
+
+		assert.True(t, first == second)
+		transport := first.(*http.Transport)
+		assert.NotNil(t, transport.Proxy)
+		assert.Equal(t, "jenkins-operator-http-example.default.svc", transport.TLSClientConfig.ServerName)
+	})
+	t.Run("TLS configuration changed", func(t *testing.T) {
+		first := getTransport(&tls.Config{ServerName: "jenkins-operator-http-changed.default.svc"})
+		second := getTransport(&tls.Config{ServerName: "jenkins-operator-http-changed.default.svc", InsecureSkipVerify: true})
+
+		assert.False(t, first == second)
+		assert.True(t, second.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+	})
+	t.Run("transport per Jenkins master", func(t *testing.T) {
+		first := getTransport(&tls.Config{ServerName: "jenkins-operator-http-first.default.svc"})
+		second := getTransport(&tls.Config{ServerName: "jenkins-operator-http-second.default.svc"})
+
+		assert.False(t, first == second)
+	})
+}
@@ -0,0 +1,74 @@
+package conditions
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReasonValidationFailed is the reason of the Ready condition when Jenkins CR is rejected by the validation
+const ReasonValidationFailed = "ValidationFailed"
+
+// Get returns the condition with the given type or nil if it's not present
+func Get(status virtuslabv1alpha1.JenkinsStatus, conditionType virtuslabv1alpha1.JenkinsConditionType) *virtuslabv1alpha1.JenkinsCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsTrue returns true if the condition with the given type is present and its status is true
+func IsTrue(status virtuslabv1alpha1.JenkinsStatus, conditionType virtuslabv1alpha1.JenkinsConditionType) bool {
+	condition := Get(status, conditionType)
+	return condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// Set adds or updates the condition, last transition time is changed only when the condition status changes,
+// the phase of the status is updated accordingly
+func Set(status *virtuslabv1alpha1.JenkinsStatus, conditionType virtuslabv1alpha1.JenkinsConditionType,
+	conditionStatus corev1.ConditionStatus, reason, message string) {
+	defer func() { status.Phase = GetPhase(*status) }()
+
+	condition := Get(*status, conditionType)
+	if condition == nil {
+		status.Conditions = append(status.Conditions, virtuslabv1alpha1.JenkinsCondition{
+			Type:               conditionType,
+			Status:             conditionStatus,
+			LastTransitionTime: metav1.Now(),
+			Reason:             reason,
+			Message:            message,
+		})
+		return
+	}
+
+	if condition.Status != conditionStatus {
+		condition.Status = conditionStatus
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Reason = reason
+	condition.Message = message
+}
+
+// GetPhase summarizes the conditions, the conditions which block the configuration take precedence
+func GetPhase(status virtuslabv1alpha1.JenkinsStatus) virtuslabv1alpha1.JenkinsPhase {
+	ready := Get(status, virtuslabv1alpha1.JenkinsConditionReady)
+	switch {
+	case IsTrue(status, virtuslabv1alpha1.JenkinsConditionPaused):
+		return virtuslabv1alpha1.JenkinsPhasePaused
+	case ready != nil && ready.Status == corev1.ConditionFalse && ready.Reason == ReasonValidationFailed:
+		return virtuslabv1alpha1.JenkinsPhaseInvalid
+	case IsTrue(status, virtuslabv1alpha1.JenkinsConditionRestarting):
+		return virtuslabv1alpha1.JenkinsPhaseRestarting
+	case IsTrue(status, virtuslabv1alpha1.JenkinsConditionMaintenance):
+		return virtuslabv1alpha1.JenkinsPhaseMaintenance
+	case !IsTrue(status, virtuslabv1alpha1.JenkinsConditionProvisioned):
+		return virtuslabv1alpha1.JenkinsPhaseProvisioning
+	case ready == nil || ready.Status != corev1.ConditionTrue:
+		return virtuslabv1alpha1.JenkinsPhaseConfiguring
+	default:
+		return virtuslabv1alpha1.JenkinsPhaseReady
+	}
+}
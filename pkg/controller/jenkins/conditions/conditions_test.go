@@ -0,0 +1,119 @@
+package conditions
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSet(t *testing.T) {
+	t.Run("add condition", func(t *testing.T) {
+		status := virtuslabv1alpha1.JenkinsStatus{}
+
+		Set(&status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, "reason", "message")
+
+		assert.True(t, IsTrue(status, virtuslabv1alpha1.JenkinsConditionRestarting))
+		assert.Len(t, status.Conditions, 1)
+		assert.Equal(t, "reason", status.Conditions[0].Reason)
+		assert.Equal(t, "message", status.Conditions[0].Message)
+	})
+	t.Run("update message without status change", func(t *testing.T) {
+		status := virtuslabv1alpha1.JenkinsStatus{}
+		Set(&status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, "reason", "message")
+		transitionTime := status.Conditions[0].LastTransitionTime
+
+		Set(&status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, "reason", "other message")
+
+		assert.Len(t, status.Conditions, 1)
+		assert.Equal(t, "other message", status.Conditions[0].Message)
+		assert.Equal(t, transitionTime, status.Conditions[0].LastTransitionTime)
+	})
+	t.Run("change status", func(t *testing.T) {
+		status := virtuslabv1alpha1.JenkinsStatus{}
+		Set(&status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, "reason", "message")
+
+		Set(&status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionFalse, "Completed", "done")
+
+		assert.False(t, IsTrue(status, virtuslabv1alpha1.JenkinsConditionRestarting))
+		assert.Len(t, status.Conditions, 1)
+	})
+}
+
+func TestGetPhase(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []virtuslabv1alpha1.JenkinsCondition
+		want       virtuslabv1alpha1.JenkinsPhase
+	}{
+		{
+			name: "provisioning",
+			want: virtuslabv1alpha1.JenkinsPhaseProvisioning,
+		},
+		{
+			name: "configuring",
+			conditions: []virtuslabv1alpha1.JenkinsCondition{
+				{Type: virtuslabv1alpha1.JenkinsConditionProvisioned, Status: corev1.ConditionTrue},
+				{Type: virtuslabv1alpha1.JenkinsConditionReady, Status: corev1.ConditionFalse},
+			},
+			want: virtuslabv1alpha1.JenkinsPhaseConfiguring,
+		},
+		{
+			name: "ready",
+			conditions: []virtuslabv1alpha1.JenkinsCondition{
+				{Type: virtuslabv1alpha1.JenkinsConditionProvisioned, Status: corev1.ConditionTrue},
+				{Type: virtuslabv1alpha1.JenkinsConditionReady, Status: corev1.ConditionTrue},
+			},
+			want: virtuslabv1alpha1.JenkinsPhaseReady,
+		},
+		{
+			name: "restarting ready Jenkins",
+			conditions: []virtuslabv1alpha1.JenkinsCondition{
+				{Type: virtuslabv1alpha1.JenkinsConditionProvisioned, Status: corev1.ConditionTrue},
+				{Type: virtuslabv1alpha1.JenkinsConditionReady, Status: corev1.ConditionTrue},
+				{Type: virtuslabv1alpha1.JenkinsConditionRestarting, Status: corev1.ConditionTrue},
+			},
+			want: virtuslabv1alpha1.JenkinsPhaseRestarting,
+		},
+		{
+			name: "maintenance",
+			conditions: []virtuslabv1alpha1.JenkinsCondition{
+				{Type: virtuslabv1alpha1.JenkinsConditionProvisioned, Status: corev1.ConditionTrue},
+				{Type: virtuslabv1alpha1.JenkinsConditionMaintenance, Status: corev1.ConditionTrue},
+			},
+			want: virtuslabv1alpha1.JenkinsPhaseMaintenance,
+		},
+		{
+			name: "invalid",
+			conditions: []virtuslabv1alpha1.JenkinsCondition{
+				{Type: virtuslabv1alpha1.JenkinsConditionReady, Status: corev1.ConditionFalse, Reason: ReasonValidationFailed},
+				{Type: virtuslabv1alpha1.JenkinsConditionRestarting, Status: corev1.ConditionTrue},
+			},
+			want: virtuslabv1alpha1.JenkinsPhaseInvalid,
+		},
+		{
+			name: "paused",
+			conditions: []virtuslabv1alpha1.JenkinsCondition{
+				{Type: virtuslabv1alpha1.JenkinsConditionReady, Status: corev1.ConditionFalse, Reason: ReasonValidationFailed},
+				{Type: virtuslabv1alpha1.JenkinsConditionPaused, Status: corev1.ConditionTrue},
+			},
+			want: virtuslabv1alpha1.JenkinsPhasePaused,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := virtuslabv1alpha1.JenkinsStatus{Conditions: tt.conditions}
+
+			assert.Equal(t, tt.want, GetPhase(status))
+		})
+	}
+	t.Run("set updates phase", func(t *testing.T) {
+		status := virtuslabv1alpha1.JenkinsStatus{}
+
+		Set(&status, virtuslabv1alpha1.JenkinsConditionPaused, corev1.ConditionTrue, "Paused", "")
+
+		assert.Equal(t, virtuslabv1alpha1.JenkinsPhasePaused, status.Phase)
+	})
+}
@@ -0,0 +1,2 @@
+// Package conditions is responsible for managing Jenkins custom resource status conditions
+package conditions
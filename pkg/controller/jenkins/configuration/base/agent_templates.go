@@ -0,0 +1,52 @@
+package base
+
+import (
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// reasonAgentTemplatesInvalid is the reason of the condition and event when agent pod templates are rejected
+	reasonAgentTemplatesInvalid = "AgentTemplatesInvalid"
+	// reasonAgentTemplatesValid is the reason of the condition when agent pod templates are valid again
+	reasonAgentTemplatesValid = "AgentTemplatesValid"
+)
+
+// ensureAgentTemplatesCondition reports errors of agent pod templates found by validateAgentTemplates by
+// the AgentTemplatesInvalid status condition and a warning event, the templates aren't pushed into Jenkins until
+// they are fixed, so a typo in one template doesn't break the Kubernetes cloud
+func (r *ReconcileJenkinsBaseConfiguration) ensureAgentTemplatesCondition() error {
+	condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionAgentTemplatesInvalid)
+	if len(r.agentPodTemplateErrors) == 0 {
+		if condition == nil || condition.Status == corev1.ConditionFalse {
+			return nil
+		}
+		conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionAgentTemplatesInvalid, corev1.ConditionFalse,
+			reasonAgentTemplatesValid, "Agent pod templates are valid")
+		return r.updateStatus()
+	}
+
+	// errors are listed in the order of the templates
+	var errors []string
+	reported := map[string]bool{}
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		if reported[template.Name] {
+			continue
+		}
+		reported[template.Name] = true
+		errors = append(errors, r.agentPodTemplateErrors[template.Name]...)
+	}
+	message := "Invalid agent pod templates: " + strings.Join(errors, "; ")
+	if condition != nil && condition.Status == corev1.ConditionTrue && condition.Message == message {
+		return nil
+	}
+
+	conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionAgentTemplatesInvalid, corev1.ConditionTrue,
+		reasonAgentTemplatesInvalid, message)
+	r.recorder.Event(r.jenkins, corev1.EventTypeWarning, reasonAgentTemplatesInvalid, message)
+	return r.updateStatus()
+}
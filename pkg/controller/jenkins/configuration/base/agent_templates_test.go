@@ -0,0 +1,105 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestReconcileJenkinsBaseConfiguration_ensureAgentTemplatesCondition(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	newReconciler := func(t *testing.T, templates ...virtuslabv1alpha1.AgentPodTemplate) (*ReconcileJenkinsBaseConfiguration, *record.FakeRecorder) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+			Spec:       virtuslabv1alpha1.JenkinsSpec{Agents: virtuslabv1alpha1.Agents{Templates: templates}},
+		}
+		fakeClient := fake.NewFakeClient()
+		if err := fakeClient.Create(context.TODO(), jenkins); err != nil {
+			t.Fatal(err)
+		}
+		recorder := record.NewFakeRecorder(10)
+		return &ReconcileJenkinsBaseConfiguration{
+			k8sClient: fakeClient,
+			scheme:    scheme.Scheme,
+			recorder:  recorder,
+			logger:    logf.ZapLogger(false),
+			jenkins:   jenkins,
+		}, recorder
+	}
+	getCondition := func(t *testing.T, r *ReconcileJenkinsBaseConfiguration) *virtuslabv1alpha1.JenkinsCondition {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "namespace-name", Name: "jenkins-cr-name"}, jenkins)
+		assert.NoError(t, err)
+		return conditions.Get(jenkins.Status, virtuslabv1alpha1.JenkinsConditionAgentTemplatesInvalid)
+	}
+
+	t.Run("invalid templates", func(t *testing.T) {
+		r, recorder := newReconciler(t,
+			virtuslabv1alpha1.AgentPodTemplate{Name: "maven", Label: "maven", Image: "jenkins/inbound-agent:4.3-4"},
+			virtuslabv1alpha1.AgentPodTemplate{Name: "gradle", Label: "master", Image: "jenkins/inbound-agent:"},
+			virtuslabv1alpha1.AgentPodTemplate{Name: "node", Label: "maven"})
+
+		valid, err := r.validateAgentTemplates()
+		assert.NoError(t, err)
+		assert.False(t, valid)
+		err = r.ensureAgentTemplatesCondition()
+		assert.NoError(t, err)
+
+		condition := getCondition(t, r)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, corev1.ConditionTrue, condition.Status)
+			assert.Equal(t, reasonAgentTemplatesInvalid, condition.Reason)
+			assert.Equal(t, "Invalid agent pod templates: Invalid image 'jenkins/inbound-agent:' of agent pod template 'gradle'; "+
+				"Label 'master' of agent pod template 'gradle' is reserved for Jenkins master; "+
+				"Agent pod template 'node' has the same label 'maven' as agent pod template 'maven'", condition.Message)
+		}
+		assert.Len(t, recorder.Events, 1)
+
+		// the event isn't repeated while the errors are the same
+		_, _ = r.validateAgentTemplates()
+		err = r.ensureAgentTemplatesCondition()
+		assert.NoError(t, err)
+		assert.Len(t, recorder.Events, 1)
+	})
+	t.Run("fixed templates", func(t *testing.T) {
+		r, recorder := newReconciler(t, virtuslabv1alpha1.AgentPodTemplate{Name: "maven", Label: "maven"})
+		conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionAgentTemplatesInvalid, corev1.ConditionTrue,
+			reasonAgentTemplatesInvalid, "Invalid agent pod templates: Invalid image")
+
+		valid, err := r.validateAgentTemplates()
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		err = r.ensureAgentTemplatesCondition()
+		assert.NoError(t, err)
+
+		condition := getCondition(t, r)
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, corev1.ConditionFalse, condition.Status)
+			assert.Equal(t, reasonAgentTemplatesValid, condition.Reason)
+		}
+		assert.Empty(t, recorder.Events)
+	})
+	t.Run("valid templates without condition", func(t *testing.T) {
+		r, _ := newReconciler(t, virtuslabv1alpha1.AgentPodTemplate{Name: "maven", Label: "maven"})
+
+		valid, err := r.validateAgentTemplates()
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		err = r.ensureAgentTemplatesCondition()
+		assert.NoError(t, err)
+
+		assert.Nil(t, getCondition(t, r))
+	})
+}
@@ -0,0 +1,63 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// agentsClusterTimeout limits the connectivity check, so an unreachable cluster doesn't block the reconciliation
+const agentsClusterTimeout = 10 * time.Second
+
+// agentsClusterPermissions are the permissions Kubernetes plugin needs to manage agent pods in the cluster
+var agentsClusterPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "create", Resource: "pods"},
+	{Verb: "delete", Resource: "pods"},
+	{Verb: "get", Resource: "pods"},
+	{Verb: "list", Resource: "pods"},
+	{Verb: "watch", Resource: "pods"},
+	{Verb: "create", Resource: "pods", Subresource: "exec"},
+	{Verb: "get", Resource: "pods", Subresource: "log"},
+}
+
+// newAgentsClusterClient returns client of the agents cluster, tests replace it with the fake client
+var newAgentsClusterClient = func(config *rest.Config) (client.Client, error) {
+	return client.New(config, client.Options{})
+}
+
+// verifyAgentsClusterAccess connects to the agents cluster with the kubeconfig and returns the permissions in
+// the agents namespace which aren't granted, returns error when the cluster isn't reachable
+func verifyAgentsClusterAccess(cluster virtuslabv1alpha1.AgentsCluster, kubeconfig []byte) ([]authorizationv1.ResourceAttributes, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig: %s", err)
+	}
+	config.Timeout = agentsClusterTimeout
+	k8sClient, err := newAgentsClusterClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var denied []authorizationv1.ResourceAttributes
+	for _, permission := range agentsClusterPermissions {
+		permission.Namespace = cluster.Namespace
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &permission},
+		}
+		if err := k8sClient.Create(context.TODO(), review); err != nil {
+			return nil, err
+		}
+		if !review.Status.Allowed {
+			denied = append(denied, permission)
+		}
+	}
+
+	return denied, nil
+}
@@ -0,0 +1,158 @@
+package base
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+	"github.com/VirtusLab/jenkins-operator/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ensureAgentsRBAC creates the agents service accounts and lets Jenkins master manage agent pods in the separate and
+// additional agents namespaces, the resources outside of Jenkins namespace have no owner so they aren't garbage
+// collected with Jenkins CR
+func (r *ReconcileJenkinsBaseConfiguration) ensureAgentsRBAC() error {
+	for _, namespace := range resources.GetAllAgentsNamespaces(r.jenkins) {
+		if err := r.ensureAgentsNamespaceRBAC(namespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) ensureAgentsNamespaceRBAC(namespace string) error {
+	meta := resources.NewAgentsObjectMeta(r.jenkins)
+	meta.Namespace = namespace
+	if err := r.createAgentsResource(resources.NewAgentsServiceAccount(meta)); err != nil {
+		return err
+	}
+
+	if namespace != r.jenkins.ObjectMeta.Namespace {
+		if err := r.createOrUpdateAgentsResource(resources.NewAgentsRole(meta)); err != nil {
+			return err
+		}
+		if err := r.createOrUpdateAgentsResource(resources.NewAgentsRoleBinding(meta, r.jenkins)); err != nil {
+			return err
+		}
+	}
+
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		if len(template.Permissions) == 0 {
+			continue
+		}
+		templateMeta := resources.NewAgentPodTemplateObjectMeta(r.jenkins, template)
+		templateMeta.Namespace = namespace
+		if err := r.createAgentsResource(resources.NewAgentPodTemplateServiceAccount(templateMeta)); err != nil {
+			return err
+		}
+		if err := r.createOrUpdateAgentsResource(resources.NewAgentPodTemplateRole(templateMeta, template)); err != nil {
+			return err
+		}
+		if err := r.createOrUpdateAgentsResource(resources.NewAgentPodTemplateRoleBinding(templateMeta)); err != nil {
+			return err
+		}
+	}
+
+	return r.deleteStaleAgentPodTemplateRBAC(namespace)
+}
+
+// deleteStaleAgentPodTemplateRBAC deletes dedicated service accounts and their RBAC resources of agent pod templates
+// which were removed or don't have permissions anymore from the agents namespace, the namespace is outside of the
+// operator cache so the resources are listed directly
+func (r *ReconcileJenkinsBaseConfiguration) deleteStaleAgentPodTemplateRBAC(namespace string) error {
+	expected := map[string]bool{}
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		if len(template.Permissions) > 0 {
+			expected[resources.GetAgentPodTemplateServiceAccountName(r.jenkins, template)] = true
+		}
+	}
+
+	lists := []runtime.Object{&rbacv1.RoleBindingList{}, &rbacv1.RoleList{}, &corev1.ServiceAccountList{}}
+	for _, list := range lists {
+		listOptions := client.InNamespace(namespace).MatchingLabels(resources.BuildResourceLabels(r.jenkins))
+		if err := r.apiClient.List(context.TODO(), listOptions, list); err != nil {
+			return err
+		}
+		objects, err := apimeta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+		for _, object := range objects {
+			accessor, err := apimeta.Accessor(object)
+			if err != nil {
+				return err
+			}
+			if !r.isStaleAgentPodTemplateResource(accessor, expected) {
+				continue
+			}
+			r.logger.V(log.VDebug).Info(fmt.Sprintf("Deleting %T '%s' of removed agent pod template", object, accessor.GetName()))
+			if err := r.apiClient.Delete(context.TODO(), object); err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isStaleAgentPodTemplateResource returns true if the object was created for the agent pod template of this Jenkins CR
+// and isn't expected anymore, the name tells apart Jenkins CRs with the same name from other namespaces
+func (r *ReconcileJenkinsBaseConfiguration) isStaleAgentPodTemplateResource(object metav1.Object, expected map[string]bool) bool {
+	templateName, ok := object.GetLabels()[constants.LabelAgentPodTemplateKey]
+	if !ok {
+		return false
+	}
+	if object.GetName() != fmt.Sprintf("%s-%s", resources.GetAgentsResourceName(r.jenkins), templateName) {
+		return false
+	}
+	return !expected[object.GetName()]
+}
+
+// createAgentsResource creates the resource unless it exists, Jenkins CR is the owner only in Jenkins namespace
+func (r *ReconcileJenkinsBaseConfiguration) createAgentsResource(obj metav1.Object) error {
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return fmt.Errorf("is not a %T a runtime.Object", obj)
+	}
+
+	var err error
+	if obj.GetNamespace() != r.jenkins.ObjectMeta.Namespace {
+		err = r.k8sClient.Create(context.TODO(), runtimeObj)
+	} else {
+		err = r.createResource(obj)
+	}
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+// createOrUpdateAgentsResource works like createOrUpdateResource but doesn't set Jenkins CR as the owner outside of
+// Jenkins namespace, owner references across namespaces are treated as missing owners by the garbage collector
+func (r *ReconcileJenkinsBaseConfiguration) createOrUpdateAgentsResource(obj metav1.Object) error {
+	if obj.GetNamespace() == r.jenkins.ObjectMeta.Namespace {
+		return r.createOrUpdateResource(obj)
+	}
+
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return fmt.Errorf("is not a %T a runtime.Object", obj)
+	}
+	err := r.k8sClient.Create(context.TODO(), runtimeObj)
+	if err != nil && apierrors.IsAlreadyExists(err) {
+		return r.k8sClient.Update(context.TODO(), runtimeObj)
+	}
+
+	return err
+}
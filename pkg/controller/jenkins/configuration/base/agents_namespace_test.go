@@ -0,0 +1,140 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestReconcileJenkinsBaseConfiguration_ensureAgentsRBAC(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	kubectlPermissions := []rbacv1.PolicyRule{
+		{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"get", "list", "patch"}},
+	}
+	newJenkins := func(agentsNamespace string, templates ...virtuslabv1alpha1.AgentPodTemplate) *virtuslabv1alpha1.Jenkins {
+		return &virtuslabv1alpha1.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Agents: virtuslabv1alpha1.Agents{Namespace: agentsNamespace, Templates: templates},
+			},
+		}
+	}
+	newReconciler := func(jenkins *virtuslabv1alpha1.Jenkins) *ReconcileJenkinsBaseConfiguration {
+		fakeClient := fake.NewFakeClient()
+		return &ReconcileJenkinsBaseConfiguration{
+			k8sClient: fakeClient,
+			apiClient: fakeClient,
+			scheme:    scheme.Scheme,
+			logger:    logf.ZapLogger(false),
+			jenkins:   jenkins,
+		}
+	}
+
+	t.Run("Jenkins namespace", func(t *testing.T) {
+		jenkins := newJenkins("", virtuslabv1alpha1.AgentPodTemplate{Name: "maven"},
+			virtuslabv1alpha1.AgentPodTemplate{Name: "kubectl", Permissions: kubectlPermissions})
+		r := newReconciler(jenkins)
+
+		err := r.ensureAgentsRBAC()
+
+		assert.NoError(t, err)
+		serviceAccount := &corev1.ServiceAccount{}
+		err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "namespace-name", Name: "jenkins-operator-agents-namespace-name-jenkins-cr-name"}, serviceAccount)
+		assert.NoError(t, err)
+		assert.False(t, *serviceAccount.AutomountServiceAccountToken)
+		assert.Len(t, serviceAccount.OwnerReferences, 1)
+		templateName := types.NamespacedName{Namespace: "namespace-name", Name: "jenkins-operator-agents-namespace-name-jenkins-cr-name-kubectl"}
+		err = r.k8sClient.Get(context.TODO(), templateName, serviceAccount)
+		assert.NoError(t, err)
+		assert.True(t, *serviceAccount.AutomountServiceAccountToken)
+		role := &rbacv1.Role{}
+		err = r.k8sClient.Get(context.TODO(), templateName, role)
+		assert.NoError(t, err)
+		assert.Equal(t, kubectlPermissions, role.Rules)
+		roleBinding := &rbacv1.RoleBinding{}
+		err = r.k8sClient.Get(context.TODO(), templateName, roleBinding)
+		assert.NoError(t, err)
+		assert.Equal(t, templateName.Name, roleBinding.Subjects[0].Name)
+		err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "namespace-name", Name: "jenkins-operator-agents-namespace-name-jenkins-cr-name-maven"}, serviceAccount)
+		assert.True(t, errors.IsNotFound(err))
+	})
+	t.Run("separate namespace", func(t *testing.T) {
+		jenkins := newJenkins("builds", virtuslabv1alpha1.AgentPodTemplate{Name: "kubectl", Permissions: kubectlPermissions})
+		r := newReconciler(jenkins)
+
+		err := r.ensureAgentsRBAC()
+
+		assert.NoError(t, err)
+		name := types.NamespacedName{Namespace: "builds", Name: "jenkins-operator-agents-namespace-name-jenkins-cr-name"}
+		roleBinding := &rbacv1.RoleBinding{}
+		err = r.k8sClient.Get(context.TODO(), name, roleBinding)
+		assert.NoError(t, err)
+		assert.Empty(t, roleBinding.OwnerReferences)
+		assert.Equal(t, rbacv1.Subject{Kind: "ServiceAccount", Name: "jenkins-operator-jenkins-cr-name", Namespace: "namespace-name"},
+			roleBinding.Subjects[0])
+		role := &rbacv1.Role{}
+		err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "builds", Name: name.Name + "-kubectl"}, role)
+		assert.NoError(t, err)
+		assert.Empty(t, role.OwnerReferences)
+	})
+	t.Run("additional namespaces", func(t *testing.T) {
+		jenkins := newJenkins("", virtuslabv1alpha1.AgentPodTemplate{Name: "kubectl", Permissions: kubectlPermissions})
+		jenkins.Spec.Agents.AdditionalNamespaces = []virtuslabv1alpha1.AgentsNamespace{{Name: "team-a"}}
+		r := newReconciler(jenkins)
+
+		err := r.ensureAgentsRBAC()
+
+		assert.NoError(t, err)
+		name := types.NamespacedName{Namespace: "team-a", Name: "jenkins-operator-agents-namespace-name-jenkins-cr-name"}
+		serviceAccount := &corev1.ServiceAccount{}
+		err = r.k8sClient.Get(context.TODO(), name, serviceAccount)
+		assert.NoError(t, err)
+		assert.Empty(t, serviceAccount.OwnerReferences)
+		err = r.k8sClient.Get(context.TODO(), name, &rbacv1.RoleBinding{})
+		assert.NoError(t, err)
+		err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "team-a", Name: name.Name + "-kubectl"}, &rbacv1.Role{})
+		assert.NoError(t, err)
+		// Jenkins master needs no role binding in its own namespace
+		err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "namespace-name", Name: name.Name}, &rbacv1.RoleBinding{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+	t.Run("removed permissions", func(t *testing.T) {
+		jenkins := newJenkins("builds", virtuslabv1alpha1.AgentPodTemplate{Name: "kubectl", Permissions: kubectlPermissions})
+		r := newReconciler(jenkins)
+		err := r.ensureAgentsRBAC()
+		assert.NoError(t, err)
+		// the same Jenkins CR name from other namespace shares the agents namespace
+		otherJenkins := newJenkins("builds", virtuslabv1alpha1.AgentPodTemplate{Name: "kubectl", Permissions: kubectlPermissions})
+		otherJenkins.Namespace = "other-namespace"
+		otherMeta := resources.NewAgentPodTemplateObjectMeta(otherJenkins, otherJenkins.Spec.Agents.Templates[0])
+		err = r.k8sClient.Create(context.TODO(), resources.NewAgentPodTemplateServiceAccount(otherMeta))
+		assert.NoError(t, err)
+
+		r.jenkins.Spec.Agents.Templates[0].Permissions = nil
+		err = r.ensureAgentsRBAC()
+
+		assert.NoError(t, err)
+		name := types.NamespacedName{Namespace: "builds", Name: "jenkins-operator-agents-namespace-name-jenkins-cr-name-kubectl"}
+		err = r.k8sClient.Get(context.TODO(), name, &corev1.ServiceAccount{})
+		assert.True(t, errors.IsNotFound(err))
+		err = r.k8sClient.Get(context.TODO(), name, &rbacv1.Role{})
+		assert.True(t, errors.IsNotFound(err))
+		err = r.k8sClient.Get(context.TODO(), name, &rbacv1.RoleBinding{})
+		assert.True(t, errors.IsNotFound(err))
+		err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "builds", Name: otherMeta.Name}, &corev1.ServiceAccount{})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,157 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gatewayapiv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/gatewayapi/v1"
+	routev1 "github.com/VirtusLab/jenkins-operator/pkg/apis/route/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	exposureReasonExternalPathFound = "ExternalPathFound"
+	exposureReasonNoExternalPath    = "NoExternalPath"
+	exposureReasonExposureExternal  = "ExposureExternal"
+)
+
+// ensureExposureCondition reports resources exposing Jenkins Services outside of the cluster by the ExternallyExposed
+// status condition when Jenkins.Spec.Exposure is internal, the resources aren't removed because they aren't managed by
+// the operator
+func (r *ReconcileJenkinsBaseConfiguration) ensureExposureCondition() error {
+	condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionExternallyExposed)
+	if !resources.IsInternalExposure(r.jenkins) {
+		if condition == nil || condition.Reason == exposureReasonExposureExternal {
+			return nil
+		}
+		conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionExternallyExposed, corev1.ConditionFalse,
+			exposureReasonExposureExternal, "Jenkins can be exposed outside of the cluster")
+		return r.updateStatus()
+	}
+
+	externalPaths, err := r.findExternalPaths()
+	if err != nil {
+		return err
+	}
+
+	status, reason, message := corev1.ConditionFalse, exposureReasonNoExternalPath, "Jenkins isn't exposed outside of the cluster"
+	if len(externalPaths) > 0 {
+		status, reason = corev1.ConditionTrue, exposureReasonExternalPathFound
+		message = fmt.Sprintf("Jenkins is exposed outside of the cluster by %s", strings.Join(externalPaths, ", "))
+	}
+	if condition != nil && condition.Status == status && condition.Message == message {
+		return nil
+	}
+	if status == corev1.ConditionTrue {
+		r.logger.Info(fmt.Sprintf("Jenkins exposure is internal but %s", message))
+	}
+	conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionExternallyExposed, status, reason, message)
+	return r.updateStatus()
+}
+
+// findExternalPaths returns sorted resources exposing Jenkins master outside of the cluster, i.e. NodePort and
+// LoadBalancer Services selecting Jenkins master pod and Ingresses, OpenShift Routes and Gateway API HTTPRoutes routing
+// to the Jenkins Services
+func (r *ReconcileJenkinsBaseConfiguration) findExternalPaths() ([]string, error) {
+	var externalPaths []string
+	listOptions := client.InNamespace(r.jenkins.ObjectMeta.Namespace)
+	serviceNames := map[string]bool{}
+	for _, name := range resources.GetServiceNames(r.jenkins) {
+		serviceNames[name] = true
+	}
+
+	services := &corev1.ServiceList{}
+	if err := r.k8sClient.List(context.TODO(), listOptions, services); err != nil {
+		return nil, err
+	}
+	podLabels := labels.Set(resources.BuildResourceLabels(r.jenkins))
+	for _, service := range services.Items {
+		if r.minikube && service.Name == resources.GetResourceName(r.jenkins) {
+			// the operator running with minikube reaches Jenkins API through the node port
+			continue
+		}
+		if len(service.Spec.Selector) == 0 || !labels.SelectorFromSet(service.Spec.Selector).Matches(podLabels) {
+			continue
+		}
+		if service.Spec.Type == corev1.ServiceTypeNodePort || service.Spec.Type == corev1.ServiceTypeLoadBalancer ||
+			len(service.Spec.ExternalIPs) > 0 {
+			externalPaths = append(externalPaths, fmt.Sprintf("Service '%s'", service.Name))
+		}
+	}
+
+	ingresses := &extensionsv1beta1.IngressList{}
+	if err := r.k8sClient.List(context.TODO(), listOptions, ingresses); err != nil {
+		return nil, err
+	}
+	for _, ingress := range ingresses.Items {
+		backends := []string{}
+		if ingress.Spec.Backend != nil {
+			backends = append(backends, ingress.Spec.Backend.ServiceName)
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				backends = append(backends, path.Backend.ServiceName)
+			}
+		}
+		for _, backend := range backends {
+			if serviceNames[backend] {
+				externalPaths = append(externalPaths, fmt.Sprintf("Ingress '%s'", ingress.Name))
+				break
+			}
+		}
+	}
+
+	if r.openshift {
+		routes := &routev1.RouteList{}
+		if err := r.k8sClient.List(context.TODO(), listOptions, routes); err != nil {
+			return nil, err
+		}
+		for _, route := range routes.Items {
+			if route.Spec.To.Kind == routev1.ServiceKind && serviceNames[route.Spec.To.Name] {
+				externalPaths = append(externalPaths, fmt.Sprintf("OpenShift Route '%s'", route.Name))
+			}
+		}
+	}
+
+	// missing Gateway API CRDs and the operator deployed with an older role which isn't allowed to list HTTPRoutes
+	// are tolerated
+	httpRoutes := &gatewayapiv1.HTTPRouteList{}
+	err := r.k8sClient.List(context.TODO(), listOptions, httpRoutes)
+	if err != nil && !apierrors.IsForbidden(err) && !apimeta.IsNoMatchError(err) {
+		return nil, err
+	}
+	for _, httpRoute := range httpRoutes.Items {
+		if isHTTPRouteBackend(httpRoute, serviceNames) {
+			externalPaths = append(externalPaths, fmt.Sprintf("Gateway API HTTPRoute '%s'", httpRoute.Name))
+		}
+	}
+
+	sort.Strings(externalPaths)
+	return externalPaths, nil
+}
+
+// isHTTPRouteBackend tells if any rule of the HTTPRoute routes to one of the Services
+func isHTTPRouteBackend(httpRoute gatewayapiv1.HTTPRoute, serviceNames map[string]bool) bool {
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			if (len(backendRef.Kind) == 0 || backendRef.Kind == gatewayapiv1.ServiceKind) && serviceNames[backendRef.Name] {
+				return true
+			}
+		}
+	}
+	return false
+}
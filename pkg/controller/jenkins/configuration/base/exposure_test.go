@@ -0,0 +1,114 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	gatewayapiv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/gatewayapi/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestReconcileJenkinsBaseConfiguration_ensureExposureCondition(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	err = gatewayapiv1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	newReconciler := func(t *testing.T, jenkins *virtuslabv1alpha1.Jenkins, objects ...runtime.Object) *ReconcileJenkinsBaseConfiguration {
+		fakeClient := fake.NewFakeClient(objects...)
+		if err := fakeClient.Create(context.TODO(), jenkins); err != nil {
+			t.Fatal(err)
+		}
+		return &ReconcileJenkinsBaseConfiguration{
+			k8sClient: fakeClient,
+			scheme:    scheme.Scheme,
+			logger:    logf.ZapLogger(false),
+			jenkins:   jenkins,
+		}
+	}
+	newJenkins := func(exposure virtuslabv1alpha1.Exposure) *virtuslabv1alpha1.Jenkins {
+		return &virtuslabv1alpha1.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+			Spec:       virtuslabv1alpha1.JenkinsSpec{Exposure: exposure},
+		}
+	}
+	newIngress := func(name, serviceName string) *extensionsv1beta1.Ingress {
+		return &extensionsv1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: name},
+			Spec: extensionsv1beta1.IngressSpec{
+				Backend: &extensionsv1beta1.IngressBackend{ServiceName: serviceName, ServicePort: intstr.FromInt(8080)},
+			},
+		}
+	}
+
+	t.Run("internal without external paths", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.ExposureInternal)
+		r := newReconciler(t, jenkins, newIngress("other", "other-service"))
+
+		err := r.ensureExposureCondition()
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionExternallyExposed)
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.Equal(t, exposureReasonNoExternalPath, condition.Reason)
+	})
+	t.Run("internal with external paths", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.ExposureInternal)
+		loadBalancer := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "public"},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeLoadBalancer,
+				Selector: resources.BuildResourceLabels(jenkins),
+			},
+		}
+		httpRoute := &gatewayapiv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "public"},
+			Spec: gatewayapiv1.HTTPRouteSpec{
+				Rules: []gatewayapiv1.HTTPRouteRule{
+					{BackendRefs: []gatewayapiv1.HTTPBackendRef{{Name: resources.GetAgentServiceName(jenkins)}}},
+				},
+			},
+		}
+		r := newReconciler(t, jenkins, loadBalancer, httpRoute, newIngress("public", resources.GetResourceName(jenkins)))
+
+		err := r.ensureExposureCondition()
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionExternallyExposed)
+		assert.Equal(t, corev1.ConditionTrue, condition.Status)
+		assert.Equal(t, "Jenkins is exposed outside of the cluster by Gateway API HTTPRoute 'public', Ingress 'public', Service 'public'",
+			condition.Message)
+	})
+	t.Run("exposure changed to external", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.ExposureExternal)
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionExternallyExposed, corev1.ConditionTrue,
+			exposureReasonExternalPathFound, "")
+		r := newReconciler(t, jenkins)
+
+		err := r.ensureExposureCondition()
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionExternallyExposed)
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.Equal(t, exposureReasonExposureExternal, condition.Reason)
+	})
+	t.Run("external", func(t *testing.T) {
+		r := newReconciler(t, newJenkins(""), newIngress("public", "jenkins-operator-jenkins-cr-name"))
+
+		err := r.ensureExposureCondition()
+
+		assert.NoError(t, err)
+		assert.Nil(t, conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionExternallyExposed))
+	})
+}
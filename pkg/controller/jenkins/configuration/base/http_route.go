@@ -0,0 +1,49 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	gatewayapiv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/gatewayapi/v1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ensureHTTPRoute creates or updates Gateway API HTTPRoute of Jenkins UI, the HTTPRoute is deleted when
+// Jenkins.Spec.Service.HTTPRoute isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensureHTTPRoute(meta metav1.ObjectMeta) error {
+	if !resources.IsHTTPRouteEnabled(r.jenkins) {
+		// missing Gateway API CRDs and the operator deployed with an older role which isn't allowed to manage
+		// HTTPRoutes are tolerated
+		err := r.k8sClient.Delete(context.TODO(), &gatewayapiv1.HTTPRoute{ObjectMeta: meta})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) && !apimeta.IsNoMatchError(err) {
+			return err
+		}
+		return nil
+	}
+
+	httpRoute := resources.NewHTTPRoute(meta, r.jenkins)
+	currentHTTPRoute := &gatewayapiv1.HTTPRoute{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: httpRoute.Name, Namespace: httpRoute.Namespace}, currentHTTPRoute)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating Gateway API HTTPRoute '%s'", httpRoute.Name))
+		return r.createResource(httpRoute)
+	} else if err != nil {
+		return err
+	}
+
+	annotationsUpToDate := (len(currentHTTPRoute.Annotations) == 0 && len(httpRoute.Annotations) == 0) ||
+		reflect.DeepEqual(currentHTTPRoute.Annotations, httpRoute.Annotations)
+	if annotationsUpToDate && reflect.DeepEqual(currentHTTPRoute.Spec, httpRoute.Spec) {
+		return nil
+	}
+	// custom resources can't be updated without resource version, so the current object is updated
+	currentHTTPRoute.Spec = httpRoute.Spec
+	currentHTTPRoute.Annotations = httpRoute.Annotations
+	return r.updateResource(currentHTTPRoute)
+}
@@ -0,0 +1,60 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	certmanagerv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/certmanager/v1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ensureIngress creates or updates Ingress of Jenkins UI, the Ingress is deleted when Jenkins.Spec.Service.Ingress
+// isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensureIngress(meta metav1.ObjectMeta) error {
+	if !resources.IsIngressEnabled(r.jenkins) {
+		ingress := &extensionsv1beta1.Ingress{ObjectMeta: meta}
+		// the operator deployed with an older role isn't allowed to delete Ingresses, the Ingress is left behind then
+		err := r.k8sClient.Delete(context.TODO(), ingress)
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := r.ensureIngressCertificate(meta); err != nil {
+		return err
+	}
+	return r.createOrUpdateResource(resources.NewIngress(meta, r.jenkins))
+}
+
+// ensureIngressCertificate creates or updates cert-manager Certificate of the Ingress host when
+// Jenkins.Spec.Service.Ingress.TLS.IssuerRef is set
+func (r *ReconcileJenkinsBaseConfiguration) ensureIngressCertificate(meta metav1.ObjectMeta) error {
+	ingressTLS := r.jenkins.Spec.Service.Ingress.TLS
+	if ingressTLS == nil || ingressTLS.IssuerRef == nil {
+		return nil
+	}
+
+	certificate := resources.NewIngressCertificate(meta, r.jenkins)
+	currentCertificate := &certmanagerv1.Certificate{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: certificate.Name, Namespace: certificate.Namespace}, currentCertificate)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating cert-manager Certificate '%s'", certificate.Name))
+		return r.createResource(certificate)
+	} else if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(currentCertificate.Spec, certificate.Spec) {
+		return nil
+	}
+	// custom resources can't be updated without resource version, so the current object is updated
+	currentCertificate.Spec = certificate.Spec
+	return r.updateResource(currentCertificate)
+}
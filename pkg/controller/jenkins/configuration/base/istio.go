@@ -0,0 +1,75 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	istiov1beta1 "github.com/VirtusLab/jenkins-operator/pkg/apis/istio/v1beta1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ensureIstio creates or updates Istio Gateway and VirtualService of Jenkins UI, they are deleted when
+// Jenkins.Spec.Service.Istio isn't set or the Gateway is managed outside of the operator
+func (r *ReconcileJenkinsBaseConfiguration) ensureIstio(meta metav1.ObjectMeta) error {
+	if !resources.IsIstioGatewayManaged(r.jenkins) {
+		if err := r.deleteIstioResource(&istiov1beta1.Gateway{ObjectMeta: meta}); err != nil {
+			return err
+		}
+	}
+	if !resources.IsIstioEnabled(r.jenkins) {
+		return r.deleteIstioResource(&istiov1beta1.VirtualService{ObjectMeta: meta})
+	}
+
+	if resources.IsIstioGatewayManaged(r.jenkins) {
+		gateway := resources.NewIstioGateway(meta, r.jenkins)
+		currentGateway := &istiov1beta1.Gateway{}
+		err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: gateway.Name, Namespace: gateway.Namespace}, currentGateway)
+		if err != nil && apierrors.IsNotFound(err) {
+			r.logger.Info(fmt.Sprintf("Creating Istio Gateway '%s'", gateway.Name))
+			if err := r.createResource(gateway); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		} else if !reflect.DeepEqual(currentGateway.Spec, gateway.Spec) {
+			// custom resources can't be updated without resource version, so the current object is updated
+			currentGateway.Spec = gateway.Spec
+			if err := r.updateResource(currentGateway); err != nil {
+				return err
+			}
+		}
+	}
+
+	virtualService := resources.NewIstioVirtualService(meta, r.jenkins)
+	currentVirtualService := &istiov1beta1.VirtualService{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: virtualService.Name, Namespace: virtualService.Namespace}, currentVirtualService)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating Istio VirtualService '%s'", virtualService.Name))
+		return r.createResource(virtualService)
+	} else if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(currentVirtualService.Spec, virtualService.Spec) {
+		return nil
+	}
+	currentVirtualService.Spec = virtualService.Spec
+	return r.updateResource(currentVirtualService)
+}
+
+// deleteIstioResource deletes Istio resource, missing Istio CRDs and the operator deployed with an older role which
+// isn't allowed to manage Istio resources are tolerated
+func (r *ReconcileJenkinsBaseConfiguration) deleteIstioResource(obj runtime.Object) error {
+	err := r.k8sClient.Delete(context.TODO(), obj)
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) && !apimeta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
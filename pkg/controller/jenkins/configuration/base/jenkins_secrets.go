@@ -0,0 +1,117 @@
+package base
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// readJenkinsSecretsScript prints secret.key and files from Jenkins home secrets directory as name=base64 lines,
+// hudson.util.Secret key is generated lazily, so it's initialized before any credentials are encrypted
+const readJenkinsSecretsScript = `
+import hudson.util.Secret
+import jenkins.model.Jenkins
+
+Secret.fromString('').getEncryptedValue()
+
+def home = Jenkins.instance.rootDir
+def files = [new File(home, '` + resources.JenkinsSecretKeyFileName + `')]
+def secretsDirectory = new File(home, 'secrets')
+if (secretsDirectory.isDirectory()) {
+    files.addAll(secretsDirectory.listFiles())
+}
+files.findAll { it.isFile() }.each { file ->
+    println(file.name + '=' + file.bytes.encodeBase64().toString())
+}
+`
+
+// parseJenkinsSecrets returns file names and contents printed by readJenkinsSecretsScript, files whose names can't
+// be used as Secret keys are skipped
+func parseJenkinsSecrets(output string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		separator := strings.Index(line, "=")
+		if separator <= 0 {
+			continue
+		}
+		name := line[:separator]
+		if len(validation.IsConfigMapKey(name)) > 0 {
+			continue
+		}
+		content, err := base64.StdEncoding.DecodeString(line[separator+1:])
+		if err != nil {
+			return nil, fmt.Errorf("couldn't decode Jenkins secret file '%s': %s", name, err)
+		}
+		files[name] = content
+	}
+	if _, found := files[resources.JenkinsSecretKeyFileName]; !found {
+		return nil, fmt.Errorf("couldn't find '%s' in groovy script output", resources.JenkinsSecretKeyFileName)
+	}
+	return files, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) createJenkinsSecretsSecret(meta metav1.ObjectMeta) error {
+	currentSecret := &corev1.Secret{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: resources.GetJenkinsSecretsSecretName(r.jenkins), Namespace: r.jenkins.ObjectMeta.Namespace}, currentSecret)
+	if err != nil && apierrors.IsNotFound(err) {
+		return r.createResource(resources.NewJenkinsSecretsSecret(meta, r.jenkins))
+	}
+	// the keys are never overwritten here, they would be lost
+	return err
+}
+
+// ensureJenkinsSecretsPersisted copies Jenkins encryption keys to the Secret which is restored into Jenkins home
+// by init script, Jenkins home is an emptyDir, so without the keys credentials couldn't be decrypted after pod recreation
+func (r *ReconcileJenkinsBaseConfiguration) ensureJenkinsSecretsPersisted(jenkinsClient jenkinsclient.Jenkins) error {
+	output, err := jenkinsClient.ExecuteScript(readJenkinsSecretsScript)
+	if err != nil {
+		return err
+	}
+	files, err := parseJenkinsSecrets(output)
+	if err != nil {
+		return err
+	}
+
+	secret := &corev1.Secret{}
+	err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: resources.GetJenkinsSecretsSecretName(r.jenkins), Namespace: r.jenkins.ObjectMeta.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+
+	var changedFiles []string
+	for name, content := range files {
+		if !bytes.Equal(secret.Data[name], content) {
+			changedFiles = append(changedFiles, name)
+		}
+	}
+	if len(changedFiles) == 0 {
+		return nil
+	}
+	sort.Strings(changedFiles)
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for _, name := range changedFiles {
+		secret.Data[name] = files[name]
+	}
+	if err := r.updateResource(secret); err != nil {
+		return err
+	}
+	r.logger.Info(fmt.Sprintf("Jenkins encryption keys '%s' have been persisted in Secret '%s'",
+		strings.Join(changedFiles, "', '"), secret.Name))
+	return nil
+}
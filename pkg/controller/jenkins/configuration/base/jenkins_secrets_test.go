@@ -0,0 +1,123 @@
+package base
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func encodeJenkinsSecretFile(name, content string) string {
+	return name + "=" + base64.StdEncoding.EncodeToString([]byte(content)) + "\n"
+}
+
+func TestParseJenkinsSecrets(t *testing.T) {
+	t.Run("happy", func(t *testing.T) {
+		output := encodeJenkinsSecretFile("secret.key", "instance-key") +
+			encodeJenkinsSecretFile("master.key", "master-key") +
+			encodeJenkinsSecretFile("hudson.util.Secret", "secret")
+
+		files, err := parseJenkinsSecrets(output)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{
+			"secret.key":         []byte("instance-key"),
+			"master.key":         []byte("master-key"),
+			"hudson.util.Secret": []byte("secret"),
+		}, files)
+	})
+	t.Run("invalid secret key is skipped", func(t *testing.T) {
+		output := encodeJenkinsSecretFile("secret.key", "instance-key") + encodeJenkinsSecretFile("file name", "content")
+
+		files, err := parseJenkinsSecrets(output)
+
+		assert.NoError(t, err)
+		assert.Len(t, files, 1)
+	})
+	t.Run("no secret.key", func(t *testing.T) {
+		_, err := parseJenkinsSecrets(encodeJenkinsSecretFile("master.key", "master-key"))
+
+		assert.Error(t, err)
+	})
+	t.Run("invalid base64", func(t *testing.T) {
+		_, err := parseJenkinsSecrets("secret.key=not base64\n")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestReconcileJenkinsBaseConfiguration_ensureJenkinsSecretsPersisted(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	jenkins := &virtuslabv1alpha1.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+	}
+	newReconciler := func(t *testing.T, secretData map[string][]byte) *ReconcileJenkinsBaseConfiguration {
+		fakeClient := fake.NewFakeClient()
+		secret := resources.NewJenkinsSecretsSecret(resources.NewResourceObjectMeta(jenkins), jenkins)
+		secret.Data = secretData
+		if err := fakeClient.Create(context.TODO(), secret); err != nil {
+			t.Fatal(err)
+		}
+		return &ReconcileJenkinsBaseConfiguration{
+			k8sClient: fakeClient,
+			scheme:    scheme.Scheme,
+			logger:    logf.ZapLogger(false),
+			jenkins:   jenkins,
+		}
+	}
+	getSecretData := func(t *testing.T, r *ReconcileJenkinsBaseConfiguration) map[string][]byte {
+		secret := &corev1.Secret{}
+		err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: resources.GetJenkinsSecretsSecretName(jenkins), Namespace: jenkins.Namespace}, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return secret.Data
+	}
+	output := encodeJenkinsSecretFile("secret.key", "instance-key") + encodeJenkinsSecretFile("master.key", "master-key")
+
+	t.Run("new keys are persisted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(readJenkinsSecretsScript).Return(output, nil)
+		r := newReconciler(t, map[string][]byte{"secret.key": []byte("instance-key")})
+
+		err := r.ensureJenkinsSecretsPersisted(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{
+			"secret.key": []byte("instance-key"),
+			"master.key": []byte("master-key"),
+		}, getSecretData(t, r))
+	})
+	t.Run("keys removed from Jenkins are kept", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(readJenkinsSecretsScript).Return(output, nil)
+		secretData := map[string][]byte{
+			"secret.key":         []byte("instance-key"),
+			"master.key":         []byte("master-key"),
+			"hudson.util.Secret": []byte("secret"),
+		}
+		r := newReconciler(t, secretData)
+
+		err := r.ensureJenkinsSecretsPersisted(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.Equal(t, secretData, getSecretData(t, r))
+	})
+}
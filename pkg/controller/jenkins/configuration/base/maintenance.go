@@ -0,0 +1,84 @@
+package base
+
+import (
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	maintenanceReasonEnabled  = "MaintenanceModeEnabled"
+	maintenanceReasonDisabled = "MaintenanceModeDisabled"
+)
+
+// quietDownScript prints true when it puts Jenkins into quiet mode and false when Jenkins is already in quiet mode
+const quietDownScript = `
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.getInstance()
+if (jenkins.isQuietingDown()) {
+    println false
+} else {
+    jenkins.doQuietDown()
+    println true
+}
+`
+
+const cancelQuietDownScript = `
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.getInstance()
+if (jenkins.isQuietingDown()) {
+    jenkins.doCancelQuietDown()
+}
+`
+
+// ensureMaintenanceMode keeps Jenkins in quiet mode while Jenkins.Spec.MaintenanceMode is enabled and cancels
+// the quiet mode once it's disabled, the state is exposed by the Maintenance status condition, quiet mode which
+// the operator hasn't set for the maintenance, e.g. of the safe restart, isn't canceled
+func (r *ReconcileJenkinsBaseConfiguration) ensureMaintenanceMode(jenkinsClient jenkinsclient.Jenkins) error {
+	inMaintenance := conditions.IsTrue(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance)
+	if r.jenkins.Spec.MaintenanceMode {
+		// Jenkins master pod can be restarted during maintenance, so quiet mode is ensured on every reconciliation
+		output, err := jenkinsClient.ExecuteScript(quietDownScript)
+		if err != nil {
+			return err
+		}
+		quietedDown := strings.TrimSpace(output) == "true"
+		if inMaintenance && (!quietedDown || r.jenkins.Status.MaintenanceQuietDown) {
+			return nil
+		}
+
+		if quietedDown {
+			r.jenkins.Status.MaintenanceQuietDown = true
+		}
+		if !inMaintenance {
+			r.logger.Info("Jenkins is in maintenance mode")
+			conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance, corev1.ConditionTrue, maintenanceReasonEnabled,
+				"Jenkins is in quiet mode, new builds aren't started and configuration isn't applied")
+		}
+		return r.updateStatus()
+	}
+
+	if !inMaintenance {
+		return nil
+	}
+	message := "Jenkins quiet mode has been canceled"
+	if r.jenkins.Status.MaintenanceQuietDown && !conditions.IsTrue(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting) {
+		if _, err := jenkinsClient.ExecuteScript(cancelQuietDownScript); err != nil {
+			return err
+		}
+	} else {
+		message = "Jenkins quiet mode hasn't been set by the maintenance mode, it isn't canceled"
+	}
+
+	r.logger.Info("Jenkins maintenance mode has been disabled")
+	r.jenkins.Status.MaintenanceQuietDown = false
+	conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance, corev1.ConditionFalse, maintenanceReasonDisabled,
+		message)
+	return r.updateStatus()
+}
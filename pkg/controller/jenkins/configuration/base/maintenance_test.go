@@ -0,0 +1,127 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestReconcileJenkinsBaseConfiguration_ensureMaintenanceMode(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	newReconciler := func(t *testing.T, jenkins *virtuslabv1alpha1.Jenkins) *ReconcileJenkinsBaseConfiguration {
+		fakeClient := fake.NewFakeClient()
+		if err := fakeClient.Create(context.TODO(), jenkins); err != nil {
+			t.Fatal(err)
+		}
+		return &ReconcileJenkinsBaseConfiguration{
+			k8sClient: fakeClient,
+			scheme:    scheme.Scheme,
+			logger:    logf.ZapLogger(false),
+			jenkins:   jenkins,
+		}
+	}
+	newJenkins := func(maintenanceMode bool) *virtuslabv1alpha1.Jenkins {
+		return &virtuslabv1alpha1.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+			Spec:       virtuslabv1alpha1.JenkinsSpec{MaintenanceMode: maintenanceMode},
+		}
+	}
+
+	t.Run("enable maintenance mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(quietDownScript).Return("true\n", nil)
+		r := newReconciler(t, newJenkins(true))
+
+		err := r.ensureMaintenanceMode(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.True(t, conditions.IsTrue(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance))
+		assert.True(t, r.jenkins.Status.MaintenanceQuietDown)
+	})
+	t.Run("enable maintenance mode during safe restart", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(quietDownScript).Return("false\n", nil)
+		r := newReconciler(t, newJenkins(true))
+
+		err := r.ensureMaintenanceMode(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.True(t, conditions.IsTrue(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance))
+		assert.False(t, r.jenkins.Status.MaintenanceQuietDown)
+	})
+	t.Run("disable maintenance mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(cancelQuietDownScript).Return("", nil)
+		jenkins := newJenkins(false)
+		jenkins.Status.MaintenanceQuietDown = true
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance, corev1.ConditionTrue, maintenanceReasonEnabled, "")
+		r := newReconciler(t, jenkins)
+
+		err := r.ensureMaintenanceMode(jenkinsClient)
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance)
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.False(t, r.jenkins.Status.MaintenanceQuietDown)
+	})
+	t.Run("disable maintenance mode during safe restart", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkins := newJenkins(false)
+		jenkins.Status.MaintenanceQuietDown = true
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance, corev1.ConditionTrue, maintenanceReasonEnabled, "")
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, restartReasonSpecChanged, "")
+		r := newReconciler(t, jenkins)
+
+		err := r.ensureMaintenanceMode(jenkinsClient)
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance)
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.False(t, r.jenkins.Status.MaintenanceQuietDown)
+	})
+	t.Run("disable maintenance mode when quiet mode wasn't set by the operator", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkins := newJenkins(false)
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance, corev1.ConditionTrue, maintenanceReasonEnabled, "")
+		r := newReconciler(t, jenkins)
+
+		err := r.ensureMaintenanceMode(jenkinsClient)
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance)
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	})
+	t.Run("maintenance mode never enabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		r := newReconciler(t, newJenkins(false))
+
+		err := r.ensureMaintenanceMode(jenkinsClient)
+
+		assert.NoError(t, err)
+		assert.Nil(t, conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionMaintenance))
+	})
+}
@@ -0,0 +1,167 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	monitoringv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/monitoring/v1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ensurePodMonitor creates or updates Prometheus Operator PodMonitor of Jenkins master pod, the PodMonitor is deleted
+// when Jenkins.Spec.Monitoring isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensurePodMonitor(meta metav1.ObjectMeta) error {
+	if !resources.IsMonitoringEnabled(r.jenkins) {
+		// missing Prometheus Operator CRDs and the operator deployed with an older role which isn't allowed to manage
+		// PodMonitors are tolerated
+		err := r.k8sClient.Delete(context.TODO(), &monitoringv1.PodMonitor{ObjectMeta: meta})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) && !apimeta.IsNoMatchError(err) {
+			return err
+		}
+		return nil
+	}
+
+	podMonitor := resources.NewPodMonitor(meta, r.jenkins)
+	currentPodMonitor := &monitoringv1.PodMonitor{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: podMonitor.Name, Namespace: podMonitor.Namespace}, currentPodMonitor)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating PodMonitor '%s'", podMonitor.Name))
+		return r.createResource(podMonitor)
+	} else if err != nil {
+		return err
+	}
+
+	// the labels select the PodMonitor by Prometheus
+	if reflect.DeepEqual(currentPodMonitor.Labels, podMonitor.Labels) && reflect.DeepEqual(currentPodMonitor.Spec, podMonitor.Spec) {
+		return nil
+	}
+	// custom resources can't be updated without resource version, so the current object is updated
+	currentPodMonitor.Spec = podMonitor.Spec
+	currentPodMonitor.Labels = podMonitor.Labels
+	return r.updateResource(currentPodMonitor)
+}
+
+// ensureMetricsSecret creates the Secret with the metrics plugin access key once, Jenkins master pod reads the key from
+// the Secret, so it has to exist before the pod is created
+func (r *ReconcileJenkinsBaseConfiguration) ensureMetricsSecret(meta metav1.ObjectMeta) error {
+	if !resources.IsMonitoringEnabled(r.jenkins) {
+		secret := &corev1.Secret{ObjectMeta: meta}
+		secret.Name = resources.GetMetricsSecretName(r.jenkins)
+		err := r.k8sClient.Delete(context.TODO(), secret)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	err := r.createResource(resources.NewMetricsSecret(meta, r.jenkins))
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// ensureMetricsService creates or updates the Service exposing Jenkins metrics, the Service is deleted when
+// Jenkins.Spec.Monitoring isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensureMetricsService(meta metav1.ObjectMeta) error {
+	service := resources.NewMetricsService(meta, r.jenkins)
+	if !resources.IsMonitoringEnabled(r.jenkins) {
+		// the operator deployed with an older role isn't allowed to delete Services, the Service is left behind then
+		err := r.k8sClient.Delete(context.TODO(), service)
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return err
+		}
+		return nil
+	}
+
+	currentService := &corev1.Service{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, currentService)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating metrics Service '%s'", service.Name))
+		return r.createResource(service)
+	} else if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(currentService.Annotations, service.Annotations) && reflect.DeepEqual(currentService.Spec.Ports, service.Spec.Ports) {
+		return nil
+	}
+	// cluster IP is immutable, so the current Service is updated
+	currentService.Annotations = service.Annotations
+	currentService.Spec.Ports = service.Spec.Ports
+	return r.updateResource(currentService)
+}
+
+// ensureGrafanaDashboard creates or updates ConfigMap with Grafana dashboard of the Jenkins CR, the ConfigMap is
+// deleted when Jenkins.Spec.Monitoring.GrafanaDashboard isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensureGrafanaDashboard(meta metav1.ObjectMeta) error {
+	if !resources.IsGrafanaDashboardEnabled(r.jenkins) {
+		configMap := &corev1.ConfigMap{ObjectMeta: meta}
+		configMap.Name = resources.GetGrafanaDashboardConfigMapName(r.jenkins)
+		err := r.k8sClient.Delete(context.TODO(), configMap)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	configMap, err := resources.NewGrafanaDashboardConfigMap(meta, r.jenkins)
+	if err != nil {
+		return err
+	}
+	currentConfigMap := &corev1.ConfigMap{}
+	err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, currentConfigMap)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating Grafana dashboard ConfigMap '%s'", configMap.Name))
+		return r.createResource(configMap)
+	} else if err != nil {
+		return err
+	}
+
+	// the labels select the ConfigMap by Grafana dashboards sidecar
+	if reflect.DeepEqual(currentConfigMap.Labels, configMap.Labels) && reflect.DeepEqual(currentConfigMap.Data, configMap.Data) {
+		return nil
+	}
+	currentConfigMap.Labels = configMap.Labels
+	currentConfigMap.Data = configMap.Data
+	return r.updateResource(currentConfigMap)
+}
+
+// ensurePrometheusRule creates or updates Prometheus Operator PrometheusRule with alerts of the Jenkins CR,
+// the PrometheusRule is deleted when Jenkins.Spec.Monitoring.PrometheusRule isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensurePrometheusRule(meta metav1.ObjectMeta) error {
+	if !resources.IsPrometheusRuleEnabled(r.jenkins) {
+		// missing Prometheus Operator CRDs and the operator deployed with an older role which isn't allowed to manage
+		// PrometheusRules are tolerated
+		err := r.k8sClient.Delete(context.TODO(), &monitoringv1.PrometheusRule{ObjectMeta: meta})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) && !apimeta.IsNoMatchError(err) {
+			return err
+		}
+		return nil
+	}
+
+	prometheusRule := resources.NewPrometheusRule(meta, r.jenkins)
+	currentPrometheusRule := &monitoringv1.PrometheusRule{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: prometheusRule.Name, Namespace: prometheusRule.Namespace}, currentPrometheusRule)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating PrometheusRule '%s'", prometheusRule.Name))
+		return r.createResource(prometheusRule)
+	} else if err != nil {
+		return err
+	}
+
+	// the labels select the PrometheusRule by Prometheus
+	if reflect.DeepEqual(currentPrometheusRule.Labels, prometheusRule.Labels) && reflect.DeepEqual(currentPrometheusRule.Spec, prometheusRule.Spec) {
+		return nil
+	}
+	currentPrometheusRule.Spec = prometheusRule.Spec
+	currentPrometheusRule.Labels = prometheusRule.Labels
+	return r.updateResource(currentPrometheusRule)
+}
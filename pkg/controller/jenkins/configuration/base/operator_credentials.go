@@ -0,0 +1,180 @@
+package base
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// operatorTokenRotationPeriod is the maximum age of the operator API token, older token is regenerated
+	operatorTokenRotationPeriod = 24 * time.Hour
+	// reasonAdminPasswordRotated is the reason of event emitted when the operator user password has been rotated
+	reasonAdminPasswordRotated = "AdminPasswordRotated"
+)
+
+const updateOperatorPasswordFmt = `
+import hudson.model.User
+import hudson.security.HudsonPrivateSecurityRealm
+
+def decode(String value) {
+    return new String(Base64.getDecoder().decode(value), 'UTF-8')
+}
+
+def user = User.getById(decode('%s'), false)
+if (user == null) {
+    throw new IllegalStateException('Operator user not found')
+}
+user.addProperty(HudsonPrivateSecurityRealm.Details.fromPlainPassword(decode('%s')))
+user.save()
+`
+
+// buildUpdateOperatorPasswordGroovyScript renders groovy script which sets the operator user password in Jenkins,
+// values are base64 encoded so they don't need escaping
+func buildUpdateOperatorPasswordGroovyScript(userName, password []byte) string {
+	return fmt.Sprintf(updateOperatorPasswordFmt,
+		base64.StdEncoding.EncodeToString(userName), base64.StdEncoding.EncodeToString(password))
+}
+
+const revokeOperatorTokensFmt = `
+import hudson.model.User
+import jenkins.security.ApiTokenProperty
+
+def decode(String value) {
+    return new String(Base64.getDecoder().decode(value), 'UTF-8')
+}
+
+def user = User.getById(decode('%s'), false)
+if (user == null) {
+    throw new IllegalStateException('Operator user not found')
+}
+def tokenStore = user.getProperty(ApiTokenProperty).tokenStore
+def currentTokenUUID = decode('%s')
+tokenStore.tokenListSortedByName.findAll { it.uuid != currentTokenUUID }.each { tokenStore.revokeToken(it.uuid) }
+user.save()
+`
+
+// buildRevokeOperatorTokensGroovyScript renders groovy script which revokes all API tokens of the operator user
+// except the current one
+func buildRevokeOperatorTokensGroovyScript(userName []byte, currentTokenUUID string) string {
+	return fmt.Sprintf(revokeOperatorTokensFmt,
+		base64.StdEncoding.EncodeToString(userName), base64.StdEncoding.EncodeToString([]byte(currentTokenUUID)))
+}
+
+// isOperatorTokenExpired tells if the operator API token is older than the rotation period
+func isOperatorTokenExpired(tokenCreationTime time.Time) bool {
+	return time.Since(tokenCreationTime) > operatorTokenRotationPeriod
+}
+
+// isAdminPasswordExpired tells if the operator user password is older than the rotation period,
+// the password without known creation time is considered expired
+func isAdminPasswordExpired(credentialsSecret *corev1.Secret, rotationPeriod time.Duration) bool {
+	passwordCreationTime := time.Time{}
+	if err := passwordCreationTime.UnmarshalText(credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordCreationKey]); err != nil {
+		return true
+	}
+	return time.Since(passwordCreationTime) > rotationPeriod
+}
+
+// rotateAdminPassword generates a new operator user password when it's older than Jenkins.Spec.Master.AdminPasswordRotationPeriod,
+// the Secret is updated before Jenkins, so when Jenkins update fails it's retried as a rotated operator credentials Secret
+func (r *ReconcileJenkinsBaseConfiguration) rotateAdminPassword(jenkinsClient jenkinsclient.Jenkins, credentialsSecret *corev1.Secret) error {
+	rotationPeriod := r.jenkins.Spec.Master.AdminPasswordRotationPeriod
+	if rotationPeriod == nil || !isAdminPasswordExpired(credentialsSecret, rotationPeriod.Duration) {
+		return nil
+	}
+
+	r.logger.Info("Rotating operator user password")
+	password := []byte(resources.NewOperatorPassword())
+	credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordKey] = password
+	now, _ := time.Now().UTC().MarshalText()
+	credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordCreationKey] = now
+	if err := r.updateResource(credentialsSecret); err != nil {
+		return err
+	}
+
+	userName := credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey]
+	if _, err := jenkinsClient.ExecuteScript(buildUpdateOperatorPasswordGroovyScript(userName, password)); err != nil {
+		return err
+	}
+	r.logger.Info("Operator user password has been updated in Jenkins")
+	r.recorder.Eventf(r.jenkins, corev1.EventTypeNormal, reasonAdminPasswordRotated,
+		"Operator user password has been rotated in Secret '%s'", credentialsSecret.Name)
+
+	return nil
+}
+
+// isOperatorCredentialsSecretRotated tells if the operator credentials Secret has been changed since the last
+// successful Jenkins authentication
+func (r *ReconcileJenkinsBaseConfiguration) isOperatorCredentialsSecretRotated(credentialsSecret *corev1.Secret) bool {
+	resourceVersion := r.jenkins.Status.OperatorCredentialsResourceVersion
+	return len(resourceVersion) > 0 && resourceVersion != credentialsSecret.ResourceVersion
+}
+
+// applyRotatedOperatorCredentials sets the rotated password of the operator user in Jenkins using the current token,
+// returns false when the token is no longer valid and has to be regenerated
+func (r *ReconcileJenkinsBaseConfiguration) applyRotatedOperatorCredentials(jenkinsURL string, tlsConfig *tls.Config, credentialsSecret *corev1.Secret) bool {
+	token := credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey]
+	if len(token) == 0 {
+		return false
+	}
+
+	userName := credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey]
+	jenkinsClient, err := jenkinsclient.New(r.ctx, jenkinsURL, string(userName), string(token), tlsConfig)
+	if err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Couldn't authenticate with operator token: %s", err))
+		return false
+	}
+
+	password := credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordKey]
+	if _, err := jenkinsClient.ExecuteScript(buildUpdateOperatorPasswordGroovyScript(userName, password)); err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Couldn't update operator user password: %s", err))
+		return false
+	}
+	r.logger.Info("Operator user password has been updated in Jenkins")
+
+	return true
+}
+
+// generateOperatorToken generates a new API token of the operator user and stores it in the operator credentials
+// Secret, the previous tokens are revoked afterwards so only the stored one stays valid
+func (r *ReconcileJenkinsBaseConfiguration) generateOperatorToken(jenkinsClient jenkinsclient.Jenkins, credentialsSecret *corev1.Secret) error {
+	userName := credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey]
+	token, err := jenkinsClient.GenerateToken(string(userName), "token")
+	if err != nil {
+		return err
+	}
+
+	credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey] = []byte(token.GetToken())
+	now, _ := time.Now().UTC().MarshalText()
+	credentialsSecret.Data[resources.OperatorCredentialsSecretTokenCreationKey] = now
+	err = r.updateResource(credentialsSecret)
+	if err != nil {
+		return err
+	}
+
+	// the new token is already stored, so failing to revoke the previous ones isn't fatal
+	if _, err := jenkinsClient.ExecuteScript(buildRevokeOperatorTokensGroovyScript(userName, token.GetUUID())); err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Couldn't revoke previous operator API tokens: %s", err))
+	}
+
+	return nil
+}
+
+// updateOperatorCredentialsResourceVersion stores the resource version of operator credentials Secret
+// which was successfully used to authenticate in Jenkins
+func (r *ReconcileJenkinsBaseConfiguration) updateOperatorCredentialsResourceVersion(credentialsSecret *corev1.Secret) error {
+	if r.jenkins.Status.OperatorCredentialsResourceVersion == credentialsSecret.ResourceVersion {
+		return nil
+	}
+
+	r.jenkins.Status.OperatorCredentialsResourceVersion = credentialsSecret.ResourceVersion
+	return r.updateStatus()
+}
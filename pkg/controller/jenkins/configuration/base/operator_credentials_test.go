@@ -0,0 +1,96 @@
+package base
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReconcileJenkinsBaseConfiguration_isOperatorCredentialsSecretRotated(t *testing.T) {
+	tests := []struct {
+		name                  string
+		statusResourceVersion string
+		want                  bool
+	}{
+		{name: "first authentication", statusResourceVersion: "", want: false},
+		{name: "not changed", statusResourceVersion: "100", want: false},
+		{name: "rotated", statusResourceVersion: "99", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcileJenkinsBaseConfiguration{
+				jenkins: &virtuslabv1alpha1.Jenkins{
+					Status: virtuslabv1alpha1.JenkinsStatus{OperatorCredentialsResourceVersion: tt.statusResourceVersion},
+				},
+			}
+			secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "100"}}
+
+			assert.Equal(t, tt.want, r.isOperatorCredentialsSecretRotated(secret))
+		})
+	}
+}
+
+func TestBuildUpdateOperatorPasswordGroovyScript(t *testing.T) {
+	script := buildUpdateOperatorPasswordGroovyScript([]byte("jenkins-operator"), []byte("it's secret"))
+
+	assert.Contains(t, script, base64.StdEncoding.EncodeToString([]byte("jenkins-operator")))
+	assert.Contains(t, script, base64.StdEncoding.EncodeToString([]byte("it's secret")))
+	assert.NotContains(t, script, "it's secret")
+}
+
+func TestBuildRevokeOperatorTokensGroovyScript(t *testing.T) {
+	script := buildRevokeOperatorTokensGroovyScript([]byte("jenkins-operator"), "9b5f4b5c-7c4e-4b5a-8d53-2b4f0c5f6f1a")
+
+	assert.Contains(t, script, base64.StdEncoding.EncodeToString([]byte("jenkins-operator")))
+	assert.Contains(t, script, base64.StdEncoding.EncodeToString([]byte("9b5f4b5c-7c4e-4b5a-8d53-2b4f0c5f6f1a")))
+	assert.Contains(t, script, "tokenStore.revokeToken(it.uuid)")
+}
+
+func TestIsOperatorTokenExpired(t *testing.T) {
+	tests := []struct {
+		name              string
+		tokenCreationTime time.Time
+		want              bool
+	}{
+		{name: "new token", tokenCreationTime: time.Now(), want: false},
+		{name: "token within rotation period", tokenCreationTime: time.Now().Add(-operatorTokenRotationPeriod + time.Hour), want: false},
+		{name: "expired token", tokenCreationTime: time.Now().Add(-operatorTokenRotationPeriod - time.Hour), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isOperatorTokenExpired(tt.tokenCreationTime))
+		})
+	}
+}
+
+func TestIsAdminPasswordExpired(t *testing.T) {
+	passwordCreationTime := func(creationTime time.Time) []byte {
+		text, _ := creationTime.MarshalText()
+		return text
+	}
+	tests := []struct {
+		name                 string
+		passwordCreationTime []byte
+		want                 bool
+	}{
+		{name: "new password", passwordCreationTime: passwordCreationTime(time.Now()), want: false},
+		{name: "expired password", passwordCreationTime: passwordCreationTime(time.Now().Add(-25 * time.Hour)), want: true},
+		{name: "missing creation time", want: true},
+		{name: "invalid creation time", passwordCreationTime: []byte("yesterday"), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credentialsSecret := &corev1.Secret{
+				Data: map[string][]byte{resources.OperatorCredentialsSecretPasswordCreationKey: tt.passwordCreationTime},
+			}
+			assert.Equal(t, tt.want, isAdminPasswordExpired(credentialsSecret, 24*time.Hour))
+		})
+	}
+}
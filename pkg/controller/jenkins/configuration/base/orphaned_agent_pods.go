@@ -0,0 +1,86 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/metrics"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OrphanedAgentPodsCheckPeriod is how often orphaned agent pods are looked for when Jenkins.Spec.Agents.OrphanedPodsGracePeriod
+// is set, nothing else triggers the reconciliation when agents are removed
+const OrphanedAgentPodsCheckPeriod = 5 * time.Minute
+
+// orphanedAgentPodsPermissions are the permissions the operator needs to delete orphaned agent pods in the separate
+// agents namespace
+var orphanedAgentPodsPermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "list", Resource: "pods"},
+	{Verb: "delete", Resource: "pods"},
+}
+
+// ensureOrphanedAgentPodsDeleted deletes agent pods of the Kubernetes clouds in the local agents namespaces which have
+// no Jenkins agent for longer than Jenkins.Spec.Agents.OrphanedPodsGracePeriod, pods of templates with pod retention
+// are kept on purpose so they aren't deleted, the agents namespaces are outside of the operator cache so the pods are
+// listed directly
+func (r *ReconcileJenkinsBaseConfiguration) ensureOrphanedAgentPodsDeleted(jenkinsClient jenkinsclient.Jenkins) error {
+	gracePeriod := r.jenkins.Spec.Agents.OrphanedPodsGracePeriod
+	if gracePeriod == nil {
+		return nil
+	}
+
+	nodes, err := jenkinsClient.GetAllNodes()
+	if err != nil {
+		return err
+	}
+	// Kubernetes plugin names agents after their pods
+	agents := map[string]bool{}
+	for _, node := range nodes {
+		agents[node.GetName()] = true
+	}
+	retainedTemplates := map[string]bool{}
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		if len(template.PodRetention) > 0 && template.PodRetention != virtuslabv1alpha1.PodRetentionNever {
+			retainedTemplates[template.Name] = true
+		}
+	}
+
+	orphaned, deleted := 0, 0
+	for _, namespace := range resources.GetAllAgentsNamespaces(r.jenkins) {
+		pods := &corev1.PodList{}
+		listOptions := client.InNamespace(namespace).MatchingLabels(resources.BuildAgentPodLabels(r.jenkins))
+		if err := r.apiClient.List(context.TODO(), listOptions, pods); err != nil {
+			return err
+		}
+
+		for _, pod := range pods.Items {
+			if pod.ObjectMeta.DeletionTimestamp != nil || agents[pod.Name] ||
+				retainedTemplates[pod.ObjectMeta.Labels[constants.LabelAgentPodTemplateKey]] {
+				continue
+			}
+			orphaned++
+			if time.Since(pod.ObjectMeta.CreationTimestamp.Time) < gracePeriod.Duration {
+				continue
+			}
+
+			r.logger.Info(fmt.Sprintf("Deleting orphaned agent pod '%s' in namespace '%s'", pod.Name, pod.Namespace))
+			if err := r.apiClient.Delete(context.TODO(), &pod); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			deleted++
+		}
+	}
+
+	metrics.OrphanedAgentPods.WithLabelValues(r.jenkins.Namespace, r.jenkins.Name).Set(float64(orphaned - deleted))
+	metrics.DeletedOrphanedAgentPods.WithLabelValues(r.jenkins.Namespace, r.jenkins.Name).Add(float64(deleted))
+	return nil
+}
@@ -0,0 +1,103 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	"github.com/bndr/gojenkins"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestReconcileJenkinsBaseConfiguration_ensureOrphanedAgentPodsDeleted(t *testing.T) {
+	jenkins := &virtuslabv1alpha1.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+		Spec: virtuslabv1alpha1.JenkinsSpec{
+			Agents: virtuslabv1alpha1.Agents{
+				Templates: []virtuslabv1alpha1.AgentPodTemplate{
+					{Name: "maven"},
+					{Name: "debug", PodRetention: virtuslabv1alpha1.PodRetentionOnFailure},
+				},
+				AdditionalNamespaces:    []virtuslabv1alpha1.AgentsNamespace{{Name: "builds"}},
+				OrphanedPodsGracePeriod: &metav1.Duration{Duration: time.Hour},
+			},
+		},
+	}
+	newAgentPod := func(namespace, name, template string, age time.Duration, labels map[string]string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         namespace,
+				Name:              name,
+				Labels:            map[string]string{constants.LabelAgentPodTemplateKey: template},
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+		}
+		for key, value := range labels {
+			pod.ObjectMeta.Labels[key] = value
+		}
+		return pod
+	}
+	agentPodLabels := resources.BuildAgentPodLabels(jenkins)
+	otherJenkinsPodLabels := resources.BuildAgentPodLabels(&virtuslabv1alpha1.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "other-namespace", Name: "jenkins-cr-name"},
+	})
+	pods := []*corev1.Pod{
+		newAgentPod("namespace-name", "maven-running", "maven", 2*time.Hour, agentPodLabels),
+		newAgentPod("namespace-name", "maven-orphaned", "maven", 2*time.Hour, agentPodLabels),
+		newAgentPod("namespace-name", "maven-new", "maven", time.Minute, agentPodLabels),
+		newAgentPod("namespace-name", "debug-failed", "debug", 2*time.Hour, agentPodLabels),
+		newAgentPod("builds", "maven-builds-orphaned", "maven", 2*time.Hour, agentPodLabels),
+		newAgentPod("namespace-name", "maven-other-jenkins", "maven", 2*time.Hour, otherJenkinsPodLabels),
+	}
+	fakeClient := fake.NewFakeClient()
+	for _, pod := range pods {
+		assert.NoError(t, fakeClient.Create(context.TODO(), pod))
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jenkinsClient := client.NewMockJenkins(ctrl)
+	jenkinsClient.EXPECT().GetAllNodes().Return([]*gojenkins.Node{
+		{Raw: &gojenkins.NodeResponse{DisplayName: "master"}},
+		{Raw: &gojenkins.NodeResponse{DisplayName: "maven-running"}},
+	}, nil)
+	r := &ReconcileJenkinsBaseConfiguration{
+		k8sClient: fakeClient,
+		apiClient: fakeClient,
+		logger:    logf.ZapLogger(false),
+		jenkins:   jenkins,
+	}
+
+	err := r.ensureOrphanedAgentPodsDeleted(jenkinsClient)
+
+	assert.NoError(t, err)
+	for _, pod := range pods {
+		err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, &corev1.Pod{})
+		deleted := pod.Name == "maven-orphaned" || pod.Name == "maven-builds-orphaned"
+		assert.Equal(t, deleted, err != nil, pod.Name)
+	}
+}
+
+func TestReconcileJenkinsBaseConfiguration_ensureOrphanedAgentPodsDeleted_disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r := &ReconcileJenkinsBaseConfiguration{
+		k8sClient: fake.NewFakeClient(),
+		logger:    logf.ZapLogger(false),
+		jenkins:   &virtuslabv1alpha1.Jenkins{},
+	}
+
+	err := r.ensureOrphanedAgentPodsDeleted(client.NewMockJenkins(ctrl))
+
+	assert.NoError(t, err)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/groovy"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/metrics"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/plugins"
 	"github.com/VirtusLab/jenkins-operator/pkg/log"
 
@@ -22,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -32,28 +34,42 @@ const (
 
 // ReconcileJenkinsBaseConfiguration defines values required for Jenkins base configuration
 type ReconcileJenkinsBaseConfiguration struct {
-	k8sClient       client.Client
-	scheme          *runtime.Scheme
-	logger          logr.Logger
-	jenkins         *virtuslabv1alpha1.Jenkins
-	local, minikube bool
+	// ctx is the context of the running phase set by Validate and Reconcile, spans of Jenkins API requests are its
+	// children
+	ctx       context.Context
+	k8sClient client.Client
+	// apiClient talks to the API server directly, k8sClient reads from the cache restricted to the operator namespace
+	// so it doesn't see cluster scoped resources and other namespaces
+	apiClient                  client.Client
+	scheme                     *runtime.Scheme
+	recorder                   record.EventRecorder
+	logger                     logr.Logger
+	jenkins                    *virtuslabv1alpha1.Jenkins
+	local, minikube, openshift bool
+	// agentPodTemplateErrors are validation errors of agent pod templates by template name
+	agentPodTemplateErrors map[string][]string
 }
 
 // New create structure which takes care of base configuration
-func New(client client.Client, scheme *runtime.Scheme, logger logr.Logger,
-	jenkins *virtuslabv1alpha1.Jenkins, local, minikube bool) *ReconcileJenkinsBaseConfiguration {
+func New(client, apiClient client.Client, scheme *runtime.Scheme, recorder record.EventRecorder, logger logr.Logger,
+	jenkins *virtuslabv1alpha1.Jenkins, local, minikube, openshift bool) *ReconcileJenkinsBaseConfiguration {
 	return &ReconcileJenkinsBaseConfiguration{
 		k8sClient: client,
+		apiClient: apiClient,
 		scheme:    scheme,
-		logger:    logger,
+		recorder:  recorder,
+		logger:    log.ForComponent(logger, log.ComponentBase),
 		jenkins:   jenkins,
 		local:     local,
 		minikube:  minikube,
+		openshift: openshift,
 	}
 }
 
-// Reconcile takes care of base configuration
-func (r *ReconcileJenkinsBaseConfiguration) Reconcile() (reconcile.Result, jenkinsclient.Jenkins, error) {
+// Reconcile takes care of base configuration, spans of Jenkins API requests are children of the span of ctx, so are
+// the requests of the returned Jenkins API client
+func (r *ReconcileJenkinsBaseConfiguration) Reconcile(ctx context.Context) (reconcile.Result, jenkinsclient.Jenkins, error) {
+	r.ctx = ctx
 	metaObject := resources.NewResourceObjectMeta(r.jenkins)
 
 	err := r.ensureResourcesReuiredForJenkinsPod(metaObject)
@@ -85,6 +101,19 @@ func (r *ReconcileJenkinsBaseConfiguration) Reconcile() (reconcile.Result, jenki
 	}
 	r.logger.V(log.VDebug).Info("Jenkins API client set")
 
+	if err = r.ensureProvisionedCondition(jenkinsClient); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+
+	if err = r.ensureJenkinsSecretsPersisted(jenkinsClient); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	r.logger.V(log.VDebug).Info("Jenkins encryption keys are persisted")
+
+	if err = r.completeRestart(metaObject, jenkinsClient); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+
 	ok, err := r.verifyBasePlugins(jenkinsClient)
 	if err != nil {
 		return reconcile.Result{}, nil, err
@@ -100,6 +129,24 @@ func (r *ReconcileJenkinsBaseConfiguration) Reconcile() (reconcile.Result, jenki
 		}
 	}
 
+	if err = r.ensureServiceUsers(metaObject, jenkinsClient); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	r.logger.V(log.VDebug).Info("Service users API tokens are published")
+
+	if err = r.ensureOrphanedAgentPodsDeleted(jenkinsClient); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+
+	if err = r.ensureMaintenanceMode(jenkinsClient); err != nil {
+		return reconcile.Result{}, nil, err
+	}
+	if r.jenkins.Spec.MaintenanceMode {
+		// base configuration job wouldn't be started in quiet mode
+		r.logger.V(log.VDebug).Info("Jenkins is in maintenance mode, skipping base configuration")
+		return reconcile.Result{}, jenkinsClient, nil
+	}
+
 	result, err = r.ensureBaseConfiguration(jenkinsClient)
 	return result, jenkinsClient, err
 }
@@ -110,6 +157,11 @@ func (r *ReconcileJenkinsBaseConfiguration) ensureResourcesReuiredForJenkinsPod(
 	}
 	r.logger.V(log.VDebug).Info("Operator credentials secret is present")
 
+	if err := r.ensureMetricsSecret(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Metrics secret is up to date")
+
 	if err := r.createScriptsConfigMap(metaObject); err != nil {
 		return err
 	}
@@ -133,18 +185,97 @@ func (r *ReconcileJenkinsBaseConfiguration) ensureResourcesReuiredForJenkinsPod(
 	if err := r.createRBAC(metaObject); err != nil {
 		return err
 	}
-	r.logger.V(log.VDebug).Info("User configuration config map is present")
+
+	if err := r.ensureAgentsRBAC(); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Agents RBAC is up to date")
 
 	if err := r.createService(metaObject); err != nil {
 		return err
 	}
 	r.logger.V(log.VDebug).Info("Service is present")
 
+	if err := r.ensureSSHDService(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("SSH server Service is up to date")
+
+	if err := r.ensureAgentService(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Agent Service is up to date")
+
+	if err := r.ensureHeadlessService(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Headless Service is up to date")
+
+	if err := r.ensureMetricsService(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Metrics Service is up to date")
+
+	if err := r.ensureIngress(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Ingress is up to date")
+
+	if err := r.ensureRoute(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("OpenShift Route is up to date")
+
+	if err := r.ensureIstio(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Istio resources are up to date")
+
+	if err := r.ensureHTTPRoute(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Gateway API HTTPRoute is up to date")
+
+	if err := r.ensurePodMonitor(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("PodMonitor is up to date")
+
+	if err := r.ensureGrafanaDashboard(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Grafana dashboard config map is up to date")
+
+	if err := r.ensurePrometheusRule(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("PrometheusRule is up to date")
+
+	if err := r.ensureCertificate(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("TLS certificate is up to date")
+
+	if err := r.ensureNetworkPolicy(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Network policy is up to date")
+
+	if err := r.ensureExposureCondition(); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Exposure condition is up to date")
+
 	if err := r.createBackupCredentialsSecret(metaObject); err != nil {
 		return err
 	}
 	r.logger.V(log.VDebug).Info("Backup credentials secret is present")
 
+	if err := r.createJenkinsSecretsSecret(metaObject); err != nil {
+		return err
+	}
+	r.logger.V(log.VDebug).Info("Jenkins secrets secret is present")
+
 	return nil
 }
 
@@ -224,13 +355,103 @@ func (r *ReconcileJenkinsBaseConfiguration) createInitConfigurationConfigMap(met
 }
 
 func (r *ReconcileJenkinsBaseConfiguration) createBaseConfigurationConfigMap(meta metav1.ObjectMeta) error {
-	configMap, err := resources.NewBaseConfigurationConfigMap(meta, r.jenkins)
+	overrides, err := r.getBaseScriptsOverrides()
+	if err != nil {
+		return err
+	}
+	jenkins, err := r.resolveSAMLIdPMetadata()
+	if err != nil {
+		return err
+	}
+	jenkins, err = r.resolveAgentPodTemplatesYaml(jenkins)
+	if err != nil {
+		return err
+	}
+	configMap, err := resources.NewBaseConfigurationConfigMap(meta, jenkins, overrides)
 	if err != nil {
 		return err
 	}
 	return r.createOrUpdateResource(configMap)
 }
 
+func (r *ReconcileJenkinsBaseConfiguration) getBaseScriptsOverrides() (map[string]string, error) {
+	reference := r.jenkins.Spec.Configuration.BaseScriptsOverride
+	if reference == nil {
+		return nil, nil
+	}
+
+	overrideConfigMap, err := r.getWatchedConfigMap(reference.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return overrideConfigMap.Data, nil
+}
+
+// resolveSAMLIdPMetadata returns copy of Jenkins CR with SAML identity provider metadata read from the ConfigMap,
+// so the metadata is rendered into base configuration and its changes are applied
+func (r *ReconcileJenkinsBaseConfiguration) resolveSAMLIdPMetadata() (*virtuslabv1alpha1.Jenkins, error) {
+	saml := r.jenkins.Spec.Security.SAML
+	if saml == nil || saml.IdPMetadata.ConfigMap == nil {
+		return r.jenkins, nil
+	}
+
+	metadataConfigMap, err := r.getWatchedConfigMap(saml.IdPMetadata.ConfigMap.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	jenkins := r.jenkins.DeepCopy()
+	jenkins.Spec.Security.SAML.IdPMetadata.XML = metadataConfigMap.Data[resources.SAMLIdPMetadataConfigMapKey]
+	return jenkins, nil
+}
+
+// resolveAgentPodTemplatesYaml returns copy of the Jenkins CR with agent pod template definitions read from
+// the ConfigMaps, so they are rendered into base configuration and their changes are applied
+func (r *ReconcileJenkinsBaseConfiguration) resolveAgentPodTemplatesYaml(jenkins *virtuslabv1alpha1.Jenkins) (*virtuslabv1alpha1.Jenkins, error) {
+	resolved := jenkins
+	for i, template := range jenkins.Spec.Agents.Templates {
+		if template.YamlConfigMap == nil {
+			continue
+		}
+		podConfigMap, err := r.getWatchedConfigMap(template.YamlConfigMap.Name)
+		if err != nil {
+			return nil, err
+		}
+		if resolved == jenkins {
+			resolved = jenkins.DeepCopy()
+		}
+		resolved.Spec.Agents.Templates[i], err = resources.ResolveAgentPodTemplateYaml(template, podConfigMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resolved, nil
+}
+
+// getWatchedConfigMap returns ConfigMap from the Jenkins CR namespace and labels it, so its changes trigger reconciliation
+func (r *ReconcileJenkinsBaseConfiguration) getWatchedConfigMap(name string) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: r.jenkins.Namespace}, configMap)
+	if err != nil {
+		return nil, err
+	}
+	if !r.verifyLabelsForWatchedResource(configMap) {
+		if configMap.ObjectMeta.Labels == nil {
+			configMap.ObjectMeta.Labels = map[string]string{}
+		}
+		for key, value := range resources.BuildLabelsForWatchedResources(r.jenkins) {
+			configMap.ObjectMeta.Labels[key] = value
+		}
+		if err = r.k8sClient.Update(context.TODO(), configMap); err != nil {
+			return nil, err
+		}
+	}
+
+	return configMap, nil
+}
+
 func (r *ReconcileJenkinsBaseConfiguration) createUserConfigurationConfigMap(meta metav1.ObjectMeta) error {
 	currentConfigMap := &corev1.ConfigMap{}
 	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: resources.GetUserConfigurationConfigMapName(r.jenkins), Namespace: r.jenkins.Namespace}, currentConfigMap)
@@ -271,16 +492,203 @@ func (r *ReconcileJenkinsBaseConfiguration) createRBAC(meta metav1.ObjectMeta) e
 }
 
 func (r *ReconcileJenkinsBaseConfiguration) createService(meta metav1.ObjectMeta) error {
-	err := r.createResource(resources.NewService(meta, r.minikube))
+	return r.createOrUpdateService(resources.NewService(meta, r.jenkins, r.minikube))
+}
+
+// createOrUpdateService creates the Service or updates type, load balancer IP, session affinity, ports and annotations
+// of the current one
+func (r *ReconcileJenkinsBaseConfiguration) createOrUpdateService(service *corev1.Service) error {
+	err := r.createResource(service)
 	if err != nil && !apierrors.IsAlreadyExists(err) {
 		return err
+	} else if err == nil {
+		return nil
+	}
+
+	// cluster IP is immutable, so the current Service is updated
+	currentService := &corev1.Service{}
+	err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, currentService)
+	if err != nil {
+		return err
+	}
+	if service.Spec.Type != corev1.ServiceTypeClusterIP {
+		// keep node ports allocated for the existing ports unless they are set explicitly, ports are matched by number
+		// too so that renamed ports keep their node ports
+		for i, port := range service.Spec.Ports {
+			for _, currentPort := range currentService.Spec.Ports {
+				if (currentPort.Name == port.Name || currentPort.Port == port.Port) && port.NodePort == 0 {
+					service.Spec.Ports[i].NodePort = currentPort.NodePort
+				}
+			}
+		}
+	}
+	// annotations added by others, e.g. cloud controllers, are kept
+	annotationsUpToDate := true
+	if currentService.Annotations == nil {
+		currentService.Annotations = map[string]string{}
+	}
+	for key, value := range service.Annotations {
+		if currentValue, found := currentService.Annotations[key]; !found || currentValue != value {
+			currentService.Annotations[key] = value
+			annotationsUpToDate = false
+		}
+	}
+	// external-dns and internal load balancer annotations are managed by the operator, so they are removed when they're
+	// no longer expected
+	managedAnnotationKeys := append(append([]string{}, resources.ExternalDNSAnnotationKeys...), resources.InternalLoadBalancerAnnotationKeys...)
+	for _, key := range managedAnnotationKeys {
+		if _, found := service.Annotations[key]; !found {
+			if _, found := currentService.Annotations[key]; found {
+				delete(currentService.Annotations, key)
+				annotationsUpToDate = false
+			}
+		}
+	}
+	if annotationsUpToDate && currentService.Spec.Type == service.Spec.Type &&
+		currentService.Spec.LoadBalancerIP == service.Spec.LoadBalancerIP &&
+		areStringSlicesEqual(currentService.Spec.LoadBalancerSourceRanges, service.Spec.LoadBalancerSourceRanges) &&
+		currentService.Spec.SessionAffinity == service.Spec.SessionAffinity &&
+		currentService.Spec.PublishNotReadyAddresses == service.Spec.PublishNotReadyAddresses &&
+		currentService.Spec.ExternalTrafficPolicy == service.Spec.ExternalTrafficPolicy &&
+		reflect.DeepEqual(currentService.Spec.SessionAffinityConfig, service.Spec.SessionAffinityConfig) &&
+		areServicePortsEqual(currentService.Spec.Ports, service.Spec.Ports) {
+		return nil
+	}
+
+	currentService.Spec.Type = service.Spec.Type
+	currentService.Spec.LoadBalancerIP = service.Spec.LoadBalancerIP
+	currentService.Spec.LoadBalancerSourceRanges = service.Spec.LoadBalancerSourceRanges
+	currentService.Spec.SessionAffinity = service.Spec.SessionAffinity
+	currentService.Spec.SessionAffinityConfig = service.Spec.SessionAffinityConfig
+	currentService.Spec.PublishNotReadyAddresses = service.Spec.PublishNotReadyAddresses
+	currentService.Spec.ExternalTrafficPolicy = service.Spec.ExternalTrafficPolicy
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer || service.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeLocal {
+		// the health check node port is allocated only for LoadBalancer Service with Local policy
+		currentService.Spec.HealthCheckNodePort = 0
+	}
+	currentService.Spec.Ports = service.Spec.Ports
+	return r.updateResource(currentService)
+}
+
+// areServicePortsEqual compares ports ignoring fields defaulted by Kubernetes API server
+func areServicePortsEqual(currentPorts, ports []corev1.ServicePort) bool {
+	if len(currentPorts) != len(ports) {
+		return false
+	}
+	for i, port := range ports {
+		currentPort := currentPorts[i]
+		if currentPort.Name != port.Name || currentPort.Port != port.Port ||
+			currentPort.TargetPort != port.TargetPort || currentPort.NodePort != port.NodePort {
+			return false
+		}
+	}
+	return true
+}
+
+// areStringSlicesEqual compares slices treating nil and empty slice as equal
+func areStringSlicesEqual(current, expected []string) bool {
+	if len(current) != len(expected) {
+		return false
+	}
+	for i := range expected {
+		if current[i] != expected[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ensureSSHDService creates or updates the Service exposing Jenkins SSH server, the Service is deleted when
+// Jenkins.Spec.Master.Remoting.SSHD.ServiceEnabled isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensureSSHDService(meta metav1.ObjectMeta) error {
+	service := resources.NewSSHDService(meta, r.jenkins)
+	if !resources.IsSSHDServiceEnabled(r.jenkins) {
+		// the operator deployed with an older role isn't allowed to delete Services, the Service is left behind then
+		err := r.k8sClient.Delete(context.TODO(), service)
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return err
+		}
+		return nil
+	}
+
+	err := r.createResource(service)
+	if err == nil || !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	currentService := &corev1.Service{}
+	err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: service.Name, Namespace: service.Namespace}, currentService)
+	if err != nil {
+		return err
+	}
+	if len(currentService.Spec.Ports) == 1 && currentService.Spec.Ports[0].Port == service.Spec.Ports[0].Port &&
+		currentService.Spec.Ports[0].Name == service.Spec.Ports[0].Name {
+		return nil
+	}
+	currentService.Spec.Ports = service.Spec.Ports
+	return r.updateResource(currentService)
+}
+
+// ensureAgentService creates or updates the Service exposing Jenkins TCP agent listener, the Service is deleted when
+// Jenkins.Spec.Service.Agent isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensureAgentService(meta metav1.ObjectMeta) error {
+	if !resources.IsAgentServiceEnabled(r.jenkins) {
+		service := &corev1.Service{ObjectMeta: meta}
+		service.Name = resources.GetAgentServiceName(r.jenkins)
+		err := r.k8sClient.Delete(context.TODO(), service)
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return err
+		}
+		return nil
+	}
+
+	return r.createOrUpdateService(resources.NewAgentService(meta, r.jenkins))
+}
+
+// ensureHeadlessService creates or updates the headless Service of Jenkins master pod, the Service is deleted when
+// Jenkins.Spec.Service.Headless isn't set
+func (r *ReconcileJenkinsBaseConfiguration) ensureHeadlessService(meta metav1.ObjectMeta) error {
+	if !resources.IsHeadlessServiceEnabled(r.jenkins) {
+		service := &corev1.Service{ObjectMeta: meta}
+		service.Name = resources.GetHeadlessServiceName(r.jenkins)
+		err := r.k8sClient.Delete(context.TODO(), service)
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return err
+		}
+		return nil
+	}
+
+	return r.createOrUpdateService(resources.NewHeadlessService(meta, r.jenkins))
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) ensureNetworkPolicy(meta metav1.ObjectMeta) error {
+	networkPolicy := resources.NewNetworkPolicy(meta, r.jenkins)
+	if r.jenkins.Spec.NetworkPolicy != nil {
+		return r.createOrUpdateResource(networkPolicy)
+	}
+
+	// the network policy might have been disabled, when the operator isn't allowed to manage network policies
+	// it couldn't create it either
+	err := r.k8sClient.Delete(context.TODO(), networkPolicy)
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+		return err
 	}
 
 	return nil
 }
 
+// newJenkinsMasterPod builds Jenkins master pod with the spec hash which includes the base configuration
+func (r *ReconcileJenkinsBaseConfiguration) newJenkinsMasterPod(meta metav1.ObjectMeta) (*corev1.Pod, error) {
+	baseConfiguration := &corev1.ConfigMap{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: resources.GetBaseConfigurationConfigMapName(r.jenkins), Namespace: r.jenkins.ObjectMeta.Namespace}, baseConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	return resources.NewJenkinsMasterPod(meta, r.jenkins, baseConfiguration.Data), nil
+}
+
 func (r *ReconcileJenkinsBaseConfiguration) getJenkinsMasterPod(meta metav1.ObjectMeta) (*corev1.Pod, error) {
-	jenkinsMasterPod := resources.NewJenkinsMasterPod(meta, r.jenkins)
+	jenkinsMasterPod := resources.NewJenkinsMasterPod(meta, r.jenkins, nil)
 	currentJenkinsMasterPod := &corev1.Pod{}
 	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: jenkinsMasterPod.Name, Namespace: jenkinsMasterPod.Namespace}, currentJenkinsMasterPod)
 	if err != nil {
@@ -293,14 +701,42 @@ func (r *ReconcileJenkinsBaseConfiguration) ensureJenkinsMasterPod(meta metav1.O
 	// Check if this Pod already exists
 	currentJenkinsMasterPod, err := r.getJenkinsMasterPod(meta)
 	if err != nil && errors.IsNotFound(err) {
-		jenkinsMasterPod := resources.NewJenkinsMasterPod(meta, r.jenkins)
+		certificateHash, err := r.getTLSCertificateHash()
+		if err != nil && errors.IsNotFound(err) && r.jenkins.Spec.Master.TLS != nil && r.jenkins.Spec.Master.TLS.IssuerRef != nil {
+			r.logger.Info("Waiting for cert-manager to issue Jenkins master certificate")
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 5}, nil
+		} else if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		jenkinsMasterPod, err := r.newJenkinsMasterPod(meta)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if len(certificateHash) > 0 {
+			jenkinsMasterPod.ObjectMeta.Annotations[constants.AnnotationTLSCertificateHashKey] = certificateHash
+		}
 		r.logger.Info(fmt.Sprintf("Creating a new Jenkins Master Pod %s/%s", jenkinsMasterPod.Namespace, jenkinsMasterPod.Name))
 		err = r.createResource(jenkinsMasterPod)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
-		r.jenkins.Status = virtuslabv1alpha1.JenkinsStatus{}
-		err = r.updateResource(r.jenkins)
+		r.recorder.Eventf(r.jenkins, corev1.EventTypeNormal, reasonMasterPodCreated, "Jenkins master pod '%s' has been created",
+			jenkinsMasterPod.Name)
+		now := metav1.Now()
+		// the spec history, the operator credentials and backups aren't bound to the Jenkins master pod
+		r.jenkins.Status = virtuslabv1alpha1.JenkinsStatus{
+			URL:                                r.jenkins.Status.URL,
+			ObservedGeneration:                 r.jenkins.Status.ObservedGeneration,
+			ProvisionStartTime:                 &now,
+			LastBackupTime:                     r.jenkins.Status.LastBackupTime,
+			MasterPodSpecHash:                  resources.GetJenkinsMasterPodSpecHash(jenkinsMasterPod),
+			Conditions:                         r.jenkins.Status.Conditions,
+			OperatorCredentialsResourceVersion: r.jenkins.Status.OperatorCredentialsResourceVersion,
+			SpecHistory:                        r.jenkins.Status.SpecHistory,
+		}
+		setProvisioningConditions(&r.jenkins.Status)
+		err = r.updateStatus()
 		if err != nil {
 			return reconcile.Result{}, err
 		}
@@ -309,46 +745,66 @@ func (r *ReconcileJenkinsBaseConfiguration) ensureJenkinsMasterPod(meta metav1.O
 		return reconcile.Result{}, err
 	}
 
+	if currentJenkinsMasterPod.ObjectMeta.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
 	// Recreate pod
-	recreatePod := false
-	if currentJenkinsMasterPod != nil &&
-		(currentJenkinsMasterPod.Status.Phase == corev1.PodFailed ||
-			currentJenkinsMasterPod.Status.Phase == corev1.PodSucceeded ||
-			currentJenkinsMasterPod.Status.Phase == corev1.PodUnknown) {
+	if currentJenkinsMasterPod.Status.Phase == corev1.PodFailed ||
+		currentJenkinsMasterPod.Status.Phase == corev1.PodSucceeded ||
+		currentJenkinsMasterPod.Status.Phase == corev1.PodUnknown {
 		r.logger.Info(fmt.Sprintf("Invalid Jenkins pod phase '%+v', recreating pod", currentJenkinsMasterPod.Status.Phase))
-		recreatePod = true
+		metrics.JenkinsRestarts.WithLabelValues(r.jenkins.Namespace, r.jenkins.Name, restartReasonInvalidPodPhase).Inc()
+		return r.terminateJenkinsMasterPod(currentJenkinsMasterPod)
 	}
 
-	if currentJenkinsMasterPod != nil &&
-		r.jenkins.Spec.Master.Image != currentJenkinsMasterPod.Spec.Containers[0].Image {
-		r.logger.Info(fmt.Sprintf("Jenkins image has changed to '%+v', recreating pod", r.jenkins.Spec.Master.Image))
-		recreatePod = true
+	// Only changes which affect the rendered pod spec, annotations, plugins or base configuration require restart
+	requiredJenkinsMasterPod, err := r.newJenkinsMasterPod(meta)
+	if err != nil {
+		return reconcile.Result{}, err
 	}
-
-	if currentJenkinsMasterPod != nil && len(r.jenkins.Spec.Master.Annotations) > 0 &&
-		!reflect.DeepEqual(r.jenkins.Spec.Master.Annotations, currentJenkinsMasterPod.ObjectMeta.Annotations) {
-		r.logger.Info(fmt.Sprintf("Jenkins pod annotations have changed to '%+v', recreating pod", r.jenkins.Spec.Master.Annotations))
-		recreatePod = true
+	requiredSpecHash := resources.GetJenkinsMasterPodSpecHash(requiredJenkinsMasterPod)
+	currentSpecHash := resources.GetJenkinsMasterPodSpecHash(currentJenkinsMasterPod)
+	if len(currentSpecHash) == 0 {
+		// the pod has been created before the spec hash was introduced, there is nothing to compare with
+		return reconcile.Result{}, r.annotateSpecHash(currentJenkinsMasterPod, requiredSpecHash)
 	}
-
-	if currentJenkinsMasterPod != nil &&
-		!reflect.DeepEqual(r.jenkins.Spec.Master.Resources, currentJenkinsMasterPod.Spec.Containers[0].Resources) {
-		r.logger.Info(fmt.Sprintf("Jenkins pod resources have changed, actual '%+v' required '%+v' - recreating pod",
-			currentJenkinsMasterPod.Spec.Containers[0].Resources, r.jenkins.Spec.Master.Resources))
-		recreatePod = true
+	if requiredSpecHash != currentSpecHash {
+		r.logger.Info(fmt.Sprintf("Jenkins pod spec hash has changed, actual '%s' required '%s' - restarting Jenkins",
+			currentSpecHash, requiredSpecHash))
+		return r.safeRestartJenkins(meta, currentJenkinsMasterPod, restartReasonSpecChanged, "Jenkins master pod spec has changed")
 	}
 
-	if currentJenkinsMasterPod != nil && recreatePod && currentJenkinsMasterPod.ObjectMeta.DeletionTimestamp == nil {
-		r.logger.Info(fmt.Sprintf("Terminating Jenkins Master Pod %s/%s", currentJenkinsMasterPod.Namespace, currentJenkinsMasterPod.Name))
-		if err := r.k8sClient.Delete(context.TODO(), currentJenkinsMasterPod); err != nil {
+	currentCertificateHash, found := currentJenkinsMasterPod.ObjectMeta.Annotations[constants.AnnotationTLSCertificateHashKey]
+	if found {
+		requiredCertificateHash, err := r.getTLSCertificateHash()
+		if err != nil {
 			return reconcile.Result{}, err
 		}
-		return reconcile.Result{Requeue: true}, nil
+		if requiredCertificateHash != currentCertificateHash {
+			r.logger.Info("Jenkins master certificate has changed - restarting Jenkins")
+			return r.safeRestartJenkins(meta, currentJenkinsMasterPod, restartReasonCertificateRenewed, "Jenkins master certificate has been renewed")
+		}
 	}
 
 	return reconcile.Result{}, nil
 }
 
+// annotateSpecHash stores the spec hash in the Jenkins master pod annotations and status without restarting Jenkins
+func (r *ReconcileJenkinsBaseConfiguration) annotateSpecHash(jenkinsMasterPod *corev1.Pod, specHash string) error {
+	r.logger.Info(fmt.Sprintf("Jenkins master pod has no spec hash, annotating it with '%s'", specHash))
+	if jenkinsMasterPod.ObjectMeta.Annotations == nil {
+		jenkinsMasterPod.ObjectMeta.Annotations = map[string]string{}
+	}
+	jenkinsMasterPod.ObjectMeta.Annotations[constants.AnnotationSpecHashKey] = specHash
+	if err := r.updateResource(jenkinsMasterPod); err != nil {
+		return err
+	}
+
+	r.jenkins.Status.MasterPodSpecHash = specHash
+	return r.updateStatus()
+}
+
 func (r *ReconcileJenkinsBaseConfiguration) waitForJenkins(meta metav1.ObjectMeta) (reconcile.Result, error) {
 	jenkinsMasterPodStatus, err := r.getJenkinsMasterPod(meta)
 	if err != nil {
@@ -376,8 +832,17 @@ func (r *ReconcileJenkinsBaseConfiguration) waitForJenkins(meta metav1.ObjectMet
 }
 
 func (r *ReconcileJenkinsBaseConfiguration) ensureJenkinsClient(meta metav1.ObjectMeta) (jenkinsclient.Jenkins, error) {
+	port := resources.HTTPPortInt
+	if resources.IsTLSEnabled(r.jenkins) {
+		port = resources.HTTPSPortInt
+	}
 	jenkinsURL, err := jenkinsclient.BuildJenkinsAPIUrl(
-		r.jenkins.ObjectMeta.Namespace, meta.Name, resources.HTTPPortInt, r.local, r.minikube)
+		r.jenkins.ObjectMeta.Namespace, meta.Name, port, resources.IsTLSEnabled(r.jenkins), r.local, r.minikube)
+	if err != nil {
+		return nil, err
+	}
+	jenkinsURL += resources.GetJenkinsPrefix(r.jenkins)
+	tlsConfig, err := r.buildJenkinsClientTLSConfig()
 	if err != nil {
 		return nil, err
 	}
@@ -393,6 +858,12 @@ func (r *ReconcileJenkinsBaseConfiguration) ensureJenkinsClient(meta metav1.Obje
 		return nil, err
 	}
 
+	tokenValid := true
+	if r.isOperatorCredentialsSecretRotated(credentialsSecret) {
+		r.logger.Info("Operator credentials Secret has changed, synchronizing operator credentials with Jenkins")
+		tokenValid = r.applyRotatedOperatorCredentials(jenkinsURL, tlsConfig, credentialsSecret)
+	}
+
 	var tokenCreationTime *time.Time
 	tokenCreationTimeBytes := credentialsSecret.Data[resources.OperatorCredentialsSecretTokenCreationKey]
 	if tokenCreationTimeBytes != nil {
@@ -403,37 +874,67 @@ func (r *ReconcileJenkinsBaseConfiguration) ensureJenkinsClient(meta metav1.Obje
 		}
 
 	}
-	if credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey] == nil ||
-		tokenCreationTimeBytes == nil || tokenCreationTime == nil ||
-		currentJenkinsMasterPod.ObjectMeta.CreationTimestamp.Time.UTC().After(tokenCreationTime.UTC()) {
-		r.logger.Info("Generating Jenkins API token for operator")
-		userName := string(credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey])
+
+	userName := string(credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey])
+	// the client used to generate a new token, the current token is used when it's still valid, so the rotation
+	// doesn't depend on the operator password
+	var tokenGenerationClient jenkinsclient.Jenkins
+	if tokenValid && credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey] != nil &&
+		tokenCreationTime != nil && !currentJenkinsMasterPod.ObjectMeta.CreationTimestamp.Time.UTC().After(tokenCreationTime.UTC()) {
 		jenkinsClient, err := jenkinsclient.New(
+			r.ctx,
 			jenkinsURL,
 			userName,
-			string(credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordKey]))
-		if err != nil {
-			return nil, err
+			string(credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey]),
+			tlsConfig)
+		switch {
+		case err != nil:
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Couldn't authenticate with operator token, it might have been revoked: %s", err))
+		case !isOperatorTokenExpired(*tokenCreationTime):
+			if err = r.rotateAdminPassword(jenkinsClient, credentialsSecret); err != nil {
+				return nil, err
+			}
+			return jenkinsClient, r.updateOperatorCredentialsResourceVersion(credentialsSecret)
+		default:
+			r.logger.Info("Rotating Jenkins API token for operator")
+			tokenGenerationClient = jenkinsClient
 		}
+	}
 
-		token, err := jenkinsClient.GenerateToken(userName, "token")
+	if tokenGenerationClient == nil {
+		r.logger.Info("Generating Jenkins API token for operator")
+		tokenGenerationClient, err = jenkinsclient.New(
+			r.ctx,
+			jenkinsURL,
+			userName,
+			string(credentialsSecret.Data[resources.OperatorCredentialsSecretPasswordKey]),
+			tlsConfig)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("couldn't authenticate with operator user and password, restore the previous password "+
+				"in operator credentials Secret or delete Jenkins master pod: %s", err)
 		}
+	}
 
-		credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey] = []byte(token.GetToken())
-		now, _ := time.Now().UTC().MarshalText()
-		credentialsSecret.Data[resources.OperatorCredentialsSecretTokenCreationKey] = now
-		err = r.updateResource(credentialsSecret)
-		if err != nil {
-			return nil, err
-		}
+	err = r.generateOperatorToken(tokenGenerationClient, credentialsSecret)
+	if err != nil {
+		return nil, err
 	}
 
-	return jenkinsclient.New(
+	// verifies connectivity with the new token
+	jenkinsClient, err := jenkinsclient.New(
+		r.ctx,
 		jenkinsURL,
-		string(credentialsSecret.Data[resources.OperatorCredentialsSecretUserNameKey]),
-		string(credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey]))
+		userName,
+		string(credentialsSecret.Data[resources.OperatorCredentialsSecretTokenKey]),
+		tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.rotateAdminPassword(jenkinsClient, credentialsSecret); err != nil {
+		return nil, err
+	}
+
+	return jenkinsClient, r.updateOperatorCredentialsResourceVersion(credentialsSecret)
 }
 
 func (r *ReconcileJenkinsBaseConfiguration) ensureBaseConfiguration(jenkinsClient jenkinsclient.Jenkins) (reconcile.Result, error) {
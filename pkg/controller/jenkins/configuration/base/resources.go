@@ -36,6 +36,11 @@ func (r *ReconcileJenkinsBaseConfiguration) updateResource(obj metav1.Object) er
 	return r.k8sClient.Update(context.TODO(), runtimeObj)
 }
 
+// updateStatus persists Jenkins status by the status subresource, changes of the spec and metadata are ignored
+func (r *ReconcileJenkinsBaseConfiguration) updateStatus() error {
+	return r.k8sClient.Status().Update(context.TODO(), r.jenkins)
+}
+
 func (r *ReconcileJenkinsBaseConfiguration) createOrUpdateResource(obj metav1.Object) error {
 	runtimeObj, ok := obj.(runtime.Object)
 	if !ok {
@@ -0,0 +1,68 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// IsAgentServiceEnabled tells if the dedicated Service exposing Jenkins TCP agent listener should exist
+func IsAgentServiceEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Service.Agent != nil
+}
+
+// GetAgentServiceName returns name of the Service exposing Jenkins TCP agent listener
+func GetAgentServiceName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-agent-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// GetAgentServiceType returns type of the Service exposing Jenkins TCP agent listener, ClusterIP by default
+func GetAgentServiceType(jenkins *virtuslabv1alpha1.Jenkins) corev1.ServiceType {
+	if serviceType := jenkins.Spec.Service.Agent.Type; len(serviceType) > 0 {
+		return serviceType
+	}
+	return corev1.ServiceTypeClusterIP
+}
+
+// NewAgentService builds the Kubernetes service resource exposing only Jenkins TCP agent listener port
+func NewAgentService(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *corev1.Service {
+	spec := jenkins.Spec.Service.Agent
+	selector := meta.Labels
+	meta.Name = GetAgentServiceName(jenkins)
+	meta.Annotations = map[string]string{}
+	for key, value := range spec.Annotations {
+		meta.Annotations[key] = value
+	}
+
+	service := &corev1.Service{
+		TypeMeta:   buildServiceTypeMeta(),
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Type:            GetAgentServiceType(jenkins),
+			Selector:        selector,
+			SessionAffinity: corev1.ServiceAffinityNone,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       GetServicePortNames(jenkins).AgentListener,
+					Port:       slavePortInt32,
+					TargetPort: intstr.FromInt(slavePortInt),
+					NodePort:   spec.NodePort,
+				},
+			},
+		},
+	}
+
+	switch service.Spec.Type {
+	case corev1.ServiceTypeClusterIP:
+		service.Spec.Ports[0].NodePort = 0
+	case corev1.ServiceTypeLoadBalancer:
+		service.Spec.LoadBalancerIP = spec.LoadBalancerIP
+	}
+
+	return service
+}
@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetAgentPodTemplateServiceAccountName returns name of the service account used by agent pods of the template,
+// templates with permissions get the dedicated one, the others share the agents service account
+func GetAgentPodTemplateServiceAccountName(jenkins *virtuslabv1alpha1.Jenkins, template virtuslabv1alpha1.AgentPodTemplate) string {
+	if len(template.Permissions) == 0 {
+		return GetAgentsResourceName(jenkins)
+	}
+	return fmt.Sprintf("%s-%s", GetAgentsResourceName(jenkins), template.Name)
+}
+
+// NewAgentPodTemplateObjectMeta builds ObjectMeta of the dedicated service account, role and role binding of the agent
+// pod template
+func NewAgentPodTemplateObjectMeta(jenkins *virtuslabv1alpha1.Jenkins, template virtuslabv1alpha1.AgentPodTemplate) metav1.ObjectMeta {
+	meta := NewAgentsObjectMeta(jenkins)
+	meta.Name = GetAgentPodTemplateServiceAccountName(jenkins, template)
+	meta.Labels[constants.LabelAgentPodTemplateKey] = template.Name
+	return meta
+}
+
+// NewAgentPodTemplateServiceAccount returns the dedicated service account of the agent pod template, it mounts
+// Kubernetes API token so the builds can use the template permissions
+func NewAgentPodTemplateServiceAccount(meta metav1.ObjectMeta) *corev1.ServiceAccount {
+	serviceAccount := NewServiceAccount(meta)
+	automountServiceAccountToken := true
+	serviceAccount.AutomountServiceAccountToken = &automountServiceAccountToken
+	return serviceAccount
+}
+
+// NewAgentPodTemplateRole returns rbac role granting only AgentPodTemplate.Permissions
+func NewAgentPodTemplateRole(meta metav1.ObjectMeta, template virtuslabv1alpha1.AgentPodTemplate) *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Role",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: meta,
+		Rules:      template.Permissions,
+	}
+}
+
+// NewAgentPodTemplateRoleBinding returns rbac role binding of the agent pod template role to its service account
+func NewAgentPodTemplateRoleBinding(meta metav1.ObjectMeta) *rbacv1.RoleBinding {
+	return NewRoleBinding(meta)
+}
@@ -0,0 +1,224 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+const (
+	// AgentContainerName is the name of Kubernetes plugin agent container
+	AgentContainerName = "jnlp"
+	// AgentPodTemplateYamlConfigMapKey is the AgentPodTemplate.YamlConfigMap key with the agent pod definition
+	AgentPodTemplateYamlConfigMapKey = "pod.yaml"
+
+	// agentDefaultImageProperty overrides the image Kubernetes plugin uses for agent containers without image
+	agentDefaultImageProperty = "org.csanchez.jenkins.plugins.kubernetes.pipeline.PodTemplateStepExecution.defaultImage"
+)
+
+// buildAgentDefaultImageJavaOpts returns JVM options which make Kubernetes plugin use Jenkins.Spec.Agents.DefaultImage
+// in pod templates defined by pipelines, returns empty string when the image isn't set
+func buildAgentDefaultImageJavaOpts(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if len(jenkins.Spec.Agents.DefaultImage) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("-D%s=%s", agentDefaultImageProperty, jenkins.Spec.Agents.DefaultImage)
+}
+
+// ResolveAgentPodTemplateYaml returns copy of the agent pod template with Yaml combined with the pod definition from
+// AgentPodTemplate.YamlConfigMap according to AgentPodTemplate.YamlMergeStrategy
+func ResolveAgentPodTemplateYaml(template virtuslabv1alpha1.AgentPodTemplate, configMap *corev1.ConfigMap) (virtuslabv1alpha1.AgentPodTemplate, error) {
+	podYaml, ok := configMap.Data[AgentPodTemplateYamlConfigMapKey]
+	if !ok {
+		return template, fmt.Errorf("ConfigMap '%s' of agent pod template '%s' doesn't contain '%s' key",
+			configMap.Name, template.Name, AgentPodTemplateYamlConfigMapKey)
+	}
+
+	resolved := *template.DeepCopy()
+	if template.YamlMergeStrategy == virtuslabv1alpha1.YamlMergeStrategyOverride || len(template.Yaml) == 0 {
+		resolved.Yaml = podYaml
+		return resolved, nil
+	}
+
+	original, err := yaml.YAMLToJSON([]byte(podYaml))
+	if err != nil {
+		return template, fmt.Errorf("invalid yaml in ConfigMap '%s' of agent pod template '%s': %s", configMap.Name, template.Name, err)
+	}
+	patch, err := yaml.YAMLToJSON([]byte(template.Yaml))
+	if err != nil {
+		return template, fmt.Errorf("invalid yaml of '%s' agent pod template: %s", template.Name, err)
+	}
+	merged, err := strategicpatch.StrategicMergePatch(original, patch, corev1.Pod{})
+	if err != nil {
+		return template, fmt.Errorf("can't merge yaml of '%s' agent pod template into ConfigMap '%s': %s", template.Name, configMap.Name, err)
+	}
+	mergedYaml, err := yaml.JSONToYAML(merged)
+	if err != nil {
+		return template, err
+	}
+	resolved.Yaml = string(mergedYaml)
+
+	return resolved, nil
+}
+
+// NewAgentPod builds the pod definition of Kubernetes plugin pod template, the template fields are merged into the pod
+// parsed from AgentPodTemplate.Yaml and override it
+func NewAgentPod(template virtuslabv1alpha1.AgentPodTemplate) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	if err := yaml.Unmarshal([]byte(template.Yaml), pod); err != nil {
+		return nil, fmt.Errorf("invalid yaml of '%s' agent pod template: %s", template.Name, err)
+	}
+
+	containerIndex := -1
+	for i, container := range pod.Spec.Containers {
+		if container.Name == AgentContainerName {
+			containerIndex = i
+		}
+	}
+	if containerIndex < 0 {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: AgentContainerName})
+		containerIndex = len(pod.Spec.Containers) - 1
+	}
+	container := &pod.Spec.Containers[containerIndex]
+	if len(template.Image) > 0 {
+		container.Image = template.Image
+	}
+	for name, quantity := range template.Resources.Requests {
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = corev1.ResourceList{}
+		}
+		container.Resources.Requests[name] = quantity
+	}
+	for name, quantity := range template.Resources.Limits {
+		if container.Resources.Limits == nil {
+			container.Resources.Limits = corev1.ResourceList{}
+		}
+		container.Resources.Limits[name] = quantity
+	}
+	container.Env = append(container.Env, template.Env...)
+	container.VolumeMounts = append(container.VolumeMounts, template.VolumeMounts...)
+	pod.Spec.Volumes = append(pod.Spec.Volumes, template.Volumes...)
+	for key, value := range template.NodeSelector {
+		if pod.Spec.NodeSelector == nil {
+			pod.Spec.NodeSelector = map[string]string{}
+		}
+		pod.Spec.NodeSelector[key] = value
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, template.Tolerations...)
+	if template.Affinity != nil {
+		pod.Spec.Affinity = template.Affinity
+	}
+	applyImageBuilder(pod, template.ImageBuilder)
+	applyAgentGPU(pod, template.GPU)
+	applyWorkspaceVolume(pod, template.WorkspaceVolume)
+	applySpotNodes(pod, template.Spot)
+	applyAgentOS(pod, template)
+
+	return pod, nil
+}
+
+// addImagePullSecrets adds Jenkins.Spec.Agents.ImagePullSecrets the agent pod doesn't already reference
+func addImagePullSecrets(pod *corev1.Pod, secrets []corev1.LocalObjectReference) {
+	for _, secret := range secrets {
+		found := false
+		for _, current := range pod.Spec.ImagePullSecrets {
+			if current.Name == secret.Name {
+				found = true
+			}
+		}
+		if !found {
+			pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, secret)
+		}
+	}
+}
+
+// podRetentionClasses contains Kubernetes plugin pod retention classes per AgentPodTemplate.PodRetention
+var podRetentionClasses = map[virtuslabv1alpha1.PodRetention]string{
+	virtuslabv1alpha1.PodRetentionNever:     "Never",
+	virtuslabv1alpha1.PodRetentionOnFailure: "OnFailure",
+	virtuslabv1alpha1.PodRetentionAlways:    "Always",
+}
+
+// buildKubernetesCloudPodTemplatesGroovyScript returns groovy statements adding Jenkins.Spec.Agents.Templates to
+// the Kubernetes cloud, agent pods use the operator managed service accounts unless the template yaml sets one, returns
+// empty string when there are no templates
+func buildKubernetesCloudPodTemplatesGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return buildPodTemplatesGroovyScript(jenkins, "kubernetes", "podTemplate", "", func(template virtuslabv1alpha1.AgentPodTemplate) string {
+		return GetAgentPodTemplateServiceAccountName(jenkins, template)
+	})
+}
+
+// buildPodTemplatesGroovyScript returns groovy statements adding Jenkins.Spec.Agents.Templates to the cloud in
+// cloudVariable, the template labels get labelPrefix and pods without service account get serviceAccountName
+func buildPodTemplatesGroovyScript(jenkins *virtuslabv1alpha1.Jenkins, cloudVariable, templateVariable, labelPrefix string,
+	serviceAccountName func(virtuslabv1alpha1.AgentPodTemplate) string) string {
+	script := ""
+	for i, template := range jenkins.Spec.Agents.Templates {
+		pod, err := NewAgentPod(template)
+		if err != nil {
+			// templates with invalid yaml are rejected by the validation
+			continue
+		}
+		for i, container := range pod.Spec.Containers {
+			if container.Name == AgentContainerName && len(container.Image) == 0 {
+				pod.Spec.Containers[i].Image = jenkins.Spec.Agents.DefaultImage
+			}
+		}
+		if len(pod.Spec.ServiceAccountName) == 0 {
+			pod.Spec.ServiceAccountName = serviceAccountName(template)
+		}
+		addImagePullSecrets(pod, jenkins.Spec.Agents.ImagePullSecrets)
+		// the template of orphaned agent pods tells whether they are retained
+		if pod.ObjectMeta.Labels == nil {
+			pod.ObjectMeta.Labels = map[string]string{}
+		}
+		pod.ObjectMeta.Labels[constants.LabelAgentPodTemplateKey] = template.Name
+		podYAML, err := yaml.Marshal(pod)
+		if err != nil {
+			continue
+		}
+		podYAML, err = setAgentPodYamlRuntimeClassName(podYAML, template.GPU)
+		if err != nil {
+			continue
+		}
+		podYAML, err = setAgentPodYamlWorkspaceVolume(podYAML, template.WorkspaceVolume)
+		if err != nil {
+			continue
+		}
+
+		label := template.Label
+		if len(labelPrefix) > 0 {
+			var labels []string
+			for _, atom := range strings.Fields(template.Label) {
+				labels = append(labels, labelPrefix+atom)
+			}
+			label = strings.Join(labels, " ")
+		}
+		script += fmt.Sprintf(`def %[1]s%[2]d = new org.csanchez.jenkins.plugins.kubernetes.PodTemplate()
+%[1]s%[2]d.setName('%[3]s')
+%[1]s%[2]d.setLabel('%[4]s')
+%[1]s%[2]d.setYaml('''%[5]s''')
+`, templateVariable, i, escapeGroovyString(template.Name), escapeGroovyString(label), escapeGroovyString(string(podYAML)))
+		if template.MaxConcurrent > 0 {
+			script += fmt.Sprintf("%s%d.setInstanceCap(%d)\n", templateVariable, i, template.MaxConcurrent)
+		}
+		if template.IdleMinutes > 0 {
+			script += fmt.Sprintf("%s%d.setIdleMinutes(%d)\n", templateVariable, i, template.IdleMinutes)
+		}
+		if template.ConnectionTimeoutSeconds > 0 {
+			script += fmt.Sprintf("%s%d.setSlaveConnectTimeout(%d)\n", templateVariable, i, template.ConnectionTimeoutSeconds)
+		}
+		if retention, ok := podRetentionClasses[template.PodRetention]; ok {
+			script += fmt.Sprintf("%s%d.setPodRetention(new org.csanchez.jenkins.plugins.kubernetes.pod.retention.%s())\n",
+				templateVariable, i, retention)
+		}
+		script += fmt.Sprintf("%s.addTemplate(%s%d)\n", cloudVariable, templateVariable, i)
+	}
+	return script
+}
@@ -0,0 +1,432 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewAgentPod(t *testing.T) {
+	t.Run("fields merged into yaml", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:  "maven",
+			Label: "maven jdk11",
+			Image: "jenkins/inbound-agent:4.3-4",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			},
+			Env:          []corev1.EnvVar{{Name: "MAVEN_OPTS", Value: "-Xmx1g"}},
+			Volumes:      []corev1.Volume{{Name: "maven-cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "maven-cache", MountPath: "/root/.m2"}},
+			Yaml: `spec:
+  nodeSelector:
+    pool: builds
+  containers:
+  - name: jnlp
+    image: jenkins/jnlp-slave
+    resources:
+      limits:
+        memory: 2Gi
+  - name: docker
+    image: docker:dind
+`,
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"pool": "builds"}, pod.Spec.NodeSelector)
+		assert.Len(t, pod.Spec.Containers, 2)
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, AgentContainerName, container.Name)
+		assert.Equal(t, "jenkins/inbound-agent:4.3-4", container.Image)
+		assert.Equal(t, resource.MustParse("500m"), container.Resources.Requests[corev1.ResourceCPU])
+		assert.Equal(t, resource.MustParse("2Gi"), container.Resources.Limits[corev1.ResourceMemory])
+		assert.Equal(t, template.Env, container.Env)
+		assert.Equal(t, template.VolumeMounts, container.VolumeMounts)
+		assert.Equal(t, template.Volumes, pod.Spec.Volumes)
+	})
+	t.Run("without yaml", func(t *testing.T) {
+		pod, err := NewAgentPod(virtuslabv1alpha1.AgentPodTemplate{Name: "default"})
+
+		assert.NoError(t, err)
+		assert.Len(t, pod.Spec.Containers, 1)
+		assert.Equal(t, AgentContainerName, pod.Spec.Containers[0].Name)
+		assert.Empty(t, pod.Spec.Containers[0].Image)
+	})
+	t.Run("invalid yaml", func(t *testing.T) {
+		_, err := NewAgentPod(virtuslabv1alpha1.AgentPodTemplate{Name: "default", Yaml: "spec: ["})
+
+		assert.Error(t, err)
+	})
+	t.Run("spot nodes", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name: "maven",
+			Spot: &virtuslabv1alpha1.SpotNodes{
+				Provider:     virtuslabv1alpha1.SpotProviderGCP,
+				NodeSelector: map[string]string{"pool": "spot-builds"},
+				Tolerations:  []corev1.Toleration{{Key: "builds", Operator: corev1.TolerationOpExists}},
+			},
+			Yaml: "spec:\n  nodeSelector:\n    disk: ssd\n",
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"disk": "ssd", "pool": "spot-builds", GCPSpotNodeLabelKey: "true"}, pod.Spec.NodeSelector)
+		assert.Equal(t, []corev1.Toleration{
+			{Key: GCPSpotNodeLabelKey, Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			{Key: "builds", Operator: corev1.TolerationOpExists},
+		}, pod.Spec.Tolerations)
+	})
+	t.Run("scheduling", func(t *testing.T) {
+		affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+				Weight: 50,
+				Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "disk", Operator: corev1.NodeSelectorOpIn, Values: []string{"nvme"}},
+				}},
+			}},
+		}}
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:         "gradle",
+			NodeSelector: map[string]string{"pool": "big-memory"},
+			Tolerations:  []corev1.Toleration{{Key: "big-memory", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+			Affinity:     affinity,
+			Yaml: `spec:
+  nodeSelector:
+    pool: default
+    zone: a
+  tolerations:
+  - key: builds
+    operator: Exists
+  affinity:
+    podAntiAffinity:
+      requiredDuringSchedulingIgnoredDuringExecution:
+      - topologyKey: kubernetes.io/hostname
+`,
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"pool": "big-memory", "zone": "a"}, pod.Spec.NodeSelector)
+		assert.Equal(t, []corev1.Toleration{
+			{Key: "builds", Operator: corev1.TolerationOpExists},
+			{Key: "big-memory", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		}, pod.Spec.Tolerations)
+		assert.Equal(t, affinity, pod.Spec.Affinity)
+	})
+	t.Run("Windows", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name: "dotnet",
+			OS:   virtuslabv1alpha1.AgentOSWindows,
+			Yaml: `spec:
+  containers:
+  - name: dotnet
+    image: mcr.microsoft.com/dotnet/sdk:5.0-nanoserver-1809
+  - name: shell
+    image: mcr.microsoft.com/powershell:nanoserver-1809
+    command:
+    - pwsh
+`,
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{OSNodeLabelKey: "windows"}, pod.Spec.NodeSelector)
+		assert.Equal(t, windowsKeepAliveCommand, pod.Spec.Containers[0].Command)
+		assert.Equal(t, []string{"pwsh"}, pod.Spec.Containers[1].Command)
+		assert.Equal(t, DefaultWindowsAgentImage, pod.Spec.Containers[2].Image)
+		assert.Equal(t, OSNodeTaintKey, pod.Spec.Tolerations[0].Key)
+	})
+	t.Run("Linux", func(t *testing.T) {
+		pod, err := NewAgentPod(virtuslabv1alpha1.AgentPodTemplate{Name: "maven", OS: virtuslabv1alpha1.AgentOSLinux})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{OSNodeLabelKey: "linux"}, pod.Spec.NodeSelector)
+		assert.Empty(t, pod.Spec.Containers[0].Image)
+		assert.Empty(t, pod.Spec.Tolerations)
+	})
+	t.Run("Docker in Docker", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:         "docker",
+			ImageBuilder: &virtuslabv1alpha1.ImageBuilder{Type: virtuslabv1alpha1.ImageBuilderTypeDind, Image: "docker:25.0-dind"},
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		assert.Len(t, pod.Spec.Containers, 2)
+		dind := pod.Spec.Containers[1]
+		assert.Equal(t, DindContainerName, dind.Name)
+		assert.Equal(t, "docker:25.0-dind", dind.Image)
+		assert.True(t, *dind.SecurityContext.Privileged)
+		assert.Contains(t, dind.Args, "--group=1000")
+		assert.Equal(t, []corev1.EnvVar{{Name: "DOCKER_HOST", Value: "unix:///var/run/dind/docker.sock"}}, pod.Spec.Containers[0].Env)
+		assert.Equal(t, []corev1.VolumeMount{{Name: "dind-socket", MountPath: "/var/run/dind"}}, pod.Spec.Containers[0].VolumeMounts)
+		assert.Len(t, pod.Spec.Volumes, 2)
+	})
+	t.Run("BuildKit", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:         "buildkit",
+			ImageBuilder: &virtuslabv1alpha1.ImageBuilder{Type: virtuslabv1alpha1.ImageBuilderTypeBuildKit},
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		buildKit := pod.Spec.Containers[1]
+		assert.Equal(t, BuildKitContainerName, buildKit.Name)
+		assert.Equal(t, DefaultBuildKitImage, buildKit.Image)
+		assert.Nil(t, buildKit.SecurityContext.Privileged)
+		assert.Equal(t, int64(1000), *buildKit.SecurityContext.RunAsUser)
+		assert.Equal(t, "unconfined", pod.ObjectMeta.Annotations["container.seccomp.security.alpha.kubernetes.io/buildkitd"])
+		assert.Equal(t, "unconfined", pod.ObjectMeta.Annotations["container.apparmor.security.beta.kubernetes.io/buildkitd"])
+		assert.Equal(t, "BUILDKIT_HOST", pod.Spec.Containers[0].Env[0].Name)
+	})
+	t.Run("Kaniko", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:         "kaniko",
+			ImageBuilder: &virtuslabv1alpha1.ImageBuilder{Type: virtuslabv1alpha1.ImageBuilderTypeKaniko},
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		kaniko := pod.Spec.Containers[1]
+		assert.Equal(t, KanikoContainerName, kaniko.Name)
+		assert.Equal(t, []string{"/busybox/cat"}, kaniko.Command)
+		assert.False(t, *kaniko.SecurityContext.Privileged)
+		assert.Empty(t, pod.Spec.Containers[0].Env)
+		assert.Empty(t, pod.Spec.Volumes)
+	})
+	t.Run("GPU", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name: "cuda",
+			GPU:  &virtuslabv1alpha1.AgentGPU{Count: 2, Container: "cuda", DriverCapabilities: []string{"compute", "utility"}},
+			Yaml: "spec:\n  containers:\n  - name: cuda\n    image: nvidia/cuda:12.2.0-base-ubuntu22.04\n",
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		cuda := pod.Spec.Containers[0]
+		requests, limits := cuda.Resources.Requests[DefaultGPUResourceName], cuda.Resources.Limits[DefaultGPUResourceName]
+		assert.Equal(t, int64(2), requests.Value())
+		assert.Equal(t, int64(2), limits.Value())
+		assert.Equal(t, []corev1.EnvVar{{Name: "NVIDIA_DRIVER_CAPABILITIES", Value: "compute,utility"}}, cuda.Env)
+		assert.Empty(t, pod.Spec.Containers[1].Resources.Limits)
+		assert.Equal(t, []corev1.Toleration{
+			{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		}, pod.Spec.Tolerations)
+	})
+	t.Run("GPU runtime class", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "cuda", GPU: &virtuslabv1alpha1.AgentGPU{ResourceName: "nvidia.com/mig-1g.5gb", Count: 1, RuntimeClassName: "nvidia"}},
+		}
+
+		script := buildKubernetesCloudPodTemplatesGroovyScript(jenkins)
+
+		assert.Contains(t, script, "nvidia.com/mig-1g.5gb: \"1\"")
+		assert.Contains(t, script, "  runtimeClassName: nvidia\n")
+	})
+	t.Run("emptyDir workspace volume", func(t *testing.T) {
+		sizeLimit := resource.MustParse("10Gi")
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:            "maven",
+			WorkspaceVolume: &virtuslabv1alpha1.WorkspaceVolume{Type: virtuslabv1alpha1.WorkspaceVolumeTypeEmptyDir, Memory: true, SizeLimit: &sizeLimit},
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []corev1.Volume{{Name: WorkspaceVolumeName, VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory, SizeLimit: &sizeLimit},
+		}}}, pod.Spec.Volumes)
+	})
+	t.Run("hostPath workspace volume", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:            "maven",
+			WorkspaceVolume: &virtuslabv1alpha1.WorkspaceVolume{Type: virtuslabv1alpha1.WorkspaceVolumeTypeHostPath, Path: "/var/cache/jenkins"},
+		}
+
+		pod, err := NewAgentPod(template)
+
+		assert.NoError(t, err)
+		assert.Len(t, pod.Spec.Volumes, 1)
+		assert.Equal(t, WorkspaceVolumeName, pod.Spec.Volumes[0].Name)
+		assert.Equal(t, "/var/cache/jenkins", pod.Spec.Volumes[0].HostPath.Path)
+		assert.Equal(t, corev1.HostPathDirectoryOrCreate, *pod.Spec.Volumes[0].HostPath.Type)
+	})
+	t.Run("ephemeral workspace volume", func(t *testing.T) {
+		size := resource.MustParse("20Gi")
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "gradle", WorkspaceVolume: &virtuslabv1alpha1.WorkspaceVolume{
+				Type: virtuslabv1alpha1.WorkspaceVolumeTypeEphemeral, StorageClassName: "fast-ssd", Size: &size}},
+		}
+
+		script := buildKubernetesCloudPodTemplatesGroovyScript(jenkins)
+
+		assert.Contains(t, script, `  volumes:
+  - ephemeral:
+      volumeClaimTemplate:
+        spec:
+          accessModes:
+          - ReadWriteOnce
+          resources:
+            requests:
+              storage: 20Gi
+          storageClassName: fast-ssd
+    name: workspace-volume
+`)
+	})
+	t.Run("Kubernetes cloud", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "maven", Label: "maven"},
+			{Name: "node", Label: "node", Image: "jenkins/inbound-agent:4.3-4"},
+		}
+
+		script := buildKubernetesCloudPodTemplatesGroovyScript(jenkins)
+
+		assert.Contains(t, script, "podTemplate0.setName('maven')")
+		assert.Contains(t, script, "podTemplate1.setLabel('node')")
+		assert.Contains(t, script, "image: jenkins/inbound-agent:4.3-4")
+		assert.Contains(t, script, "kubernetes.addTemplate(podTemplate1)")
+		assert.Contains(t, script, "    jenkins-operator/agent-pod-template: maven\n")
+	})
+	t.Run("capacity limits", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.MaxConcurrent = 20
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "maven"},
+			{Name: "gradle", MaxConcurrent: 5},
+		}
+
+		script := buildConfigureKubernetesPluginGroovyScript(jenkins)
+
+		assert.Contains(t, script, "kubernetes.setContainerCap(20)\n")
+		assert.NotContains(t, script, "podTemplate0.setInstanceCap")
+		assert.Contains(t, script, "podTemplate1.setInstanceCap(5)\nkubernetes.addTemplate(podTemplate1)\n")
+		assert.NotContains(t, buildConfigureKubernetesPluginGroovyScript(&virtuslabv1alpha1.Jenkins{}), "setContainerCap")
+	})
+	t.Run("retention", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "maven"},
+			{Name: "gradle", IdleMinutes: 10, ConnectionTimeoutSeconds: 300, PodRetention: virtuslabv1alpha1.PodRetentionOnFailure},
+		}
+
+		script := buildKubernetesCloudPodTemplatesGroovyScript(jenkins)
+
+		assert.NotContains(t, script, "podTemplate0.setIdleMinutes")
+		assert.NotContains(t, script, "podTemplate0.setPodRetention")
+		assert.Contains(t, script, "podTemplate1.setIdleMinutes(10)\n")
+		assert.Contains(t, script, "podTemplate1.setSlaveConnectTimeout(300)\n")
+		assert.Contains(t, script, "podTemplate1.setPodRetention(new org.csanchez.jenkins.plugins.kubernetes.pod.retention.OnFailure())\n")
+	})
+	t.Run("image pull secrets", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}, {Name: "mirror"}}
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "maven", Yaml: "spec:\n  imagePullSecrets:\n  - name: mirror\n  - name: maven-registry\n"},
+		}
+
+		script := buildKubernetesCloudPodTemplatesGroovyScript(jenkins)
+
+		assert.Contains(t, script, "imagePullSecrets:\n  - name: mirror\n  - name: maven-registry\n  - name: registry\n")
+	})
+	t.Run("default image", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.DefaultImage = "registry.example.com/jenkins/inbound-agent:4.3-4"
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "maven"},
+			{Name: "node", Image: "jenkins/inbound-agent:4.3-4"},
+		}
+
+		script := buildKubernetesCloudPodTemplatesGroovyScript(jenkins)
+
+		assert.Contains(t, script, "image: registry.example.com/jenkins/inbound-agent:4.3-4")
+		assert.Contains(t, script, "image: jenkins/inbound-agent:4.3-4")
+		assert.Contains(t, buildJavaOpts(jenkins),
+			"-Dorg.csanchez.jenkins.plugins.kubernetes.pipeline.PodTemplateStepExecution.defaultImage=registry.example.com/jenkins/inbound-agent:4.3-4")
+		assert.NotContains(t, buildJavaOpts(&virtuslabv1alpha1.Jenkins{}), agentDefaultImageProperty)
+	})
+	t.Run("service accounts", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{})
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "maven"},
+			{Name: "kubectl", Permissions: []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"list"}}}},
+			{Name: "custom", Yaml: "spec:\n  serviceAccountName: deployer\n"},
+		}
+
+		script := buildKubernetesCloudPodTemplatesGroovyScript(jenkins)
+
+		assert.Contains(t, script, "serviceAccountName: jenkins-operator-agents-namespace-name-jenkins-cr-name\n")
+		assert.Contains(t, script, "serviceAccountName: jenkins-operator-agents-namespace-name-jenkins-cr-name-kubectl\n")
+		assert.Contains(t, script, "serviceAccountName: deployer\n")
+	})
+}
+
+func TestResolveAgentPodTemplateYaml(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "maven-pod"},
+		Data: map[string]string{AgentPodTemplateYamlConfigMapKey: `spec:
+  containers:
+  - name: maven
+    image: maven:3.6-jdk-11
+    command:
+    - cat
+    tty: true
+  nodeSelector:
+    pool: builds
+`},
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:          "maven",
+			YamlConfigMap: &virtuslabv1alpha1.ConfigMapReference{Name: "maven-pod"},
+			Yaml:          "spec:\n  containers:\n  - name: maven\n    image: maven:3.6-jdk-8\n",
+		}
+
+		resolved, err := ResolveAgentPodTemplateYaml(template, configMap)
+		assert.NoError(t, err)
+		pod, err := NewAgentPod(resolved)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "maven:3.6-jdk-8", pod.Spec.Containers[0].Image)
+		assert.Equal(t, []string{"cat"}, pod.Spec.Containers[0].Command)
+		assert.Equal(t, map[string]string{"pool": "builds"}, pod.Spec.NodeSelector)
+		assert.Equal(t, "spec:\n  containers:\n  - name: maven\n    image: maven:3.6-jdk-8\n", template.Yaml)
+	})
+	t.Run("override", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{
+			Name:              "maven",
+			YamlConfigMap:     &virtuslabv1alpha1.ConfigMapReference{Name: "maven-pod"},
+			YamlMergeStrategy: virtuslabv1alpha1.YamlMergeStrategyOverride,
+		}
+
+		resolved, err := ResolveAgentPodTemplateYaml(template, configMap)
+
+		assert.NoError(t, err)
+		assert.Equal(t, configMap.Data[AgentPodTemplateYamlConfigMapKey], resolved.Yaml)
+	})
+	t.Run("missing key", func(t *testing.T) {
+		template := virtuslabv1alpha1.AgentPodTemplate{Name: "maven", YamlConfigMap: &virtuslabv1alpha1.ConfigMapReference{Name: "maven-pod"}}
+
+		_, err := ResolveAgentPodTemplateYaml(template, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "maven-pod"}})
+
+		assert.Error(t, err)
+	})
+}
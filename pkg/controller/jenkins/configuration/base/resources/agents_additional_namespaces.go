@@ -0,0 +1,78 @@
+package resources
+
+import (
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+// additionalNamespaceCloudNamePrefix is the prefix of Kubernetes clouds of Jenkins.Spec.Agents.AdditionalNamespaces
+const additionalNamespaceCloudNamePrefix = "kubernetes-"
+
+// getAdditionalNamespaceCloudName returns name of Kubernetes cloud running agent pods in the additional namespace
+func getAdditionalNamespaceCloudName(namespace string) string {
+	return additionalNamespaceCloudNamePrefix + namespace
+}
+
+// additionalNamespacesCloudsTemplate copies the 'kubernetes' cloud configured before into every additional namespace,
+// the clouds created by the operator use the operator managed credentials so clouds configured in Jenkins UI are
+// left untouched
+var additionalNamespacesCloudsTemplate = template.Must(template.New("additional-namespaces-clouds").Parse(`
+def additionalNamespaces = [{{ range .Namespaces }}
+        [cloudName: '{{ .CloudName }}', namespace: '{{ .Namespace }}', jenkinsUrl: '{{ .JenkinsURL }}', maxConcurrent: {{ .MaxConcurrent }}],{{ end }}
+]
+
+jenkins.clouds.findAll { cloud ->
+    cloud instanceof KubernetesCloud && cloud.name.startsWith('{{ .CloudNamePrefix }}') && cloud.credentialsId == kubernetesCredentialsId
+}.each { cloud ->
+    jenkins.clouds.remove(cloud)
+}
+additionalNamespaces.each { additional ->
+    def cloud = new KubernetesCloud(additional.cloudName, kubernetes)
+    cloud.setNamespace(additional.namespace)
+    cloud.setJenkinsUrl(additional.jenkinsUrl)
+    cloud.setContainerCap(additional.maxConcurrent > 0 ? additional.maxConcurrent : Integer.MAX_VALUE)
+    // templates are selected by labels prefixed with the namespace, so jobs choose where their agents run
+    cloud.setTemplates(kubernetes.templates.collect { podTemplate ->
+        def copy = new org.csanchez.jenkins.plugins.kubernetes.PodTemplate(podTemplate)
+        if (podTemplate.label) {
+            copy.setLabel(podTemplate.label.trim().split(/\s+/).collect { "${additional.namespace}-${it}" }.join(' '))
+        }
+        copy
+    })
+    jenkins.clouds.add(cloud)
+}
+`))
+
+// buildAdditionalNamespacesCloudsGroovyScript returns groovy statements adding Kubernetes cloud with the agent pod
+// templates for every Jenkins.Spec.Agents.AdditionalNamespaces, the statements are rendered even without additional
+// namespaces to remove clouds of the deleted ones
+func buildAdditionalNamespacesCloudsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	type namespaceData struct {
+		CloudName     string
+		Namespace     string
+		JenkinsURL    string
+		MaxConcurrent int
+	}
+	var namespaces []namespaceData
+	for _, additional := range jenkins.Spec.Agents.AdditionalNamespaces {
+		namespaces = append(namespaces, namespaceData{
+			CloudName:     escapeGroovyString(getAdditionalNamespaceCloudName(additional.Name)),
+			Namespace:     escapeGroovyString(additional.Name),
+			JenkinsURL:    escapeGroovyString(getKubernetesCloudJenkinsURLForNamespace(jenkins, additional.Name)),
+			MaxConcurrent: additional.MaxConcurrent,
+		})
+	}
+
+	data := struct {
+		CloudNamePrefix string
+		Namespaces      []namespaceData
+	}{
+		CloudNamePrefix: additionalNamespaceCloudNamePrefix,
+		Namespaces:      namespaces,
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(additionalNamespacesCloudsTemplate, data)
+	return output
+}
@@ -0,0 +1,123 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AgentsClusterKubeconfigSecretKey is the agents cluster Secret key with kubeconfig
+	AgentsClusterKubeconfigSecretKey = "kubeconfig"
+
+	// agentsClusterCredentialsIDPrefix is the prefix of Jenkins credentials with kubeconfig of the agents cluster, it
+	// marks the clouds managed by the operator
+	agentsClusterCredentialsIDPrefix = "agents-cluster-"
+)
+
+// getAgentsClusterEnvName returns name of Jenkins master container environment variable with kubeconfig of the agents
+// cluster, cluster names are DNS-1123 labels so they only need upper case and underscores
+func getAgentsClusterEnvName(cluster virtuslabv1alpha1.AgentsCluster) string {
+	return fmt.Sprintf("JENKINS_AGENTS_CLUSTER_%s_KUBECONFIG", strings.ToUpper(strings.Replace(cluster.Name, "-", "_", -1)))
+}
+
+// buildAgentsClustersEnvVars returns Jenkins master container environment variables with kubeconfig of
+// Jenkins.Spec.Agents.Clusters referenced from the Secrets
+func buildAgentsClustersEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+	for _, cluster := range jenkins.Spec.Agents.Clusters {
+		envs = append(envs, buildSecretKeyEnvVar(getAgentsClusterEnvName(cluster), cluster.KubeconfigSecretName, AgentsClusterKubeconfigSecretKey))
+	}
+	return envs
+}
+
+var agentsClustersCloudsTemplate = template.Must(template.New("agents-clusters-clouds").Parse(`
+def agentsClusterCredentialsIdPrefix = '{{ .CredentialsIDPrefix }}'
+def agentsClusterNames = [{{ range .Clusters }}'{{ .Name }}', {{ end }}]
+def credentialsStore = SystemCredentialsProvider.getInstance().getStore()
+jenkins.clouds.findAll { cloud ->
+    cloud instanceof KubernetesCloud && cloud.credentialsId?.startsWith(agentsClusterCredentialsIdPrefix) && !agentsClusterNames.contains(cloud.name)
+}.each { cloud ->
+    jenkins.clouds.remove(cloud)
+}
+credentialsStore.getCredentials(Domain.global()).findAll { credentials ->
+    credentials.id.startsWith(agentsClusterCredentialsIdPrefix) &&
+            !agentsClusterNames.contains(credentials.id.substring(agentsClusterCredentialsIdPrefix.length()))
+}.each { credentials ->
+    credentialsStore.removeCredentials(Domain.global(), credentials)
+}
+{{ range $i, $cluster := .Clusters }}
+def cluster{{ $i }}Credentials = new org.jenkinsci.plugins.plaincredentials.impl.FileCredentialsImpl(CredentialsScope.GLOBAL,
+        agentsClusterCredentialsIdPrefix + '{{ .Name }}', 'Kubeconfig of agents cluster {{ .Name }}', 'kubeconfig',
+        com.cloudbees.plugins.credentials.SecretBytes.fromBytes(System.getenv('{{ .KubeconfigEnvName }}').getBytes('UTF-8')))
+def cluster{{ $i }}CurrentCredentials = credentialsStore.getCredentials(Domain.global()).find { it.id == cluster{{ $i }}Credentials.id }
+if (cluster{{ $i }}CurrentCredentials == null) {
+    credentialsStore.addCredentials(Domain.global(), cluster{{ $i }}Credentials)
+} else {
+    credentialsStore.updateCredentials(Domain.global(), cluster{{ $i }}CurrentCredentials, cluster{{ $i }}Credentials)
+}
+
+KubernetesCloud cluster{{ $i }} = new KubernetesCloud('{{ .Name }}')
+cluster{{ $i }}.setNamespace('{{ .Namespace }}')
+cluster{{ $i }}.setCredentialsId(cluster{{ $i }}Credentials.id)
+cluster{{ $i }}.setJenkinsUrl('{{ .JenkinsURL }}')
+{{ if .JenkinsTunnel }}cluster{{ $i }}.setJenkinsTunnel('{{ .JenkinsTunnel }}')
+{{ end }}cluster{{ $i }}.setRetentionTimeout(15)
+{{ .CloudSettings }}jenkins.clouds.remove(jenkins.clouds.getByName('{{ .Name }}'))
+jenkins.clouds.add(cluster{{ $i }})
+{{ end }}`))
+
+// buildAgentsClustersCloudsGroovyScript returns groovy statements adding Kubernetes cloud with the agent pod templates
+// for every Jenkins.Spec.Agents.Clusters, the statements are rendered even without clusters to remove clouds and
+// credentials of the deleted ones
+func buildAgentsClustersCloudsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	type clusterData struct {
+		Name              string
+		Namespace         string
+		JenkinsURL        string
+		JenkinsTunnel     string
+		KubeconfigEnvName string
+		CloudSettings     string
+	}
+	var clusters []clusterData
+	for i, cluster := range jenkins.Spec.Agents.Clusters {
+		cloudVariable := fmt.Sprintf("cluster%d", i)
+		cloudSettings := ""
+		if jenkins.Spec.Master.Remoting.WebSocket {
+			cloudSettings += fmt.Sprintf("%s.setWebSocket(true)\n", cloudVariable)
+		}
+		if cluster.MaxConcurrent > 0 {
+			cloudSettings += fmt.Sprintf("%s.setContainerCap(%d)\n", cloudVariable, cluster.MaxConcurrent)
+		}
+		serviceAccountName := cluster.ServiceAccountName
+		cloudSettings += buildPodTemplatesGroovyScript(jenkins, cloudVariable, cloudVariable+"PodTemplate", cluster.Name+"-",
+			func(virtuslabv1alpha1.AgentPodTemplate) string {
+				return serviceAccountName
+			})
+
+		clusters = append(clusters, clusterData{
+			Name:              escapeGroovyString(cluster.Name),
+			Namespace:         escapeGroovyString(cluster.Namespace),
+			JenkinsURL:        escapeGroovyString(cluster.JenkinsURL),
+			JenkinsTunnel:     escapeGroovyString(cluster.JenkinsTunnel),
+			KubeconfigEnvName: getAgentsClusterEnvName(cluster),
+			CloudSettings:     cloudSettings,
+		})
+	}
+
+	data := struct {
+		CredentialsIDPrefix string
+		Clusters            []clusterData
+	}{
+		CredentialsIDPrefix: agentsClusterCredentialsIDPrefix,
+		Clusters:            clusters,
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(agentsClustersCloudsTemplate, data)
+	return output
+}
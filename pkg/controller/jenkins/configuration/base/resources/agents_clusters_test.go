@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildAgentsClustersCloudsGroovyScript(t *testing.T) {
+	t.Run("clusters", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{{Name: "maven", Label: "maven jdk11", MaxConcurrent: 2}}
+		jenkins.Spec.Agents.Clusters = []virtuslabv1alpha1.AgentsCluster{
+			{Name: "builds", KubeconfigSecretName: "builds-kubeconfig", Namespace: "jenkins-agents", JenkinsURL: "https://jenkins.example.com",
+				JenkinsTunnel: "jenkins-agents.example.com:50000", MaxConcurrent: 10, ServiceAccountName: "agent"},
+		}
+
+		script := buildAgentsClustersCloudsGroovyScript(jenkins)
+
+		assert.Contains(t, script, "def agentsClusterNames = ['builds', ]")
+		assert.Contains(t, script, "System.getenv('JENKINS_AGENTS_CLUSTER_BUILDS_KUBECONFIG')")
+		assert.Contains(t, script, "KubernetesCloud cluster0 = new KubernetesCloud('builds')")
+		assert.Contains(t, script, "cluster0.setNamespace('jenkins-agents')")
+		assert.Contains(t, script, "cluster0.setJenkinsUrl('https://jenkins.example.com')")
+		assert.Contains(t, script, "cluster0.setJenkinsTunnel('jenkins-agents.example.com:50000')")
+		assert.Contains(t, script, "cluster0.setContainerCap(10)")
+		assert.Contains(t, script, "cluster0PodTemplate0.setLabel('builds-maven builds-jdk11')")
+		assert.Contains(t, script, "serviceAccountName: agent")
+		assert.Contains(t, script, "cluster0PodTemplate0.setInstanceCap(2)\ncluster0.addTemplate(cluster0PodTemplate0)\n")
+		assert.Contains(t, buildConfigureKubernetesPluginGroovyScript(jenkins), "jenkins.clouds.add(cluster0)")
+	})
+	t.Run("no clusters", func(t *testing.T) {
+		script := buildAgentsClustersCloudsGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		// clouds and credentials of the removed clusters are still deleted
+		assert.Contains(t, script, "def agentsClusterNames = []")
+		assert.Contains(t, script, "credentialsStore.removeCredentials(Domain.global(), credentials)")
+		assert.NotContains(t, script, "new KubernetesCloud")
+	})
+	t.Run("env vars", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Clusters = []virtuslabv1alpha1.AgentsCluster{{Name: "gpu-builds", KubeconfigSecretName: "gpu-kubeconfig"}}
+
+		envs := buildAgentsClustersEnvVars(jenkins)
+
+		assert.Len(t, envs, 1)
+		assert.Equal(t, "JENKINS_AGENTS_CLUSTER_GPU_BUILDS_KUBECONFIG", envs[0].Name)
+		assert.Equal(t, "gpu-kubeconfig", envs[0].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, AgentsClusterKubeconfigSecretKey, envs[0].ValueFrom.SecretKeyRef.Key)
+	})
+}
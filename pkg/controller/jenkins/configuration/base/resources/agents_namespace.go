@@ -0,0 +1,126 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// namespaceNameLabelKey is the label set by Kubernetes on every namespace to its name
+const namespaceNameLabelKey = "kubernetes.io/metadata.name"
+
+// GetAgentsNamespace returns the namespace of agent pods, Jenkins namespace by default
+func GetAgentsNamespace(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if namespace := jenkins.Spec.Agents.Namespace; len(namespace) > 0 {
+		return namespace
+	}
+	return jenkins.ObjectMeta.Namespace
+}
+
+// IsAgentsNamespaceSeparate returns true if agent pods run outside of Jenkins namespace
+func IsAgentsNamespaceSeparate(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return GetAgentsNamespace(jenkins) != jenkins.ObjectMeta.Namespace
+}
+
+// GetAllAgentsNamespaces returns the agents namespace followed by Jenkins.Spec.Agents.AdditionalNamespaces
+func GetAllAgentsNamespaces(jenkins *virtuslabv1alpha1.Jenkins) []string {
+	namespaces := []string{GetAgentsNamespace(jenkins)}
+	for _, additional := range jenkins.Spec.Agents.AdditionalNamespaces {
+		namespaces = append(namespaces, additional.Name)
+	}
+	return namespaces
+}
+
+// BuildAgentPodLabels returns labels of agent pods of the Kubernetes clouds, they identify the Jenkins instance
+// the pods belong to
+func BuildAgentPodLabels(jenkins *virtuslabv1alpha1.Jenkins) map[string]string {
+	return map[string]string{
+		constants.LabelAgentJenkinsCRKey:        jenkins.ObjectMeta.Name,
+		constants.LabelAgentJenkinsNamespaceKey: jenkins.ObjectMeta.Namespace,
+	}
+}
+
+// buildKubernetesCloudPodLabelsGroovyScript returns groovy statement adding BuildAgentPodLabels to the labels of all
+// pods of the Kubernetes cloud, including pod templates defined by pipelines
+func buildKubernetesCloudPodLabelsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	labels := BuildAgentPodLabels(jenkins)
+	var keys []string
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var podLabels []string
+	for _, key := range keys {
+		podLabels = append(podLabels, fmt.Sprintf("new org.csanchez.jenkins.plugins.kubernetes.PodLabel('%s', '%s')",
+			escapeGroovyString(key), escapeGroovyString(labels[key])))
+	}
+	return fmt.Sprintf("kubernetes.setPodLabels(kubernetes.getPodLabels() + [%s])\n", strings.Join(podLabels, ", "))
+}
+
+// GetAgentsResourceName returns name of the shared agents ServiceAccount and Jenkins master Role and RoleBinding in
+// the agents namespace, it contains Jenkins namespace because Jenkins instances from different namespaces can share
+// the agents namespace
+func GetAgentsResourceName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-agents-%s-%s", constants.OperatorName, jenkins.ObjectMeta.Namespace, jenkins.ObjectMeta.Name)
+}
+
+// NewAgentsObjectMeta builds ObjectMeta of resources in the agents namespace, they have no owner in the separate
+// agents namespace because owner references can't point to other namespaces
+func NewAgentsObjectMeta(jenkins *virtuslabv1alpha1.Jenkins) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      GetAgentsResourceName(jenkins),
+		Namespace: GetAgentsNamespace(jenkins),
+		Labels:    BuildResourceLabels(jenkins),
+	}
+}
+
+// NewAgentsServiceAccount returns the service account shared by agent pod templates without permissions, builds don't
+// get Kubernetes API token
+func NewAgentsServiceAccount(meta metav1.ObjectMeta) *corev1.ServiceAccount {
+	serviceAccount := NewServiceAccount(meta)
+	automountServiceAccountToken := false
+	serviceAccount.AutomountServiceAccountToken = &automountServiceAccountToken
+	return serviceAccount
+}
+
+// NewAgentsRole returns rbac role for jenkins master in the agents namespace, it grants the same permissions as
+// the role in Jenkins namespace
+func NewAgentsRole(meta metav1.ObjectMeta) *rbacv1.Role {
+	return NewRole(meta)
+}
+
+// NewAgentsRoleBinding returns rbac role binding of jenkins master service account to the role in the agents namespace
+func NewAgentsRoleBinding(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *rbacv1.RoleBinding {
+	roleBinding := NewRoleBinding(meta)
+	roleBinding.Subjects = []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			Name:      GetResourceName(jenkins),
+			Namespace: jenkins.ObjectMeta.Namespace,
+		},
+	}
+	return roleBinding
+}
+
+// getKubernetesCloudJenkinsURL returns the URL used by agents to connect to Jenkins, agents in the separate namespace
+// need namespace qualified Service name
+func getKubernetesCloudJenkinsURL(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return getKubernetesCloudJenkinsURLForNamespace(jenkins, GetAgentsNamespace(jenkins))
+}
+
+// getKubernetesCloudJenkinsURLForNamespace returns the URL used by agents running in the namespace to connect to Jenkins
+func getKubernetesCloudJenkinsURLForNamespace(jenkins *virtuslabv1alpha1.Jenkins, namespace string) string {
+	host := GetResourceName(jenkins)
+	if namespace != jenkins.ObjectMeta.Namespace {
+		host = fmt.Sprintf("%s.%s", host, jenkins.ObjectMeta.Namespace)
+	}
+	return fmt.Sprintf("http://%s:%d%s", host, HTTPPortInt, GetJenkinsPrefix(jenkins))
+}
@@ -0,0 +1,94 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentsNamespace(t *testing.T) {
+	agentsSpec := func(agentsNamespace string) virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Agents: virtuslabv1alpha1.Agents{
+				Namespace: agentsNamespace,
+				Templates: []virtuslabv1alpha1.AgentPodTemplate{{Name: "maven", Label: "maven"}},
+			},
+			NetworkPolicy: &virtuslabv1alpha1.NetworkPolicy{},
+		}
+	}
+
+	t.Run("Jenkins namespace", func(t *testing.T) {
+		for _, agentsNamespace := range []string{"", "namespace-name"} {
+			jenkins := newJenkins(agentsSpec(agentsNamespace))
+
+			assert.False(t, IsAgentsNamespaceSeparate(jenkins))
+			script := buildConfigureKubernetesPluginGroovyScript(jenkins)
+			assert.Contains(t, script, `kubernetes.setNamespace("namespace-name")`)
+			assert.Contains(t, script, `kubernetes.setJenkinsUrl("http://jenkins-operator-jenkins-cr-name:8080")`)
+			assert.Contains(t, script, "serviceAccountName: jenkins-operator-agents-namespace-name-jenkins-cr-name")
+			networkPolicy := NewNetworkPolicy(NewResourceObjectMeta(jenkins), jenkins)
+			assert.Nil(t, networkPolicy.Spec.Ingress[1].From[0].NamespaceSelector)
+		}
+	})
+	t.Run("separate namespace", func(t *testing.T) {
+		jenkins := newJenkins(agentsSpec("builds"))
+
+		assert.True(t, IsAgentsNamespaceSeparate(jenkins))
+		script := buildConfigureKubernetesPluginGroovyScript(jenkins)
+		assert.Contains(t, script, `kubernetes.setNamespace("builds")`)
+		assert.Contains(t, script, `kubernetes.setJenkinsUrl("http://jenkins-operator-jenkins-cr-name.namespace-name:8080")`)
+		assert.Contains(t, script, "serviceAccountName: jenkins-operator-agents-namespace-name-jenkins-cr-name")
+		networkPolicy := NewNetworkPolicy(NewResourceObjectMeta(jenkins), jenkins)
+		agentPeer := networkPolicy.Spec.Ingress[1].From[0]
+		assert.Equal(t, map[string]string{"kubernetes.io/metadata.name": "builds"}, agentPeer.NamespaceSelector.MatchLabels)
+		assert.Equal(t, map[string]string{"jenkins": "slave"}, agentPeer.PodSelector.MatchLabels)
+	})
+	t.Run("additional namespaces", func(t *testing.T) {
+		jenkins := newJenkins(agentsSpec(""))
+		jenkins.Spec.Agents.AdditionalNamespaces = []virtuslabv1alpha1.AgentsNamespace{{Name: "team-a", MaxConcurrent: 5}, {Name: "team-b"}}
+
+		assert.Equal(t, []string{"namespace-name", "team-a", "team-b"}, GetAllAgentsNamespaces(jenkins))
+		script := buildConfigureKubernetesPluginGroovyScript(jenkins)
+		assert.Contains(t, script, `kubernetes.setNamespace("namespace-name")`)
+		assert.Contains(t, script, `[cloudName: 'kubernetes-team-a', namespace: 'team-a', jenkinsUrl: 'http://jenkins-operator-jenkins-cr-name.namespace-name:8080', maxConcurrent: 5],`)
+		assert.Contains(t, script, `[cloudName: 'kubernetes-team-b', namespace: 'team-b'`)
+		networkPolicy := NewNetworkPolicy(NewResourceObjectMeta(jenkins), jenkins)
+		agentPeers := networkPolicy.Spec.Ingress[1].From
+		assert.Len(t, agentPeers, 3)
+		assert.Nil(t, agentPeers[0].NamespaceSelector)
+		assert.Equal(t, map[string]string{"kubernetes.io/metadata.name": "team-b"}, agentPeers[2].NamespaceSelector.MatchLabels)
+	})
+	t.Run("no additional namespaces", func(t *testing.T) {
+		script := buildConfigureKubernetesPluginGroovyScript(newJenkins(agentsSpec("")))
+
+		// clouds of the removed namespaces are still deleted
+		assert.Contains(t, script, "def additionalNamespaces = [\n]")
+		assert.Contains(t, script, "jenkins.clouds.remove(cloud)")
+	})
+	t.Run("RBAC", func(t *testing.T) {
+		jenkins := newJenkins(agentsSpec("builds"))
+		meta := NewAgentsObjectMeta(jenkins)
+
+		serviceAccount := NewAgentsServiceAccount(meta)
+		roleBinding := NewAgentsRoleBinding(meta, jenkins)
+
+		assert.Equal(t, "builds", meta.Namespace)
+		assert.Empty(t, meta.OwnerReferences)
+		assert.False(t, *serviceAccount.AutomountServiceAccountToken)
+		assert.Equal(t, meta.Name, roleBinding.RoleRef.Name)
+		assert.Equal(t, "jenkins-operator-jenkins-cr-name", roleBinding.Subjects[0].Name)
+		assert.Equal(t, "namespace-name", roleBinding.Subjects[0].Namespace)
+	})
+}
+
+func TestBuildKubernetesCloudPodLabelsGroovyScript(t *testing.T) {
+	jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{})
+
+	script := buildConfigureKubernetesPluginGroovyScript(jenkins)
+
+	assert.Contains(t, script, "kubernetes.setPodLabels(kubernetes.getPodLabels() + ["+
+		"new org.csanchez.jenkins.plugins.kubernetes.PodLabel('jenkins-operator/agent-jenkins-cr', 'jenkins-cr-name'), "+
+		"new org.csanchez.jenkins.plugins.kubernetes.PodLabel('jenkins-operator/agent-jenkins-namespace', 'namespace-name')])\n")
+}
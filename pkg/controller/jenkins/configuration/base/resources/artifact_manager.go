@@ -0,0 +1,87 @@
+package resources
+
+import (
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ArtifactManagerS3PluginName is the name of plugin required by Jenkins.Spec.ArtifactManager.AmazonS3
+	ArtifactManagerS3PluginName = "artifact-manager-s3"
+	// ArtifactManagerS3AccessKeyIDSecretKey is the artifact manager Secret key with AWS access key ID
+	ArtifactManagerS3AccessKeyIDSecretKey = "accessKeyId"
+	// ArtifactManagerS3SecretAccessKeySecretKey is the artifact manager Secret key with AWS secret access key
+	ArtifactManagerS3SecretAccessKeySecretKey = "secretAccessKey"
+
+	// environment variables read by the AWS SDK default credentials and region provider chains
+	awsRegionEnvName          = "AWS_REGION"
+	awsAccessKeyIDEnvName     = "AWS_ACCESS_KEY_ID"
+	awsSecretAccessKeyEnvName = "AWS_SECRET_ACCESS_KEY"
+)
+
+// buildArtifactManagerEnvVars returns Jenkins master container environment variables with AWS region and credentials
+// used by artifact-manager-s3 plugin, the credentials are referenced from the Secret
+func buildArtifactManagerEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	if jenkins.Spec.ArtifactManager == nil || jenkins.Spec.ArtifactManager.AmazonS3 == nil {
+		return nil
+	}
+
+	s3 := jenkins.Spec.ArtifactManager.AmazonS3
+	var envs []corev1.EnvVar
+	if len(s3.Region) > 0 {
+		envs = append(envs, corev1.EnvVar{Name: awsRegionEnvName, Value: s3.Region})
+	}
+	if len(s3.CredentialsSecretName) > 0 {
+		envs = append(envs,
+			buildSecretKeyEnvVar(awsAccessKeyIDEnvName, s3.CredentialsSecretName, ArtifactManagerS3AccessKeyIDSecretKey),
+			buildSecretKeyEnvVar(awsSecretAccessKeyEnvName, s3.CredentialsSecretName, ArtifactManagerS3SecretAccessKeySecretKey))
+	}
+
+	return envs
+}
+
+var configureArtifactManagerTemplate = template.Must(template.New("configure-artifact-manager").Parse(`
+import jenkins.model.ArtifactManagerConfiguration
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+// artifact manager classes are loaded dynamically because the plugin is optional
+def classLoader = jenkins.pluginManager.uberClassLoader
+
+def config = classLoader.loadClass('io.jenkins.plugins.artifact_manager_jclouds.s3.S3BlobStoreConfig').get()
+config.setContainer('{{ .BucketName }}')
+config.setPrefix('{{ .Prefix }}')
+config.save()
+
+def provider = classLoader.loadClass('io.jenkins.plugins.artifact_manager_jclouds.s3.S3BlobStore').newInstance()
+// fail before artifacts are redirected to the bucket which isn't accessible
+if (!provider.getContext().getBlobStore().containerExists('{{ .BucketName }}')) {
+    throw new IllegalStateException("S3 bucket '{{ .BucketName }}' doesn't exist or isn't accessible")
+}
+
+def factory = classLoader.loadClass('io.jenkins.plugins.artifact_manager_jclouds.JCloudsArtifactManagerFactory').newInstance(provider)
+ArtifactManagerConfiguration.get().getArtifactManagerFactories().replace(factory)
+`))
+
+// buildConfigureArtifactManagerGroovyScript renders groovy script which configures and verifies AWS S3 artifact manager
+// from Jenkins.Spec.ArtifactManager
+func buildConfigureArtifactManagerGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if jenkins.Spec.ArtifactManager == nil || jenkins.Spec.ArtifactManager.AmazonS3 == nil {
+		return ""
+	}
+
+	data := struct {
+		BucketName string
+		Prefix     string
+	}{
+		BucketName: escapeGroovyString(jenkins.Spec.ArtifactManager.AmazonS3.BucketName),
+		Prefix:     escapeGroovyString(jenkins.Spec.ArtifactManager.AmazonS3.Prefix),
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureArtifactManagerTemplate, data)
+	return output
+}
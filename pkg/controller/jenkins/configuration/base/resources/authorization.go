@@ -0,0 +1,146 @@
+package resources
+
+import (
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const (
+	// MatrixAuthPluginName is the name of plugin required by Jenkins.Spec.Security.Authorization.Matrix
+	MatrixAuthPluginName = "matrix-auth"
+	// RoleStrategyPluginName is the name of plugin required by Jenkins.Spec.Security.Authorization.RoleBased
+	RoleStrategyPluginName = "role-strategy"
+	// OperatorRoleName is the name of global role assigned to the operator user by role-based authorization
+	OperatorRoleName = "jenkins-operator"
+)
+
+// groovyRole is a role with names and patterns rendered as groovy literals
+type groovyRole struct {
+	Name        string
+	Pattern     string
+	Permissions string
+	Assignments string
+}
+
+var configureAuthorizationTemplate = template.Must(template.New("configure-authorization").Parse(`
+import hudson.security.Permission
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+// authorization strategy classes are loaded dynamically because the plugins are optional
+def classLoader = jenkins.pluginManager.uberClassLoader
+def operatorUserName = new File('{{ .OperatorCredentialsPath }}/{{ .OperatorUserNameFile }}').text
+
+// permissions are referenced by 'group/name' like in Jenkins UI and configuration as code plugin
+def permissions = Permission.getAll().collectEntries { [("${it.group.title}/${it.name}".toString()): it] }
+def findPermission = { String name ->
+    def permission = permissions[name]
+    if (permission == null) {
+        throw new IllegalArgumentException("Unknown permission '${name}'")
+    }
+    return permission
+}
+{{ if .MatrixStrategyClass }}
+def strategy = classLoader.loadClass('{{ .MatrixStrategyClass }}').newInstance()
+def grant = { String sid, String permissionName ->
+    strategy.add(findPermission(permissionName), sid)
+}
+
+// the operator user has to stay administrator, otherwise it would be locked out
+grant(operatorUserName, 'Overall/Administer')
+{{- range .Grants }}
+{{- $sid := .Sid }}
+{{- range .Permissions }}
+grant('{{ $sid }}', '{{ . }}')
+{{- end }}
+{{- end }}
+{{ else }}
+def strategyClass = classLoader.loadClass('com.michelin.cio.hudson.plugins.rolestrategy.RoleBasedAuthorizationStrategy')
+def roleClass = classLoader.loadClass('com.michelin.cio.hudson.plugins.rolestrategy.Role')
+def strategy = strategyClass.newInstance()
+def addRole = { String type, String name, String pattern, List<String> permissionNames, List<String> sids ->
+    def role = roleClass.newInstance(name, pattern, permissionNames.collect { findPermission(it) } as Set)
+    strategy.addRole(type, role)
+    sids.each { strategy.assignRole(type, role, it) }
+}
+
+// the operator user has to stay administrator, otherwise it would be locked out
+addRole(strategyClass.GLOBAL, '{{ .OperatorRoleName }}', '.*', ['Overall/Administer'], [operatorUserName])
+{{- range .GlobalRoles }}
+addRole(strategyClass.GLOBAL, '{{ .Name }}', '.*', {{ .Permissions }}, {{ .Assignments }})
+{{- end }}
+{{- range .ItemRoles }}
+addRole(strategyClass.PROJECT, '{{ .Name }}', '{{ .Pattern }}', {{ .Permissions }}, {{ .Assignments }})
+{{- end }}
+{{ end }}
+jenkins.setAuthorizationStrategy(strategy)
+jenkins.save()
+`))
+
+// buildConfigureAuthorizationGroovyScript renders groovy script which sets Jenkins authorization strategy
+// from Jenkins.Spec.Security.Authorization
+func buildConfigureAuthorizationGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	authorization := jenkins.Spec.Security.Authorization
+	if authorization == nil || (authorization.Matrix == nil && authorization.RoleBased == nil) {
+		return ""
+	}
+
+	data := struct {
+		OperatorCredentialsPath string
+		OperatorUserNameFile    string
+		MatrixStrategyClass     string
+		Grants                  []virtuslabv1alpha1.MatrixGrant
+		OperatorRoleName        string
+		GlobalRoles             []groovyRole
+		ItemRoles               []groovyRole
+	}{
+		OperatorCredentialsPath: jenkinsOperatorCredentialsVolumePath,
+		OperatorUserNameFile:    OperatorCredentialsSecretUserNameKey,
+		OperatorRoleName:        OperatorRoleName,
+	}
+
+	if authorization.Matrix != nil {
+		data.MatrixStrategyClass = "hudson.security.GlobalMatrixAuthorizationStrategy"
+		if authorization.Matrix.ProjectBased {
+			data.MatrixStrategyClass = "hudson.security.ProjectMatrixAuthorizationStrategy"
+		}
+		for _, grant := range authorization.Matrix.Grants {
+			escaped := virtuslabv1alpha1.MatrixGrant{Sid: escapeGroovyString(grant.Sid)}
+			for _, permission := range grant.Permissions {
+				escaped.Permissions = append(escaped.Permissions, escapeGroovyString(permission))
+			}
+			data.Grants = append(data.Grants, escaped)
+		}
+	} else {
+		data.GlobalRoles = buildGroovyRoles(authorization.RoleBased.GlobalRoles)
+		data.ItemRoles = buildGroovyRoles(authorization.RoleBased.ItemRoles)
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureAuthorizationTemplate, data)
+	return output
+}
+
+func buildGroovyRoles(roles []virtuslabv1alpha1.Role) []groovyRole {
+	var groovyRoles []groovyRole
+	for _, role := range roles {
+		groovyRoles = append(groovyRoles, groovyRole{
+			Name:        escapeGroovyString(role.Name),
+			Pattern:     escapeGroovyString(role.Pattern),
+			Permissions: buildGroovyStringList(role.Permissions),
+			Assignments: buildGroovyStringList(role.Assignments),
+		})
+	}
+	return groovyRoles
+}
+
+// buildGroovyStringList returns groovy list literal of single-quoted strings
+func buildGroovyStringList(values []string) string {
+	var quoted []string
+	for _, value := range values {
+		quoted = append(quoted, "'"+escapeGroovyString(value)+"'")
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
@@ -16,22 +16,26 @@ import jenkins.model.JenkinsLocationConfiguration
 import hudson.model.Node.Mode
 
 def jenkins = Jenkins.instance
-//Number of jobs that run simultaneously on master, currently only backup and SeedJob.
+//Number of jobs that run simultaneously on master, operator jobs like backup and SeedJob require at least one.
 jenkins.setNumExecutors(%d)
-//Jobs must specify that they want to run on master
-jenkins.setMode(Mode.EXCLUSIVE)
+//Jobs must specify that they want to run on master in EXCLUSIVE mode
+jenkins.setMode(Mode.%s)
 jenkins.save()
 
 `
 
-const enableCSRF = `
+const enableCSRFFmt = `
 import hudson.security.csrf.DefaultCrumbIssuer
 import jenkins.model.Jenkins
 
 def jenkins = Jenkins.instance
+def enforce = %t
+def excludeClientIPFromCrumb = %t
 
-if (jenkins.getCrumbIssuer() == null) {
-    jenkins.setCrumbIssuer(new DefaultCrumbIssuer(true))
+def crumbIssuer = jenkins.getCrumbIssuer()
+if (crumbIssuer == null || (enforce && !(crumbIssuer instanceof DefaultCrumbIssuer &&
+        crumbIssuer.isExcludeClientIPFromCrumb() == excludeClientIPFromCrumb))) {
+    jenkins.setCrumbIssuer(new DefaultCrumbIssuer(excludeClientIPFromCrumb))
     jenkins.save()
     println('CSRF Protection enabled.')
 } else {
@@ -39,17 +43,18 @@ if (jenkins.getCrumbIssuer() == null) {
 }
 `
 
-const disableUsageStats = `
+const configureUsageStatsFmt = `
 import jenkins.model.Jenkins
 
 def jenkins = Jenkins.instance
+def collected = %t
 
-if (jenkins.isUsageStatisticsCollected()) {
-    jenkins.setNoUsageStatistics(true)
+if (jenkins.isUsageStatisticsCollected() != collected) {
+    jenkins.setNoUsageStatistics(!collected)
     jenkins.save()
-    println('Jenkins usage stats submitting disabled.')
+    println(collected ? 'Jenkins usage stats submitting enabled.' : 'Jenkins usage stats submitting disabled.')
 } else {
-    println('Nothing changed.  Usage stats are not submitted to the Jenkins project.')
+    println('Nothing changed.  Usage stats submitting is already configured.')
 }
 `
 
@@ -65,42 +70,6 @@ jenkins.getInjector()
 jenkins.save()
 `
 
-const disableInsecureFeatures = `
-import jenkins.*
-import jenkins.model.*
-import hudson.model.*
-import jenkins.security.s2m.*
-
-def jenkins = Jenkins.instance
-
-println("Disabling insecure Jenkins features...")
-
-println("Disabling insecure protocols...")
-println("Old protocols: [" + jenkins.getAgentProtocols().join(", ") + "]")
-HashSet<String> newProtocols = new HashSet<>(jenkins.getAgentProtocols())
-newProtocols.removeAll(Arrays.asList("JNLP3-connect", "JNLP2-connect", "JNLP-connect", "CLI-connect"))
-println("New protocols: [" + newProtocols.join(", ") + "]")
-jenkins.setAgentProtocols(newProtocols)
-
-println("Disabling CLI access of /cli URL...")
-def remove = { list ->
-    list.each { item ->
-        if (item.getClass().name.contains("CLIAction")) {
-            println("Removing extension ${item.getClass().name}")
-            list.remove(item)
-        }
-    }
-}
-remove(jenkins.getExtensionList(RootAction.class))
-remove(jenkins.actions)
-
-println("Disable CLI completely...")
-CLI.get().setEnabled(false)
-println("CLI disabled")
-
-jenkins.save()
-`
-
 const configureKubernetesPluginFmt = `
 import com.cloudbees.plugins.credentials.CredentialsScope
 import com.cloudbees.plugins.credentials.SystemCredentialsProvider
@@ -123,10 +92,10 @@ KubernetesCloud kubernetes = new KubernetesCloud("kubernetes")
 kubernetes.setServerUrl("https://kubernetes.default")
 kubernetes.setNamespace("%s")
 kubernetes.setCredentialsId(kubernetesCredentialsId)
-kubernetes.setJenkinsUrl("http://%s:%d")
+kubernetes.setJenkinsUrl("%s")
 kubernetes.setRetentionTimeout(15)
-jenkins.clouds.add(kubernetes)
-
+%sjenkins.clouds.add(kubernetes)
+%s
 jenkins.save()
 `
 
@@ -161,27 +130,164 @@ if (jenkins.getView(jenkinsViewName) == null) {
 jenkins.save()
 `
 
+// buildBasicSettingsGroovyScript renders groovy script which configures Jenkins master node executors and mode
+func buildBasicSettingsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	executors := int32(constants.DefaultAmountOfExecutors)
+	if jenkins.Spec.Master.Executors != nil {
+		executors = *jenkins.Spec.Master.Executors
+	}
+	mode := "EXCLUSIVE"
+	if jenkins.Spec.Master.Mode == virtuslabv1alpha1.MasterModeNormal {
+		mode = "NORMAL"
+	}
+	return fmt.Sprintf(basicSettingsFmt, executors, mode)
+}
+
+// buildEnableCSRFGroovyScript renders groovy script which configures Jenkins crumb issuer, the crumb issuer
+// configured manually is kept when neither Jenkins.Spec.Master.CSRF nor the security profile set it
+func buildEnableCSRFGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	excludeClientIPFromCrumb := getExcludeClientIPFromCrumb(jenkins)
+	if excludeClientIPFromCrumb == nil {
+		return fmt.Sprintf(enableCSRFFmt, false, true)
+	}
+	return fmt.Sprintf(enableCSRFFmt, true, *excludeClientIPFromCrumb)
+}
+
 // GetBaseConfigurationConfigMapName returns name of Kubernetes config map used to base configuration
 func GetBaseConfigurationConfigMapName(jenkins *virtuslabv1alpha1.Jenkins) string {
 	return fmt.Sprintf("%s-base-configuration-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
 }
 
-// NewBaseConfigurationConfigMap builds Kubernetes config map used to base configuration
-func NewBaseConfigurationConfigMap(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) (*corev1.ConfigMap, error) {
+const (
+	groovyScriptExtension        = ".groovy"
+	configurationAsCodeExtension = ".yaml"
+	// ConfigurationAsCodePluginName is the name of plugin required to apply configuration as code files
+	ConfigurationAsCodePluginName = "configuration-as-code"
+)
+
+// baseConfigurationScript is a groovy script or configuration as code file applied by the operator during
+// base configuration, scripts rendered as empty string are skipped
+type baseConfigurationScript struct {
+	name      string
+	extension string
+	render    func(jenkins *virtuslabv1alpha1.Jenkins) string
+}
+
+// baseConfigurationScripts contains all built-in base configuration scripts in order of execution,
+// new scripts have to be appended to keep the names of ConfigMap keys stable
+var baseConfigurationScripts = []baseConfigurationScript{
+	{name: "basic-settings", render: buildBasicSettingsGroovyScript},
+	{name: "enable-csrf", render: buildEnableCSRFGroovyScript},
+	{name: "disable-usage-stats", render: func(jenkins *virtuslabv1alpha1.Jenkins) string {
+		return fmt.Sprintf(configureUsageStatsFmt, jenkins.Spec.Master.UsageStatisticsEnabled)
+	}},
+	{name: "enable-master-access-control", render: staticScript(enableMasterAccessControl)},
+	{name: "disable-insecure-features", render: buildDisableInsecureFeaturesGroovyScript},
+	{name: "configure-kubernetes-plugin", render: buildConfigureKubernetesPluginGroovyScript},
+	{name: "configure-views", render: staticScript(configureViews)},
+	{name: "configure-shared-libraries", extension: configurationAsCodeExtension, render: buildSharedLibrariesConfigurationAsCode},
+	{name: "configure-tools", render: buildConfigureToolsGroovyScript},
+	{name: "configure-vault", render: buildConfigureVaultGroovyScript},
+	{name: "configure-script-approvals", render: buildConfigureScriptApprovalsGroovyScript},
+	{name: "configure-global-env", render: buildConfigureGlobalEnvGroovyScript},
+	{name: "configure-build-settings", render: buildConfigureBuildSettingsGroovyScript},
+	{name: "configure-markup-formatter", render: buildConfigureMarkupFormatterGroovyScript},
+	{name: "configure-system-message", render: buildConfigureSystemMessageGroovyScript},
+	{name: "configure-theme", render: buildConfigureThemeGroovyScript},
+	{name: "configure-artifact-manager", render: buildConfigureArtifactManagerGroovyScript},
+	{name: "configure-ldap", render: buildConfigureLDAPGroovyScript},
+	{name: "configure-oidc", extension: configurationAsCodeExtension, render: buildOIDCConfigurationAsCode},
+	{name: "configure-saml", extension: configurationAsCodeExtension, render: buildSAMLConfigurationAsCode},
+	{name: "configure-github-oauth", render: buildConfigureGitHubOAuthGroovyScript},
+	{name: "configure-authorization", render: buildConfigureAuthorizationGroovyScript},
+	{name: "configure-login-throttling", render: buildConfigureLoginThrottlingGroovyScript},
+	{name: "configure-content-security-policy", render: buildConfigureContentSecurityPolicyGroovyScript},
+	{name: "configure-resource-root-url", render: buildConfigureResourceRootURLGroovyScript},
+	{name: "configure-jenkins-location", render: buildConfigureJenkinsLocationGroovyScript},
+	{name: "configure-response-headers", render: buildConfigureResponseHeadersGroovyScript},
+	{name: "configure-static-agents", render: buildConfigureStaticAgentsGroovyScript},
+	{name: "configure-vm-clouds", render: buildConfigureVMCloudsGroovyScript},
+	{name: "configure-metrics", render: buildConfigureMetricsGroovyScript},
+	{name: "configure-spot-agents-retry", render: buildConfigureSpotAgentsRetryGroovyScript},
+}
+
+// buildConfigureKubernetesPluginGroovyScript renders groovy script which configures Kubernetes cloud of the agents
+// connecting to Jenkins master Service
+func buildConfigureKubernetesPluginGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	cloudSettings := ""
+	if jenkins.Spec.Master.Remoting.WebSocket {
+		cloudSettings += "kubernetes.setWebSocket(true)\n"
+	}
+	if maxConcurrent := jenkins.Spec.Agents.MaxConcurrent; maxConcurrent > 0 {
+		cloudSettings += fmt.Sprintf("kubernetes.setContainerCap(%d)\n", maxConcurrent)
+	}
+	cloudSettings += buildKubernetesCloudPodLabelsGroovyScript(jenkins)
+	cloudSettings += buildKubernetesCloudIstioGroovyScript(jenkins)
+	cloudSettings += buildKubernetesCloudPodTemplatesGroovyScript(jenkins)
+
+	return fmt.Sprintf(configureKubernetesPluginFmt, GetAgentsNamespace(jenkins), getKubernetesCloudJenkinsURL(jenkins), cloudSettings,
+		buildAdditionalNamespacesCloudsGroovyScript(jenkins)+buildAgentsClustersCloudsGroovyScript(jenkins))
+}
+
+func staticScript(script string) func(*virtuslabv1alpha1.Jenkins) string {
+	return func(*virtuslabv1alpha1.Jenkins) string {
+		return script
+	}
+}
+
+// GetBaseConfigurationScriptNames returns names of all built-in base configuration scripts in order of execution
+func GetBaseConfigurationScriptNames() []string {
+	var names []string
+	for _, script := range baseConfigurationScripts {
+		names = append(names, script.name)
+	}
+	return names
+}
+
+// IsBaseConfigurationScriptName returns true if there is built-in base configuration script with the given name
+func IsBaseConfigurationScriptName(name string) bool {
+	for _, script := range baseConfigurationScripts {
+		if script.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBaseConfigurationConfigMap builds Kubernetes config map used to base configuration,
+// scripts listed in Jenkins.Spec.Configuration.DisabledBaseScripts are skipped and scripts present
+// in overrides (script name to groovy script) are replaced, the keys are prefixed with zero-padded index of the script,
+// so their lexical order is the order of execution
+func NewBaseConfigurationConfigMap(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins, overrides map[string]string) (*corev1.ConfigMap, error) {
 	meta.Name = GetBaseConfigurationConfigMapName(jenkins)
 
+	disabled := map[string]bool{}
+	for _, name := range jenkins.Spec.Configuration.DisabledBaseScripts {
+		disabled[name] = true
+	}
+
+	data := map[string]string{}
+	for i, script := range baseConfigurationScripts {
+		if disabled[script.name] {
+			continue
+		}
+		content, overridden := overrides[script.name]
+		if !overridden {
+			content = script.render(jenkins)
+		}
+		if len(content) == 0 {
+			continue
+		}
+		extension := script.extension
+		if len(extension) == 0 {
+			extension = groovyScriptExtension
+		}
+		data[fmt.Sprintf("%02d-%s%s", i+1, script.name, extension)] = content
+	}
+
 	return &corev1.ConfigMap{
 		TypeMeta:   buildConfigMapTypeMeta(),
 		ObjectMeta: meta,
-		Data: map[string]string{
-			"1-basic-settings.groovy":               fmt.Sprintf(basicSettingsFmt, constants.DefaultAmountOfExecutors),
-			"2-enable-csrf.groovy":                  enableCSRF,
-			"3-disable-usage-stats.groovy":          disableUsageStats,
-			"4-enable-master-access-control.groovy": enableMasterAccessControl,
-			"5-disable-insecure-features.groovy":    disableInsecureFeatures,
-			"6-configure-kubernetes-plugin.groovy": fmt.Sprintf(configureKubernetesPluginFmt,
-				jenkins.ObjectMeta.Namespace, GetResourceName(jenkins), HTTPPortInt),
-			"7-configure-views.groovy": configureViews,
-		},
+		Data:       data,
 	}, nil
 }
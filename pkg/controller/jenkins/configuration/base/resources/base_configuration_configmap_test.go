@@ -0,0 +1,637 @@
+package resources
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewBaseConfigurationConfigMap(t *testing.T) {
+	t.Run("all scripts", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{})
+
+		configMap, err := NewBaseConfigurationConfigMap(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NoError(t, err)
+		// optional scripts aren't rendered when their configuration is empty
+		assert.Len(t, configMap.Data, len(GetBaseConfigurationScriptNames())-19)
+		assert.Contains(t, configMap.Data["06-configure-kubernetes-plugin.groovy"], "namespace-name")
+		assert.Contains(t, configMap.Data["03-disable-usage-stats.groovy"], "def collected = false")
+	})
+	t.Run("usage statistics enabled", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{})
+		jenkins.Spec.Master.UsageStatisticsEnabled = true
+
+		configMap, err := NewBaseConfigurationConfigMap(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NoError(t, err)
+		assert.Contains(t, configMap.Data["03-disable-usage-stats.groovy"], "def collected = true")
+	})
+	t.Run("disabled script", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{})
+		jenkins.Spec.Configuration.DisabledBaseScripts = []string{"configure-kubernetes-plugin"}
+
+		configMap, err := NewBaseConfigurationConfigMap(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, configMap.Data, len(GetBaseConfigurationScriptNames())-20)
+		assert.NotContains(t, configMap.Data, "06-configure-kubernetes-plugin.groovy")
+		assert.Contains(t, configMap.Data, "07-configure-views.groovy")
+	})
+	t.Run("overridden script", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{})
+
+		configMap, err := NewBaseConfigurationConfigMap(NewResourceObjectMeta(jenkins), jenkins,
+			map[string]string{"configure-views": "println 'custom views'"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "println 'custom views'", configMap.Data["07-configure-views.groovy"])
+	})
+	t.Run("keys sorted in order of execution", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{})
+		overrides := map[string]string{}
+		for _, name := range GetBaseConfigurationScriptNames() {
+			overrides[name] = "println '" + name + "'"
+		}
+
+		configMap, err := NewBaseConfigurationConfigMap(NewResourceObjectMeta(jenkins), jenkins, overrides)
+
+		assert.NoError(t, err)
+		var keys []string
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var names []string
+		for _, key := range keys {
+			names = append(names, strings.TrimSuffix(strings.SplitN(key, "-", 2)[1], filepath.Ext(key)))
+		}
+		assert.Equal(t, GetBaseConfigurationScriptNames(), names)
+	})
+}
+
+func TestBuildSharedLibrariesConfigurationAsCode(t *testing.T) {
+	t.Run("no libraries", func(t *testing.T) {
+		assert.Equal(t, "", buildSharedLibrariesConfigurationAsCode(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("global and folder libraries", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Configuration: virtuslabv1alpha1.JenkinsConfiguration{
+					SharedLibraries: []virtuslabv1alpha1.SharedLibrary{
+						{Name: "global-library", RepositoryURL: "https://github.com/VirtusLab/global-library.git", Implicit: true},
+						{Name: "team-library", RepositoryURL: "https://github.com/VirtusLab/team-library.git", CredentialsID: "team", Folder: "team's"},
+					},
+				},
+			},
+		}
+
+		configuration := buildSharedLibrariesConfigurationAsCode(jenkins)
+
+		assert.Contains(t, configuration, `"globalLibraries"`)
+		assert.Contains(t, configuration, `"name": "global-library"`)
+		assert.Contains(t, configuration, `"defaultVersion": "master"`)
+		assert.Contains(t, configuration, `folder('team\\'s')`)
+		assert.Contains(t, configuration, `credentialsId('team')`)
+	})
+}
+
+func TestBuildConfigureToolsGroovyScript(t *testing.T) {
+	t.Run("no tools", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureToolsGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("installed and preinstalled tools", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{
+					Tools: []virtuslabv1alpha1.Tool{
+						{Type: virtuslabv1alpha1.ToolTypeMaven, Name: "maven-3", Version: "3.6.0"},
+						{Type: virtuslabv1alpha1.ToolTypeJDK, Name: "jdk-8", Home: "/usr/lib/jvm/java-8"},
+					},
+				},
+			},
+		}
+
+		script := buildConfigureToolsGroovyScript(jenkins)
+
+		assert.Contains(t, script, "'hudson.tasks.Maven$MavenInstallation').newInstance(\n        'maven-3', '', [new InstallSourceProperty([classLoader.loadClass('hudson.tasks.Maven$MavenInstaller').newInstance('3.6.0')])])")
+		assert.Contains(t, script, "'hudson.model.JDK').newInstance(\n        'jdk-8', '/usr/lib/jvm/java-8', [])")
+	})
+}
+
+func TestBuildConfigureVaultGroovyScript(t *testing.T) {
+	t.Run("vault not configured", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureVaultGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("appRole auth method", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Vault: &virtuslabv1alpha1.Vault{
+					Address:    "https://vault.example.com",
+					AuthMethod: virtuslabv1alpha1.VaultAuthMethodAppRole,
+					SecretName: "vault-approle",
+				},
+			},
+		}
+
+		script := buildConfigureVaultGroovyScript(jenkins)
+
+		assert.Contains(t, script, "com.datapipe.jenkins.vault.credentials.VaultAppRoleCredential")
+		assert.Contains(t, script, "env['CASC_VAULT_APPROLE_SECRET']")
+		assert.NotContains(t, script, "VaultTokenCredential")
+	})
+}
+
+func TestBuildConfigureScriptApprovalsGroovyScript(t *testing.T) {
+	t.Run("no approvals", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureScriptApprovalsGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("signatures and scripts", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{
+					ScriptApprovals: virtuslabv1alpha1.ScriptApprovals{
+						Signatures:   []string{"method java.lang.String trim"},
+						ScriptHashes: []string{"3e2ef1ea5b1e9e1b4a1d1c1ac5bbe8e9b5e2f1b9"},
+					},
+				},
+			},
+		}
+
+		script := buildConfigureScriptApprovalsGroovyScript(jenkins)
+
+		assert.Contains(t, script, "scriptApproval.approveSignature('method java.lang.String trim')")
+		assert.Contains(t, script, "scriptApproval.approveScript('3e2ef1ea5b1e9e1b4a1d1c1ac5bbe8e9b5e2f1b9')")
+	})
+}
+
+func TestBuildConfigureGlobalEnvGroovyScript(t *testing.T) {
+	t.Run("no variables", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureGlobalEnvGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("sorted variables", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{
+					GlobalEnv: map[string]string{"REGISTRY": "registry.example.com", "GREETING": "it's me"},
+				},
+			},
+		}
+
+		script := buildConfigureGlobalEnvGroovyScript(jenkins)
+
+		greeting := "envVars.put('GREETING', 'it\\'s me')"
+		registry := "envVars.put('REGISTRY', 'registry.example.com')"
+		assert.Contains(t, script, greeting)
+		assert.Contains(t, script, registry)
+		assert.True(t, strings.Index(script, greeting) < strings.Index(script, registry))
+	})
+}
+
+func TestBuildBasicSettingsGroovyScript(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		script := buildBasicSettingsGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		assert.Contains(t, script, "jenkins.setNumExecutors(3)")
+		assert.Contains(t, script, "jenkins.setMode(Mode.EXCLUSIVE)")
+	})
+	t.Run("executors and normal mode", func(t *testing.T) {
+		executors := int32(1)
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{Executors: &executors, Mode: virtuslabv1alpha1.MasterModeNormal},
+			},
+		}
+
+		script := buildBasicSettingsGroovyScript(jenkins)
+
+		assert.Contains(t, script, "jenkins.setNumExecutors(1)")
+		assert.Contains(t, script, "jenkins.setMode(Mode.NORMAL)")
+	})
+}
+
+func TestBuildConfigureBuildSettingsGroovyScript(t *testing.T) {
+	t.Run("no settings", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureBuildSettingsGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("quiet period and build discarder", func(t *testing.T) {
+		quietPeriod := int32(0)
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{
+					BuildSettings: virtuslabv1alpha1.BuildSettings{
+						QuietPeriod:    &quietPeriod,
+						BuildDiscarder: &virtuslabv1alpha1.BuildDiscarder{NumToKeep: 20},
+					},
+				},
+			},
+		}
+
+		script := buildConfigureBuildSettingsGroovyScript(jenkins)
+
+		assert.Contains(t, script, "jenkins.setQuietPeriod(0)")
+		assert.NotContains(t, script, "setScmCheckoutRetryCount")
+		assert.Contains(t, script, "new LogRotator('', '20',")
+	})
+}
+
+func TestBuildDisableInsecureFeaturesGroovyScript(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		script := buildDisableInsecureFeaturesGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		assert.Contains(t, script, `newProtocols.removeAll(Arrays.asList("JNLP3-connect", "JNLP2-connect", "JNLP-connect", "CLI-connect"))`)
+		assert.Contains(t, script, "jenkins.setSlaveAgentPort(50000)")
+		assert.Contains(t, script, "remove(jenkins.getExtensionList(RootAction.class))")
+		assert.Contains(t, script, "CLI.get().setEnabled(false)")
+	})
+	t.Run("custom remoting", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{
+					Remoting: virtuslabv1alpha1.Remoting{
+						CLIEnabled:            true,
+						AgentProtocols:        []string{"JNLP4-connect", "Ping"},
+						AgentListenerDisabled: true,
+					},
+				},
+			},
+		}
+
+		script := buildDisableInsecureFeaturesGroovyScript(jenkins)
+
+		assert.Contains(t, script, `new HashSet<>(Arrays.asList("JNLP4-connect", "Ping"))`)
+		assert.Contains(t, script, "jenkins.setSlaveAgentPort(-1)")
+		assert.NotContains(t, script, "CLIAction")
+		assert.Contains(t, script, "CLI.get().setEnabled(false)")
+	})
+}
+
+func TestBuildEnableCSRFGroovyScript(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		script := buildEnableCSRFGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		assert.Contains(t, script, "def enforce = false")
+		assert.Contains(t, script, "def excludeClientIPFromCrumb = true")
+	})
+	t.Run("client IP in crumb", func(t *testing.T) {
+		excludeClientIPFromCrumb := false
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{
+					CSRF: virtuslabv1alpha1.CSRF{ExcludeClientIPFromCrumb: &excludeClientIPFromCrumb},
+				},
+			},
+		}
+
+		script := buildEnableCSRFGroovyScript(jenkins)
+
+		assert.Contains(t, script, "def enforce = true")
+		assert.Contains(t, script, "def excludeClientIPFromCrumb = false")
+	})
+}
+
+func TestBuildConfigureMarkupFormatterGroovyScript(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureMarkupFormatterGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("plain text", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{MarkupFormatter: virtuslabv1alpha1.MarkupFormatterPlainText},
+			},
+		}
+
+		script := buildConfigureMarkupFormatterGroovyScript(jenkins)
+
+		assert.Contains(t, script, "jenkins.setMarkupFormatter(classLoader.loadClass('hudson.markup.EscapedMarkupFormatter').newInstance())")
+	})
+}
+
+func TestBuildConfigureSystemMessageGroovyScript(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureSystemMessageGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("message", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{SystemMessage: "PRODUCTION CI - authorized use only"},
+			},
+		}
+
+		script := buildConfigureSystemMessageGroovyScript(jenkins)
+
+		assert.Contains(t, script, "jenkins.setSystemMessage('PRODUCTION CI - authorized use only')")
+	})
+}
+
+func TestBuildConfigureThemeGroovyScript(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureThemeGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("login disclaimer", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{LoginDisclaimer: "Authorized \"use\" only\nAll activity is logged"},
+			},
+		}
+
+		script := buildConfigureThemeGroovyScript(jenkins)
+
+		assert.Contains(t, script, `new CssTextThemeElement('form[name="login"]::before { content: "Authorized \\"use\\" only\\A All activity is logged";`)
+	})
+}
+
+func TestBuildThemeElements(t *testing.T) {
+	jenkins := &virtuslabv1alpha1.Jenkins{
+		Spec: virtuslabv1alpha1.JenkinsSpec{
+			Master: virtuslabv1alpha1.JenkinsMaster{
+				Theme: virtuslabv1alpha1.Theme{
+					DisplayName: "Production CI",
+					LogoURL:     "https://example.com/logo.png",
+					CSSURL:      "https://example.com/theme.css",
+					CSS:         "#page-body { background: #fee; }",
+				},
+			},
+		},
+	}
+
+	elements := buildThemeElements(jenkins)
+
+	assert.Equal(t, []string{
+		"new CssUrlThemeElement('https://example.com/theme.css')",
+		`new CssTextThemeElement('#jenkins-head-icon { content: url("https://example.com/logo.png"); max-height: 40px; }')`,
+		`new CssTextThemeElement('#jenkins-name-icon { display: none; } #jenkins-home-link::after { content: "Production CI"; color: #fff; font-size: 20px; font-weight: bold; vertical-align: middle; margin-left: 0.5em; }')`,
+		"new CssTextThemeElement('#page-body { background: #fee; }')",
+	}, elements)
+}
+
+func TestBuildConfigureArtifactManagerGroovyScript(t *testing.T) {
+	t.Run("artifact manager not configured", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureArtifactManagerGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("amazon s3", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				ArtifactManager: &virtuslabv1alpha1.ArtifactManager{
+					AmazonS3: &virtuslabv1alpha1.ArtifactManagerAmazonS3{BucketName: "artifacts", Prefix: "jenkins/"},
+				},
+			},
+		}
+
+		script := buildConfigureArtifactManagerGroovyScript(jenkins)
+
+		assert.Contains(t, script, "config.setContainer('artifacts')")
+		assert.Contains(t, script, "config.setPrefix('jenkins/')")
+		assert.Contains(t, script, "containerExists('artifacts')")
+	})
+}
+
+func TestBuildConfigureLDAPGroovyScript(t *testing.T) {
+	t.Run("ldap not configured", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureLDAPGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("default user search filter", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					LDAP: &virtuslabv1alpha1.LDAP{
+						Server:            "ldaps://ldap.example.com",
+						RootDN:            "dc=example,dc=com",
+						GroupSearchFilter: "(&(cn={0})(objectclass=groupOfNames))",
+					},
+				},
+			},
+		}
+
+		script := buildConfigureLDAPGroovyScript(jenkins)
+
+		assert.Contains(t, script, "'ldaps://ldap.example.com', 'dc=example,dc=com', false, env['JENKINS_LDAP_BIND_DN']")
+		assert.Contains(t, script, "configuration.setUserSearch('uid={0}')")
+		assert.Contains(t, script, "configuration.setGroupSearchFilter('(&(cn={0})(objectclass=groupOfNames))')")
+		assert.Contains(t, script, "new File('/var/jenkins/operator-credentials/user').text")
+	})
+}
+
+func TestBuildOIDCConfigurationAsCode(t *testing.T) {
+	t.Run("oidc not configured", func(t *testing.T) {
+		assert.Equal(t, "", buildOIDCConfigurationAsCode(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("defaults", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					OIDC: &virtuslabv1alpha1.OIDC{IssuerURL: "https://accounts.example.com/", ClientSecretName: "oidc"},
+				},
+			},
+		}
+
+		configuration := buildOIDCConfigurationAsCode(jenkins)
+
+		assert.Contains(t, configuration, `"wellKnownOpenIDConfigurationUrl": "https://accounts.example.com/.well-known/openid-configuration"`)
+		assert.Contains(t, configuration, `"clientSecret": "${JENKINS_OIDC_CLIENT_SECRET}"`)
+		assert.Contains(t, configuration, `"scopes": "openid email profile"`)
+		assert.Contains(t, configuration, `"userNameField": "sub"`)
+		assert.Contains(t, configuration, `"escapeHatchEnabled": false`)
+		assert.NotContains(t, configuration, "groupsFieldName")
+	})
+	t.Run("groups claim and break-glass account", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					OIDC: &virtuslabv1alpha1.OIDC{
+						IssuerURL:            "https://accounts.example.com",
+						ClientSecretName:     "oidc",
+						Scopes:               []string{"openid", "groups"},
+						GroupsClaim:          "groups",
+						BreakGlassSecretName: "break-glass",
+					},
+				},
+			},
+		}
+
+		configuration := buildOIDCConfigurationAsCode(jenkins)
+
+		assert.Contains(t, configuration, `"scopes": "openid groups"`)
+		assert.Contains(t, configuration, `"groupsFieldName": "groups"`)
+		assert.Contains(t, configuration, `"escapeHatchEnabled": true`)
+		assert.Contains(t, configuration, `"escapeHatchSecret": "${JENKINS_OIDC_BREAK_GLASS_PASSWORD}"`)
+	})
+}
+
+func TestBuildSAMLConfigurationAsCode(t *testing.T) {
+	t.Run("saml not configured", func(t *testing.T) {
+		assert.Equal(t, "", buildSAMLConfigurationAsCode(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("metadata URL", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					SAML: &virtuslabv1alpha1.SAML{
+						IdPMetadata:     virtuslabv1alpha1.SAMLIdPMetadata{URL: "https://idp.example.com/metadata"},
+						GroupsAttribute: "groups",
+					},
+				},
+			},
+		}
+
+		configuration := buildSAMLConfigurationAsCode(jenkins)
+
+		assert.Contains(t, configuration, `"url": "https://idp.example.com/metadata"`)
+		assert.Contains(t, configuration, `"groupsAttributeName": "groups"`)
+		assert.NotContains(t, configuration, "emailAttributeName")
+		assert.NotContains(t, configuration, "encryptionData")
+	})
+	t.Run("inline metadata and keystore", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					SAML: &virtuslabv1alpha1.SAML{
+						IdPMetadata:        virtuslabv1alpha1.SAMLIdPMetadata{XML: `<EntityDescriptor entityID="idp"/>`},
+						KeystoreSecretName: "saml-keystore",
+						PrivateKeyAlias:    "jenkins",
+					},
+				},
+			},
+		}
+
+		configuration := buildSAMLConfigurationAsCode(jenkins)
+
+		// JSON escapes of XML markup are valid YAML double-quoted string escapes
+		assert.Contains(t, configuration, `"xml": "\u003cEntityDescriptor entityID=\"idp\"/\u003e"`)
+		assert.Contains(t, configuration, `"keystorePath": "/var/jenkins/saml-keystore/keystore.jks"`)
+		assert.Contains(t, configuration, `"keystorePassword": "${JENKINS_SAML_KEYSTORE_PASSWORD}"`)
+		assert.Contains(t, configuration, `"privateKeyAlias": "jenkins"`)
+	})
+}
+
+func TestBuildConfigureGitHubOAuthGroovyScript(t *testing.T) {
+	t.Run("github oauth not configured", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureGitHubOAuthGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("github.com", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					GitHubOAuth: &virtuslabv1alpha1.GitHubOAuth{
+						ClientSecretName: "github",
+						Organizations:    []string{"VirtusLab"},
+						Teams:            []string{"VirtusLab/jenkins-admins"},
+						Admins:           []string{"octocat"},
+					},
+				},
+			},
+		}
+
+		script := buildConfigureGitHubOAuthGroovyScript(jenkins)
+
+		assert.Contains(t, script, "'https://github.com', 'https://api.github.com', env['JENKINS_GITHUB_OAUTH_CLIENT_ID']")
+		assert.Contains(t, script, "def admins = [operatorUserName, 'octocat']")
+		assert.Contains(t, script, "def organizations = ['VirtusLab', 'VirtusLab*jenkins-admins']")
+	})
+	t.Run("github enterprise", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					GitHubOAuth: &virtuslabv1alpha1.GitHubOAuth{WebURL: "https://github.example.com/", ClientSecretName: "github"},
+				},
+			},
+		}
+
+		script := buildConfigureGitHubOAuthGroovyScript(jenkins)
+
+		assert.Contains(t, script, "'https://github.example.com', 'https://github.example.com/api/v3'")
+		assert.Contains(t, script, "def organizations = []")
+	})
+}
+
+func TestBuildConfigureAuthorizationGroovyScript(t *testing.T) {
+	t.Run("authorization not configured", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureAuthorizationGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("project-based matrix", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					Authorization: &virtuslabv1alpha1.Authorization{
+						Matrix: &virtuslabv1alpha1.MatrixAuthorization{
+							ProjectBased: true,
+							Grants: []virtuslabv1alpha1.MatrixGrant{
+								{Sid: "authenticated", Permissions: []string{"Overall/Read", "Job/Read"}},
+								{Sid: "o'brien", Permissions: []string{"Job/Build"}},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		script := buildConfigureAuthorizationGroovyScript(jenkins)
+
+		assert.Contains(t, script, "loadClass('hudson.security.ProjectMatrixAuthorizationStrategy')")
+		assert.Contains(t, script, "def operatorUserName = new File('/var/jenkins/operator-credentials/user').text")
+		assert.Contains(t, script, "grant(operatorUserName, 'Overall/Administer')")
+		assert.Contains(t, script, "grant('authenticated', 'Overall/Read')\ngrant('authenticated', 'Job/Read')")
+		assert.Contains(t, script, "grant('o\\'brien', 'Job/Build')")
+	})
+	t.Run("role-based", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					Authorization: &virtuslabv1alpha1.Authorization{
+						RoleBased: &virtuslabv1alpha1.RoleBasedAuthorization{
+							GlobalRoles: []virtuslabv1alpha1.Role{
+								{Name: "readers", Permissions: []string{"Overall/Read"}, Assignments: []string{"authenticated"}},
+							},
+							ItemRoles: []virtuslabv1alpha1.Role{
+								{Name: "team-a", Pattern: "team-a(/.*)?", Permissions: []string{"Job/Read", "Job/Build"}, Assignments: []string{"team-a"}},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		script := buildConfigureAuthorizationGroovyScript(jenkins)
+
+		assert.Contains(t, script, "RoleBasedAuthorizationStrategy")
+		assert.Contains(t, script, "addRole(strategyClass.GLOBAL, 'jenkins-operator', '.*', ['Overall/Administer'], [operatorUserName])")
+		assert.Contains(t, script, "addRole(strategyClass.GLOBAL, 'readers', '.*', ['Overall/Read'], ['authenticated'])")
+		assert.Contains(t, script, "addRole(strategyClass.PROJECT, 'team-a', 'team-a(/.*)?', ['Job/Read', 'Job/Build'], ['team-a'])")
+		assert.NotContains(t, script, "MatrixAuthorizationStrategy")
+	})
+}
+
+func TestBuildConfigureLoginThrottlingGroovyScript(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		script := buildConfigureLoginThrottlingGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		assert.Contains(t, script, "PluginServletFilter.removeFilter(previousFilter)")
+		assert.Contains(t, script, "def enabled = false")
+	})
+	t.Run("throttling", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					LoginThrottling: &virtuslabv1alpha1.LoginThrottling{
+						MaxFailedAttempts: 5,
+						Window:            metav1.Duration{Duration: 15 * time.Minute},
+					},
+				},
+			},
+		}
+
+		script := buildConfigureLoginThrottlingGroovyScript(jenkins)
+
+		assert.Contains(t, script, "def enabled = true")
+		assert.Contains(t, script, "new LoginThrottlingFilter(maxFailedAttempts: 5, windowMillis: 900000L)")
+		assert.Contains(t, script, "def attempts = failedLogins.get(userName)")
+		assert.Contains(t, script, "pruneExpiredFailedLogins(now)")
+	})
+}
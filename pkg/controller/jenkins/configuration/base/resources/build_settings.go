@@ -0,0 +1,87 @@
+package resources
+
+import (
+	"strconv"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+var configureBuildSettingsTemplate = template.Must(template.New("configure-build-settings").Parse(`
+import hudson.tasks.LogRotator
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+{{- if .QuietPeriod }}
+jenkins.setQuietPeriod({{ .QuietPeriod }})
+{{- end }}
+{{- if .SCMCheckoutRetryCount }}
+jenkins.setScmCheckoutRetryCount({{ .SCMCheckoutRetryCount }})
+{{- end }}
+jenkins.save()
+{{- if .BuildDiscarder }}
+
+// global build discarder is available since Jenkins 2.221, classes are loaded dynamically to report missing support clearly
+def classLoader = jenkins.pluginManager.uberClassLoader
+def configurationClass
+try {
+    configurationClass = classLoader.loadClass('jenkins.model.GlobalBuildDiscarderConfiguration')
+} catch (ClassNotFoundException e) {
+    throw new IllegalStateException('Global build discarder requires Jenkins 2.221 or newer')
+}
+def strategyClass = classLoader.loadClass('jenkins.model.SimpleGlobalBuildDiscarderStrategy')
+def configuration = configurationClass.get()
+def discarders = configuration.getConfiguredBuildDiscarders()
+discarders.removeAll(strategyClass)
+discarders.add(strategyClass.newInstance(new LogRotator('{{ .BuildDiscarder.DaysToKeep }}', '{{ .BuildDiscarder.NumToKeep }}',
+        '{{ .BuildDiscarder.ArtifactDaysToKeep }}', '{{ .BuildDiscarder.ArtifactNumToKeep }}')))
+configuration.save()
+{{- end }}
+`))
+
+// logRotatorValue converts build discarder limit to LogRotator argument, empty string means no limit
+func logRotatorValue(value int32) string {
+	if value <= 0 {
+		return ""
+	}
+	return strconv.Itoa(int(value))
+}
+
+// buildConfigureBuildSettingsGroovyScript renders groovy script which configures global build settings
+// from Jenkins.Spec.Master.BuildSettings, settings which aren't set are left unchanged
+func buildConfigureBuildSettingsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	settings := jenkins.Spec.Master.BuildSettings
+	if settings.QuietPeriod == nil && settings.SCMCheckoutRetryCount == nil && settings.BuildDiscarder == nil {
+		return ""
+	}
+
+	type buildDiscarder struct {
+		DaysToKeep         string
+		NumToKeep          string
+		ArtifactDaysToKeep string
+		ArtifactNumToKeep  string
+	}
+	data := struct {
+		QuietPeriod           string
+		SCMCheckoutRetryCount string
+		BuildDiscarder        *buildDiscarder
+	}{}
+	if settings.QuietPeriod != nil {
+		data.QuietPeriod = strconv.Itoa(int(*settings.QuietPeriod))
+	}
+	if settings.SCMCheckoutRetryCount != nil {
+		data.SCMCheckoutRetryCount = strconv.Itoa(int(*settings.SCMCheckoutRetryCount))
+	}
+	if settings.BuildDiscarder != nil {
+		data.BuildDiscarder = &buildDiscarder{
+			DaysToKeep:         logRotatorValue(settings.BuildDiscarder.DaysToKeep),
+			NumToKeep:          logRotatorValue(settings.BuildDiscarder.NumToKeep),
+			ArtifactDaysToKeep: logRotatorValue(settings.BuildDiscarder.ArtifactDaysToKeep),
+			ArtifactNumToKeep:  logRotatorValue(settings.BuildDiscarder.ArtifactNumToKeep),
+		}
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureBuildSettingsTemplate, data)
+	return output
+}
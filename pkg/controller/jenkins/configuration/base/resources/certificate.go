@@ -0,0 +1,41 @@
+package resources
+
+import (
+	"fmt"
+
+	certmanagerv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/certmanager/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewCertificate builds cert-manager Certificate of Jenkins master valid for the Jenkins master Service DNS names,
+// the issued certificate Secret is labeled so the operator restarts Jenkins when the certificate is renewed
+func NewCertificate(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *certmanagerv1.Certificate {
+	masterTLS := jenkins.Spec.Master.TLS
+	serviceName := GetResourceName(jenkins)
+
+	return &certmanagerv1.Certificate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       certmanagerv1.CertificateKind,
+			APIVersion: certmanagerv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: certmanagerv1.CertificateSpec{
+			DNSNames: []string{
+				serviceName,
+				fmt.Sprintf("%s.%s", serviceName, jenkins.ObjectMeta.Namespace),
+				GetJenkinsMasterServiceDNSName(jenkins),
+			},
+			SecretName: masterTLS.SecretName,
+			SecretTemplate: &certmanagerv1.CertificateSecretTemplate{
+				Labels: BuildLabelsForWatchedResources(jenkins),
+			},
+			IssuerRef: certmanagerv1.ObjectReference{
+				Name:  masterTLS.IssuerRef.Name,
+				Kind:  masterTLS.IssuerRef.Kind,
+				Group: masterTLS.IssuerRef.Group,
+			},
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCertificate(t *testing.T) {
+	jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{
+		Master: virtuslabv1alpha1.JenkinsMaster{
+			TLS: &virtuslabv1alpha1.MasterTLS{
+				SecretName: "jenkins-tls",
+				IssuerRef:  &virtuslabv1alpha1.CertManagerIssuerReference{Name: "ca-issuer", Kind: "ClusterIssuer"},
+			},
+		},
+	})
+
+	certificate := NewCertificate(NewResourceObjectMeta(jenkins), jenkins)
+
+	assert.Equal(t, "jenkins-operator-jenkins-cr-name", certificate.Name)
+	assert.Equal(t, []string{
+		"jenkins-operator-jenkins-cr-name",
+		"jenkins-operator-jenkins-cr-name.namespace-name",
+		"jenkins-operator-jenkins-cr-name.namespace-name.svc",
+	}, certificate.Spec.DNSNames)
+	assert.Equal(t, "jenkins-tls", certificate.Spec.SecretName)
+	assert.Equal(t, BuildLabelsForWatchedResources(jenkins), certificate.Spec.SecretTemplate.Labels)
+	assert.Equal(t, "ca-issuer", certificate.Spec.IssuerRef.Name)
+	assert.Equal(t, "ClusterIssuer", certificate.Spec.IssuerRef.Kind)
+}
@@ -0,0 +1,20 @@
+package resources
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+// IsInternalExposure tells if Jenkins can't be exposed outside of the cluster
+func IsInternalExposure(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Exposure == virtuslabv1alpha1.ExposureInternal
+}
+
+// GetServiceNames returns names of all Services of Jenkins master managed by the operator, including the optional ones
+func GetServiceNames(jenkins *virtuslabv1alpha1.Jenkins) []string {
+	return []string{
+		GetResourceName(jenkins),
+		GetAgentServiceName(jenkins),
+		GetHeadlessServiceName(jenkins),
+		GetSSHDServiceName(jenkins),
+	}
+}
@@ -0,0 +1,33 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const (
+	// ExternalDNSHostnameAnnotationKey is the annotation listing DNS names published by external-dns
+	ExternalDNSHostnameAnnotationKey = "external-dns.alpha.kubernetes.io/hostname"
+	// ExternalDNSTTLAnnotationKey is the annotation setting TTL of DNS records published by external-dns
+	ExternalDNSTTLAnnotationKey = "external-dns.alpha.kubernetes.io/ttl"
+)
+
+// ExternalDNSAnnotationKeys contains all external-dns annotations managed by the operator
+var ExternalDNSAnnotationKeys = []string{ExternalDNSHostnameAnnotationKey, ExternalDNSTTLAnnotationKey}
+
+// applyExternalDNSAnnotations sets external-dns annotations from Jenkins.Spec.Service.ExternalDNS, the hostname is set
+// only when withHostname is true so that the same records aren't published for both the Ingress and the Service
+func applyExternalDNSAnnotations(annotations map[string]string, jenkins *virtuslabv1alpha1.Jenkins, withHostname bool) {
+	externalDNS := jenkins.Spec.Service.ExternalDNS
+	if externalDNS == nil {
+		return
+	}
+
+	if withHostname && len(externalDNS.Hostname) > 0 {
+		annotations[ExternalDNSHostnameAnnotationKey] = externalDNS.Hostname
+	}
+	if externalDNS.TTL > 0 {
+		annotations[ExternalDNSTTLAnnotationKey] = fmt.Sprintf("%d", externalDNS.TTL)
+	}
+}
@@ -0,0 +1,45 @@
+package resources
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const (
+	// FIPSMinRSAKeySize is the minimal RSA key size approved by FIPS 140
+	FIPSMinRSAKeySize = 2048
+
+	// fipsJavaOpts enables Jenkins FIPS 140 compliance mode, which disables non-approved algorithms in Jenkins core and
+	// plugins, and FIPS mode of Red Hat OpenJDK builds
+	fipsJavaOpts = "-Djenkins.security.FIPS140.COMPLIANCE=true -Dcom.redhat.fips=true"
+)
+
+// FIPSCipherSuites contains FIPS 140 approved TLS 1.2 cipher suites used by the operator to connect to Jenkins
+var FIPSCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// IsFIPSModeEnabled tells if Jenkins and the operator are restricted to FIPS 140 approved cryptography
+func IsFIPSModeEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Security.FIPSMode
+}
+
+// IsFIPSApprovedPublicKey tells if the key is RSA key of at least FIPSMinRSAKeySize bits or ECDSA key
+// on P-256, P-384 or P-521 curve
+func IsFIPSApprovedPublicKey(publicKey interface{}) bool {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return key.N.BitLen() >= FIPSMinRSAKeySize
+	case *ecdsa.PublicKey:
+		return key.Curve == elliptic.P256() || key.Curve == elliptic.P384() || key.Curve == elliptic.P521()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,44 @@
+package resources
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsFIPSApprovedPublicKey(t *testing.T) {
+	rsaKey := func(bits int) interface{} {
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &key.PublicKey
+	}
+	ecdsaKey := func(curve elliptic.Curve) interface{} {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &key.PublicKey
+	}
+
+	assert.True(t, IsFIPSApprovedPublicKey(rsaKey(2048)))
+	assert.False(t, IsFIPSApprovedPublicKey(rsaKey(1024)))
+	assert.True(t, IsFIPSApprovedPublicKey(ecdsaKey(elliptic.P256())))
+	assert.False(t, IsFIPSApprovedPublicKey(ecdsaKey(elliptic.P224())))
+	assert.False(t, IsFIPSApprovedPublicKey("unknown key"))
+}
+
+func TestBuildJavaOpts(t *testing.T) {
+	jenkins := &virtuslabv1alpha1.Jenkins{}
+	assert.NotContains(t, buildJavaOpts(jenkins), fipsJavaOpts)
+
+	jenkins.Spec.Security.FIPSMode = true
+	assert.Contains(t, buildJavaOpts(jenkins), fipsJavaOpts)
+}
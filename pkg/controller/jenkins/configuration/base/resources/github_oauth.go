@@ -0,0 +1,121 @@
+package resources
+
+import (
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// GitHubOAuthPluginName is the name of plugin required by Jenkins.Spec.Security.GitHubOAuth
+	GitHubOAuthPluginName = "github-oauth"
+	// GitHubOAuthClientIDSecretKey is the GitHub OAuth Secret key with client ID
+	GitHubOAuthClientIDSecretKey = "clientId"
+	// GitHubOAuthClientSecretSecretKey is the GitHub OAuth Secret key with client secret
+	GitHubOAuthClientSecretSecretKey = "clientSecret"
+
+	defaultGitHubWebURL = "https://github.com"
+	defaultGitHubAPIURL = "https://api.github.com"
+	gitHubOAuthScopes   = "read:org,user:email"
+
+	gitHubOAuthClientIDEnvName     = "JENKINS_GITHUB_OAUTH_CLIENT_ID"
+	gitHubOAuthClientSecretEnvName = "JENKINS_GITHUB_OAUTH_CLIENT_SECRET"
+)
+
+// buildGitHubOAuthEnvVars returns Jenkins master container environment variables with GitHub OAuth application
+// credentials referenced from the Secret
+func buildGitHubOAuthEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	github := jenkins.Spec.Security.GitHubOAuth
+	if github == nil {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		buildSecretKeyEnvVar(gitHubOAuthClientIDEnvName, github.ClientSecretName, GitHubOAuthClientIDSecretKey),
+		buildSecretKeyEnvVar(gitHubOAuthClientSecretEnvName, github.ClientSecretName, GitHubOAuthClientSecretSecretKey),
+	}
+}
+
+var configureGitHubOAuthTemplate = template.Must(template.New("configure-github-oauth").Parse(`
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+// GitHub OAuth plugin classes are loaded dynamically because the plugin is optional
+def classLoader = jenkins.pluginManager.uberClassLoader
+def env = System.getenv()
+
+def realm = classLoader.loadClass('org.jenkinsci.plugins.GithubSecurityRealm').newInstance(
+        '{{ .WebURL }}', '{{ .APIURL }}', env['{{ .ClientIDEnv }}'], env['{{ .ClientSecretEnv }}'], '{{ .Scopes }}')
+
+// the operator user has to stay administrator, otherwise it would be locked out
+def operatorUserName = new File('{{ .OperatorCredentialsPath }}/{{ .OperatorUserNameFile }}').text
+def admins = [operatorUserName{{ range .Admins }}, '{{ . }}'{{ end }}]
+def organizations = [{{ range $index, $organization := .Organizations }}{{ if $index }}, {{ end }}'{{ $organization }}'{{ end }}]
+def strategy = classLoader.loadClass('org.jenkinsci.plugins.GithubAuthorizationStrategy').newInstance(
+        admins.join(','), false, false, false, organizations.join(','), false, false, false, false)
+
+jenkins.setSecurityRealm(realm)
+jenkins.setAuthorizationStrategy(strategy)
+jenkins.save()
+`))
+
+// buildConfigureGitHubOAuthGroovyScript renders groovy script which configures GitHub OAuth security realm
+// and GitHub organization based authorization from Jenkins.Spec.Security.GitHubOAuth
+func buildConfigureGitHubOAuthGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	github := jenkins.Spec.Security.GitHubOAuth
+	if github == nil {
+		return ""
+	}
+
+	webURL := strings.TrimSuffix(github.WebURL, "/")
+	if len(webURL) == 0 {
+		webURL = defaultGitHubWebURL
+	}
+	apiURL := github.APIURL
+	if len(apiURL) == 0 && webURL == defaultGitHubWebURL {
+		apiURL = defaultGitHubAPIURL
+	} else if len(apiURL) == 0 {
+		apiURL = webURL + "/api/v3"
+	}
+
+	var organizations, admins []string
+	for _, organization := range github.Organizations {
+		organizations = append(organizations, escapeGroovyString(organization))
+	}
+	// the plugin expects teams in 'organization*team' format
+	for _, team := range github.Teams {
+		organizations = append(organizations, escapeGroovyString(strings.Replace(team, "/", "*", 1)))
+	}
+	for _, admin := range github.Admins {
+		admins = append(admins, escapeGroovyString(admin))
+	}
+
+	data := struct {
+		WebURL                  string
+		APIURL                  string
+		ClientIDEnv             string
+		ClientSecretEnv         string
+		Scopes                  string
+		OperatorCredentialsPath string
+		OperatorUserNameFile    string
+		Admins                  []string
+		Organizations           []string
+	}{
+		WebURL:                  escapeGroovyString(webURL),
+		APIURL:                  escapeGroovyString(apiURL),
+		ClientIDEnv:             gitHubOAuthClientIDEnvName,
+		ClientSecretEnv:         gitHubOAuthClientSecretEnvName,
+		Scopes:                  gitHubOAuthScopes,
+		OperatorCredentialsPath: jenkinsOperatorCredentialsVolumePath,
+		OperatorUserNameFile:    OperatorCredentialsSecretUserNameKey,
+		Admins:                  admins,
+		Organizations:           organizations,
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureGitHubOAuthTemplate, data)
+	return output
+}
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"sort"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+var configureGlobalEnvTemplate = template.Must(template.New("configure-global-env").Parse(`
+import hudson.slaves.EnvironmentVariablesNodeProperty
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+def globalNodeProperties = jenkins.getGlobalNodeProperties()
+def envProperty = globalNodeProperties.get(EnvironmentVariablesNodeProperty.class)
+if (envProperty == null) {
+    envProperty = new EnvironmentVariablesNodeProperty()
+    globalNodeProperties.add(envProperty)
+}
+
+def envVars = envProperty.getEnvVars()
+envVars.clear()
+{{- range .Variables }}
+envVars.put('{{ .Name }}', '{{ .Value }}')
+{{- end }}
+jenkins.save()
+`))
+
+// buildConfigureGlobalEnvGroovyScript renders groovy script which replaces Jenkins global environment variables
+// with Jenkins.Spec.Master.GlobalEnv
+func buildConfigureGlobalEnvGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if len(jenkins.Spec.Master.GlobalEnv) == 0 {
+		return ""
+	}
+
+	type variable struct {
+		Name  string
+		Value string
+	}
+	var variables []variable
+	for name, value := range jenkins.Spec.Master.GlobalEnv {
+		variables = append(variables, variable{Name: escapeGroovyString(name), Value: escapeGroovyString(value)})
+	}
+	// map iteration order is random, the script has to be stable because its hash triggers configuration
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureGlobalEnvTemplate, struct{ Variables interface{} }{Variables: variables})
+	return output
+}
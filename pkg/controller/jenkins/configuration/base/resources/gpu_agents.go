@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// DefaultGPUResourceName is the extended resource of NVIDIA device plugin
+	DefaultGPUResourceName corev1.ResourceName = "nvidia.com/gpu"
+
+	// nvidiaResourcePrefix is the prefix of NVIDIA device plugin resources, e.g. MIG devices 'nvidia.com/mig-1g.5gb'
+	nvidiaResourcePrefix = "nvidia.com/"
+	// nvidiaDriverCapabilitiesEnv is the variable of NVIDIA container runtime selecting driver libraries mounted to
+	// the container
+	nvidiaDriverCapabilitiesEnv = "NVIDIA_DRIVER_CAPABILITIES"
+)
+
+// NVIDIADriverCapabilities consists driver capabilities supported by NVIDIA container runtime
+var NVIDIADriverCapabilities = []string{"compute", "compat32", "graphics", "utility", "video", "display", "all"}
+
+// GetAgentGPUResourceName returns the extended resource of AgentGPU, NVIDIA GPU by default
+func GetAgentGPUResourceName(gpu *virtuslabv1alpha1.AgentGPU) corev1.ResourceName {
+	if len(gpu.ResourceName) > 0 {
+		return gpu.ResourceName
+	}
+	return DefaultGPUResourceName
+}
+
+// IsNVIDIAResource returns true if the extended resource is advertised by NVIDIA device plugin
+func IsNVIDIAResource(name corev1.ResourceName) bool {
+	return strings.HasPrefix(string(name), nvidiaResourcePrefix)
+}
+
+// getAgentGPUContainerName returns the container getting the devices of AgentPodTemplate.GPU
+func getAgentGPUContainerName(gpu *virtuslabv1alpha1.AgentGPU) string {
+	if len(gpu.Container) > 0 {
+		return gpu.Container
+	}
+	return AgentContainerName
+}
+
+// applyAgentGPU sets requests and limits of AgentPodTemplate.GPU to the container, it tolerates the taint GPU node pools
+// usually have with the resource name, so other pods aren't scheduled there
+func applyAgentGPU(pod *corev1.Pod, gpu *virtuslabv1alpha1.AgentGPU) {
+	if gpu == nil {
+		return
+	}
+
+	resourceName := GetAgentGPUResourceName(gpu)
+	quantity := *resource.NewQuantity(gpu.Count, resource.DecimalSI)
+	for i, container := range pod.Spec.Containers {
+		if container.Name != getAgentGPUContainerName(gpu) {
+			continue
+		}
+		if container.Resources.Requests == nil {
+			pod.Spec.Containers[i].Resources.Requests = corev1.ResourceList{}
+		}
+		if container.Resources.Limits == nil {
+			pod.Spec.Containers[i].Resources.Limits = corev1.ResourceList{}
+		}
+		pod.Spec.Containers[i].Resources.Requests[resourceName] = quantity
+		pod.Spec.Containers[i].Resources.Limits[resourceName] = quantity
+		if len(gpu.DriverCapabilities) > 0 {
+			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, corev1.EnvVar{
+				Name:  nvidiaDriverCapabilitiesEnv,
+				Value: strings.Join(gpu.DriverCapabilities, ","),
+			})
+		}
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+		Key:      string(resourceName),
+		Operator: corev1.TolerationOpExists,
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+}
+
+// setAgentPodYamlRuntimeClassName adds runtime class of AgentPodTemplate.GPU to the agent pod yaml, the field isn't
+// available in the vendored pod API, Kubernetes plugin passes it through to the cluster
+func setAgentPodYamlRuntimeClassName(podYAML []byte, gpu *virtuslabv1alpha1.AgentGPU) ([]byte, error) {
+	if gpu == nil || len(gpu.RuntimeClassName) == 0 {
+		return podYAML, nil
+	}
+
+	pod := map[string]interface{}{}
+	if err := yaml.Unmarshal(podYAML, &pod); err != nil {
+		return nil, err
+	}
+	spec, ok := pod["spec"].(map[string]interface{})
+	if !ok {
+		spec = map[string]interface{}{}
+		pod["spec"] = spec
+	}
+	spec["runtimeClassName"] = gpu.RuntimeClassName
+	return yaml.Marshal(pod)
+}
@@ -0,0 +1,222 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// grafanaDashboardLabelKey is the label of ConfigMaps loaded by Grafana dashboards sidecar by default
+	grafanaDashboardLabelKey   = "grafana_dashboard"
+	grafanaDashboardLabelValue = "1"
+
+	grafanaDashboardSchemaVersion = 27
+	grafanaDashboardPanelWidth    = 12
+	grafanaDashboardPanelHeight   = 8
+)
+
+type grafanaDashboardQuery struct {
+	expr   string
+	legend string
+}
+
+type grafanaDashboardPanel struct {
+	title   string
+	unit    string
+	queries []grafanaDashboardQuery
+}
+
+type grafanaDashboardRow struct {
+	title  string
+	panels []grafanaDashboardPanel
+}
+
+// IsGrafanaDashboardEnabled tells if the operator creates ConfigMap with Grafana dashboard of the Jenkins CR
+func IsGrafanaDashboardEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Monitoring != nil && jenkins.Spec.Monitoring.GrafanaDashboard != nil
+}
+
+// GetGrafanaDashboardConfigMapName returns the name of the ConfigMap with Grafana dashboard
+func GetGrafanaDashboardConfigMapName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-grafana-dashboard-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// getGrafanaDashboardFileName returns the ConfigMap key of the dashboard, the sidecar writes every ConfigMap key
+// into the same directory, so the key is unique across namespaces
+func getGrafanaDashboardFileName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("jenkins-%s-%s.json", jenkins.ObjectMeta.Namespace, jenkins.ObjectMeta.Name)
+}
+
+// NewGrafanaDashboardConfigMap builds the ConfigMap with Grafana dashboard of Jenkins metrics scraped by the PodMonitor
+// and the operator metrics of the Jenkins CR
+func NewGrafanaDashboardConfigMap(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) (*corev1.ConfigMap, error) {
+	meta.Name = GetGrafanaDashboardConfigMapName(jenkins)
+	labels := jenkins.Spec.Monitoring.GrafanaDashboard.Labels
+	if len(labels) == 0 {
+		labels = map[string]string{grafanaDashboardLabelKey: grafanaDashboardLabelValue}
+	}
+	meta.Labels = map[string]string{}
+	for key, value := range labels {
+		meta.Labels[key] = value
+	}
+	for key, value := range BuildResourceLabels(jenkins) {
+		meta.Labels[key] = value
+	}
+
+	dashboard, err := buildGrafanaDashboard(jenkins)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta:   buildConfigMapTypeMeta(),
+		ObjectMeta: meta,
+		Data: map[string]string{
+			getGrafanaDashboardFileName(jenkins): dashboard,
+		},
+	}, nil
+}
+
+// buildGrafanaDashboardRows returns panels of the dashboard, Jenkins metrics are exposed by the prometheus and metrics
+// plugins, the operator metrics are labeled by the namespace and name of the Jenkins CR
+func buildGrafanaDashboardRows(jenkins *virtuslabv1alpha1.Jenkins) []grafanaDashboardRow {
+	pod := fmt.Sprintf(`namespace="%s",pod="%s"`, jenkins.ObjectMeta.Namespace, GetResourceName(jenkins))
+	cr := fmt.Sprintf(`namespace="%s",jenkins="%s"`, jenkins.ObjectMeta.Namespace, jenkins.ObjectMeta.Name)
+
+	return []grafanaDashboardRow{
+		{
+			title: "Jenkins",
+			panels: []grafanaDashboardPanel{
+				{title: "Executors", unit: "short", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("sum(jenkins_executor_count_value{%s})", pod), legend: "total"},
+					{expr: fmt.Sprintf("sum(jenkins_executor_in_use_value{%s})", pod), legend: "in use"},
+				}},
+				{title: "Build queue", unit: "short", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("sum(jenkins_queue_size_value{%s})", pod), legend: "queued"},
+					{expr: fmt.Sprintf("sum(jenkins_queue_buildable_value{%s})", pod), legend: "buildable"},
+				}},
+				{title: "Builds", unit: "ops", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("sum(rate(jenkins_runs_success_total{%s}[5m]))", pod), legend: "success"},
+					{expr: fmt.Sprintf("sum(rate(jenkins_runs_failure_total{%s}[5m]))", pod), legend: "failure"},
+					{expr: fmt.Sprintf("sum(rate(jenkins_runs_unstable_total{%s}[5m]))", pod), legend: "unstable"},
+				}},
+				{title: "Online nodes", unit: "short", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("sum(jenkins_node_online_value{%s})", pod), legend: "online"},
+					{expr: fmt.Sprintf("sum(jenkins_node_count_value{%s})", pod), legend: "total"},
+				}},
+				{title: "JVM heap usage", unit: "percentunit", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("max(vm_memory_heap_usage{%s})", pod), legend: "heap"},
+				}},
+				{title: "Health check score", unit: "percentunit", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("max(jenkins_health_check_score{%s})", pod), legend: "score"},
+				}},
+			},
+		},
+		{
+			title: "Operator",
+			panels: []grafanaDashboardPanel{
+				{title: "Reconcile duration", unit: "s", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("histogram_quantile(0.95, sum(rate(jenkins_operator_reconcile_duration_seconds_bucket{%s}[5m])) by (le))", cr), legend: "p95"},
+					{expr: fmt.Sprintf("histogram_quantile(0.5, sum(rate(jenkins_operator_reconcile_duration_seconds_bucket{%s}[5m])) by (le))", cr), legend: "p50"},
+				}},
+				{title: "Reconcile errors", unit: "ops", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("sum(rate(jenkins_operator_reconcile_errors_total{%s}[5m])) by (reason)", cr), legend: "{{reason}}"},
+				}},
+				{title: "Jenkins restarts", unit: "short", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("sum(increase(jenkins_operator_jenkins_restarts_total{%s}[1h])) by (reason)", cr), legend: "{{reason}}"},
+				}},
+				{title: "Orphaned agent pods", unit: "short", queries: []grafanaDashboardQuery{
+					{expr: fmt.Sprintf("sum(jenkins_operator_orphaned_agent_pods{%s})", cr), legend: "orphaned"},
+					{expr: fmt.Sprintf("sum(increase(jenkins_operator_orphaned_agent_pods_deleted_total{%s}[1h]))", cr), legend: "deleted"},
+				}},
+			},
+		},
+	}
+}
+
+// buildGrafanaDashboard renders Grafana dashboard JSON of the Jenkins CR, the Prometheus data source is selected by
+// the datasource variable
+func buildGrafanaDashboard(jenkins *virtuslabv1alpha1.Jenkins) (string, error) {
+	var panels []map[string]interface{}
+	id, y := 1, 0
+	for _, row := range buildGrafanaDashboardRows(jenkins) {
+		panels = append(panels, map[string]interface{}{
+			"id":        id,
+			"type":      "row",
+			"title":     row.title,
+			"collapsed": false,
+			"panels":    []interface{}{},
+			"gridPos":   map[string]int{"x": 0, "y": y, "w": 2 * grafanaDashboardPanelWidth, "h": 1},
+		})
+		id++
+		y++
+		for i, panel := range row.panels {
+			var targets []map[string]interface{}
+			for j, query := range panel.queries {
+				targets = append(targets, map[string]interface{}{
+					"refId":        string(rune('A' + j)),
+					"expr":         query.expr,
+					"legendFormat": query.legend,
+				})
+			}
+			panels = append(panels, map[string]interface{}{
+				"id":         id,
+				"type":       "timeseries",
+				"title":      panel.title,
+				"datasource": "${datasource}",
+				"targets":    targets,
+				"fieldConfig": map[string]interface{}{
+					"defaults":  map[string]interface{}{"unit": panel.unit},
+					"overrides": []interface{}{},
+				},
+				"gridPos": map[string]int{
+					"x": (i % 2) * grafanaDashboardPanelWidth,
+					"y": y + (i/2)*grafanaDashboardPanelHeight,
+					"w": grafanaDashboardPanelWidth,
+					"h": grafanaDashboardPanelHeight,
+				},
+			})
+			id++
+		}
+		y += (len(row.panels) + 1) / 2 * grafanaDashboardPanelHeight
+	}
+
+	uid := sha256.Sum256([]byte(jenkins.ObjectMeta.Namespace + "/" + jenkins.ObjectMeta.Name))
+	dashboard := map[string]interface{}{
+		"uid":           "jenkins-" + hex.EncodeToString(uid[:])[:16],
+		"title":         fmt.Sprintf("Jenkins %s/%s", jenkins.ObjectMeta.Namespace, jenkins.ObjectMeta.Name),
+		"tags":          []string{"jenkins", constants.OperatorName},
+		"editable":      false,
+		"timezone":      "browser",
+		"refresh":       "1m",
+		"schemaVersion": grafanaDashboardSchemaVersion,
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":    "datasource",
+					"label":   "Data source",
+					"type":    "datasource",
+					"query":   "prometheus",
+					"current": map[string]interface{}{},
+					"hide":    0,
+				},
+			},
+		},
+		"panels": panels,
+	}
+
+	data, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
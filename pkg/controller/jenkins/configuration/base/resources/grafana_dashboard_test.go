@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGrafanaDashboardConfigMap(t *testing.T) {
+	monitoringSpec := func(dashboard *virtuslabv1alpha1.GrafanaDashboard) virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Monitoring: &virtuslabv1alpha1.Monitoring{GrafanaDashboard: dashboard},
+		}
+	}
+
+	t.Run("default label", func(t *testing.T) {
+		jenkins := newJenkins(monitoringSpec(&virtuslabv1alpha1.GrafanaDashboard{}))
+
+		configMap, err := NewGrafanaDashboardConfigMap(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "jenkins-operator-grafana-dashboard-jenkins-cr-name", configMap.Name)
+		assert.Equal(t, "1", configMap.Labels["grafana_dashboard"])
+		assert.Equal(t, "jenkins-cr-name", configMap.Labels["jenkins-cr"])
+		assert.Contains(t, configMap.Data, "jenkins-namespace-name-jenkins-cr-name.json")
+	})
+	t.Run("custom labels", func(t *testing.T) {
+		jenkins := newJenkins(monitoringSpec(&virtuslabv1alpha1.GrafanaDashboard{Labels: map[string]string{"dashboards": "jenkins"}}))
+
+		configMap, err := NewGrafanaDashboardConfigMap(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "jenkins", configMap.Labels["dashboards"])
+		assert.NotContains(t, configMap.Labels, "grafana_dashboard")
+	})
+	t.Run("dashboard of the instance", func(t *testing.T) {
+		jenkins := newJenkins(monitoringSpec(&virtuslabv1alpha1.GrafanaDashboard{}))
+
+		data, err := buildGrafanaDashboard(jenkins)
+		assert.NoError(t, err)
+
+		dashboard := struct {
+			UID    string `json:"uid"`
+			Title  string `json:"title"`
+			Panels []struct {
+				ID      int    `json:"id"`
+				Type    string `json:"type"`
+				Targets []struct {
+					Expr string `json:"expr"`
+				} `json:"targets"`
+			} `json:"panels"`
+		}{}
+		err = json.Unmarshal([]byte(data), &dashboard)
+		assert.NoError(t, err)
+		assert.Equal(t, "Jenkins namespace-name/jenkins-cr-name", dashboard.Title)
+		assert.True(t, len(dashboard.UID) <= 40)
+		ids := map[int]bool{}
+		var exprs []string
+		for _, panel := range dashboard.Panels {
+			assert.False(t, ids[panel.ID], "duplicated panel id %d", panel.ID)
+			ids[panel.ID] = true
+			for _, target := range panel.Targets {
+				exprs = append(exprs, target.Expr)
+			}
+		}
+		assert.Contains(t, exprs, `sum(jenkins_queue_size_value{namespace="namespace-name",pod="jenkins-operator-jenkins-cr-name"})`)
+		assert.Contains(t, exprs, `sum(rate(jenkins_operator_reconcile_errors_total{namespace="namespace-name",jenkins="jenkins-cr-name"}[5m])) by (reason)`)
+	})
+}
@@ -0,0 +1,87 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// IsHeadlessServiceEnabled tells if the headless Service of Jenkins master pod should exist
+func IsHeadlessServiceEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Service.Headless != nil
+}
+
+// GetHeadlessServiceName returns name of the headless Service of Jenkins master pod
+func GetHeadlessServiceName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-headless-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// NewHeadlessService builds the Kubernetes headless service resource resolving to Jenkins master pod IP, it exposes
+// all Jenkins master container ports
+func NewHeadlessService(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *corev1.Service {
+	spec := jenkins.Spec.Service.Headless
+	portNames := GetServicePortNames(jenkins)
+	selector := meta.Labels
+	meta.Name = GetHeadlessServiceName(jenkins)
+	meta.Annotations = map[string]string{}
+	for key, value := range spec.Annotations {
+		meta.Annotations[key] = value
+	}
+
+	service := &corev1.Service{
+		TypeMeta:   buildServiceTypeMeta(),
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
+			Selector:                 selector,
+			SessionAffinity:          corev1.ServiceAffinityNone,
+			PublishNotReadyAddresses: spec.PublishNotReadyAddresses,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       portNames.HTTP,
+					Port:       httpPortInt32,
+					TargetPort: intstr.FromInt(HTTPPortInt),
+				},
+				{
+					Name:       portNames.AgentListener,
+					Port:       slavePortInt32,
+					TargetPort: intstr.FromInt(slavePortInt),
+				},
+			},
+		},
+	}
+
+	if IsTLSEnabled(jenkins) {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:       portNames.HTTPS,
+			Port:       httpsPortInt32,
+			TargetPort: intstr.FromInt(HTTPSPortInt),
+		})
+	}
+	if IsSSHDEnabled(jenkins) {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:       portNames.SSHD,
+			Port:       GetSSHDPort(jenkins),
+			TargetPort: intstr.FromString(sshdPortName),
+		})
+	}
+
+	return service
+}
+
+// applyHeadlessServiceSubdomain sets hostname and subdomain of Jenkins master pod, so the cluster DNS publishes the pod
+// as <pod_name>.<headless_service_name>
+func applyHeadlessServiceSubdomain(pod *corev1.Pod, jenkins *virtuslabv1alpha1.Jenkins) {
+	if !IsHeadlessServiceEnabled(jenkins) {
+		return
+	}
+
+	pod.Spec.Hostname = pod.ObjectMeta.Name
+	pod.Spec.Subdomain = GetHeadlessServiceName(jenkins)
+}
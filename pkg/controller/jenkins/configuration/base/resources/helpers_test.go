@@ -0,0 +1,15 @@
+package resources
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newJenkins returns Jenkins CR with the given spec used as a fixture by the tests in this package
+func newJenkins(spec virtuslabv1alpha1.JenkinsSpec) *virtuslabv1alpha1.Jenkins {
+	return &virtuslabv1alpha1.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+		Spec:       spec,
+	}
+}
@@ -0,0 +1,76 @@
+package resources
+
+import (
+	"strings"
+
+	gatewayapiv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/gatewayapi/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsHTTPRouteEnabled tells if the operator manages Gateway API HTTPRoute of Jenkins UI
+func IsHTTPRouteEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Service.HTTPRoute != nil
+}
+
+// GetHTTPRouteGateway returns namespace and name of the Gateway referenced by Jenkins.Spec.Service.HTTPRoute.Gateway,
+// the Jenkins CR namespace is used when the reference doesn't contain it
+func GetHTTPRouteGateway(jenkins *virtuslabv1alpha1.Jenkins) (string, string) {
+	gateway := jenkins.Spec.Service.HTTPRoute.Gateway
+	if parts := strings.SplitN(gateway, "/", 2); len(parts) == 2 {
+		return parts[0], parts[1]
+	}
+	return jenkins.ObjectMeta.Namespace, gateway
+}
+
+// NewHTTPRoute builds Gateway API HTTPRoute routing Jenkins.Spec.Service.HTTPRoute.Host on the referenced Gateway to
+// Jenkins master HTTP port, the Gateway terminates TLS
+func NewHTTPRoute(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *gatewayapiv1.HTTPRoute {
+	spec := jenkins.Spec.Service.HTTPRoute
+	gatewayNamespace, gatewayName := GetHTTPRouteGateway(jenkins)
+	meta.Annotations = map[string]string{}
+	for key, value := range spec.Annotations {
+		meta.Annotations[key] = value
+	}
+
+	return &gatewayapiv1.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       gatewayapiv1.HTTPRouteKind,
+			APIVersion: gatewayapiv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: gatewayapiv1.HTTPRouteSpec{
+			ParentRefs: []gatewayapiv1.ParentReference{
+				{
+					Group:       gatewayapiv1.SchemeGroupVersion.Group,
+					Kind:        gatewayapiv1.GatewayKind,
+					Namespace:   gatewayNamespace,
+					Name:        gatewayName,
+					SectionName: spec.SectionName,
+				},
+			},
+			Hostnames: []string{spec.Host},
+			Rules: []gatewayapiv1.HTTPRouteRule{
+				{
+					Matches: []gatewayapiv1.HTTPRouteMatch{
+						{
+							Path: &gatewayapiv1.HTTPPathMatch{
+								Type:  gatewayapiv1.PathMatchPathPrefix,
+								Value: getIngressPath(jenkins),
+							},
+						},
+					},
+					BackendRefs: []gatewayapiv1.HTTPBackendRef{
+						{
+							Kind:   gatewayapiv1.ServiceKind,
+							Name:   GetResourceName(jenkins),
+							Port:   httpPortInt32,
+							Weight: 1,
+						},
+					},
+				},
+			},
+		},
+	}
+}
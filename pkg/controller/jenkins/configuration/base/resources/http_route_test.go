@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"testing"
+
+	gatewayapiv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/gatewayapi/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPRoute(t *testing.T) {
+	httpRouteSpec := func(httpRoute *virtuslabv1alpha1.HTTPRoute, prefix string) virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Master:  virtuslabv1alpha1.JenkinsMaster{Prefix: prefix},
+			Service: virtuslabv1alpha1.JenkinsService{HTTPRoute: httpRoute},
+		}
+	}
+
+	t.Run("Gateway in another namespace", func(t *testing.T) {
+		jenkins := newJenkins(httpRouteSpec(&virtuslabv1alpha1.HTTPRoute{
+			Host:        "jenkins.example.com",
+			Gateway:     "gateway-system/public",
+			SectionName: "https",
+			HTTPS:       true,
+		}, ""))
+
+		httpRoute := NewHTTPRoute(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, gatewayapiv1.HTTPRouteKind, httpRoute.Kind)
+		assert.Equal(t, "gateway.networking.k8s.io/v1", httpRoute.APIVersion)
+		parentRef := httpRoute.Spec.ParentRefs[0]
+		assert.Equal(t, "gateway-system", parentRef.Namespace)
+		assert.Equal(t, "public", parentRef.Name)
+		assert.Equal(t, "https", parentRef.SectionName)
+		assert.Equal(t, []string{"jenkins.example.com"}, httpRoute.Spec.Hostnames)
+		assert.Equal(t, "/", httpRoute.Spec.Rules[0].Matches[0].Path.Value)
+		backendRef := httpRoute.Spec.Rules[0].BackendRefs[0]
+		assert.Equal(t, "jenkins-operator-jenkins-cr-name", backendRef.Name)
+		assert.Equal(t, int32(HTTPPortInt), backendRef.Port)
+		assert.Equal(t, "https://jenkins.example.com/", GetJenkinsRootURL(jenkins))
+	})
+	t.Run("Gateway in Jenkins CR namespace with prefix", func(t *testing.T) {
+		jenkins := newJenkins(httpRouteSpec(&virtuslabv1alpha1.HTTPRoute{Host: "jenkins.example.com", Gateway: "public"}, "/jenkins"))
+
+		httpRoute := NewHTTPRoute(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "namespace-name", httpRoute.Spec.ParentRefs[0].Namespace)
+		assert.Empty(t, httpRoute.Spec.ParentRefs[0].SectionName)
+		assert.Equal(t, "/jenkins", httpRoute.Spec.Rules[0].Matches[0].Path.Value)
+		assert.Equal(t, "http://jenkins.example.com/jenkins/", GetJenkinsRootURL(jenkins))
+	})
+}
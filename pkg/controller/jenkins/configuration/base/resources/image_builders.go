@@ -0,0 +1,139 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultDindImage is the image of Docker in Docker container
+	DefaultDindImage = "docker:24.0-dind"
+	// DefaultBuildKitImage is the image of rootless BuildKit daemon container
+	DefaultBuildKitImage = "moby/buildkit:v0.12.5-rootless"
+	// DefaultKanikoImage is the image of Kaniko executor container, the debug image contains shell keeping it running
+	DefaultKanikoImage = "gcr.io/kaniko-project/executor:v1.23.2-debug"
+
+	// DindContainerName is the name of Docker in Docker container
+	DindContainerName = "dind"
+	// BuildKitContainerName is the name of BuildKit daemon container
+	BuildKitContainerName = "buildkitd"
+	// KanikoContainerName is the name of Kaniko executor container
+	KanikoContainerName = "kaniko"
+
+	dindSocketDirectory     = "/var/run/dind"
+	buildKitSocketDirectory = "/run/user/1000/buildkit"
+
+	// agentUserID is the user and group of the jnlp container in the inbound agent images
+	agentUserID int64 = 1000
+)
+
+// imageBuilderContainerNames contains names of the image builder containers added to agent pods
+var imageBuilderContainerNames = map[virtuslabv1alpha1.ImageBuilderType]string{
+	virtuslabv1alpha1.ImageBuilderTypeDind:     DindContainerName,
+	virtuslabv1alpha1.ImageBuilderTypeBuildKit: BuildKitContainerName,
+	virtuslabv1alpha1.ImageBuilderTypeKaniko:   KanikoContainerName,
+}
+
+// GetImageBuilderContainerName returns name of the container added to agent pods by the image builder
+func GetImageBuilderContainerName(builder virtuslabv1alpha1.ImageBuilderType) string {
+	return imageBuilderContainerNames[builder]
+}
+
+// applyImageBuilder adds container of AgentPodTemplate.ImageBuilder with its volumes and security context to the agent
+// pod, the jnlp container gets the environment variable pointing to the daemon socket
+func applyImageBuilder(pod *corev1.Pod, builder *virtuslabv1alpha1.ImageBuilder) {
+	if builder == nil {
+		return
+	}
+
+	var container corev1.Container
+	var agentEnv []corev1.EnvVar
+	var agentVolumeMounts []corev1.VolumeMount
+	switch builder.Type {
+	case virtuslabv1alpha1.ImageBuilderTypeDind:
+		// Docker daemon listens only on the socket shared with the jnlp container, TCP port would be reachable by
+		// other pods, the socket group allows jnlp container user to use it
+		privileged := true
+		container = corev1.Container{
+			Name:  DindContainerName,
+			Image: DefaultDindImage,
+			Args:  []string{"--host=unix://" + dindSocketDirectory + "/docker.sock", fmt.Sprintf("--group=%d", agentUserID)},
+			Env:   []corev1.EnvVar{{Name: "DOCKER_TLS_CERTDIR", Value: ""}},
+			SecurityContext: &corev1.SecurityContext{
+				Privileged: &privileged,
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "dind-socket", MountPath: dindSocketDirectory},
+				{Name: "dind-storage", MountPath: "/var/lib/docker"},
+			},
+		}
+		agentEnv = []corev1.EnvVar{{Name: "DOCKER_HOST", Value: "unix://" + dindSocketDirectory + "/docker.sock"}}
+		agentVolumeMounts = []corev1.VolumeMount{{Name: "dind-socket", MountPath: dindSocketDirectory}}
+		pod.Spec.Volumes = append(pod.Spec.Volumes,
+			corev1.Volume{Name: "dind-socket", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			corev1.Volume{Name: "dind-storage", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+	case virtuslabv1alpha1.ImageBuilderTypeBuildKit:
+		// rootless BuildKit isn't privileged, it only needs unconfined seccomp and AppArmor profiles to create user
+		// namespaces
+		userID := agentUserID
+		runAsNonRoot := true
+		buildKitHost := "unix://" + buildKitSocketDirectory + "/buildkitd.sock"
+		container = corev1.Container{
+			Name:  BuildKitContainerName,
+			Image: DefaultBuildKitImage,
+			Args:  []string{"--addr", buildKitHost, "--oci-worker-no-process-sandbox"},
+			Env:   []corev1.EnvVar{{Name: "BUILDKIT_HOST", Value: buildKitHost}},
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser:    &userID,
+				RunAsGroup:   &userID,
+				RunAsNonRoot: &runAsNonRoot,
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "buildkit-socket", MountPath: buildKitSocketDirectory},
+				{Name: "buildkit-storage", MountPath: "/home/user/.local/share/buildkit"},
+			},
+		}
+		agentEnv = []corev1.EnvVar{{Name: "BUILDKIT_HOST", Value: buildKitHost}}
+		agentVolumeMounts = []corev1.VolumeMount{{Name: "buildkit-socket", MountPath: buildKitSocketDirectory}}
+		pod.Spec.Volumes = append(pod.Spec.Volumes,
+			corev1.Volume{Name: "buildkit-socket", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			corev1.Volume{Name: "buildkit-storage", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+		if pod.ObjectMeta.Annotations == nil {
+			pod.ObjectMeta.Annotations = map[string]string{}
+		}
+		pod.ObjectMeta.Annotations["container.seccomp.security.alpha.kubernetes.io/"+BuildKitContainerName] = "unconfined"
+		pod.ObjectMeta.Annotations["container.apparmor.security.beta.kubernetes.io/"+BuildKitContainerName] = "unconfined"
+	case virtuslabv1alpha1.ImageBuilderTypeKaniko:
+		// Kaniko runs as root to unpack the base image, but it doesn't need privileged container, the executor is
+		// called in the workspace shared by Kubernetes plugin
+		rootUserID := int64(0)
+		privileged := false
+		container = corev1.Container{
+			Name:    KanikoContainerName,
+			Image:   DefaultKanikoImage,
+			Command: []string{"/busybox/cat"},
+			TTY:     true,
+			SecurityContext: &corev1.SecurityContext{
+				RunAsUser:  &rootUserID,
+				Privileged: &privileged,
+			},
+		}
+	default:
+		// unknown image builders are rejected by the validation
+		return
+	}
+	if len(builder.Image) > 0 {
+		container.Image = builder.Image
+	}
+
+	pod.Spec.Containers = append(pod.Spec.Containers, container)
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == AgentContainerName {
+			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, agentEnv...)
+			pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, agentVolumeMounts...)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	certmanagerv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/certmanager/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// IngressClassAnnotationKey is the annotation selecting ingress controller of the Ingress
+	IngressClassAnnotationKey = "kubernetes.io/ingress.class"
+
+	// ingress-nginx annotations restricting access to Jenkins UI
+	nginxWhitelistSourceRangeAnnotationKey = "nginx.ingress.kubernetes.io/whitelist-source-range"
+	nginxAuthURLAnnotationKey              = "nginx.ingress.kubernetes.io/auth-url"
+	nginxAuthSigninAnnotationKey           = "nginx.ingress.kubernetes.io/auth-signin"
+	nginxAuthTypeAnnotationKey             = "nginx.ingress.kubernetes.io/auth-type"
+	nginxAuthSecretAnnotationKey           = "nginx.ingress.kubernetes.io/auth-secret"
+	nginxAuthRealmAnnotationKey            = "nginx.ingress.kubernetes.io/auth-realm"
+)
+
+// IsIngressEnabled tells if the operator manages Ingress of Jenkins UI
+func IsIngressEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Service.Ingress != nil
+}
+
+// GetIngressCertificateName returns name of cert-manager Certificate of the Ingress host
+func GetIngressCertificateName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-ingress-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// getIngressPath returns path routed by the Ingress, Jenkins.Spec.Master.Prefix or the root path
+func getIngressPath(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if prefix := GetJenkinsPrefix(jenkins); len(prefix) > 0 {
+		return prefix
+	}
+	return "/"
+}
+
+// NewIngress builds the Kubernetes ingress resource routing Jenkins.Spec.Service.Ingress.Host to Jenkins master
+// HTTP port, the ingress controller terminates TLS
+func NewIngress(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *extensionsv1beta1.Ingress {
+	spec := jenkins.Spec.Service.Ingress
+	meta.Annotations = map[string]string{}
+	for key, value := range spec.Annotations {
+		meta.Annotations[key] = value
+	}
+	if len(spec.IngressClassName) > 0 {
+		meta.Annotations[IngressClassAnnotationKey] = spec.IngressClassName
+	}
+	applyIngressAccessAnnotations(meta.Annotations, spec)
+	applyIngressResponseHeadersAnnotation(meta.Annotations, spec)
+	applyExternalDNSAnnotations(meta.Annotations, jenkins, true)
+
+	ingress := &extensionsv1beta1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: meta,
+		Spec: extensionsv1beta1.IngressSpec{
+			Rules: []extensionsv1beta1.IngressRule{
+				{
+					Host: spec.Host,
+					IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+						HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+							Paths: []extensionsv1beta1.HTTPIngressPath{
+								{
+									Path: getIngressPath(jenkins),
+									Backend: extensionsv1beta1.IngressBackend{
+										ServiceName: GetResourceName(jenkins),
+										ServicePort: intstr.FromInt(HTTPPortInt),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if spec.TLS != nil {
+		ingress.Spec.TLS = []extensionsv1beta1.IngressTLS{
+			{
+				Hosts:      []string{spec.Host},
+				SecretName: spec.TLS.SecretName,
+			},
+		}
+	}
+
+	return ingress
+}
+
+// applyIngressAccessAnnotations sets ingress-nginx annotations from Jenkins.Spec.Service.Ingress.AllowSourceRanges and
+// Jenkins.Spec.Service.Ingress.Auth, they override the same annotations of Jenkins.Spec.Service.Ingress.Annotations
+func applyIngressAccessAnnotations(annotations map[string]string, ingress *virtuslabv1alpha1.Ingress) {
+	if len(ingress.AllowSourceRanges) > 0 {
+		annotations[nginxWhitelistSourceRangeAnnotationKey] = strings.Join(ingress.AllowSourceRanges, ",")
+	}
+
+	auth := ingress.Auth
+	if auth == nil {
+		return
+	}
+	if len(auth.OAuth2ProxyURL) > 0 {
+		oauth2ProxyURL := strings.TrimSuffix(auth.OAuth2ProxyURL, "/")
+		annotations[nginxAuthURLAnnotationKey] = oauth2ProxyURL + "/auth"
+		// oauth2-proxy redirects back to the requested page after sign in
+		annotations[nginxAuthSigninAnnotationKey] = oauth2ProxyURL + "/start?rd=$scheme://$host$escaped_request_uri"
+	}
+	if len(auth.BasicAuthSecretName) > 0 {
+		annotations[nginxAuthTypeAnnotationKey] = "basic"
+		annotations[nginxAuthSecretAnnotationKey] = auth.BasicAuthSecretName
+		realm := auth.BasicAuthRealm
+		if len(realm) == 0 {
+			realm = "Authentication Required"
+		}
+		annotations[nginxAuthRealmAnnotationKey] = realm
+	}
+}
+
+// NewIngressCertificate builds cert-manager Certificate of Jenkins.Spec.Service.Ingress.Host, it's used only
+// when Jenkins.Spec.Service.Ingress.TLS.IssuerRef is set
+func NewIngressCertificate(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *certmanagerv1.Certificate {
+	ingress := jenkins.Spec.Service.Ingress
+	meta.Name = GetIngressCertificateName(jenkins)
+
+	return &certmanagerv1.Certificate{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       certmanagerv1.CertificateKind,
+			APIVersion: certmanagerv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: certmanagerv1.CertificateSpec{
+			DNSNames:   []string{ingress.Host},
+			SecretName: ingress.TLS.SecretName,
+			IssuerRef: certmanagerv1.ObjectReference{
+				Name:  ingress.TLS.IssuerRef.Name,
+				Kind:  ingress.TLS.IssuerRef.Kind,
+				Group: ingress.TLS.IssuerRef.Group,
+			},
+		},
+	}
+}
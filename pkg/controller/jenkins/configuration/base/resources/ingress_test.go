@@ -0,0 +1,115 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestNewIngress(t *testing.T) {
+	ingressSpec := func() virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Service: virtuslabv1alpha1.JenkinsService{
+				Ingress: &virtuslabv1alpha1.Ingress{
+					Host:             "jenkins.example.com",
+					IngressClassName: "nginx",
+					Annotations:      map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "50m"},
+				},
+			},
+		}
+	}
+
+	t.Run("HTTP", func(t *testing.T) {
+		jenkins := newJenkins(ingressSpec())
+
+		ingress := NewIngress(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "jenkins-operator-jenkins-cr-name", ingress.Name)
+		assert.Equal(t, map[string]string{
+			"nginx.ingress.kubernetes.io/proxy-body-size": "50m",
+			IngressClassAnnotationKey:                     "nginx",
+		}, ingress.Annotations)
+		assert.Equal(t, "jenkins.example.com", ingress.Spec.Rules[0].Host)
+		backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend
+		assert.Equal(t, "jenkins-operator-jenkins-cr-name", backend.ServiceName)
+		assert.Equal(t, intstr.FromInt(HTTPPortInt), backend.ServicePort)
+		assert.Empty(t, ingress.Spec.TLS)
+		assert.Equal(t, "http://jenkins.example.com/", GetJenkinsRootURL(jenkins))
+		assert.Contains(t, buildConfigureJenkinsLocationGroovyScript(jenkins), "location.setUrl('http://jenkins.example.com/')")
+	})
+	t.Run("TLS with cert-manager issuer", func(t *testing.T) {
+		jenkins := newJenkins(ingressSpec())
+		jenkins.Spec.Service.Ingress.TLS = &virtuslabv1alpha1.IngressTLS{
+			SecretName: "jenkins-ingress-tls",
+			IssuerRef:  &virtuslabv1alpha1.CertManagerIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+		}
+
+		ingress := NewIngress(NewResourceObjectMeta(jenkins), jenkins)
+		certificate := NewIngressCertificate(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, []string{"jenkins.example.com"}, ingress.Spec.TLS[0].Hosts)
+		assert.Equal(t, "jenkins-ingress-tls", ingress.Spec.TLS[0].SecretName)
+		assert.Equal(t, "https://jenkins.example.com/", GetJenkinsRootURL(jenkins))
+		assert.Equal(t, "jenkins-operator-ingress-jenkins-cr-name", certificate.Name)
+		assert.Equal(t, []string{"jenkins.example.com"}, certificate.Spec.DNSNames)
+		assert.Equal(t, "jenkins-ingress-tls", certificate.Spec.SecretName)
+		assert.Equal(t, "letsencrypt", certificate.Spec.IssuerRef.Name)
+	})
+	t.Run("access restrictions", func(t *testing.T) {
+		jenkins := newJenkins(ingressSpec())
+		jenkins.Spec.Service.Ingress.AllowSourceRanges = []string{"10.0.0.0/8", "192.168.0.0/16"}
+		jenkins.Spec.Service.Ingress.Auth = &virtuslabv1alpha1.IngressAuth{OAuth2ProxyURL: "https://auth.example.com/oauth2/"}
+
+		ingress := NewIngress(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "10.0.0.0/8,192.168.0.0/16", ingress.Annotations["nginx.ingress.kubernetes.io/whitelist-source-range"])
+		assert.Equal(t, "https://auth.example.com/oauth2/auth", ingress.Annotations["nginx.ingress.kubernetes.io/auth-url"])
+		assert.Equal(t, "https://auth.example.com/oauth2/start?rd=$scheme://$host$escaped_request_uri",
+			ingress.Annotations["nginx.ingress.kubernetes.io/auth-signin"])
+		assert.NotContains(t, ingress.Annotations, "nginx.ingress.kubernetes.io/auth-type")
+	})
+	t.Run("basic authentication", func(t *testing.T) {
+		jenkins := newJenkins(ingressSpec())
+		jenkins.Spec.Service.Ingress.Auth = &virtuslabv1alpha1.IngressAuth{BasicAuthSecretName: "jenkins-basic-auth"}
+
+		ingress := NewIngress(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "basic", ingress.Annotations["nginx.ingress.kubernetes.io/auth-type"])
+		assert.Equal(t, "jenkins-basic-auth", ingress.Annotations["nginx.ingress.kubernetes.io/auth-secret"])
+		assert.Equal(t, "Authentication Required", ingress.Annotations["nginx.ingress.kubernetes.io/auth-realm"])
+	})
+	t.Run("response headers", func(t *testing.T) {
+		jenkins := newJenkins(ingressSpec())
+		jenkins.Spec.Service.Ingress.Annotations[nginxConfigurationSnippetAnnotationKey] = "proxy_hide_header X-Powered-By;"
+		jenkins.Spec.Service.Ingress.ResponseHeaders = map[string]string{
+			"X-Frame-Options":           "DENY",
+			"Strict-Transport-Security": "max-age=31536000",
+		}
+
+		ingress := NewIngress(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "proxy_hide_header X-Powered-By;\n"+
+			"more_set_headers \"Strict-Transport-Security: max-age=31536000\";\n"+
+			"more_set_headers \"X-Frame-Options: DENY\";\n", ingress.Annotations[nginxConfigurationSnippetAnnotationKey])
+	})
+	t.Run("prefix", func(t *testing.T) {
+		jenkins := newJenkins(ingressSpec())
+		jenkins.Spec.Master.Prefix = "/jenkins"
+
+		ingress := NewIngress(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "/jenkins", ingress.Spec.Rules[0].HTTP.Paths[0].Path)
+		assert.Equal(t, "http://jenkins.example.com/jenkins/", GetJenkinsRootURL(jenkins))
+		assert.Contains(t, buildConfigureJenkinsLocationGroovyScript(jenkins), "location.setUrl('http://jenkins.example.com/jenkins/')")
+	})
+	t.Run("disabled", func(t *testing.T) {
+		jenkins := newJenkins(ingressSpec())
+		jenkins.Spec.Service.Ingress = nil
+
+		assert.Empty(t, GetJenkinsRootURL(jenkins))
+		assert.Empty(t, buildConfigureJenkinsLocationGroovyScript(jenkins))
+	})
+}
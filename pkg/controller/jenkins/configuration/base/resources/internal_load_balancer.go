@@ -0,0 +1,41 @@
+package resources
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const (
+	// AWSInternalLoadBalancerAnnotationKey is the annotation creating internal AWS Elastic Load Balancer
+	AWSInternalLoadBalancerAnnotationKey = "service.beta.kubernetes.io/aws-load-balancer-internal"
+	// GCPLoadBalancerTypeAnnotationKey is the annotation selecting Google Cloud load balancer type
+	GCPLoadBalancerTypeAnnotationKey = "cloud.google.com/load-balancer-type"
+	// AzureInternalLoadBalancerAnnotationKey is the annotation creating internal Azure load balancer
+	AzureInternalLoadBalancerAnnotationKey = "service.beta.kubernetes.io/azure-load-balancer-internal"
+)
+
+// internalLoadBalancerAnnotations contains annotations of internal load balancer per cloud provider
+var internalLoadBalancerAnnotations = map[virtuslabv1alpha1.InternalLoadBalancerProvider]map[string]string{
+	virtuslabv1alpha1.InternalLoadBalancerProviderAWS:   {AWSInternalLoadBalancerAnnotationKey: "true"},
+	virtuslabv1alpha1.InternalLoadBalancerProviderGCP:   {GCPLoadBalancerTypeAnnotationKey: "Internal"},
+	virtuslabv1alpha1.InternalLoadBalancerProviderAzure: {AzureInternalLoadBalancerAnnotationKey: "true"},
+}
+
+// InternalLoadBalancerAnnotationKeys contains all internal load balancer annotations managed by the operator
+var InternalLoadBalancerAnnotationKeys = []string{AWSInternalLoadBalancerAnnotationKey, GCPLoadBalancerTypeAnnotationKey,
+	AzureInternalLoadBalancerAnnotationKey}
+
+// IsInternalLoadBalancerEnabled tells if the load balancer of Jenkins master Service is reachable only from the cluster
+// network
+func IsInternalLoadBalancerEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return len(jenkins.Spec.Service.InternalLoadBalancer) > 0
+}
+
+// applyInternalLoadBalancerAnnotations sets annotations of Jenkins.Spec.Service.InternalLoadBalancer cloud provider,
+// annotations set already aren't overridden
+func applyInternalLoadBalancerAnnotations(annotations map[string]string, jenkins *virtuslabv1alpha1.Jenkins) {
+	for key, value := range internalLoadBalancerAnnotations[jenkins.Spec.Service.InternalLoadBalancer] {
+		if _, found := annotations[key]; !found {
+			annotations[key] = value
+		}
+	}
+}
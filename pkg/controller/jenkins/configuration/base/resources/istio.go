@@ -0,0 +1,150 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	istiov1beta1 "github.com/VirtusLab/jenkins-operator/pkg/apis/istio/v1beta1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IstioSidecarInjectLabelKey is the pod label disabling Istio sidecar injection of Kubernetes plugin agents
+	IstioSidecarInjectLabelKey = "sidecar.istio.io/inject"
+	// IstioRewriteAppHTTPProbersAnnotationKey is the pod annotation which makes Istio sidecar handle HTTP probes,
+	// the probes would be rejected by the sidecar with mTLS otherwise
+	IstioRewriteAppHTTPProbersAnnotationKey = "sidecar.istio.io/rewriteAppHTTPProbers"
+	// IstioProxyConfigAnnotationKey is the pod annotation overriding Istio sidecar configuration
+	IstioProxyConfigAnnotationKey = "proxy.istio.io/config"
+	// IstioExcludeInboundPortsAnnotationKey is the pod annotation listing ports bypassing Istio sidecar
+	IstioExcludeInboundPortsAnnotationKey = "traffic.sidecar.istio.io/excludeInboundPorts"
+)
+
+// defaultIstioGatewaySelector selects pods of the default Istio ingress gateway
+var defaultIstioGatewaySelector = map[string]string{"istio": "ingressgateway"}
+
+// IsIstioEnabled tells if the operator manages Istio resources of Jenkins UI and adjusts Jenkins master to Istio
+// sidecar
+func IsIstioEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Service.Istio != nil
+}
+
+// IsIstioGatewayManaged tells if the operator creates Istio Gateway jenkins-operator-<cr_name>
+func IsIstioGatewayManaged(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return IsIstioEnabled(jenkins) && len(jenkins.Spec.Service.Istio.Gateway) == 0
+}
+
+// GetIstioGateway returns Istio Gateway routing traffic to Jenkins master as <namespace>/<name>
+func GetIstioGateway(jenkins *virtuslabv1alpha1.Jenkins) string {
+	gateway := jenkins.Spec.Service.Istio.Gateway
+	if len(gateway) == 0 {
+		gateway = GetResourceName(jenkins)
+	}
+	if !strings.Contains(gateway, "/") {
+		gateway = fmt.Sprintf("%s/%s", jenkins.ObjectMeta.Namespace, gateway)
+	}
+	return gateway
+}
+
+// NewIstioGateway builds Istio Gateway exposing Jenkins.Spec.Service.Istio.Host on Istio ingress gateway, plain HTTP
+// requests are redirected to HTTPS when Jenkins.Spec.Service.Istio.TLSSecretName is set
+func NewIstioGateway(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *istiov1beta1.Gateway {
+	spec := jenkins.Spec.Service.Istio
+	selector := spec.GatewaySelector
+	if len(selector) == 0 {
+		selector = defaultIstioGatewaySelector
+	}
+
+	httpServer := istiov1beta1.Server{
+		Port:  istiov1beta1.Port{Number: 80, Protocol: istiov1beta1.ProtocolHTTP, Name: "http"},
+		Hosts: []string{spec.Host},
+	}
+	servers := []istiov1beta1.Server{httpServer}
+	if len(spec.TLSSecretName) > 0 {
+		servers[0].TLS = &istiov1beta1.ServerTLSSettings{HTTPSRedirect: true}
+		servers = append(servers, istiov1beta1.Server{
+			Port:  istiov1beta1.Port{Number: 443, Protocol: istiov1beta1.ProtocolHTTPS, Name: "https"},
+			Hosts: []string{spec.Host},
+			TLS: &istiov1beta1.ServerTLSSettings{
+				Mode:           istiov1beta1.TLSModeSimple,
+				CredentialName: spec.TLSSecretName,
+			},
+		})
+	}
+
+	return &istiov1beta1.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       istiov1beta1.GatewayKind,
+			APIVersion: istiov1beta1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: istiov1beta1.GatewaySpec{
+			Selector: selector,
+			Servers:  servers,
+		},
+	}
+}
+
+// NewIstioVirtualService builds Istio VirtualService routing Jenkins.Spec.Service.Istio.Host on the gateway to Jenkins
+// master HTTP port
+func NewIstioVirtualService(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *istiov1beta1.VirtualService {
+	return &istiov1beta1.VirtualService{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       istiov1beta1.VirtualServiceKind,
+			APIVersion: istiov1beta1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: istiov1beta1.VirtualServiceSpec{
+			Hosts:    []string{jenkins.Spec.Service.Istio.Host},
+			Gateways: []string{GetIstioGateway(jenkins)},
+			HTTP: []istiov1beta1.HTTPRoute{
+				{
+					Route: []istiov1beta1.HTTPRouteDestination{
+						{
+							Destination: istiov1beta1.Destination{
+								Host: fmt.Sprintf("%s.%s.svc.cluster.local", GetResourceName(jenkins), jenkins.ObjectMeta.Namespace),
+								Port: &istiov1beta1.PortSelector{Number: uint32(HTTPPortInt)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// getJenkinsMasterPodAnnotations returns Jenkins master pod annotations, Jenkins.Spec.Master.Annotations are merged with
+// the annotations adjusting Istio sidecar when Istio is enabled
+func getJenkinsMasterPodAnnotations(jenkins *virtuslabv1alpha1.Jenkins) map[string]string {
+	if !IsIstioEnabled(jenkins) {
+		return jenkins.Spec.Master.Annotations
+	}
+
+	annotations := map[string]string{
+		IstioRewriteAppHTTPProbersAnnotationKey: "true",
+		// Jenkins starts after the sidecar so that plugins can be downloaded during the start
+		IstioProxyConfigAnnotationKey: "holdApplicationUntilProxyStarts: true",
+		// agents run without the sidecar and the remoting protocol has its own TLS
+		IstioExcludeInboundPortsAnnotationKey: fmt.Sprintf("%d", slavePortInt),
+	}
+	for key, value := range jenkins.Spec.Master.Annotations {
+		annotations[key] = value
+	}
+	return annotations
+}
+
+// buildKubernetesCloudIstioGroovyScript returns groovy statements disabling Istio sidecar injection of Kubernetes
+// plugin agents, the sidecar would keep finished agent pods running, returns empty string when Istio is disabled
+func buildKubernetesCloudIstioGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if !IsIstioEnabled(jenkins) {
+		return ""
+	}
+
+	return fmt.Sprintf(`kubernetes.setPodLabels([
+        new org.csanchez.jenkins.plugins.kubernetes.PodLabel('%s', '%s'),
+        new org.csanchez.jenkins.plugins.kubernetes.PodLabel('%s', 'false'),
+])
+`, agentPodLabelKey, agentPodLabelValue, IstioSidecarInjectLabelKey)
+}
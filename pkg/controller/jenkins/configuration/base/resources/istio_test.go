@@ -0,0 +1,68 @@
+package resources
+
+import (
+	"testing"
+
+	istiov1beta1 "github.com/VirtusLab/jenkins-operator/pkg/apis/istio/v1beta1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIstio(t *testing.T) {
+	istioSpec := func(istio *virtuslabv1alpha1.Istio) virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Master:  virtuslabv1alpha1.JenkinsMaster{Annotations: map[string]string{"test": "value"}},
+			Service: virtuslabv1alpha1.JenkinsService{Istio: istio},
+		}
+	}
+
+	t.Run("managed Gateway with TLS", func(t *testing.T) {
+		jenkins := newJenkins(istioSpec(&virtuslabv1alpha1.Istio{Host: "jenkins.example.com", TLSSecretName: "jenkins-tls"}))
+
+		gateway := NewIstioGateway(NewResourceObjectMeta(jenkins), jenkins)
+		virtualService := NewIstioVirtualService(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.True(t, IsIstioGatewayManaged(jenkins))
+		assert.Equal(t, map[string]string{"istio": "ingressgateway"}, gateway.Spec.Selector)
+		assert.Len(t, gateway.Spec.Servers, 2)
+		assert.True(t, gateway.Spec.Servers[0].TLS.HTTPSRedirect)
+		assert.Equal(t, istiov1beta1.ProtocolHTTPS, gateway.Spec.Servers[1].Port.Protocol)
+		assert.Equal(t, "jenkins-tls", gateway.Spec.Servers[1].TLS.CredentialName)
+		assert.Equal(t, []string{"namespace-name/jenkins-operator-jenkins-cr-name"}, virtualService.Spec.Gateways)
+		assert.Equal(t, []string{"jenkins.example.com"}, virtualService.Spec.Hosts)
+		destination := virtualService.Spec.HTTP[0].Route[0].Destination
+		assert.Equal(t, "jenkins-operator-jenkins-cr-name.namespace-name.svc.cluster.local", destination.Host)
+		assert.Equal(t, uint32(HTTPPortInt), destination.Port.Number)
+		assert.Equal(t, "https://jenkins.example.com/", GetJenkinsRootURL(jenkins))
+	})
+	t.Run("existing Gateway", func(t *testing.T) {
+		jenkins := newJenkins(istioSpec(&virtuslabv1alpha1.Istio{Host: "jenkins.example.com", Gateway: "public-gateway"}))
+
+		virtualService := NewIstioVirtualService(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.False(t, IsIstioGatewayManaged(jenkins))
+		assert.Equal(t, []string{"namespace-name/public-gateway"}, virtualService.Spec.Gateways)
+		assert.Equal(t, "http://jenkins.example.com/", GetJenkinsRootURL(jenkins))
+	})
+	t.Run("sidecar aware Jenkins master", func(t *testing.T) {
+		jenkins := newJenkins(istioSpec(&virtuslabv1alpha1.Istio{Host: "jenkins.example.com"}))
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+
+		assert.Equal(t, "value", pod.Annotations["test"])
+		assert.Equal(t, "true", pod.Annotations[IstioRewriteAppHTTPProbersAnnotationKey])
+		assert.Equal(t, "50000", pod.Annotations[IstioExcludeInboundPortsAnnotationKey])
+		assert.Equal(t, "tcp-jnlp", service.Spec.Ports[1].Name)
+		assert.Contains(t, buildKubernetesCloudIstioGroovyScript(jenkins), "PodLabel('sidecar.istio.io/inject', 'false')")
+	})
+	t.Run("disabled", func(t *testing.T) {
+		jenkins := newJenkins(istioSpec(nil))
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NotContains(t, pod.Annotations, IstioRewriteAppHTTPProbersAnnotationKey)
+		assert.Empty(t, buildKubernetesCloudIstioGroovyScript(jenkins))
+	})
+}
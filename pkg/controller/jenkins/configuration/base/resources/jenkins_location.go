@@ -0,0 +1,100 @@
+package resources
+
+import (
+	"fmt"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+// GetJenkinsRootURL returns Jenkins root URL from Jenkins.Spec.ExternalURL or the Ingress, OpenShift Route, Istio or
+// Gateway API HTTPRoute host followed by Jenkins.Spec.Master.Prefix, returns empty string when none is set or the Route
+// host is generated by OpenShift router
+func GetJenkinsRootURL(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if len(jenkins.Spec.ExternalURL) > 0 {
+		return jenkins.Spec.ExternalURL
+	}
+	if IsRouteEnabled(jenkins) {
+		if host := jenkins.Spec.Service.Route.Host; len(host) > 0 {
+			// all Route terminations use TLS and plain HTTP requests are redirected
+			return fmt.Sprintf("https://%s%s/", host, GetJenkinsPrefix(jenkins))
+		}
+		return ""
+	}
+	if IsIstioEnabled(jenkins) {
+		istio := jenkins.Spec.Service.Istio
+		scheme := "http"
+		if len(istio.TLSSecretName) > 0 {
+			scheme = "https"
+		}
+		return fmt.Sprintf("%s://%s%s/", scheme, istio.Host, GetJenkinsPrefix(jenkins))
+	}
+	if IsHTTPRouteEnabled(jenkins) {
+		httpRoute := jenkins.Spec.Service.HTTPRoute
+		scheme := "http"
+		if httpRoute.HTTPS {
+			scheme = "https"
+		}
+		return fmt.Sprintf("%s://%s%s/", scheme, httpRoute.Host, GetJenkinsPrefix(jenkins))
+	}
+	if !IsIngressEnabled(jenkins) {
+		return ""
+	}
+
+	ingress := jenkins.Spec.Service.Ingress
+	scheme := "http"
+	if ingress.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s/", scheme, ingress.Host, GetJenkinsPrefix(jenkins))
+}
+
+// GetJenkinsURL returns Jenkins root URL or URL of Jenkins Service inside the cluster when the root URL isn't known
+func GetJenkinsURL(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if rootURL := GetJenkinsRootURL(jenkins); len(rootURL) > 0 {
+		return rootURL
+	}
+
+	scheme, port := "http", HTTPPortInt
+	if IsTLSEnabled(jenkins) {
+		scheme, port = "https", HTTPSPortInt
+	}
+	return fmt.Sprintf("%s://%s.%s.svc:%d%s/", scheme, GetResourceName(jenkins), jenkins.ObjectMeta.Namespace, port,
+		GetJenkinsPrefix(jenkins))
+}
+
+var configureJenkinsLocationTemplate = template.Must(template.New("configure-jenkins-location").Parse(`
+import jenkins.model.JenkinsLocationConfiguration
+
+def location = JenkinsLocationConfiguration.get()
+{{ if .RootURL -}}
+location.setUrl('{{ .RootURL }}')
+println("Jenkins root URL: ${location.getUrl()}")
+{{ end -}}
+{{ if .AdminEmail -}}
+location.setAdminAddress('{{ .AdminEmail }}')
+println("Jenkins admin e-mail address: ${location.getAdminAddress()}")
+{{ end -}}
+location.save()
+`))
+
+// buildConfigureJenkinsLocationGroovyScript renders groovy script which sets Jenkins root URL and admin e-mail
+// address, returns empty string when neither is known
+func buildConfigureJenkinsLocationGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	rootURL := GetJenkinsRootURL(jenkins)
+	adminEmail := jenkins.Spec.Master.AdminEmail
+	if len(rootURL) == 0 && len(adminEmail) == 0 {
+		return ""
+	}
+
+	data := struct {
+		RootURL    string
+		AdminEmail string
+	}{
+		RootURL:    escapeGroovyString(rootURL),
+		AdminEmail: escapeGroovyString(adminEmail),
+	}
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureJenkinsLocationTemplate, data)
+	return output
+}
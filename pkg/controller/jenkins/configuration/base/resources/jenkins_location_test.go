@@ -0,0 +1,64 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildConfigureJenkinsLocationGroovyScript(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Empty(t, buildConfigureJenkinsLocationGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("external URL overrides Ingress host", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				ExternalURL: "https://example.com/jenkins/",
+				Service: virtuslabv1alpha1.JenkinsService{
+					Ingress: &virtuslabv1alpha1.Ingress{Host: "jenkins.example.com"},
+				},
+			},
+		}
+
+		script := buildConfigureJenkinsLocationGroovyScript(jenkins)
+
+		assert.Equal(t, "https://example.com/jenkins/", GetJenkinsRootURL(jenkins))
+		assert.Contains(t, script, "location.setUrl('https://example.com/jenkins/')")
+		assert.NotContains(t, script, "setAdminAddress")
+	})
+	t.Run("admin e-mail address only", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{AdminEmail: "Jenkins O'Neil <jenkins@example.com>"},
+			},
+		}
+
+		script := buildConfigureJenkinsLocationGroovyScript(jenkins)
+
+		assert.NotContains(t, script, "setUrl")
+		assert.Contains(t, script, `location.setAdminAddress('Jenkins O\'Neil <jenkins@example.com>')`)
+	})
+}
+
+func TestGetJenkinsURL(t *testing.T) {
+	t.Run("root URL", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{ExternalURL: "https://example.com/jenkins/"},
+		}
+
+		assert.Equal(t, "https://example.com/jenkins/", GetJenkinsURL(jenkins))
+	})
+	t.Run("Service inside the cluster", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "example"},
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{Prefix: "/jenkins/"},
+			},
+		}
+
+		assert.Equal(t, "http://jenkins-operator-example.namespace-name.svc:8080/jenkins/", GetJenkinsURL(jenkins))
+	})
+}
@@ -0,0 +1,36 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// JenkinsSecretKeyFileName is the Jenkins instance key file stored directly in Jenkins home, all other
+	// encryption keys are stored in Jenkins home secrets directory
+	JenkinsSecretKeyFileName = "secret.key"
+
+	jenkinsSecretsVolumeName = "jenkins-secrets"
+	jenkinsSecretsVolumePath = "/var/jenkins/jenkins-secrets"
+)
+
+// GetJenkinsSecretsSecretName returns name of Kubernetes secret used to persist Jenkins encryption keys
+func GetJenkinsSecretsSecretName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-secrets-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// NewJenkinsSecretsSecret builds the Kubernetes secret used to persist Jenkins encryption keys, the keys are file names
+// and they are filled by the operator once Jenkins generates them
+func NewJenkinsSecretsSecret(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *corev1.Secret {
+	meta.Name = GetJenkinsSecretsSecretName(jenkins)
+
+	return &corev1.Secret{
+		TypeMeta:   buildSecretTypeMeta(),
+		ObjectMeta: meta,
+	}
+}
@@ -0,0 +1,111 @@
+package resources
+
+import (
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// LDAPPluginName is the name of plugin required by Jenkins.Spec.Security.LDAP
+	LDAPPluginName = "ldap"
+	// LDAPBindDNSecretKey is the LDAP Secret key with DN used to search the directory
+	LDAPBindDNSecretKey = "bindDN"
+	// LDAPBindPasswordSecretKey is the LDAP Secret key with password used to search the directory
+	LDAPBindPasswordSecretKey = "bindPassword"
+
+	defaultLDAPUserSearchFilter = "uid={0}"
+
+	ldapBindDNEnvName       = "JENKINS_LDAP_BIND_DN"
+	ldapBindPasswordEnvName = "JENKINS_LDAP_BIND_PASSWORD"
+)
+
+// buildLDAPEnvVars returns Jenkins master container environment variables with LDAP bind credentials referenced
+// from the Secret
+func buildLDAPEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	ldap := jenkins.Spec.Security.LDAP
+	if ldap == nil || len(ldap.BindSecretName) == 0 {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		buildSecretKeyEnvVar(ldapBindDNEnvName, ldap.BindSecretName, LDAPBindDNSecretKey),
+		buildSecretKeyEnvVar(ldapBindPasswordEnvName, ldap.BindSecretName, LDAPBindPasswordSecretKey),
+	}
+}
+
+var configureLDAPTemplate = template.Must(template.New("configure-ldap").Parse(`
+import hudson.util.Secret
+import jenkins.model.IdStrategy
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+// LDAP plugin classes are loaded dynamically because the plugin is optional
+def classLoader = jenkins.pluginManager.uberClassLoader
+def env = System.getenv()
+
+def configuration = classLoader.loadClass('jenkins.security.plugins.ldap.LDAPConfiguration').newInstance(
+        '{{ .Server }}', '{{ .RootDN }}', false, env['{{ .BindDNEnv }}'], Secret.fromString(env['{{ .BindPasswordEnv }}']))
+configuration.setUserSearchBase('{{ .UserSearchBase }}')
+configuration.setUserSearch('{{ .UserSearchFilter }}')
+configuration.setGroupSearchBase('{{ .GroupSearchBase }}')
+configuration.setGroupSearchFilter('{{ .GroupSearchFilter }}')
+
+def realm = classLoader.loadClass('hudson.security.LDAPSecurityRealm').newInstance(
+        [configuration], false, null, IdStrategy.CASE_INSENSITIVE, IdStrategy.CASE_INSENSITIVE)
+
+// verify the connection and the operator user before switching the realm, otherwise the operator would be locked out
+def operatorUserName = new File('{{ .OperatorCredentialsPath }}/{{ .OperatorUserNameFile }}').text
+try {
+    realm.loadUserByUsername(operatorUserName)
+} catch (Exception e) {
+    throw new IllegalStateException("Operator user '${operatorUserName}' can't be loaded from LDAP: ${e.message}", e)
+}
+
+jenkins.setSecurityRealm(realm)
+jenkins.save()
+`))
+
+// buildConfigureLDAPGroovyScript renders groovy script which configures and verifies LDAP security realm
+// from Jenkins.Spec.Security.LDAP
+func buildConfigureLDAPGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	ldap := jenkins.Spec.Security.LDAP
+	if ldap == nil {
+		return ""
+	}
+
+	userSearchFilter := ldap.UserSearchFilter
+	if len(userSearchFilter) == 0 {
+		userSearchFilter = defaultLDAPUserSearchFilter
+	}
+
+	data := struct {
+		Server                  string
+		RootDN                  string
+		UserSearchBase          string
+		UserSearchFilter        string
+		GroupSearchBase         string
+		GroupSearchFilter       string
+		BindDNEnv               string
+		BindPasswordEnv         string
+		OperatorCredentialsPath string
+		OperatorUserNameFile    string
+	}{
+		Server:                  escapeGroovyString(ldap.Server),
+		RootDN:                  escapeGroovyString(ldap.RootDN),
+		UserSearchBase:          escapeGroovyString(ldap.UserSearchBase),
+		UserSearchFilter:        escapeGroovyString(userSearchFilter),
+		GroupSearchBase:         escapeGroovyString(ldap.GroupSearchBase),
+		GroupSearchFilter:       escapeGroovyString(ldap.GroupSearchFilter),
+		BindDNEnv:               ldapBindDNEnvName,
+		BindPasswordEnv:         ldapBindPasswordEnvName,
+		OperatorCredentialsPath: jenkinsOperatorCredentialsVolumePath,
+		OperatorUserNameFile:    OperatorCredentialsSecretUserNameKey,
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureLDAPTemplate, data)
+	return output
+}
@@ -0,0 +1,117 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+// configureLoginThrottlingFmt registers servlet filter which counts failed logins of every user name and rejects
+// login form submissions of locked out users with 429 status, the filter registered previously is always removed
+// so disabled throttling is applied without restart, only user names with failed logins in the window are kept
+// in memory
+const configureLoginThrottlingFmt = `
+import hudson.util.PluginServletFilter
+import jenkins.model.Jenkins
+
+import javax.servlet.Filter
+import javax.servlet.FilterChain
+import javax.servlet.FilterConfig
+import javax.servlet.ServletRequest
+import javax.servlet.ServletResponse
+import javax.servlet.http.HttpServletRequest
+import javax.servlet.http.HttpServletResponse
+import javax.servlet.http.HttpServletResponseWrapper
+import java.util.concurrent.ConcurrentHashMap
+
+class LoginThrottlingFilter implements Filter {
+    static final long PRUNE_INTERVAL_MILLIS = 60000L
+    int maxFailedAttempts
+    long windowMillis
+    Map<String, List<Long>> failedLogins = new ConcurrentHashMap<>()
+    long lastPruneMillis = 0L
+
+    void init(FilterConfig filterConfig) {}
+
+    void destroy() {}
+
+    void doFilter(ServletRequest request, ServletResponse response, FilterChain chain) {
+        def httpRequest = (HttpServletRequest) request
+        def path = httpRequest.requestURI.substring(httpRequest.contextPath.length())
+        if (httpRequest.method != 'POST' || !(path in ['/j_spring_security_check', '/j_security_check'])) {
+            chain.doFilter(request, response)
+            return
+        }
+
+        def userName = (httpRequest.getParameter('j_username') ?: '').toLowerCase()
+        def now = System.currentTimeMillis()
+        def attempts = failedLogins.get(userName)
+        if (attempts != null) {
+            synchronized (attempts) {
+                attempts.removeAll { it < now - windowMillis }
+                if (attempts.size() >= maxFailedAttempts) {
+                    ((HttpServletResponse) response).sendError(429, 'Too many failed login attempts, try again later')
+                    return
+                }
+            }
+        }
+
+        def redirects = []
+        def responseWrapper = new HttpServletResponseWrapper((HttpServletResponse) response) {
+            void sendRedirect(String location) {
+                redirects.add(location)
+                super.sendRedirect(location)
+            }
+        }
+        chain.doFilter(request, responseWrapper)
+        if (redirects.any { it.contains('loginError') }) {
+            pruneExpiredFailedLogins(now)
+            def failures = failedLogins.computeIfAbsent(userName, { new ArrayList<Long>() })
+            synchronized (failures) {
+                failures.add(now)
+            }
+        } else if (!redirects.isEmpty()) {
+            failedLogins.remove(userName)
+        }
+    }
+
+    // user names which didn't fail to log in within the window are forgotten, so random user names don't pile up
+    synchronized void pruneExpiredFailedLogins(long now) {
+        if (now - lastPruneMillis < PRUNE_INTERVAL_MILLIS) {
+            return
+        }
+        lastPruneMillis = now
+        failedLogins.entrySet().removeIf { entry ->
+            synchronized (entry.value) {
+                return entry.value.every { it < now - windowMillis }
+            }
+        }
+    }
+}
+
+def filterAttributeName = 'jenkins-operator.login-throttling-filter'
+def servletContext = Jenkins.instance.servletContext
+def previousFilter = servletContext.getAttribute(filterAttributeName)
+if (previousFilter != null) {
+    PluginServletFilter.removeFilter(previousFilter)
+    servletContext.removeAttribute(filterAttributeName)
+}
+
+def enabled = %t
+if (enabled) {
+    def filter = new LoginThrottlingFilter(maxFailedAttempts: %d, windowMillis: %dL)
+    PluginServletFilter.addFilter(filter)
+    servletContext.setAttribute(filterAttributeName, filter)
+}
+`
+
+// buildConfigureLoginThrottlingGroovyScript renders groovy script which configures lockout of users after
+// failed logins from Jenkins.Spec.Security.LoginThrottling
+func buildConfigureLoginThrottlingGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	loginThrottling := jenkins.Spec.Security.LoginThrottling
+	if loginThrottling == nil {
+		return fmt.Sprintf(configureLoginThrottlingFmt, false, 0, 0)
+	}
+	return fmt.Sprintf(configureLoginThrottlingFmt, true, loginThrottling.MaxFailedAttempts,
+		loginThrottling.Window.Duration.Nanoseconds()/1e6)
+}
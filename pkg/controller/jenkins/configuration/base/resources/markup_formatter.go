@@ -0,0 +1,36 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+// AntisamyMarkupFormatterPluginName is the name of plugin required by safe HTML markup formatter
+const AntisamyMarkupFormatterPluginName = "antisamy-markup-formatter"
+
+const configureMarkupFormatterFmt = `
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+// formatter classes are loaded dynamically because the safe HTML formatter is provided by optional plugin
+def classLoader = jenkins.pluginManager.uberClassLoader
+jenkins.setMarkupFormatter(%s)
+jenkins.save()
+`
+
+// markupFormatters maps markup formatters to groovy expressions creating them
+var markupFormatters = map[virtuslabv1alpha1.MarkupFormatter]string{
+	virtuslabv1alpha1.MarkupFormatterPlainText: "classLoader.loadClass('hudson.markup.EscapedMarkupFormatter').newInstance()",
+	virtuslabv1alpha1.MarkupFormatterSafeHTML:  "classLoader.loadClass('hudson.markup.RawHtmlMarkupFormatter').newInstance(false)",
+}
+
+// buildConfigureMarkupFormatterGroovyScript renders groovy script which sets Jenkins markup formatter
+// from Jenkins.Spec.Master.MarkupFormatter or the security profile
+func buildConfigureMarkupFormatterGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	formatter, ok := markupFormatters[getMarkupFormatter(jenkins)]
+	if !ok {
+		return "" // not set or rejected by validation
+	}
+	return fmt.Sprintf(configureMarkupFormatterFmt, formatter)
+}
@@ -0,0 +1,219 @@
+package resources
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	monitoringv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/monitoring/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/plugins"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// PrometheusPluginName is the name of Jenkins plugin exposing Jenkins metrics in Prometheus format
+	PrometheusPluginName = "prometheus"
+	// MetricsSecretAccessKeyKey is the key of metrics plugin access key in the metrics Secret
+	MetricsSecretAccessKeyKey = "accessKey"
+	// MetricsServicePortName is the name of the metrics Service port
+	MetricsServicePortName = "metrics"
+
+	// defaultPrometheusPluginPath is the default path of the metrics exposed by the prometheus plugin
+	defaultPrometheusPluginPath = "prometheus"
+	// metricsAccessKeyEnvName is the name of Jenkins master container environment variable with the access key
+	metricsAccessKeyEnvName = "JENKINS_METRICS_ACCESS_KEY"
+	// metricsAccessKeyDescription identifies the access key managed by the operator among access keys of the
+	// metrics plugin
+	metricsAccessKeyDescription = "jenkins-operator"
+)
+
+// prometheusPlugins are installed together with the plugins of Jenkins.Spec.Master.Plugins when
+// Jenkins.Spec.Monitoring is set, the metrics plugin provides the access keys
+var prometheusPlugins = map[string][]plugins.Plugin{
+	plugins.Must(plugins.New("prometheus:2.0.0")).String(): {
+		plugins.Must(plugins.New("metrics:4.0.2.2")),
+	},
+}
+
+// IsMonitoringEnabled tells if the operator configures the prometheus plugin and creates the metrics Service and
+// Prometheus Operator PodMonitor of Jenkins master pod
+func IsMonitoringEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Monitoring != nil
+}
+
+// GetMonitoringPath returns the path of the prometheus plugin endpoint relative to Jenkins prefix without slashes
+func GetMonitoringPath(jenkins *virtuslabv1alpha1.Jenkins) string {
+	path := strings.Trim(jenkins.Spec.Monitoring.Path, "/")
+	if len(path) == 0 {
+		return defaultPrometheusPluginPath
+	}
+	return path
+}
+
+// getMetricsURLPath returns the absolute URL path of the prometheus plugin endpoint
+func getMetricsURLPath(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s/%s/", GetJenkinsPrefix(jenkins), GetMonitoringPath(jenkins))
+}
+
+// GetMasterPlugins returns plugins installed during Jenkins master pod start, Jenkins.Spec.Master.Plugins and
+// the prometheus plugin when Jenkins.Spec.Monitoring is set, plugins configured by the user take precedence
+func GetMasterPlugins(jenkins *virtuslabv1alpha1.Jenkins) map[string][]string {
+	if !IsMonitoringEnabled(jenkins) {
+		return jenkins.Spec.Master.Plugins
+	}
+
+	configured := map[string]bool{}
+	masterPlugins := map[string][]string{}
+	for rootPluginName, dependentPluginNames := range jenkins.Spec.Master.Plugins {
+		masterPlugins[rootPluginName] = dependentPluginNames
+		for _, name := range append([]string{rootPluginName}, dependentPluginNames...) {
+			if p, err := plugins.New(name); err == nil {
+				configured[p.Name] = true
+			}
+		}
+	}
+	for rootPluginName, dependentPlugins := range prometheusPlugins {
+		rootPlugin := plugins.Must(plugins.New(rootPluginName))
+		if configured[rootPlugin.Name] {
+			continue
+		}
+		masterPlugins[rootPluginName] = []string{}
+		for _, dependentPlugin := range dependentPlugins {
+			if !configured[dependentPlugin.Name] {
+				masterPlugins[rootPluginName] = append(masterPlugins[rootPluginName], dependentPlugin.String())
+			}
+		}
+	}
+	return masterPlugins
+}
+
+// GetMetricsSecretName returns the name of the Secret with the metrics plugin access key
+func GetMetricsSecretName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-metrics-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// NewMetricsSecret builds the Secret with random access key of the metrics plugin endpoints, e.g.
+// /metrics/<access key>/healthcheck, the key is generated once and kept
+func NewMetricsSecret(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *corev1.Secret {
+	meta.Name = GetMetricsSecretName(jenkins)
+	return &corev1.Secret{
+		TypeMeta:   buildSecretTypeMeta(),
+		ObjectMeta: meta,
+		Data: map[string][]byte{
+			MetricsSecretAccessKeyKey: []byte(randomString(40)),
+		},
+	}
+}
+
+// buildMetricsEnvVars returns Jenkins master container environment variable with the metrics plugin access key
+func buildMetricsEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	if !IsMonitoringEnabled(jenkins) {
+		return nil
+	}
+	return []corev1.EnvVar{buildSecretKeyEnvVar(metricsAccessKeyEnvName, GetMetricsSecretName(jenkins), MetricsSecretAccessKeyKey)}
+}
+
+// GetMetricsServiceName returns the name of the Service exposing Jenkins metrics
+func GetMetricsServiceName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-metrics-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// NewMetricsService builds the Service exposing Jenkins HTTP port as the metrics port, it's annotated for Prometheus
+// configured with Kubernetes service discovery of annotated Services
+func NewMetricsService(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *corev1.Service {
+	selector := meta.Labels
+	meta.Name = GetMetricsServiceName(jenkins)
+	meta.Annotations = map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/path":   getMetricsURLPath(jenkins),
+		"prometheus.io/port":   strconv.Itoa(HTTPPortInt),
+	}
+
+	return &corev1.Service{
+		TypeMeta:   buildServiceTypeMeta(),
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeClusterIP,
+			Selector:        selector,
+			SessionAffinity: corev1.ServiceAffinityNone,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       MetricsServicePortName,
+					Port:       httpPortInt32,
+					TargetPort: intstr.FromInt(HTTPPortInt),
+				},
+			},
+		},
+	}
+}
+
+// NewPodMonitor builds Prometheus Operator PodMonitor scraping Jenkins metrics from HTTP port of Jenkins master pod,
+// the pod is selected by its labels, a ServiceMonitor would scrape it through both Jenkins Service and the headless
+// Service
+func NewPodMonitor(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *monitoringv1.PodMonitor {
+	spec := jenkins.Spec.Monitoring
+	selector := map[string]string{}
+	for key, value := range meta.Labels {
+		selector[key] = value
+	}
+	meta.Labels = map[string]string{}
+	for key, value := range selector {
+		meta.Labels[key] = value
+	}
+	for key, value := range spec.Labels {
+		meta.Labels[key] = value
+	}
+
+	return &monitoringv1.PodMonitor{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       monitoringv1.PodMonitorKind,
+			APIVersion: monitoringv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: monitoringv1.PodMonitorSpec{
+			Selector: metav1.LabelSelector{MatchLabels: selector},
+			PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
+				{
+					Port:     httpPortName,
+					Path:     getMetricsURLPath(jenkins),
+					Interval: spec.Interval,
+				},
+			},
+		},
+	}
+}
+
+const configureMetricsFmt = `
+import jenkins.metrics.api.MetricsAccessKey
+import jenkins.model.Jenkins
+import org.jenkinsci.plugins.prometheus.config.PrometheusConfiguration
+
+def prometheusConfiguration = PrometheusConfiguration.get()
+prometheusConfiguration.setPath('%s')
+// Prometheus scrapes the endpoint without Jenkins credentials
+prometheusConfiguration.setUseAuthenticatedEndpoint(false)
+prometheusConfiguration.save()
+
+// the access key managed by the operator is replaced, access keys added by users are kept
+def accessKeys = Jenkins.instance.getDescriptorByType(MetricsAccessKey.DescriptorImpl.class)
+def keys = accessKeys.accessKeys.findAll { it.description != '%s' }
+keys.add(new MetricsAccessKey('%s', System.getenv('%s'), true, false, true, true, null))
+accessKeys.setAccessKeys(keys)
+accessKeys.save()
+`
+
+// buildConfigureMetricsGroovyScript renders groovy script which configures the prometheus plugin endpoint and
+// the metrics plugin access key of Jenkins.Spec.Monitoring
+func buildConfigureMetricsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if !IsMonitoringEnabled(jenkins) {
+		return ""
+	}
+
+	return fmt.Sprintf(configureMetricsFmt, GetMonitoringPath(jenkins), metricsAccessKeyDescription,
+		metricsAccessKeyDescription, metricsAccessKeyEnvName)
+}
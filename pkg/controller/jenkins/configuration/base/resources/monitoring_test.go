@@ -0,0 +1,91 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPodMonitor(t *testing.T) {
+	jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{
+		Master: virtuslabv1alpha1.JenkinsMaster{Prefix: "/jenkins/"},
+		Monitoring: &virtuslabv1alpha1.Monitoring{
+			Interval: "30s",
+			Labels:   map[string]string{"release": "kube-prometheus"},
+		},
+	})
+
+	podMonitor := NewPodMonitor(NewResourceObjectMeta(jenkins), jenkins)
+
+	assert.Equal(t, "jenkins-operator-jenkins-cr-name", podMonitor.Name)
+	assert.Equal(t, "kube-prometheus", podMonitor.Labels["release"])
+	assert.Equal(t, BuildResourceLabels(jenkins), podMonitor.Spec.Selector.MatchLabels)
+	if assert.Len(t, podMonitor.Spec.PodMetricsEndpoints, 1) {
+		endpoint := podMonitor.Spec.PodMetricsEndpoints[0]
+		assert.Equal(t, "http", endpoint.Port)
+		assert.Equal(t, "/jenkins/prometheus/", endpoint.Path)
+		assert.Equal(t, "30s", endpoint.Interval)
+	}
+}
+
+func TestGetMasterPlugins(t *testing.T) {
+	userPlugins := map[string][]string{"git:3.9.1": {"credentials:2.1.18"}}
+
+	t.Run("monitoring disabled", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{Spec: virtuslabv1alpha1.JenkinsSpec{
+			Master: virtuslabv1alpha1.JenkinsMaster{Plugins: userPlugins},
+		}}
+
+		assert.Equal(t, userPlugins, GetMasterPlugins(jenkins))
+	})
+	t.Run("prometheus plugin installed by the operator", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{Spec: virtuslabv1alpha1.JenkinsSpec{
+			Master:     virtuslabv1alpha1.JenkinsMaster{Plugins: userPlugins},
+			Monitoring: &virtuslabv1alpha1.Monitoring{},
+		}}
+
+		assert.Equal(t, map[string][]string{
+			"git:3.9.1":        {"credentials:2.1.18"},
+			"prometheus:2.0.0": {"metrics:4.0.2.2"},
+		}, GetMasterPlugins(jenkins))
+		assert.Len(t, jenkins.Spec.Master.Plugins, 1)
+	})
+	t.Run("prometheus plugin configured by the user", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{Spec: virtuslabv1alpha1.JenkinsSpec{
+			Master:     virtuslabv1alpha1.JenkinsMaster{Plugins: map[string][]string{"prometheus:2.0.6": {}}},
+			Monitoring: &virtuslabv1alpha1.Monitoring{},
+		}}
+
+		assert.Equal(t, map[string][]string{"prometheus:2.0.6": {}}, GetMasterPlugins(jenkins))
+	})
+}
+
+func TestNewMetricsService(t *testing.T) {
+	jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{Monitoring: &virtuslabv1alpha1.Monitoring{Path: "/metrics/prometheus/"}})
+
+	service := NewMetricsService(NewResourceObjectMeta(jenkins), jenkins)
+
+	assert.Equal(t, "jenkins-operator-metrics-jenkins-cr-name", service.Name)
+	assert.Equal(t, BuildResourceLabels(jenkins), service.Spec.Selector)
+	assert.Equal(t, map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/path":   "/metrics/prometheus/",
+		"prometheus.io/port":   "8080",
+	}, service.Annotations)
+	if assert.Len(t, service.Spec.Ports, 1) {
+		assert.Equal(t, "metrics", service.Spec.Ports[0].Name)
+		assert.Equal(t, HTTPPortInt, service.Spec.Ports[0].TargetPort.IntValue())
+	}
+}
+
+func TestBuildConfigureMetricsGroovyScript(t *testing.T) {
+	assert.Equal(t, "", buildConfigureMetricsGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+
+	jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{Monitoring: &virtuslabv1alpha1.Monitoring{}})
+	script := buildConfigureMetricsGroovyScript(jenkins)
+	assert.Contains(t, script, "prometheusConfiguration.setPath('prometheus')")
+	assert.Contains(t, script, "System.getenv('JENKINS_METRICS_ACCESS_KEY')")
+	assert.Contains(t, buildMetricsEnvVars(jenkins)[0].ValueFrom.SecretKeyRef.Name, "jenkins-operator-metrics-jenkins-cr-name")
+}
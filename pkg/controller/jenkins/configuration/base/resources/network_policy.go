@@ -0,0 +1,97 @@
+package resources
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// operator pod label, see deploy/operator.yaml
+	operatorPodLabelKey   = "name"
+	operatorPodLabelValue = "jenkins-operator"
+	// label set by Kubernetes plugin on all agent pods
+	agentPodLabelKey   = "jenkins"
+	agentPodLabelValue = "slave"
+)
+
+// NewNetworkPolicy builds NetworkPolicy which allows only the operator, Kubernetes plugin agents, ingress controllers
+// and the user defined rules to connect to Jenkins master pod
+func NewNetworkPolicy(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *networkingv1.NetworkPolicy {
+	operatorPorts := []networkingv1.NetworkPolicyPort{buildNetworkPolicyPort(HTTPPortInt)}
+	if IsTLSEnabled(jenkins) {
+		operatorPorts = append(operatorPorts, buildNetworkPolicyPort(HTTPSPortInt))
+	}
+
+	agentPorts := []networkingv1.NetworkPolicyPort{buildNetworkPolicyPort(HTTPPortInt)}
+	if IsAgentListenerExposed(jenkins) {
+		agentPorts = append(agentPorts, buildNetworkPolicyPort(slavePortInt))
+	}
+
+	var agentPeers []networkingv1.NetworkPolicyPeer
+	for _, namespace := range GetAllAgentsNamespaces(jenkins) {
+		agentPeer := networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{agentPodLabelKey: agentPodLabelValue},
+			},
+		}
+		if namespace != jenkins.ObjectMeta.Namespace {
+			agentPeer.NamespaceSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{namespaceNameLabelKey: namespace},
+			}
+		}
+		agentPeers = append(agentPeers, agentPeer)
+	}
+
+	rules := []networkingv1.NetworkPolicyIngressRule{
+		{
+			// the operator can run in any namespace
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{},
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{operatorPodLabelKey: operatorPodLabelValue},
+					},
+				},
+			},
+			Ports: operatorPorts,
+		},
+		{
+			// agents download remoting jar over HTTP and connect to TCP agent listener port unless it's disabled or
+			// they use WebSocket
+			From:  agentPeers,
+			Ports: agentPorts,
+		},
+	}
+	if networkPolicy := jenkins.Spec.NetworkPolicy; networkPolicy != nil {
+		if len(networkPolicy.IngressControllers) > 0 {
+			rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+				From:  networkPolicy.IngressControllers,
+				Ports: []networkingv1.NetworkPolicyPort{buildNetworkPolicyPort(HTTPPortInt)},
+			})
+		}
+		rules = append(rules, networkPolicy.AdditionalRules...)
+	}
+
+	return &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: meta,
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: meta.Labels},
+			Ingress:     rules,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+}
+
+func buildNetworkPolicyPort(port int) networkingv1.NetworkPolicyPort {
+	protocol := corev1.ProtocolTCP
+	portNumber := intstr.FromInt(port)
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &portNumber}
+}
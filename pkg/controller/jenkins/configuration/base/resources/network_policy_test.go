@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestNewNetworkPolicy(t *testing.T) {
+	ports := func(rule networkingv1.NetworkPolicyIngressRule) []intstr.IntOrString {
+		var ports []intstr.IntOrString
+		for _, port := range rule.Ports {
+			ports = append(ports, *port.Port)
+		}
+		return ports
+	}
+
+	t.Run("generated rules", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{NetworkPolicy: &virtuslabv1alpha1.NetworkPolicy{}})
+		meta := NewResourceObjectMeta(jenkins)
+
+		networkPolicy := NewNetworkPolicy(meta, jenkins)
+
+		assert.Equal(t, meta.Labels, networkPolicy.Spec.PodSelector.MatchLabels)
+		assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, networkPolicy.Spec.PolicyTypes)
+		assert.Len(t, networkPolicy.Spec.Ingress, 2)
+		operatorRule := networkPolicy.Spec.Ingress[0]
+		assert.Equal(t, map[string]string{"name": "jenkins-operator"}, operatorRule.From[0].PodSelector.MatchLabels)
+		assert.Equal(t, []intstr.IntOrString{intstr.FromInt(HTTPPortInt)}, ports(operatorRule))
+		agentRule := networkPolicy.Spec.Ingress[1]
+		assert.Equal(t, map[string]string{"jenkins": "slave"}, agentRule.From[0].PodSelector.MatchLabels)
+		assert.Nil(t, agentRule.From[0].NamespaceSelector)
+		assert.Equal(t, []intstr.IntOrString{intstr.FromInt(HTTPPortInt), intstr.FromInt(slavePortInt)}, ports(agentRule))
+	})
+	t.Run("operator https port", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{NetworkPolicy: &virtuslabv1alpha1.NetworkPolicy{}})
+		jenkins.Spec.Master.TLS = &virtuslabv1alpha1.MasterTLS{SecretName: "jenkins-tls"}
+
+		networkPolicy := NewNetworkPolicy(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, []intstr.IntOrString{intstr.FromInt(HTTPPortInt), intstr.FromInt(HTTPSPortInt)},
+			ports(networkPolicy.Spec.Ingress[0]))
+	})
+	t.Run("agent listener disabled", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{NetworkPolicy: &virtuslabv1alpha1.NetworkPolicy{}})
+		jenkins.Spec.Master.Remoting.AgentListenerDisabled = true
+
+		networkPolicy := NewNetworkPolicy(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, []intstr.IntOrString{intstr.FromInt(HTTPPortInt)}, ports(networkPolicy.Spec.Ingress[1]))
+	})
+	t.Run("ingress controllers and additional rules", func(t *testing.T) {
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsSpec{NetworkPolicy: &virtuslabv1alpha1.NetworkPolicy{}})
+		ingressController := networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "ingress-nginx"}},
+		}
+		additionalRule := networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+		}
+		jenkins.Spec.NetworkPolicy.IngressControllers = []networkingv1.NetworkPolicyPeer{ingressController}
+		jenkins.Spec.NetworkPolicy.AdditionalRules = []networkingv1.NetworkPolicyIngressRule{additionalRule}
+
+		networkPolicy := NewNetworkPolicy(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Len(t, networkPolicy.Spec.Ingress, 4)
+		assert.Equal(t, []networkingv1.NetworkPolicyPeer{ingressController}, networkPolicy.Spec.Ingress[2].From)
+		assert.Equal(t, []intstr.IntOrString{intstr.FromInt(HTTPPortInt)}, ports(networkPolicy.Spec.Ingress[2]))
+		assert.Equal(t, additionalRule, networkPolicy.Spec.Ingress[3])
+	})
+}
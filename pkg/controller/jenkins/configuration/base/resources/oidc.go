@@ -0,0 +1,113 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// OIDCPluginName is the name of plugin required by Jenkins.Spec.Security.OIDC
+	OIDCPluginName = "oic-auth"
+	// OIDCClientIDSecretKey is the OpenID Connect client Secret key with client ID
+	OIDCClientIDSecretKey = "clientId"
+	// OIDCClientSecretSecretKey is the OpenID Connect client Secret key with client secret
+	OIDCClientSecretSecretKey = "clientSecret"
+	// OIDCBreakGlassUserNameSecretKey is the break-glass Secret key with local account user name
+	OIDCBreakGlassUserNameSecretKey = "username"
+	// OIDCBreakGlassPasswordSecretKey is the break-glass Secret key with local account password
+	OIDCBreakGlassPasswordSecretKey = "password"
+
+	defaultOIDCUserNameClaim = "sub"
+
+	// environment variables resolved by configuration as code plugin, so the secrets aren't stored in ConfigMaps
+	oidcClientIDEnvName           = "JENKINS_OIDC_CLIENT_ID"
+	oidcClientSecretEnvName       = "JENKINS_OIDC_CLIENT_SECRET"
+	oidcBreakGlassUserNameEnvName = "JENKINS_OIDC_BREAK_GLASS_USERNAME"
+	oidcBreakGlassPasswordEnvName = "JENKINS_OIDC_BREAK_GLASS_PASSWORD"
+)
+
+var defaultOIDCScopes = []string{"openid", "email", "profile"}
+
+// buildOIDCEnvVars returns Jenkins master container environment variables with OpenID Connect client and break-glass
+// account credentials referenced from the Secrets
+func buildOIDCEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	oidc := jenkins.Spec.Security.OIDC
+	if oidc == nil {
+		return nil
+	}
+
+	envs := []corev1.EnvVar{
+		buildSecretKeyEnvVar(oidcClientIDEnvName, oidc.ClientSecretName, OIDCClientIDSecretKey),
+		buildSecretKeyEnvVar(oidcClientSecretEnvName, oidc.ClientSecretName, OIDCClientSecretSecretKey),
+	}
+	if len(oidc.BreakGlassSecretName) > 0 {
+		envs = append(envs,
+			buildSecretKeyEnvVar(oidcBreakGlassUserNameEnvName, oidc.BreakGlassSecretName, OIDCBreakGlassUserNameSecretKey),
+			buildSecretKeyEnvVar(oidcBreakGlassPasswordEnvName, oidc.BreakGlassSecretName, OIDCBreakGlassPasswordSecretKey))
+	}
+
+	return envs
+}
+
+// buildOIDCConfigurationAsCode renders configuration as code file which sets OpenID Connect security realm
+// from Jenkins.Spec.Security.OIDC, the break-glass account is the oic-auth plugin escape hatch
+func buildOIDCConfigurationAsCode(jenkins *virtuslabv1alpha1.Jenkins) string {
+	oidc := jenkins.Spec.Security.OIDC
+	if oidc == nil {
+		return ""
+	}
+
+	scopes := oidc.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultOIDCScopes
+	}
+	userNameClaim := oidc.UserNameClaim
+	if len(userNameClaim) == 0 {
+		userNameClaim = defaultOIDCUserNameClaim
+	}
+
+	realm := map[string]interface{}{
+		"clientId":                        envVariableReference(oidcClientIDEnvName),
+		"clientSecret":                    envVariableReference(oidcClientSecretEnvName),
+		"automanualconfigure":             "auto",
+		"wellKnownOpenIDConfigurationUrl": strings.TrimSuffix(oidc.IssuerURL, "/") + "/.well-known/openid-configuration",
+		"scopes":                          strings.Join(scopes, " "),
+		"userNameField":                   userNameClaim,
+		"escapeHatchEnabled":              len(oidc.BreakGlassSecretName) > 0,
+	}
+	if len(oidc.FullNameClaim) > 0 {
+		realm["fullNameFieldName"] = oidc.FullNameClaim
+	}
+	if len(oidc.EmailClaim) > 0 {
+		realm["emailFieldName"] = oidc.EmailClaim
+	}
+	if len(oidc.GroupsClaim) > 0 {
+		realm["groupsFieldName"] = oidc.GroupsClaim
+	}
+	if len(oidc.BreakGlassSecretName) > 0 {
+		realm["escapeHatchUsername"] = envVariableReference(oidcBreakGlassUserNameEnvName)
+		realm["escapeHatchSecret"] = envVariableReference(oidcBreakGlassPasswordEnvName)
+	}
+
+	configuration := map[string]interface{}{
+		"jenkins": map[string]interface{}{
+			"securityRealm": map[string]interface{}{
+				"oic": realm,
+			},
+		},
+	}
+
+	// JSON is valid YAML, marshalling maps of strings and bools never fails
+	output, _ := json.MarshalIndent(configuration, "", "  ")
+	return string(output)
+}
+
+// envVariableReference returns configuration as code variable resolved from the environment variable
+func envVariableReference(name string) string {
+	return fmt.Sprintf("${%s}", name)
+}
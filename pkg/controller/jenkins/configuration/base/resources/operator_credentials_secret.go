@@ -2,6 +2,8 @@ package resources
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
 
 	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
@@ -21,6 +23,8 @@ const (
 	OperatorCredentialsSecretTokenKey = "token"
 	// OperatorCredentialsSecretTokenCreationKey defines key of token creation time in operator credentials secret
 	OperatorCredentialsSecretTokenCreationKey = "tokenCreationTime"
+	// OperatorCredentialsSecretPasswordCreationKey defines key of password creation time in operator credentials secret
+	OperatorCredentialsSecretPasswordCreationKey = "passwordCreationTime"
 )
 
 func buildSecretTypeMeta() metav1.TypeMeta {
@@ -40,12 +44,19 @@ func GetOperatorCredentialsSecretName(jenkins *virtuslabv1alpha1.Jenkins) string
 // to allow calls to Jenkins API
 func NewOperatorCredentialsSecret(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *corev1.Secret {
 	meta.Name = GetOperatorCredentialsSecretName(jenkins)
+	now, _ := time.Now().UTC().MarshalText()
 	return &corev1.Secret{
 		TypeMeta:   buildSecretTypeMeta(),
 		ObjectMeta: meta,
 		Data: map[string][]byte{
-			OperatorCredentialsSecretUserNameKey: []byte(OperatorUserName),
-			OperatorCredentialsSecretPasswordKey: []byte(randomString(20)),
+			OperatorCredentialsSecretUserNameKey:         []byte(OperatorUserName),
+			OperatorCredentialsSecretPasswordKey:         []byte(NewOperatorPassword()),
+			OperatorCredentialsSecretPasswordCreationKey: now,
 		},
 	}
 }
+
+// NewOperatorPassword returns random password of the operator user
+func NewOperatorPassword() string {
+	return randomString(20)
+}
@@ -1,8 +1,14 @@
 package resources
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"strings"
+
 	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,14 +40,20 @@ const (
 	JenkinsUserConfigurationVolumePath = "/var/jenkins/user-configuration"
 
 	jenkinsBackupCredentialsVolumeName = "backup-credentials"
-	jenkinsBackupCredentialsVolumePath = "/var/jenkins/backup-credentials"
+	// JenkinsBackupCredentialsVolumePath is a path where are the keys of the backup credentials Secret used by
+	// the backup job
+	JenkinsBackupCredentialsVolumePath = "/var/jenkins/backup-credentials"
 
 	httpPortName  = "http"
+	httpsPortName = "https"
 	slavePortName = "slavelistener"
 	// HTTPPortInt defines Jenkins master HTTP port
-	HTTPPortInt    = 8080
+	HTTPPortInt = 8080
+	// HTTPSPortInt defines Jenkins master HTTPS port, used only when TLS is enabled
+	HTTPSPortInt   = 8443
 	slavePortInt   = 50000
 	httpPortInt32  = int32(8080)
+	httpsPortInt32 = int32(8443)
 	slavePortInt32 = int32(50000)
 
 	jenkinsUserUID = int64(1000) // build in Docker image jenkins user UID
@@ -54,16 +66,85 @@ func buildPodTypeMeta() metav1.TypeMeta {
 	}
 }
 
-// NewJenkinsMasterPod builds Jenkins Master Kubernetes Pod resource
-func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *corev1.Pod {
+// buildJavaOpts returns Jenkins master JVM options
+func buildJavaOpts(jenkins *virtuslabv1alpha1.Jenkins) string {
+	javaOpts := "-XX:+UnlockExperimentalVMOptions -XX:+UseCGroupMemoryLimitForHeap -XX:MaxRAMFraction=1 -Djenkins.install.runSetupWizard=false -Djava.awt.headless=true"
+	if IsFIPSModeEnabled(jenkins) {
+		javaOpts += " " + fipsJavaOpts
+	}
+	if serviceAccountTokenJavaOpts := buildServiceAccountTokenJavaOpts(jenkins); len(serviceAccountTokenJavaOpts) > 0 {
+		javaOpts += " " + serviceAccountTokenJavaOpts
+	}
+	if agentDefaultImageJavaOpts := buildAgentDefaultImageJavaOpts(jenkins); len(agentDefaultImageJavaOpts) > 0 {
+		javaOpts += " " + agentDefaultImageJavaOpts
+	}
+	return javaOpts
+}
+
+// buildJenkinsOpts returns Winstone options of Jenkins master, returns empty string when there are none
+func buildJenkinsOpts(jenkins *virtuslabv1alpha1.Jenkins) string {
+	var jenkinsOpts []string
+	if tlsJenkinsOpts := buildTLSJenkinsOpts(jenkins); len(tlsJenkinsOpts) > 0 {
+		jenkinsOpts = append(jenkinsOpts, tlsJenkinsOpts)
+	}
+	if prefix := GetJenkinsPrefix(jenkins); len(prefix) > 0 {
+		jenkinsOpts = append(jenkinsOpts, fmt.Sprintf("--prefix=%s", prefix))
+	}
+	if sessionTimeout := jenkins.Spec.Master.SessionTimeout; sessionTimeout > 0 {
+		jenkinsOpts = append(jenkinsOpts, fmt.Sprintf("--sessionTimeout=%d", sessionTimeout))
+	}
+	return strings.Join(jenkinsOpts, " ")
+}
+
+// buildJenkinsMasterEnvVars returns Jenkins master container environment variables, optional integrations read
+// their credentials from environment variables referencing Secrets
+func buildJenkinsMasterEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	envs := []corev1.EnvVar{
+		{
+			Name:  "JENKINS_HOME",
+			Value: jenkinsHomePath,
+		},
+		{
+			Name:  "JAVA_OPTS",
+			Value: buildJavaOpts(jenkins),
+		},
+	}
+	envs = append(envs, buildVaultEnvVars(jenkins)...)
+	envs = append(envs, buildArtifactManagerEnvVars(jenkins)...)
+	envs = append(envs, buildLDAPEnvVars(jenkins)...)
+	envs = append(envs, buildOIDCEnvVars(jenkins)...)
+	envs = append(envs, buildSAMLEnvVars(jenkins)...)
+	envs = append(envs, buildGitHubOAuthEnvVars(jenkins)...)
+	envs = append(envs, buildHTTPSKeystoreEnvVars(jenkins)...)
+	envs = append(envs, buildStaticAgentsEnvVars(jenkins)...)
+	envs = append(envs, buildAgentsClustersEnvVars(jenkins)...)
+	envs = append(envs, buildVMCloudsEnvVars(jenkins)...)
+	envs = append(envs, buildMetricsEnvVars(jenkins)...)
+	if jenkinsOpts := buildJenkinsOpts(jenkins); len(jenkinsOpts) > 0 {
+		envs = append(envs, corev1.EnvVar{
+			Name:  jenkinsOptsName,
+			Value: jenkinsOpts,
+		})
+	}
+
+	return envs
+}
+
+// NewJenkinsMasterPod builds Jenkins Master Kubernetes Pod resource, baseConfiguration is the data of the base
+// configuration config map which is included in the pod spec hash
+func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins, baseConfiguration map[string]string) *corev1.Pod {
 	initialDelaySeconds := int32(30)
 	timeoutSeconds := int32(5)
 	failureThreshold := int32(12)
 	runAsUser := jenkinsUserUID
 
-	objectMeta.Annotations = jenkins.Spec.Master.Annotations
+	annotations := getJenkinsMasterPodAnnotations(jenkins)
+	objectMeta.Annotations = map[string]string{}
+	for key, value := range annotations {
+		objectMeta.Annotations[key] = value
+	}
 
-	return &corev1.Pod{
+	pod := &corev1.Pod{
 		TypeMeta:   buildPodTypeMeta(),
 		ObjectMeta: objectMeta,
 		Spec: corev1.PodSpec{
@@ -84,7 +165,7 @@ func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *virtuslabv1alpha
 					LivenessProbe: &corev1.Probe{
 						Handler: corev1.Handler{
 							HTTPGet: &corev1.HTTPGetAction{
-								Path:   "/login",
+								Path:   GetJenkinsPrefix(jenkins) + "/login",
 								Port:   intstr.FromString(httpPortName),
 								Scheme: corev1.URISchemeHTTP,
 							},
@@ -96,7 +177,7 @@ func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *virtuslabv1alpha
 					ReadinessProbe: &corev1.Probe{
 						Handler: corev1.Handler{
 							HTTPGet: &corev1.HTTPGetAction{
-								Path:   "/login",
+								Path:   GetJenkinsPrefix(jenkins) + "/login",
 								Port:   intstr.FromString(httpPortName),
 								Scheme: corev1.URISchemeHTTP,
 							},
@@ -113,16 +194,7 @@ func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *virtuslabv1alpha
 							ContainerPort: httpPortInt32,
 						},
 					},
-					Env: []corev1.EnvVar{
-						{
-							Name:  "JENKINS_HOME",
-							Value: jenkinsHomePath,
-						},
-						{
-							Name:  "JAVA_OPTS",
-							Value: "-XX:+UnlockExperimentalVMOptions -XX:+UseCGroupMemoryLimitForHeap -XX:MaxRAMFraction=1 -Djenkins.install.runSetupWizard=false -Djava.awt.headless=true",
-						},
-					},
+					Env:       buildJenkinsMasterEnvVars(jenkins),
 					Resources: jenkins.Spec.Master.Resources,
 					VolumeMounts: []corev1.VolumeMount{
 						{
@@ -157,7 +229,12 @@ func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *virtuslabv1alpha
 						},
 						{
 							Name:      jenkinsBackupCredentialsVolumeName,
-							MountPath: jenkinsBackupCredentialsVolumePath,
+							MountPath: JenkinsBackupCredentialsVolumePath,
+							ReadOnly:  true,
+						},
+						{
+							Name:      jenkinsSecretsVolumeName,
+							MountPath: jenkinsSecretsVolumePath,
 							ReadOnly:  true,
 						},
 					},
@@ -226,7 +303,54 @@ func NewJenkinsMasterPod(objectMeta metav1.ObjectMeta, jenkins *virtuslabv1alpha
 						},
 					},
 				},
+				{
+					Name: jenkinsSecretsVolumeName,
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: GetJenkinsSecretsSecretName(jenkins),
+						},
+					},
+				},
 			},
 		},
 	}
+	if volume, mount := buildSAMLKeystoreVolume(jenkins); volume != nil {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, *volume)
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, *mount)
+	}
+	if volume, mount := buildTLSVolume(jenkins); volume != nil {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, *volume)
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, *mount)
+		pod.Spec.Containers[0].Ports = append(pod.Spec.Containers[0].Ports, corev1.ContainerPort{
+			Name:          httpsPortName,
+			ContainerPort: httpsPortInt32,
+		})
+	}
+	if port := buildSSHDContainerPort(jenkins); port != nil {
+		pod.Spec.Containers[0].Ports = append(pod.Spec.Containers[0].Ports, *port)
+	}
+	applyServiceAccountToken(pod, jenkins)
+	applyPodSecurityProfile(pod, jenkins)
+	applyHeadlessServiceSubdomain(pod, jenkins)
+	pod.ObjectMeta.Annotations[constants.AnnotationSpecHashKey] = calculateSpecHash(pod.Spec, annotations, GetMasterPlugins(jenkins), baseConfiguration)
+
+	return pod
+}
+
+// calculateSpecHash returns hash of everything which requires Jenkins master pod restart when changed,
+// the rendered pod spec, pod annotations, plugins installed during the pod start and the base configuration
+func calculateSpecHash(podSpec corev1.PodSpec, annotations map[string]string, plugins map[string][]string, baseConfiguration map[string]string) string {
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+	// json encoder sorts map keys so the hash is stable
+	_ = encoder.Encode(podSpec)
+	_ = encoder.Encode(annotations)
+	_ = encoder.Encode(plugins)
+	_ = encoder.Encode(baseConfiguration)
+	return base64.URLEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// GetJenkinsMasterPodSpecHash returns hash of the Jenkins master pod spec stored in the pod annotations
+func GetJenkinsMasterPodSpecHash(pod *corev1.Pod) string {
+	return pod.ObjectMeta.Annotations[constants.AnnotationSpecHashKey]
 }
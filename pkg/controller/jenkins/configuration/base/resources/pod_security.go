@@ -0,0 +1,52 @@
+package resources
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SeccompPodAnnotationKey is the annotation with seccomp profile of all pod containers
+	SeccompPodAnnotationKey = "seccomp.security.alpha.kubernetes.io/pod"
+	// SeccompContainerAnnotationKeyPrefix is the prefix of annotation with seccomp profile of a container
+	SeccompContainerAnnotationKeyPrefix = "container.seccomp.security.alpha.kubernetes.io/"
+	// AppArmorContainerAnnotationKeyPrefix is the prefix of annotation with AppArmor profile of a container
+	AppArmorContainerAnnotationKeyPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+	// SeccompProfileRuntimeDefault is the container runtime default seccomp profile
+	SeccompProfileRuntimeDefault = "runtime/default"
+	// SeccompProfileDockerDefault is the deprecated Docker default seccomp profile
+	SeccompProfileDockerDefault = "docker/default"
+	// AppArmorProfileRuntimeDefault is the container runtime default AppArmor profile
+	AppArmorProfileRuntimeDefault = "runtime/default"
+	// LocalhostProfilePrefix is the prefix of seccomp and AppArmor profiles loaded on the node
+	LocalhostProfilePrefix = "localhost/"
+	// UnconfinedProfile disables seccomp or AppArmor
+	UnconfinedProfile = "unconfined"
+)
+
+// applyPodSecurityProfile changes the pod so it complies with Jenkins.Spec.Security.PodSecurityProfile,
+// the pods are compliant with baseline level by default, so only restricted level requires changes
+func applyPodSecurityProfile(pod *corev1.Pod, jenkins *virtuslabv1alpha1.Jenkins) {
+	if jenkins.Spec.Security.PodSecurityProfile != virtuslabv1alpha1.PodSecurityProfileRestricted {
+		return
+	}
+
+	runAsNonRoot := true
+	allowPrivilegeEscalation := false
+	for i := range pod.Spec.Containers {
+		pod.Spec.Containers[i].SecurityContext = &corev1.SecurityContext{
+			RunAsNonRoot:             &runAsNonRoot,
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		}
+	}
+	pod.Spec.SecurityContext.RunAsNonRoot = &runAsNonRoot
+	// the annotation is converted to the seccompProfile field by Kubernetes API server
+	if _, found := pod.ObjectMeta.Annotations[SeccompPodAnnotationKey]; !found {
+		pod.ObjectMeta.Annotations[SeccompPodAnnotationKey] = SeccompProfileRuntimeDefault
+	}
+}
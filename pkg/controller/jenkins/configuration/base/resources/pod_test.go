@@ -0,0 +1,316 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetJenkinsMasterPodSpecHash(t *testing.T) {
+	masterSpec := func() virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Master: virtuslabv1alpha1.JenkinsMaster{
+				Image:       "jenkins/jenkins:lts",
+				Annotations: map[string]string{"test": "label"},
+				Plugins:     map[string][]string{"plugin-name:1.0": {"dependent-plugin:1.0"}},
+			},
+		}
+	}
+	podHash := func(jenkins *virtuslabv1alpha1.Jenkins) string {
+		return GetJenkinsMasterPodSpecHash(NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil))
+	}
+	baseConfiguration := map[string]string{"1-basic-settings.groovy": "println 'basic settings'"}
+
+	t.Run("the same spec", func(t *testing.T) {
+		assert.Equal(t, podHash(newJenkins(masterSpec())), podHash(newJenkins(masterSpec())))
+	})
+	t.Run("seed jobs change", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.SeedJobs = []virtuslabv1alpha1.SeedJob{{ID: "seed-job"}}
+		assert.Equal(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+	})
+	t.Run("image change", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Master.Image = "jenkins/jenkins:2.150"
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+	})
+	t.Run("annotations change", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Master.Annotations["test"] = "other-label"
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+	})
+	t.Run("plugins change", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Master.Plugins["plugin-name:1.0"] = []string{"dependent-plugin:2.0"}
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+	})
+	t.Run("base configuration change", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		baseConfigurationHash := GetJenkinsMasterPodSpecHash(NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, baseConfiguration))
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), baseConfigurationHash)
+
+		changed := map[string]string{"1-basic-settings.groovy": "println 'changed basic settings'"}
+		changedHash := GetJenkinsMasterPodSpecHash(NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, changed))
+		assert.NotEqual(t, baseConfigurationHash, changedHash)
+	})
+	t.Run("vault change", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Vault = &virtuslabv1alpha1.Vault{
+			Address:    "https://vault.example.com",
+			AuthMethod: virtuslabv1alpha1.VaultAuthMethodToken,
+			SecretName: "vault-token",
+		}
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+	})
+	t.Run("saml keystore", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Security.SAML = &virtuslabv1alpha1.SAML{
+			IdPMetadata:        virtuslabv1alpha1.SAMLIdPMetadata{URL: "https://idp.example.com/metadata"},
+			KeystoreSecretName: "saml-keystore",
+		}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+		assert.Equal(t, "saml-keystore", pod.Spec.Volumes[len(pod.Spec.Volumes)-1].Secret.SecretName)
+		mounts := pod.Spec.Containers[0].VolumeMounts
+		assert.Equal(t, samlKeystoreVolumePath, mounts[len(mounts)-1].MountPath)
+	})
+	t.Run("tls", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Master.TLS = &virtuslabv1alpha1.MasterTLS{SecretName: "jenkins-tls"}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+		assert.Equal(t, "jenkins-tls", pod.Spec.Volumes[len(pod.Spec.Volumes)-1].Secret.SecretName)
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, tlsVolumePath, container.VolumeMounts[len(container.VolumeMounts)-1].MountPath)
+		assert.Equal(t, httpsPortInt32, container.Ports[len(container.Ports)-1].ContainerPort)
+		assert.Contains(t, container.Env, corev1.EnvVar{
+			Name:  jenkinsOptsName,
+			Value: "--httpsPort=8443 --httpsCertificate=/var/jenkins/tls/tls.crt --httpsPrivateKey=/var/jenkins/tls/tls.key",
+		})
+	})
+	t.Run("https keystore", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Master.HTTPSKeystore = &virtuslabv1alpha1.HTTPSKeystore{SecretName: "jenkins-keystore", KeystoreKey: "jenkins.p12"}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+		volume := pod.Spec.Volumes[len(pod.Spec.Volumes)-1]
+		assert.Equal(t, "jenkins-keystore", volume.Secret.SecretName)
+		assert.Equal(t, []corev1.KeyToPath{{Key: "jenkins.p12", Path: httpsKeystoreFileName}}, volume.Secret.Items)
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, httpsPortInt32, container.Ports[len(container.Ports)-1].ContainerPort)
+		// the password variable has to be defined before JENKINS_OPTS which references it
+		envs := container.Env
+		assert.Equal(t, httpsKeystorePasswordEnvName, envs[len(envs)-2].Name)
+		assert.Equal(t, "password", envs[len(envs)-2].ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, corev1.EnvVar{
+			Name:  jenkinsOptsName,
+			Value: "--httpsPort=8443 --httpsKeyStore=/var/jenkins/tls/keystore --httpsKeyStorePassword=$(JENKINS_HTTPS_KEYSTORE_PASSWORD)",
+		}, envs[len(envs)-1])
+	})
+	t.Run("prefix", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Master.Prefix = "/jenkins/"
+		jenkins.Spec.Master.SessionTimeout = 60
+		jenkins.Spec.Master.TLS = &virtuslabv1alpha1.MasterTLS{SecretName: "jenkins-tls"}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, "/jenkins/login", container.LivenessProbe.HTTPGet.Path)
+		assert.Equal(t, "/jenkins/login", container.ReadinessProbe.HTTPGet.Path)
+		assert.Contains(t, container.Env, corev1.EnvVar{
+			Name:  jenkinsOptsName,
+			Value: "--httpsPort=8443 --httpsCertificate=/var/jenkins/tls/tls.crt --httpsPrivateKey=/var/jenkins/tls/tls.key --prefix=/jenkins --sessionTimeout=60",
+		})
+	})
+	t.Run("sshd", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Master.Remoting.SSHD = &virtuslabv1alpha1.SSHD{}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+		ports := pod.Spec.Containers[0].Ports
+		assert.Equal(t, corev1.ContainerPort{Name: sshdPortName, ContainerPort: DefaultSSHDPort}, ports[len(ports)-1])
+
+		jenkins.Spec.Master.Remoting.SSHD.Port = 2222
+		service := NewSSHDService(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "jenkins-operator-sshd-jenkins-cr-name", service.Name)
+		assert.Equal(t, pod.Labels, service.Spec.Selector)
+		assert.Equal(t, int32(2222), service.Spec.Ports[0].Port)
+	})
+	t.Run("restricted pod security profile", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Security.PodSecurityProfile = virtuslabv1alpha1.PodSecurityProfileRestricted
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+		assert.True(t, *pod.Spec.SecurityContext.RunAsNonRoot)
+		securityContext := pod.Spec.Containers[0].SecurityContext
+		assert.True(t, *securityContext.RunAsNonRoot)
+		assert.False(t, *securityContext.AllowPrivilegeEscalation)
+		assert.Equal(t, []corev1.Capability{"ALL"}, securityContext.Capabilities.Drop)
+		assert.Equal(t, SeccompProfileRuntimeDefault, pod.Annotations[SeccompPodAnnotationKey])
+	})
+	t.Run("baseline pod security profile", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		jenkins.Spec.Security.PodSecurityProfile = virtuslabv1alpha1.PodSecurityProfileBaseline
+
+		assert.Equal(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+	})
+	t.Run("projected service account token", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		expirationSeconds := int64(7200)
+		jenkins.Spec.Master.ServiceAccountToken = &virtuslabv1alpha1.ServiceAccountToken{
+			Audience:          "kubernetes",
+			ExpirationSeconds: &expirationSeconds,
+		}
+
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.NotEqual(t, podHash(newJenkins(masterSpec())), podHash(jenkins))
+		assert.False(t, *pod.Spec.AutomountServiceAccountToken)
+		tokenProjection := pod.Spec.Volumes[len(pod.Spec.Volumes)-1].Projected.Sources[0].ServiceAccountToken
+		assert.Equal(t, "kubernetes", tokenProjection.Audience)
+		assert.Equal(t, expirationSeconds, *tokenProjection.ExpirationSeconds)
+		container := pod.Spec.Containers[0]
+		assert.Equal(t, serviceAccountTokenVolumePath, container.VolumeMounts[len(container.VolumeMounts)-1].MountPath)
+		assert.Contains(t, container.Env[1].Value, "-D"+kubernetesClientsCacheExpirationProperty+"=720")
+	})
+	t.Run("user annotations are not modified", func(t *testing.T) {
+		jenkins := newJenkins(masterSpec())
+		_ = NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+		assert.Equal(t, map[string]string{"test": "label"}, jenkins.Spec.Master.Annotations)
+	})
+}
+
+func TestBuildVaultEnvVars(t *testing.T) {
+	t.Run("vault not configured", func(t *testing.T) {
+		assert.Empty(t, buildVaultEnvVars(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("token auth method", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Vault: &virtuslabv1alpha1.Vault{
+					Address:    "https://vault.example.com",
+					AuthMethod: virtuslabv1alpha1.VaultAuthMethodToken,
+					SecretName: "vault-token",
+					Paths:      []string{"secret/jenkins", "secret/shared"},
+				},
+			},
+		}
+
+		envs := buildVaultEnvVars(jenkins)
+
+		values := map[string]string{}
+		for _, env := range envs {
+			values[env.Name] = env.Value
+			if env.Name == vaultTokenEnvName {
+				assert.Equal(t, "vault-token", env.ValueFrom.SecretKeyRef.Name)
+				assert.Equal(t, VaultTokenSecretKey, env.ValueFrom.SecretKeyRef.Key)
+			}
+		}
+		assert.Equal(t, "https://vault.example.com", values[vaultURLEnvName])
+		assert.Equal(t, "secret/jenkins,secret/shared", values[vaultPathsEnvName])
+		assert.Equal(t, "2", values[vaultEngineVersionEnvName])
+		assert.Contains(t, values, vaultTokenEnvName)
+	})
+}
+
+func TestBuildArtifactManagerEnvVars(t *testing.T) {
+	t.Run("artifact manager not configured", func(t *testing.T) {
+		assert.Empty(t, buildArtifactManagerEnvVars(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("default credentials chain", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				ArtifactManager: &virtuslabv1alpha1.ArtifactManager{
+					AmazonS3: &virtuslabv1alpha1.ArtifactManagerAmazonS3{BucketName: "artifacts", Region: "eu-west-1"},
+				},
+			},
+		}
+
+		envs := buildArtifactManagerEnvVars(jenkins)
+
+		assert.Equal(t, []corev1.EnvVar{{Name: awsRegionEnvName, Value: "eu-west-1"}}, envs)
+	})
+	t.Run("credentials secret", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				ArtifactManager: &virtuslabv1alpha1.ArtifactManager{
+					AmazonS3: &virtuslabv1alpha1.ArtifactManagerAmazonS3{BucketName: "artifacts", CredentialsSecretName: "aws"},
+				},
+			},
+		}
+
+		envs := buildArtifactManagerEnvVars(jenkins)
+
+		assert.Len(t, envs, 2)
+		assert.Equal(t, awsAccessKeyIDEnvName, envs[0].Name)
+		assert.Equal(t, ArtifactManagerS3AccessKeyIDSecretKey, envs[0].ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, awsSecretAccessKeyEnvName, envs[1].Name)
+		assert.Equal(t, "aws", envs[1].ValueFrom.SecretKeyRef.Name)
+	})
+}
+
+func TestBuildLDAPEnvVars(t *testing.T) {
+	t.Run("anonymous bind", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{LDAP: &virtuslabv1alpha1.LDAP{Server: "ldap://ldap"}},
+			},
+		}
+		assert.Empty(t, buildLDAPEnvVars(jenkins))
+	})
+	t.Run("bind credentials secret", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{LDAP: &virtuslabv1alpha1.LDAP{Server: "ldap://ldap", BindSecretName: "ldap-bind"}},
+			},
+		}
+
+		envs := buildLDAPEnvVars(jenkins)
+
+		assert.Len(t, envs, 2)
+		assert.Equal(t, ldapBindDNEnvName, envs[0].Name)
+		assert.Equal(t, LDAPBindDNSecretKey, envs[0].ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, ldapBindPasswordEnvName, envs[1].Name)
+		assert.Equal(t, "ldap-bind", envs[1].ValueFrom.SecretKeyRef.Name)
+	})
+}
+
+func TestBuildOIDCEnvVars(t *testing.T) {
+	t.Run("oidc not configured", func(t *testing.T) {
+		assert.Empty(t, buildOIDCEnvVars(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("break-glass account", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Security: virtuslabv1alpha1.Security{
+					OIDC: &virtuslabv1alpha1.OIDC{IssuerURL: "https://accounts.example.com", ClientSecretName: "oidc", BreakGlassSecretName: "break-glass"},
+				},
+			},
+		}
+
+		envs := buildOIDCEnvVars(jenkins)
+
+		assert.Len(t, envs, 4)
+		assert.Equal(t, oidcClientIDEnvName, envs[0].Name)
+		assert.Equal(t, "oidc", envs[0].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, oidcBreakGlassPasswordEnvName, envs[3].Name)
+		assert.Equal(t, "break-glass", envs[3].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, OIDCBreakGlassPasswordSecretKey, envs[3].ValueFrom.SecretKeyRef.Key)
+	})
+}
@@ -0,0 +1,13 @@
+package resources
+
+import (
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+// GetJenkinsPrefix returns context path of Jenkins without trailing slash, returns empty string when Jenkins is
+// served from the root path
+func GetJenkinsPrefix(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return strings.TrimSuffix(jenkins.Spec.Master.Prefix, "/")
+}
@@ -0,0 +1,118 @@
+package resources
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/monitoring/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultAlertSeverity is the severity label of the alerts when Jenkins.Spec.Monitoring.PrometheusRule.Severity
+	// isn't set
+	DefaultAlertSeverity = "warning"
+	// DefaultAlertQueueLength is the build queue length of the JenkinsQueueLengthHigh alert when
+	// Jenkins.Spec.Monitoring.PrometheusRule.QueueLength isn't set
+	DefaultAlertQueueLength = 10
+
+	// reconcileErrorRatio is the ratio of failed reconciliation loops firing the JenkinsOperatorReconcileErrors alert
+	reconcileErrorRatio = 0.1
+)
+
+// IsPrometheusRuleEnabled tells if the operator creates Prometheus Operator PrometheusRule with alerts of the Jenkins CR
+func IsPrometheusRuleEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Monitoring != nil && jenkins.Spec.Monitoring.PrometheusRule != nil
+}
+
+// getAlertSeverity returns the severity label of the alerts
+func getAlertSeverity(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if len(jenkins.Spec.Monitoring.PrometheusRule.Severity) == 0 {
+		return DefaultAlertSeverity
+	}
+	return jenkins.Spec.Monitoring.PrometheusRule.Severity
+}
+
+// getAlertQueueLength returns the build queue length of the JenkinsQueueLengthHigh alert
+func getAlertQueueLength(jenkins *virtuslabv1alpha1.Jenkins) int {
+	if jenkins.Spec.Monitoring.PrometheusRule.QueueLength == 0 {
+		return DefaultAlertQueueLength
+	}
+	return jenkins.Spec.Monitoring.PrometheusRule.QueueLength
+}
+
+// NewPrometheusRule builds Prometheus Operator PrometheusRule with alerts of the Jenkins CR, Jenkins metrics are
+// scraped by the PodMonitor and the operator metrics are labeled by the namespace and name of the Jenkins CR
+func NewPrometheusRule(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *monitoringv1.PrometheusRule {
+	labels := meta.Labels
+	meta.Labels = map[string]string{}
+	for key, value := range jenkins.Spec.Monitoring.PrometheusRule.Labels {
+		meta.Labels[key] = value
+	}
+	for key, value := range labels {
+		meta.Labels[key] = value
+	}
+
+	return &monitoringv1.PrometheusRule{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       monitoringv1.PrometheusRuleKind,
+			APIVersion: monitoringv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name:  fmt.Sprintf("jenkins-%s-%s", jenkins.ObjectMeta.Namespace, jenkins.ObjectMeta.Name),
+					Rules: buildAlertRules(jenkins),
+				},
+			},
+		},
+	}
+}
+
+// buildAlertRules returns alerts of Jenkins master pod and the operator reconciliation of the Jenkins CR
+func buildAlertRules(jenkins *virtuslabv1alpha1.Jenkins) []monitoringv1.Rule {
+	pod := fmt.Sprintf(`namespace="%s",pod="%s"`, jenkins.ObjectMeta.Namespace, GetResourceName(jenkins))
+	cr := fmt.Sprintf(`namespace="%s",jenkins="%s"`, jenkins.ObjectMeta.Namespace, jenkins.ObjectMeta.Name)
+	labels := map[string]string{
+		"severity":  getAlertSeverity(jenkins),
+		"namespace": jenkins.ObjectMeta.Namespace,
+		"jenkins":   jenkins.ObjectMeta.Name,
+	}
+	name := fmt.Sprintf("%s/%s", jenkins.ObjectMeta.Namespace, jenkins.ObjectMeta.Name)
+
+	return []monitoringv1.Rule{
+		{
+			Alert:  "JenkinsDown",
+			Expr:   fmt.Sprintf("up{%s} == 0 or absent(up{%s})", pod, pod),
+			For:    "5m",
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary":     fmt.Sprintf("Jenkins %s is down", name),
+				"description": "Prometheus can't scrape metrics of Jenkins master pod for 5 minutes.",
+			},
+		},
+		{
+			Alert:  "JenkinsQueueLengthHigh",
+			Expr:   fmt.Sprintf("sum(jenkins_queue_size_value{%s}) > %d", pod, getAlertQueueLength(jenkins)),
+			For:    "15m",
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary":     fmt.Sprintf("Jenkins %s build queue is long", name),
+				"description": "{{ $value }} builds are waiting in the build queue for 15 minutes.",
+			},
+		},
+		{
+			Alert: "JenkinsOperatorReconcileErrors",
+			Expr: fmt.Sprintf("sum(rate(jenkins_operator_reconcile_errors_total{%s}[5m])) / sum(rate(jenkins_operator_reconcile_duration_seconds_count{%s}[5m])) > %g",
+				cr, cr, reconcileErrorRatio),
+			For:    "15m",
+			Labels: labels,
+			Annotations: map[string]string{
+				"summary":     fmt.Sprintf("Reconciliation of Jenkins %s fails", name),
+				"description": "{{ $value | humanizePercentage }} of reconciliation loops fail for 15 minutes, see the operator logs and events of the Jenkins CR.",
+			},
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPrometheusRule(t *testing.T) {
+	monitoringSpec := func(prometheusRule *virtuslabv1alpha1.PrometheusRule) virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Monitoring: &virtuslabv1alpha1.Monitoring{PrometheusRule: prometheusRule},
+		}
+	}
+	getRule := func(t *testing.T, jenkins *virtuslabv1alpha1.Jenkins, alert string) (expr string, labels map[string]string) {
+		prometheusRule := NewPrometheusRule(NewResourceObjectMeta(jenkins), jenkins)
+		if assert.Len(t, prometheusRule.Spec.Groups, 1) {
+			for _, rule := range prometheusRule.Spec.Groups[0].Rules {
+				if rule.Alert == alert {
+					return rule.Expr, rule.Labels
+				}
+			}
+		}
+		assert.Fail(t, "alert not found", alert)
+		return "", nil
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		jenkins := newJenkins(monitoringSpec(&virtuslabv1alpha1.PrometheusRule{}))
+
+		prometheusRule := NewPrometheusRule(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "jenkins-operator-jenkins-cr-name", prometheusRule.Name)
+		assert.Equal(t, "jenkins-cr-name", prometheusRule.Labels["jenkins-cr"])
+		expr, labels := getRule(t, jenkins, "JenkinsQueueLengthHigh")
+		assert.Equal(t, `sum(jenkins_queue_size_value{namespace="namespace-name",pod="jenkins-operator-jenkins-cr-name"}) > 10`, expr)
+		assert.Equal(t, DefaultAlertSeverity, labels["severity"])
+		assert.Equal(t, "jenkins-cr-name", labels["jenkins"])
+		expr, _ = getRule(t, jenkins, "JenkinsDown")
+		assert.Equal(t, `up{namespace="namespace-name",pod="jenkins-operator-jenkins-cr-name"} == 0 or absent(up{namespace="namespace-name",pod="jenkins-operator-jenkins-cr-name"})`, expr)
+		expr, _ = getRule(t, jenkins, "JenkinsOperatorReconcileErrors")
+		assert.Contains(t, expr, `jenkins_operator_reconcile_errors_total{namespace="namespace-name",jenkins="jenkins-cr-name"}`)
+	})
+	t.Run("custom labels, severity and queue length", func(t *testing.T) {
+		jenkins := newJenkins(monitoringSpec(&virtuslabv1alpha1.PrometheusRule{
+			Labels:      map[string]string{"release": "kube-prometheus"},
+			Severity:    "critical",
+			QueueLength: 25,
+		}))
+
+		prometheusRule := NewPrometheusRule(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "kube-prometheus", prometheusRule.Labels["release"])
+		assert.Equal(t, "jenkins-cr-name", prometheusRule.Labels["jenkins-cr"])
+		expr, labels := getRule(t, jenkins, "JenkinsQueueLengthHigh")
+		assert.Equal(t, `sum(jenkins_queue_size_value{namespace="namespace-name",pod="jenkins-operator-jenkins-cr-name"}) > 25`, expr)
+		assert.Equal(t, "critical", labels["severity"])
+	})
+}
@@ -1,20 +1,23 @@
 package resources
 
 import (
-	"math/rand"
-	"time"
+	"crypto/rand"
+	"math/big"
 )
 
 var randomCharset = []rune("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
+// randomString returns random string generated with cryptographically secure random number generator
 func randomString(n int) string {
 	b := make([]rune, n)
+	max := big.NewInt(int64(len(randomCharset)))
 	for i := range b {
-		b[i] = randomCharset[rand.Intn(len(randomCharset))]
+		index, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			// the system random number generator isn't available, passwords can't be generated safely
+			panic(err)
+		}
+		b[i] = randomCharset[index.Int64()]
 	}
 	return string(b)
 }
-
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
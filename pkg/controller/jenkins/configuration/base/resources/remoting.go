@@ -0,0 +1,109 @@
+package resources
+
+import (
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+var disableInsecureFeaturesTemplate = template.Must(template.New("disable-insecure-features").Parse(`
+import jenkins.*
+import jenkins.model.*
+import hudson.model.*
+import jenkins.security.s2m.*
+
+def jenkins = Jenkins.instance
+
+println("Disabling insecure Jenkins features...")
+
+{{ if .AgentProtocols -}}
+println("Configuring agent protocols...")
+HashSet<String> newProtocols = new HashSet<>(Arrays.asList({{ range $index, $protocol := .AgentProtocols }}{{ if $index }}, {{ end }}"{{ $protocol }}"{{ end }}))
+{{- else -}}
+println("Disabling insecure protocols...")
+println("Old protocols: [" + jenkins.getAgentProtocols().join(", ") + "]")
+HashSet<String> newProtocols = new HashSet<>(jenkins.getAgentProtocols())
+newProtocols.removeAll(Arrays.asList("JNLP3-connect", "JNLP2-connect", "JNLP-connect", "CLI-connect"))
+{{- end }}
+println("New protocols: [" + newProtocols.join(", ") + "]")
+jenkins.setAgentProtocols(newProtocols)
+
+{{ if .AgentListenerDisabled -}}
+println("Disabling TCP agent listener...")
+jenkins.setSlaveAgentPort(-1)
+{{- else -}}
+println("Enabling TCP agent listener...")
+jenkins.setSlaveAgentPort({{ .AgentListenerPort }})
+{{- end }}
+
+{{ if not .CLIEnabled -}}
+println("Disabling CLI access of /cli URL...")
+def remove = { list ->
+    list.each { item ->
+        if (item.getClass().name.contains("CLIAction")) {
+            println("Removing extension ${item.getClass().name}")
+            list.remove(item)
+        }
+    }
+}
+remove(jenkins.getExtensionList(RootAction.class))
+remove(jenkins.actions)
+
+{{ end -}}
+{{ if .CLIRemotingEnabled -}}
+println("Enabling CLI over remoting...")
+CLI.get().setEnabled(true)
+{{- else -}}
+println("Disable CLI completely...")
+CLI.get().setEnabled(false)
+println("CLI disabled")
+{{- end }}
+
+// the SSH server is a core module in older Jenkins versions and a detached plugin in newer ones
+def sshdClass = null
+try {
+    sshdClass = jenkins.pluginManager.uberClassLoader.loadClass("org.jenkinsci.main.modules.sshd.SSHD")
+} catch (ClassNotFoundException ignored) {
+}
+{{ if .SSHDEnabled -}}
+println("Enabling SSH server on port {{ .SSHDPort }}...")
+if (sshdClass == null) {
+    throw new IllegalStateException("Jenkins SSH server isn't available, install 'sshd' plugin")
+}
+sshdClass.getMethod("get").invoke(null).setPort({{ .SSHDPort }})
+{{- else -}}
+if (sshdClass != null) {
+    println("Disabling SSH server...")
+    sshdClass.getMethod("get").invoke(null).setPort(-1)
+}
+{{- end }}
+
+jenkins.save()
+`))
+
+// IsAgentListenerExposed tells if TCP agent listener port is exposed by the Service and allowed by the NetworkPolicy
+func IsAgentListenerExposed(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	remoting := jenkins.Spec.Master.Remoting
+	return !remoting.AgentListenerDisabled && !remoting.WebSocket
+}
+
+// buildDisableInsecureFeaturesGroovyScript renders groovy script which configures Jenkins CLI, SSH server and agent
+// protocols from Jenkins.Spec.Master.Remoting, agent protocols can be provided by the security profile
+func buildDisableInsecureFeaturesGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	data := struct {
+		virtuslabv1alpha1.Remoting
+		AgentListenerPort int
+		SSHDEnabled       bool
+		SSHDPort          int32
+	}{
+		Remoting:          jenkins.Spec.Master.Remoting,
+		AgentListenerPort: slavePortInt,
+		SSHDEnabled:       IsSSHDEnabled(jenkins),
+		SSHDPort:          GetSSHDPort(jenkins),
+	}
+	data.Remoting.AgentProtocols = getAgentProtocols(jenkins)
+
+	// the template doesn't contain any calls which could fail, protocol names are validated
+	output, _ := render(disableInsecureFeaturesTemplate, data)
+	return output
+}
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"bytes"
+	"strings"
 	"text/template"
 )
 
@@ -14,3 +15,8 @@ func render(template *template.Template, data interface{}) (string, error) {
 
 	return buffer.String(), nil
 }
+
+// escapeGroovyString escapes the value to be used inside single-quoted groovy string
+func escapeGroovyString(value string) string {
+	return strings.Replace(strings.Replace(value, `\`, `\\`, -1), `'`, `\'`, -1)
+}
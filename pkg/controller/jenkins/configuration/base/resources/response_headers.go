@@ -0,0 +1,119 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+// nginxConfigurationSnippetAnnotationKey is ingress-nginx annotation adding nginx configuration to the Ingress location
+const nginxConfigurationSnippetAnnotationKey = "nginx.ingress.kubernetes.io/configuration-snippet"
+
+// getSortedHeaderNames returns header names sorted so that rendered resources are stable
+func getSortedHeaderNames(headers map[string]string) []string {
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyIngressResponseHeadersAnnotation appends ingress-nginx directives setting Jenkins.Spec.Service.Ingress.ResponseHeaders
+// to the configuration snippet from Jenkins.Spec.Service.Ingress.Annotations
+func applyIngressResponseHeadersAnnotation(annotations map[string]string, ingress *virtuslabv1alpha1.Ingress) {
+	if len(ingress.ResponseHeaders) == 0 {
+		return
+	}
+
+	snippet := annotations[nginxConfigurationSnippetAnnotationKey]
+	if len(snippet) > 0 && !strings.HasSuffix(snippet, "\n") {
+		snippet += "\n"
+	}
+	for _, name := range getSortedHeaderNames(ingress.ResponseHeaders) {
+		// more_set_headers replaces the header sent by Jenkins
+		snippet += fmt.Sprintf("more_set_headers \"%s: %s\";\n", name, ingress.ResponseHeaders[name])
+	}
+	annotations[nginxConfigurationSnippetAnnotationKey] = snippet
+}
+
+const configureResponseHeadersFmt = `
+import hudson.util.PluginServletFilter
+import jenkins.model.Jenkins
+
+import javax.servlet.Filter
+import javax.servlet.FilterChain
+import javax.servlet.FilterConfig
+import javax.servlet.ServletException
+import javax.servlet.ServletRequest
+import javax.servlet.ServletResponse
+import javax.servlet.http.HttpServletResponse
+import javax.servlet.http.HttpServletResponseWrapper
+
+class ResponseHeadersFilter implements Filter {
+    Map<String, String> headers
+
+    void init(FilterConfig filterConfig) {}
+
+    void doFilter(ServletRequest request, ServletResponse response, FilterChain chain) throws IOException, ServletException {
+        if (!(response instanceof HttpServletResponse)) {
+            chain.doFilter(request, response)
+            return
+        }
+        headers.each { name, value -> response.setHeader(name, value) }
+        // the same headers set by Jenkins are ignored
+        chain.doFilter(request, new HttpServletResponseWrapper(response) {
+            void setHeader(String name, String value) {
+                if (!isReplaced(name)) {
+                    super.setHeader(name, value)
+                }
+            }
+
+            void addHeader(String name, String value) {
+                if (!isReplaced(name)) {
+                    super.addHeader(name, value)
+                }
+            }
+        })
+    }
+
+    boolean isReplaced(String name) {
+        headers.keySet().any { it.equalsIgnoreCase(name) }
+    }
+
+    void destroy() {}
+}
+
+// the filter added by the previous run is replaced, so removed headers aren't sent anymore
+def context = Jenkins.getInstance().servletContext
+def filterAttribute = 'jenkins-operator.responseHeadersFilter'
+def currentFilter = context.getAttribute(filterAttribute)
+if (currentFilter != null) {
+    PluginServletFilter.removeFilter(currentFilter)
+    context.removeAttribute(filterAttribute)
+}
+
+def headers = %s
+if (!headers.isEmpty()) {
+    def filter = new ResponseHeadersFilter(headers: headers)
+    PluginServletFilter.addFilter(filter)
+    context.setAttribute(filterAttribute, filter)
+}
+`
+
+// buildConfigureResponseHeadersGroovyScript renders groovy script which adds Jenkins.Spec.Master.ResponseHeaders to all
+// Jenkins responses by servlet filter, the script is rendered even without the headers to remove the filter
+func buildConfigureResponseHeadersGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	headers := jenkins.Spec.Master.ResponseHeaders
+	if len(headers) == 0 {
+		return fmt.Sprintf(configureResponseHeadersFmt, "[:]")
+	}
+
+	var entries []string
+	for _, name := range getSortedHeaderNames(headers) {
+		entries = append(entries, fmt.Sprintf("'%s': '%s'", escapeGroovyString(name), escapeGroovyString(headers[name])))
+	}
+	return fmt.Sprintf(configureResponseHeadersFmt, fmt.Sprintf("[%s]", strings.Join(entries, ", ")))
+}
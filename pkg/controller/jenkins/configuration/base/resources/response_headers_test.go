@@ -0,0 +1,30 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildConfigureResponseHeadersGroovyScript(t *testing.T) {
+	t.Run("headers", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Master.ResponseHeaders = map[string]string{
+			"X-Frame-Options":         "DENY",
+			"Content-Security-Policy": "default-src 'self'",
+		}
+
+		script := buildConfigureResponseHeadersGroovyScript(jenkins)
+
+		assert.Contains(t, script, `def headers = ['Content-Security-Policy': 'default-src \'self\'', 'X-Frame-Options': 'DENY']`)
+	})
+	t.Run("no headers", func(t *testing.T) {
+		script := buildConfigureResponseHeadersGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		// the filter added before is still removed
+		assert.Contains(t, script, "PluginServletFilter.removeFilter(currentFilter)")
+		assert.Contains(t, script, "def headers = [:]")
+	})
+}
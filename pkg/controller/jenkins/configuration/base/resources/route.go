@@ -0,0 +1,77 @@
+package resources
+
+import (
+	routev1 "github.com/VirtusLab/jenkins-operator/pkg/apis/route/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// IsRouteEnabled tells if the operator manages OpenShift Route of Jenkins UI
+func IsRouteEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Service.Route != nil
+}
+
+// GetRouteTermination returns TLS termination of OpenShift Route, edge by default
+func GetRouteTermination(jenkins *virtuslabv1alpha1.Jenkins) virtuslabv1alpha1.RouteTermination {
+	if termination := jenkins.Spec.Service.Route.Termination; len(termination) > 0 {
+		return termination
+	}
+	return virtuslabv1alpha1.RouteTerminationEdge
+}
+
+// RouteCertificates contains PEM encoded certificates copied to OpenShift Route, empty values are omitted
+type RouteCertificates struct {
+	// Certificate and Key are the host certificate and its private key served by the router
+	Certificate, Key string
+	// DestinationCACertificate verifies Jenkins master certificate with reencrypt termination
+	DestinationCACertificate string
+}
+
+// NewRoute builds OpenShift Route routing Jenkins.Spec.Service.Route.Host to the Jenkins master Service,
+// the router connects to the HTTP port with edge termination and to the HTTPS port otherwise
+func NewRoute(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins, certificates RouteCertificates) *routev1.Route {
+	spec := jenkins.Spec.Service.Route
+	meta.Annotations = map[string]string{}
+	for key, value := range spec.Annotations {
+		meta.Annotations[key] = value
+	}
+
+	termination := GetRouteTermination(jenkins)
+	path := GetJenkinsPrefix(jenkins)
+	if termination == virtuslabv1alpha1.RouteTerminationPassthrough {
+		// the router can't inspect paths of passthrough connections
+		path = ""
+	}
+	// the router looks up the target port by the Service port name
+	portNames := GetServicePortNames(jenkins)
+	targetPort := portNames.HTTPS
+	if termination == virtuslabv1alpha1.RouteTerminationEdge {
+		targetPort = portNames.HTTP
+	}
+
+	return &routev1.Route{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       routev1.RouteKind,
+			APIVersion: routev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: meta,
+		Spec: routev1.RouteSpec{
+			Host: spec.Host,
+			Path: path,
+			To: routev1.RouteTargetReference{
+				Kind: routev1.ServiceKind,
+				Name: GetResourceName(jenkins),
+			},
+			Port: &routev1.RoutePort{TargetPort: intstr.FromString(targetPort)},
+			TLS: &routev1.TLSConfig{
+				Termination:                   routev1.TLSTerminationType(termination),
+				Certificate:                   certificates.Certificate,
+				Key:                           certificates.Key,
+				DestinationCACertificate:      certificates.DestinationCACertificate,
+				InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+			},
+		},
+	}
+}
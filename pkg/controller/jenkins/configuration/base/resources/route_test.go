@@ -0,0 +1,52 @@
+package resources
+
+import (
+	"testing"
+
+	routev1 "github.com/VirtusLab/jenkins-operator/pkg/apis/route/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestNewRoute(t *testing.T) {
+	routeSpec := func(route *virtuslabv1alpha1.Route) virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Service: virtuslabv1alpha1.JenkinsService{Route: route},
+		}
+	}
+
+	t.Run("edge", func(t *testing.T) {
+		jenkins := newJenkins(routeSpec(&virtuslabv1alpha1.Route{
+			Host:        "jenkins.apps.example.com",
+			Annotations: map[string]string{"haproxy.router.openshift.io/timeout": "5m"},
+		}))
+
+		route := NewRoute(NewResourceObjectMeta(jenkins), jenkins, RouteCertificates{Certificate: "certificate", Key: "key"})
+
+		assert.Equal(t, "jenkins-operator-jenkins-cr-name", route.Name)
+		assert.Equal(t, map[string]string{"haproxy.router.openshift.io/timeout": "5m"}, route.Annotations)
+		assert.Equal(t, "jenkins.apps.example.com", route.Spec.Host)
+		assert.Equal(t, routev1.RouteTargetReference{Kind: "Service", Name: "jenkins-operator-jenkins-cr-name"}, route.Spec.To)
+		assert.Equal(t, intstr.FromString(httpPortName), route.Spec.Port.TargetPort)
+		assert.Equal(t, &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationEdge,
+			Certificate:                   "certificate",
+			Key:                           "key",
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyRedirect,
+		}, route.Spec.TLS)
+		assert.Equal(t, "https://jenkins.apps.example.com/", GetJenkinsRootURL(jenkins))
+	})
+	t.Run("reencrypt", func(t *testing.T) {
+		jenkins := newJenkins(routeSpec(&virtuslabv1alpha1.Route{Termination: virtuslabv1alpha1.RouteTerminationReencrypt}))
+
+		route := NewRoute(NewResourceObjectMeta(jenkins), jenkins, RouteCertificates{DestinationCACertificate: "ca"})
+
+		assert.Equal(t, intstr.FromString(httpsPortName), route.Spec.Port.TargetPort)
+		assert.Equal(t, routev1.TLSTerminationReencrypt, route.Spec.TLS.Termination)
+		assert.Equal(t, "ca", route.Spec.TLS.DestinationCACertificate)
+		// the host generated by OpenShift router isn't known
+		assert.Empty(t, GetJenkinsRootURL(jenkins))
+	})
+}
@@ -0,0 +1,126 @@
+package resources
+
+import (
+	"encoding/json"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SAMLPluginName is the name of plugin required by Jenkins.Spec.Security.SAML
+	SAMLPluginName = "saml"
+	// SAMLIdPMetadataConfigMapKey is the ConfigMap key with identity provider metadata
+	SAMLIdPMetadataConfigMapKey = "idp-metadata.xml"
+	// SAMLKeystoreSecretKey is the SAML keystore Secret key with JKS keystore
+	SAMLKeystoreSecretKey = "keystore.jks"
+	// SAMLKeystorePasswordSecretKey is the SAML keystore Secret key with keystore password
+	SAMLKeystorePasswordSecretKey = "keystorePassword"
+	// SAMLPrivateKeyPasswordSecretKey is the SAML keystore Secret key with private key password
+	SAMLPrivateKeyPasswordSecretKey = "privateKeyPassword"
+
+	samlKeystoreVolumeName = "saml-keystore"
+	samlKeystoreVolumePath = "/var/jenkins/saml-keystore"
+
+	samlHTTPRedirectBinding              = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	samlMaximumAuthenticationLifetimeSec = 24 * 60 * 60
+
+	// environment variables resolved by configuration as code plugin, so the passwords aren't stored in ConfigMaps
+	samlKeystorePasswordEnvName   = "JENKINS_SAML_KEYSTORE_PASSWORD"
+	samlPrivateKeyPasswordEnvName = "JENKINS_SAML_PRIVATE_KEY_PASSWORD"
+)
+
+// buildSAMLEnvVars returns Jenkins master container environment variables with SAML keystore passwords referenced
+// from the Secret
+func buildSAMLEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	saml := jenkins.Spec.Security.SAML
+	if saml == nil || len(saml.KeystoreSecretName) == 0 {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		buildSecretKeyEnvVar(samlKeystorePasswordEnvName, saml.KeystoreSecretName, SAMLKeystorePasswordSecretKey),
+		buildSecretKeyEnvVar(samlPrivateKeyPasswordEnvName, saml.KeystoreSecretName, SAMLPrivateKeyPasswordSecretKey),
+	}
+}
+
+// buildSAMLKeystoreVolume returns volume and its mount with SAML keystore file, only the keystore is projected
+// from the Secret, returns nils when the keystore isn't configured
+func buildSAMLKeystoreVolume(jenkins *virtuslabv1alpha1.Jenkins) (*corev1.Volume, *corev1.VolumeMount) {
+	saml := jenkins.Spec.Security.SAML
+	if saml == nil || len(saml.KeystoreSecretName) == 0 {
+		return nil, nil
+	}
+
+	volume := &corev1.Volume{
+		Name: samlKeystoreVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: saml.KeystoreSecretName,
+				Items:      []corev1.KeyToPath{{Key: SAMLKeystoreSecretKey, Path: SAMLKeystoreSecretKey}},
+			},
+		},
+	}
+	mount := &corev1.VolumeMount{
+		Name:      samlKeystoreVolumeName,
+		MountPath: samlKeystoreVolumePath,
+		ReadOnly:  true,
+	}
+	return volume, mount
+}
+
+// buildSAMLConfigurationAsCode renders configuration as code file which sets SAML security realm
+// from Jenkins.Spec.Security.SAML, metadata from ConfigMap has to be already resolved into IdPMetadata.XML
+func buildSAMLConfigurationAsCode(jenkins *virtuslabv1alpha1.Jenkins) string {
+	saml := jenkins.Spec.Security.SAML
+	if saml == nil {
+		return ""
+	}
+
+	metadata := map[string]interface{}{}
+	if len(saml.IdPMetadata.URL) > 0 {
+		metadata["url"] = saml.IdPMetadata.URL
+		metadata["period"] = 60 // minutes
+	} else {
+		metadata["xml"] = saml.IdPMetadata.XML
+	}
+
+	realm := map[string]interface{}{
+		"idpMetadataConfiguration":      metadata,
+		"binding":                       samlHTTPRedirectBinding,
+		"maximumAuthenticationLifetime": samlMaximumAuthenticationLifetimeSec,
+		"usernameCaseConversion":        "none",
+	}
+	attributes := map[string]string{
+		"usernameAttributeName":    saml.UsernameAttribute,
+		"displayNameAttributeName": saml.DisplayNameAttribute,
+		"emailAttributeName":       saml.EmailAttribute,
+		"groupsAttributeName":      saml.GroupsAttribute,
+	}
+	for name, value := range attributes {
+		if len(value) > 0 {
+			realm[name] = value
+		}
+	}
+	if len(saml.KeystoreSecretName) > 0 {
+		realm["encryptionData"] = map[string]interface{}{
+			"keystorePath":       samlKeystoreVolumePath + "/" + SAMLKeystoreSecretKey,
+			"keystorePassword":   envVariableReference(samlKeystorePasswordEnvName),
+			"privateKeyAlias":    saml.PrivateKeyAlias,
+			"privateKeyPassword": envVariableReference(samlPrivateKeyPasswordEnvName),
+		}
+	}
+
+	configuration := map[string]interface{}{
+		"jenkins": map[string]interface{}{
+			"securityRealm": map[string]interface{}{
+				"saml": realm,
+			},
+		},
+	}
+
+	// JSON is valid YAML, marshalling maps of strings, numbers and bools never fails
+	output, _ := json.MarshalIndent(configuration, "", "  ")
+	return string(output)
+}
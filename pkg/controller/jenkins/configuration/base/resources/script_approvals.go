@@ -0,0 +1,44 @@
+package resources
+
+import (
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+var configureScriptApprovalsTemplate = template.Must(template.New("configure-script-approvals").Parse(`
+import org.jenkinsci.plugins.scriptsecurity.scripts.ScriptApproval
+
+def scriptApproval = ScriptApproval.get()
+{{- range .Signatures }}
+scriptApproval.approveSignature('{{ . }}')
+{{- end }}
+{{- range .ScriptHashes }}
+scriptApproval.approveScript('{{ . }}')
+{{- end }}
+scriptApproval.save()
+`))
+
+// buildConfigureScriptApprovalsGroovyScript renders groovy script which approves signatures and scripts
+// from Jenkins.Spec.Master.ScriptApprovals, existing approvals aren't revoked
+func buildConfigureScriptApprovalsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	approvals := jenkins.Spec.Master.ScriptApprovals
+	if len(approvals.Signatures) == 0 && len(approvals.ScriptHashes) == 0 {
+		return ""
+	}
+
+	data := struct {
+		Signatures   []string
+		ScriptHashes []string
+	}{}
+	for _, signature := range approvals.Signatures {
+		data.Signatures = append(data.Signatures, escapeGroovyString(signature))
+	}
+	for _, hash := range approvals.ScriptHashes {
+		data.ScriptHashes = append(data.ScriptHashes, escapeGroovyString(hash))
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureScriptApprovalsTemplate, data)
+	return output
+}
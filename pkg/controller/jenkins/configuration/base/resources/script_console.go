@@ -0,0 +1,23 @@
+package resources
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const (
+	// RunScriptsPermission is the permission required by Groovy script console and groovy CLI commands, it can't be
+	// granted when the script console is disabled
+	RunScriptsPermission = "Overall/RunScripts"
+	// AdministerPermission implies RunScriptsPermission in Jenkins, so it can't be granted when the script console
+	// is disabled either, only the operator user stays administrator
+	AdministerPermission = "Overall/Administer"
+)
+
+// IsScriptConsoleEnabled tells if users other than the operator can use Groovy script console, it's set by
+// Jenkins.Spec.Security.ScriptConsoleEnabled or the security profile
+func IsScriptConsoleEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	if scriptConsoleEnabled := jenkins.Spec.Security.ScriptConsoleEnabled; scriptConsoleEnabled != nil {
+		return *scriptConsoleEnabled
+	}
+	return !IsSecurityProfile(jenkins, virtuslabv1alpha1.SecurityProfileStrict)
+}
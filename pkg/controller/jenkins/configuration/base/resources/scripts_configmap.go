@@ -247,6 +247,18 @@ set -x
 mkdir -p {{ .JenkinsHomePath }}/init.groovy.d
 cp -n {{ .InitConfigurationPath }}/*.groovy {{ .JenkinsHomePath }}/init.groovy.d
 
+# restore Jenkins encryption keys persisted by the operator, so credentials encrypted before the pod recreation
+# can be decrypted
+mkdir -p {{ .JenkinsHomePath }}/secrets
+for file in {{ .JenkinsSecretsVolumePath }}/*; do
+    [ -f "$file" ] || continue
+    if [ "$(basename "$file")" == "{{ .JenkinsSecretKeyFileName }}" ]; then
+        cp "$file" {{ .JenkinsHomePath }}/{{ .JenkinsSecretKeyFileName }}
+    else
+        cp "$file" {{ .JenkinsHomePath }}/secrets/
+    fi
+done
+
 mkdir -p {{ .JenkinsHomePath }}/scripts
 cp {{ .JenkinsScriptsVolumePath }}/*.sh {{ .JenkinsHomePath }}/scripts
 chmod +x {{ .JenkinsHomePath }}/scripts/*.sh
@@ -277,6 +289,8 @@ func buildInitBashScript(pluginsToInstall map[string][]string) (*string, error)
 		InitConfigurationPath    string
 		InstallPluginsCommand    string
 		JenkinsScriptsVolumePath string
+		JenkinsSecretsVolumePath string
+		JenkinsSecretKeyFileName string
 		Plugins                  map[string][]string
 	}{
 		JenkinsHomePath:          jenkinsHomePath,
@@ -284,6 +298,8 @@ func buildInitBashScript(pluginsToInstall map[string][]string) (*string, error)
 		Plugins:                  pluginsToInstall,
 		InstallPluginsCommand:    installPluginsCommand,
 		JenkinsScriptsVolumePath: jenkinsScriptsVolumePath,
+		JenkinsSecretsVolumePath: jenkinsSecretsVolumePath,
+		JenkinsSecretKeyFileName: JenkinsSecretKeyFileName,
 	}
 
 	output, err := render(initBashTemplate, data)
@@ -302,7 +318,7 @@ func getScriptsConfigMapName(jenkins *virtuslabv1alpha1.Jenkins) string {
 func NewScriptsConfigMap(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) (*corev1.ConfigMap, error) {
 	meta.Name = getScriptsConfigMapName(jenkins)
 
-	initBashScript, err := buildInitBashScript(jenkins.Spec.Master.Plugins)
+	initBashScript, err := buildInitBashScript(GetMasterPlugins(jenkins))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,116 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const (
+	// DefaultContentSecurityPolicy is Jenkins default Content-Security-Policy header of workspace files and artifacts
+	DefaultContentSecurityPolicy = "sandbox; default-src 'none'; img-src 'self'; style-src 'self';"
+	// StrictContentSecurityPolicy forbids all resources of workspace files and artifacts, including images and styles
+	StrictContentSecurityPolicy = "sandbox; default-src 'none';"
+
+	contentSecurityPolicyProperty = "hudson.model.DirectoryBrowserSupport.CSP"
+)
+
+// strictAgentProtocols contains the only agent protocols enabled by the strict security profile
+var strictAgentProtocols = []string{"JNLP4-connect", "Ping"}
+
+// IsSecurityProfile tells if the security hardening preset is at least the given one
+func IsSecurityProfile(jenkins *virtuslabv1alpha1.Jenkins, profile virtuslabv1alpha1.SecurityProfile) bool {
+	current := jenkins.Spec.Security.Profile
+	switch profile {
+	case virtuslabv1alpha1.SecurityProfileRecommended:
+		return current == virtuslabv1alpha1.SecurityProfileRecommended || current == virtuslabv1alpha1.SecurityProfileStrict
+	case virtuslabv1alpha1.SecurityProfileStrict:
+		return current == virtuslabv1alpha1.SecurityProfileStrict
+	default:
+		return true
+	}
+}
+
+// getExcludeClientIPFromCrumb returns the crumb issuer setting from Jenkins.Spec.Master.CSRF or the security profile,
+// returns nil when the crumb issuer configured in Jenkins is kept
+func getExcludeClientIPFromCrumb(jenkins *virtuslabv1alpha1.Jenkins) *bool {
+	if excludeClientIPFromCrumb := jenkins.Spec.Master.CSRF.ExcludeClientIPFromCrumb; excludeClientIPFromCrumb != nil {
+		return excludeClientIPFromCrumb
+	}
+	if !IsSecurityProfile(jenkins, virtuslabv1alpha1.SecurityProfileRecommended) {
+		return nil
+	}
+	excludeClientIPFromCrumb := true
+	return &excludeClientIPFromCrumb
+}
+
+// getContentSecurityPolicy returns the policy from Jenkins.Spec.Master.ContentSecurityPolicy or the security profile,
+// returns nil when Jenkins default is kept
+func getContentSecurityPolicy(jenkins *virtuslabv1alpha1.Jenkins) *string {
+	if contentSecurityPolicy := jenkins.Spec.Master.ContentSecurityPolicy; contentSecurityPolicy != nil {
+		return contentSecurityPolicy
+	}
+	contentSecurityPolicy := DefaultContentSecurityPolicy
+	if IsSecurityProfile(jenkins, virtuslabv1alpha1.SecurityProfileStrict) {
+		contentSecurityPolicy = StrictContentSecurityPolicy
+	} else if !IsSecurityProfile(jenkins, virtuslabv1alpha1.SecurityProfileRecommended) {
+		return nil
+	}
+	return &contentSecurityPolicy
+}
+
+// getAgentProtocols returns agent protocols from Jenkins.Spec.Master.Remoting or the security profile,
+// returns nil when only the deprecated protocols are disabled
+func getAgentProtocols(jenkins *virtuslabv1alpha1.Jenkins) []string {
+	if agentProtocols := jenkins.Spec.Master.Remoting.AgentProtocols; len(agentProtocols) > 0 {
+		return agentProtocols
+	}
+	if IsSecurityProfile(jenkins, virtuslabv1alpha1.SecurityProfileStrict) {
+		return strictAgentProtocols
+	}
+	return nil
+}
+
+// getMarkupFormatter returns markup formatter from Jenkins.Spec.Master.MarkupFormatter or the security profile,
+// returns empty string when the formatter configured in Jenkins is kept
+func getMarkupFormatter(jenkins *virtuslabv1alpha1.Jenkins) virtuslabv1alpha1.MarkupFormatter {
+	if formatter := jenkins.Spec.Master.MarkupFormatter; len(formatter) > 0 {
+		return formatter
+	}
+	if IsSecurityProfile(jenkins, virtuslabv1alpha1.SecurityProfileRecommended) {
+		return virtuslabv1alpha1.MarkupFormatterPlainText
+	}
+	return ""
+}
+
+const configureContentSecurityPolicyFmt = `
+// the property is read on every request, so it's applied without restart
+System.setProperty('%s', '%s')
+`
+
+// buildConfigureContentSecurityPolicyGroovyScript renders groovy script which sets Content-Security-Policy header
+// of workspace files and artifacts from Jenkins.Spec.Master.ContentSecurityPolicy or the security profile
+func buildConfigureContentSecurityPolicyGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	contentSecurityPolicy := getContentSecurityPolicy(jenkins)
+	if contentSecurityPolicy == nil {
+		return ""
+	}
+	return fmt.Sprintf(configureContentSecurityPolicyFmt, contentSecurityPolicyProperty, escapeGroovyString(*contentSecurityPolicy))
+}
+
+const configureResourceRootURLFmt = `
+import jenkins.security.ResourceDomainConfiguration
+
+def configuration = ResourceDomainConfiguration.get()
+configuration.setUrl('%s')
+configuration.save()
+`
+
+// buildConfigureResourceRootURLGroovyScript renders groovy script which sets Jenkins resource root URL
+// from Jenkins.Spec.Master.ResourceRootURL
+func buildConfigureResourceRootURLGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if len(jenkins.Spec.Master.ResourceRootURL) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(configureResourceRootURLFmt, escapeGroovyString(jenkins.Spec.Master.ResourceRootURL))
+}
@@ -0,0 +1,92 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityProfile(t *testing.T) {
+	profileSpec := func(profile virtuslabv1alpha1.SecurityProfile) virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{
+			Security: virtuslabv1alpha1.Security{Profile: profile},
+		}
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		jenkins := newJenkins(profileSpec(""))
+
+		assert.Nil(t, getExcludeClientIPFromCrumb(jenkins))
+		assert.Nil(t, getContentSecurityPolicy(jenkins))
+		assert.Nil(t, getAgentProtocols(jenkins))
+		assert.Equal(t, virtuslabv1alpha1.MarkupFormatter(""), getMarkupFormatter(jenkins))
+		assert.Equal(t, "", buildConfigureContentSecurityPolicyGroovyScript(jenkins))
+		assert.True(t, IsScriptConsoleEnabled(jenkins))
+	})
+	t.Run("none", func(t *testing.T) {
+		jenkins := newJenkins(profileSpec(virtuslabv1alpha1.SecurityProfileNone))
+
+		assert.Nil(t, getExcludeClientIPFromCrumb(jenkins))
+		assert.Nil(t, getContentSecurityPolicy(jenkins))
+		assert.Nil(t, getAgentProtocols(jenkins))
+		assert.Equal(t, virtuslabv1alpha1.MarkupFormatter(""), getMarkupFormatter(jenkins))
+	})
+	t.Run("recommended", func(t *testing.T) {
+		jenkins := newJenkins(profileSpec(virtuslabv1alpha1.SecurityProfileRecommended))
+
+		assert.True(t, *getExcludeClientIPFromCrumb(jenkins))
+		assert.Equal(t, DefaultContentSecurityPolicy, *getContentSecurityPolicy(jenkins))
+		assert.True(t, IsScriptConsoleEnabled(jenkins))
+		assert.Nil(t, getAgentProtocols(jenkins))
+		assert.Equal(t, virtuslabv1alpha1.MarkupFormatterPlainText, getMarkupFormatter(jenkins))
+		assert.Contains(t, buildConfigureContentSecurityPolicyGroovyScript(jenkins),
+			"System.setProperty('hudson.model.DirectoryBrowserSupport.CSP', 'sandbox; default-src \\'none\\'; img-src \\'self\\'; style-src \\'self\\';')")
+	})
+	t.Run("strict", func(t *testing.T) {
+		jenkins := newJenkins(profileSpec(virtuslabv1alpha1.SecurityProfileStrict))
+
+		assert.True(t, *getExcludeClientIPFromCrumb(jenkins))
+		assert.Equal(t, StrictContentSecurityPolicy, *getContentSecurityPolicy(jenkins))
+		assert.Equal(t, []string{"JNLP4-connect", "Ping"}, getAgentProtocols(jenkins))
+		assert.Equal(t, virtuslabv1alpha1.MarkupFormatterPlainText, getMarkupFormatter(jenkins))
+		assert.False(t, IsScriptConsoleEnabled(jenkins))
+	})
+	t.Run("explicit settings take precedence", func(t *testing.T) {
+		jenkins := newJenkins(profileSpec(virtuslabv1alpha1.SecurityProfileStrict))
+		excludeClientIPFromCrumb := false
+		contentSecurityPolicy := ""
+		jenkins.Spec.Master.CSRF.ExcludeClientIPFromCrumb = &excludeClientIPFromCrumb
+		jenkins.Spec.Master.ContentSecurityPolicy = &contentSecurityPolicy
+		jenkins.Spec.Master.Remoting.AgentProtocols = []string{"JNLP4-connect"}
+		jenkins.Spec.Master.MarkupFormatter = virtuslabv1alpha1.MarkupFormatterSafeHTML
+		scriptConsoleEnabled := true
+		jenkins.Spec.Security.ScriptConsoleEnabled = &scriptConsoleEnabled
+
+		assert.False(t, *getExcludeClientIPFromCrumb(jenkins))
+		assert.Equal(t, "", *getContentSecurityPolicy(jenkins))
+		assert.Equal(t, []string{"JNLP4-connect"}, getAgentProtocols(jenkins))
+		assert.Equal(t, virtuslabv1alpha1.MarkupFormatterSafeHTML, getMarkupFormatter(jenkins))
+		assert.True(t, IsScriptConsoleEnabled(jenkins))
+		assert.Contains(t, buildConfigureContentSecurityPolicyGroovyScript(jenkins),
+			"System.setProperty('hudson.model.DirectoryBrowserSupport.CSP', '')")
+	})
+}
+
+func TestBuildConfigureResourceRootURLGroovyScript(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		assert.Equal(t, "", buildConfigureResourceRootURLGroovyScript(&virtuslabv1alpha1.Jenkins{}))
+	})
+	t.Run("resource root URL", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Master: virtuslabv1alpha1.JenkinsMaster{ResourceRootURL: "https://jenkins-resources.example.com/"},
+			},
+		}
+
+		script := buildConfigureResourceRootURLGroovyScript(jenkins)
+
+		assert.Contains(t, script, "configuration.setUrl('https://jenkins-resources.example.com/')")
+	})
+}
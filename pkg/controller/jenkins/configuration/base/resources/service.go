@@ -1,6 +1,8 @@
 package resources
 
 import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -13,37 +15,136 @@ func buildServiceTypeMeta() metav1.TypeMeta {
 	}
 }
 
+const (
+	// default Service port names follow '<protocol>[-<suffix>]' convention of service meshes, appProtocol field
+	// isn't available in the supported Kubernetes API version
+	defaultHTTPServicePortName          = "http"
+	defaultHTTPSServicePortName         = "https"
+	defaultAgentListenerServicePortName = "tcp-jnlp"
+	defaultSSHDServicePortName          = "tcp-sshd"
+)
+
+// GetServicePortNames returns names of Jenkins Service ports from Jenkins.Spec.Service.PortNames with defaults
+func GetServicePortNames(jenkins *virtuslabv1alpha1.Jenkins) virtuslabv1alpha1.ServicePortNames {
+	portNames := jenkins.Spec.Service.PortNames
+	if len(portNames.HTTP) == 0 {
+		portNames.HTTP = defaultHTTPServicePortName
+	}
+	if len(portNames.HTTPS) == 0 {
+		portNames.HTTPS = defaultHTTPSServicePortName
+	}
+	if len(portNames.AgentListener) == 0 {
+		portNames.AgentListener = defaultAgentListenerServicePortName
+	}
+	if len(portNames.SSHD) == 0 {
+		portNames.SSHD = defaultSSHDServicePortName
+	}
+	return portNames
+}
+
+// GetServiceType returns type of Jenkins master Service from Jenkins.Spec.Service.Type, ClusterIP by default
+func GetServiceType(jenkins *virtuslabv1alpha1.Jenkins, minikube bool) corev1.ServiceType {
+	serviceType := jenkins.Spec.Service.Type
+	if len(serviceType) == 0 {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
+	if minikube && serviceType == corev1.ServiceTypeClusterIP {
+		// When running locally with minikube cluster Jenkins Service have to be exposed via node port
+		// to allow communication operator -> Jenkins API
+		serviceType = corev1.ServiceTypeNodePort
+	}
+	return serviceType
+}
+
+// buildSessionAffinity returns session affinity of Jenkins master Service from Jenkins.Spec.Service.SessionAffinity,
+// the timeout is set explicitly because Kubernetes API server defaults it
+func buildSessionAffinity(jenkins *virtuslabv1alpha1.Jenkins) (corev1.ServiceAffinity, *corev1.SessionAffinityConfig) {
+	if jenkins.Spec.Service.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return corev1.ServiceAffinityNone, nil
+	}
+
+	timeoutSeconds := jenkins.Spec.Service.SessionAffinityTimeoutSeconds
+	if timeoutSeconds == 0 {
+		timeoutSeconds = corev1.DefaultClientIPServiceAffinitySeconds
+	}
+	return corev1.ServiceAffinityClientIP, &corev1.SessionAffinityConfig{
+		ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeoutSeconds},
+	}
+}
+
 // NewService builds the Kubernetes service resource
-func NewService(meta metav1.ObjectMeta, minikube bool) *corev1.Service {
+func NewService(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins, minikube bool) *corev1.Service {
+	serviceSpec := jenkins.Spec.Service
+	portNames := GetServicePortNames(jenkins)
+	meta.Annotations = map[string]string{}
+	for key, value := range serviceSpec.Annotations {
+		meta.Annotations[key] = value
+	}
+	// external-dns publishes the Ingress host, so the hostname is moved to the Ingress when it's enabled
+	applyExternalDNSAnnotations(meta.Annotations, jenkins, !IsIngressEnabled(jenkins))
+	applyInternalLoadBalancerAnnotations(meta.Annotations, jenkins)
+
 	service := &corev1.Service{
 		TypeMeta:   buildServiceTypeMeta(),
 		ObjectMeta: meta,
 		Spec: corev1.ServiceSpec{
+			Type:     GetServiceType(jenkins, minikube),
 			Selector: meta.Labels,
 			// The first port have to be Jenkins http port because when run with minikube
 			// command 'minikube service' returns endpoints in the same sequence
 			Ports: []corev1.ServicePort{
 				{
-					Name:       httpPortName,
+					Name:       portNames.HTTP,
 					Port:       httpPortInt32,
 					TargetPort: intstr.FromInt(HTTPPortInt),
-				},
-				{
-					Name:       slavePortName,
-					Port:       slavePortInt32,
-					TargetPort: intstr.FromInt(slavePortInt),
+					NodePort:   serviceSpec.NodePort,
 				},
 			},
 		},
 	}
 
-	if minikube {
-		// When running locally with minikube cluster Jenkins Service have to be exposed via node port
-		// to allow communication operator -> Jenkins API
-		service.Spec.Type = corev1.ServiceTypeNodePort
-	} else {
-		service.Spec.Type = corev1.ServiceTypeClusterIP
+	if IsAgentListenerExposed(jenkins) {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:       portNames.AgentListener,
+			Port:       slavePortInt32,
+			TargetPort: intstr.FromInt(slavePortInt),
+			NodePort:   serviceSpec.AgentListenerNodePort,
+		})
+	}
+
+	service.Spec.SessionAffinity, service.Spec.SessionAffinityConfig = buildSessionAffinity(jenkins)
+
+	if IsTLSEnabled(jenkins) {
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:       portNames.HTTPS,
+			Port:       httpsPortInt32,
+			TargetPort: intstr.FromInt(HTTPSPortInt),
+			NodePort:   serviceSpec.HTTPSNodePort,
+		})
+	}
+
+	switch service.Spec.Type {
+	case corev1.ServiceTypeClusterIP:
+		// node ports can't be set for ClusterIP Service
+		for i := range service.Spec.Ports {
+			service.Spec.Ports[i].NodePort = 0
+		}
+	case corev1.ServiceTypeLoadBalancer:
+		service.Spec.LoadBalancerIP = serviceSpec.LoadBalancerIP
+		service.Spec.LoadBalancerSourceRanges = serviceSpec.LoadBalancerSourceRanges
+	}
+	if service.Spec.Type != corev1.ServiceTypeClusterIP {
+		service.Spec.ExternalTrafficPolicy = getServiceExternalTrafficPolicy(jenkins)
 	}
 
 	return service
 }
+
+// getServiceExternalTrafficPolicy returns external traffic policy of NodePort and LoadBalancer Jenkins master Service,
+// Cluster by default like Kubernetes API server defaults it
+func getServiceExternalTrafficPolicy(jenkins *virtuslabv1alpha1.Jenkins) corev1.ServiceExternalTrafficPolicyType {
+	if policy := jenkins.Spec.Service.ExternalTrafficPolicy; len(policy) > 0 {
+		return policy
+	}
+	return corev1.ServiceExternalTrafficPolicyTypeCluster
+}
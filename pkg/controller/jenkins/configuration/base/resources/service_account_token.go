@@ -0,0 +1,95 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// MinServiceAccountTokenExpirationSeconds is the minimal lifetime of projected service account token accepted by Kubernetes
+	MinServiceAccountTokenExpirationSeconds = int64(600)
+	// DefaultServiceAccountTokenExpirationSeconds is the lifetime of projected service account token used when not set
+	DefaultServiceAccountTokenExpirationSeconds = int64(3600)
+
+	serviceAccountTokenVolumeName = "service-account-token"
+	// the token replaces the legacy one, so Kubernetes plugin ServiceAccountCredential and Vault plugin read it
+	// from the default location
+	serviceAccountTokenVolumePath = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// kubeRootCAConfigMapName is the ConfigMap with Kubernetes API server CA published in every namespace
+	kubeRootCAConfigMapName = "kube-root-ca.crt"
+
+	// kubernetesClientsCacheExpirationProperty defines how long Kubernetes plugin reuses the client created with a token
+	kubernetesClientsCacheExpirationProperty = "org.csanchez.jenkins.plugins.kubernetes.clients.cacheExpiration"
+)
+
+// GetServiceAccountTokenExpirationSeconds returns lifetime of Jenkins master pod projected service account token
+func GetServiceAccountTokenExpirationSeconds(jenkins *virtuslabv1alpha1.Jenkins) int64 {
+	serviceAccountToken := jenkins.Spec.Master.ServiceAccountToken
+	if serviceAccountToken == nil || serviceAccountToken.ExpirationSeconds == nil {
+		return DefaultServiceAccountTokenExpirationSeconds
+	}
+	return *serviceAccountToken.ExpirationSeconds
+}
+
+// buildServiceAccountTokenJavaOpts returns JVM options which make Kubernetes plugin recreate its clients before
+// the projected token read by them expires, kubelet refreshes the token when 80% of its lifetime passes,
+// so the clients are recreated after 10% of the lifetime
+func buildServiceAccountTokenJavaOpts(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if jenkins.Spec.Master.ServiceAccountToken == nil {
+		return ""
+	}
+	return fmt.Sprintf("-D%s=%d", kubernetesClientsCacheExpirationProperty, GetServiceAccountTokenExpirationSeconds(jenkins)/10)
+}
+
+// applyServiceAccountToken replaces automatically mounted service account token of Jenkins master pod with projected
+// token from Jenkins.Spec.Master.ServiceAccountToken
+func applyServiceAccountToken(pod *corev1.Pod, jenkins *virtuslabv1alpha1.Jenkins) {
+	serviceAccountToken := jenkins.Spec.Master.ServiceAccountToken
+	if serviceAccountToken == nil {
+		return
+	}
+
+	automountServiceAccountToken := false
+	expirationSeconds := GetServiceAccountTokenExpirationSeconds(jenkins)
+	pod.Spec.AutomountServiceAccountToken = &automountServiceAccountToken
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: serviceAccountTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          serviceAccountToken.Audience,
+							ExpirationSeconds: &expirationSeconds,
+							Path:              "token",
+						},
+					},
+					{
+						ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: kubeRootCAConfigMapName},
+							Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+						},
+					},
+					{
+						DownwardAPI: &corev1.DownwardAPIProjection{
+							Items: []corev1.DownwardAPIVolumeFile{
+								{
+									Path:     "namespace",
+									FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.namespace"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      serviceAccountTokenVolumeName,
+		MountPath: serviceAccountTokenVolumePath,
+		ReadOnly:  true,
+	})
+}
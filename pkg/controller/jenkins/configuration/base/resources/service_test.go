@@ -0,0 +1,202 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNewService(t *testing.T) {
+	serviceSpec := func(service virtuslabv1alpha1.JenkinsService) virtuslabv1alpha1.JenkinsSpec {
+		return virtuslabv1alpha1.JenkinsSpec{Service: service}
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+
+		assert.Equal(t, corev1.ServiceTypeClusterIP, service.Spec.Type)
+		assert.Equal(t, "http", service.Spec.Ports[0].Name)
+		assert.Equal(t, "tcp-jnlp", service.Spec.Ports[1].Name)
+		assert.Equal(t, corev1.ServiceAffinityNone, service.Spec.SessionAffinity)
+		assert.Nil(t, service.Spec.SessionAffinityConfig)
+		assert.Empty(t, service.Annotations)
+		assert.Len(t, service.Spec.Ports, 2)
+	})
+	t.Run("minikube", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{NodePort: 30080}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, true)
+
+		assert.Equal(t, corev1.ServiceTypeNodePort, service.Spec.Type)
+		assert.Equal(t, int32(30080), service.Spec.Ports[0].NodePort)
+	})
+	t.Run("load balancer", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{
+			Type:                  corev1.ServiceTypeLoadBalancer,
+			NodePort:              30080,
+			HTTPSNodePort:         30443,
+			AgentListenerNodePort: 30500,
+			LoadBalancerIP:        "10.0.0.10",
+			Annotations:           map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+		}))
+		jenkins.Spec.Master.TLS = &virtuslabv1alpha1.MasterTLS{SecretName: "jenkins-tls"}
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+
+		assert.Equal(t, corev1.ServiceTypeLoadBalancer, service.Spec.Type)
+		assert.Equal(t, "10.0.0.10", service.Spec.LoadBalancerIP)
+		assert.Equal(t, map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}, service.Annotations)
+		assert.Equal(t, int32(30080), service.Spec.Ports[0].NodePort)
+		assert.Equal(t, int32(30500), service.Spec.Ports[1].NodePort)
+		assert.Equal(t, int32(30443), service.Spec.Ports[2].NodePort)
+		assert.Equal(t, corev1.ServiceExternalTrafficPolicyTypeCluster, service.Spec.ExternalTrafficPolicy)
+	})
+	t.Run("external traffic policy", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{
+			Type:                  corev1.ServiceTypeLoadBalancer,
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+		}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+		clusterIPService := NewService(NewResourceObjectMeta(jenkins), newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{})), false)
+
+		assert.Equal(t, corev1.ServiceExternalTrafficPolicyTypeLocal, service.Spec.ExternalTrafficPolicy)
+		assert.Empty(t, clusterIPService.Spec.ExternalTrafficPolicy)
+	})
+	t.Run("external-dns", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{
+			Type:        corev1.ServiceTypeLoadBalancer,
+			ExternalDNS: &virtuslabv1alpha1.ExternalDNS{Hostname: "jenkins.example.com", TTL: 60},
+		}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+
+		assert.Equal(t, map[string]string{
+			ExternalDNSHostnameAnnotationKey: "jenkins.example.com",
+			ExternalDNSTTLAnnotationKey:      "60",
+		}, service.Annotations)
+	})
+	t.Run("external-dns with Ingress", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{
+			Ingress:     &virtuslabv1alpha1.Ingress{Host: "jenkins.example.com"},
+			ExternalDNS: &virtuslabv1alpha1.ExternalDNS{Hostname: "ci.example.com", TTL: 60},
+		}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+		ingress := NewIngress(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, map[string]string{ExternalDNSTTLAnnotationKey: "60"}, service.Annotations)
+		assert.Equal(t, "ci.example.com", ingress.Annotations[ExternalDNSHostnameAnnotationKey])
+		assert.Equal(t, "60", ingress.Annotations[ExternalDNSTTLAnnotationKey])
+	})
+	t.Run("port names", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{
+			PortNames: virtuslabv1alpha1.ServicePortNames{HTTP: "http-web", HTTPS: "https-web", AgentListener: "tcp-agents"},
+		}))
+		jenkins.Spec.Master.TLS = &virtuslabv1alpha1.MasterTLS{SecretName: "jenkins-tls"}
+		jenkins.Spec.Master.Remoting.SSHD = &virtuslabv1alpha1.SSHD{ServiceEnabled: true}
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+		sshdService := NewSSHDService(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "http-web", service.Spec.Ports[0].Name)
+		assert.Equal(t, "tcp-agents", service.Spec.Ports[1].Name)
+		assert.Equal(t, "https-web", service.Spec.Ports[2].Name)
+		assert.Equal(t, "tcp-sshd", sshdService.Spec.Ports[0].Name)
+	})
+	t.Run("session affinity", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{SessionAffinity: corev1.ServiceAffinityClientIP}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+
+		assert.Equal(t, corev1.ServiceAffinityClientIP, service.Spec.SessionAffinity)
+		assert.Equal(t, corev1.DefaultClientIPServiceAffinitySeconds, *service.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds)
+	})
+	t.Run("WebSocket agents", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{}))
+		jenkins.Spec.Master.Remoting.WebSocket = true
+		jenkins.Spec.Master.TLS = &virtuslabv1alpha1.MasterTLS{SecretName: "jenkins-tls"}
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+		networkPolicy := NewNetworkPolicy(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Len(t, service.Spec.Ports, 2)
+		assert.Equal(t, httpPortName, service.Spec.Ports[0].Name)
+		assert.Equal(t, httpsPortName, service.Spec.Ports[1].Name)
+		assert.Len(t, networkPolicy.Spec.Ingress[1].Ports, 1)
+		assert.Contains(t, buildConfigureKubernetesPluginGroovyScript(jenkins), "kubernetes.setWebSocket(true)")
+	})
+	t.Run("agent Service", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{Agent: &virtuslabv1alpha1.AgentService{
+			Type:           corev1.ServiceTypeLoadBalancer,
+			NodePort:       30500,
+			LoadBalancerIP: "10.0.0.11",
+			Annotations:    map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+		}}))
+
+		service := NewAgentService(NewResourceObjectMeta(jenkins), jenkins)
+
+		assert.Equal(t, "jenkins-operator-agent-jenkins-cr-name", service.Name)
+		assert.Equal(t, corev1.ServiceTypeLoadBalancer, service.Spec.Type)
+		assert.Equal(t, "10.0.0.11", service.Spec.LoadBalancerIP)
+		assert.Equal(t, map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"}, service.Annotations)
+		assert.Len(t, service.Spec.Ports, 1)
+		assert.Equal(t, int32(50000), service.Spec.Ports[0].Port)
+		assert.Equal(t, int32(30500), service.Spec.Ports[0].NodePort)
+	})
+	t.Run("headless Service", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{Headless: &virtuslabv1alpha1.HeadlessService{PublishNotReadyAddresses: true}}))
+		jenkins.Spec.Master.Remoting.WebSocket = true
+
+		service := NewHeadlessService(NewResourceObjectMeta(jenkins), jenkins)
+		pod := NewJenkinsMasterPod(NewResourceObjectMeta(jenkins), jenkins, nil)
+
+		assert.Equal(t, "jenkins-operator-headless-jenkins-cr-name", service.Name)
+		assert.Equal(t, corev1.ClusterIPNone, service.Spec.ClusterIP)
+		assert.True(t, service.Spec.PublishNotReadyAddresses)
+		assert.Len(t, service.Spec.Ports, 2)
+		assert.Equal(t, "jenkins-operator-jenkins-cr-name", pod.Spec.Hostname)
+		assert.Equal(t, service.Name, pod.Spec.Subdomain)
+	})
+	t.Run("internal load balancer", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{
+			Type:                     corev1.ServiceTypeLoadBalancer,
+			InternalLoadBalancer:     virtuslabv1alpha1.InternalLoadBalancerProviderGCP,
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+			Annotations:              map[string]string{"networking.gke.io/internal-load-balancer-allow-global-access": "true"},
+		}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+
+		assert.True(t, IsInternalLoadBalancerEnabled(jenkins))
+		assert.Equal(t, map[string]string{
+			GCPLoadBalancerTypeAnnotationKey:                               "Internal",
+			"networking.gke.io/internal-load-balancer-allow-global-access": "true",
+		}, service.Annotations)
+		assert.Equal(t, []string{"10.0.0.0/8"}, service.Spec.LoadBalancerSourceRanges)
+	})
+	t.Run("internal load balancer annotation override", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{
+			Type:                 corev1.ServiceTypeLoadBalancer,
+			InternalLoadBalancer: virtuslabv1alpha1.InternalLoadBalancerProviderAWS,
+			Annotations:          map[string]string{AWSInternalLoadBalancerAnnotationKey: "0.0.0.0/0"},
+		}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+
+		assert.Equal(t, map[string]string{AWSInternalLoadBalancerAnnotationKey: "0.0.0.0/0"}, service.Annotations)
+	})
+	t.Run("ClusterIP ignores node ports", func(t *testing.T) {
+		jenkins := newJenkins(serviceSpec(virtuslabv1alpha1.JenkinsService{NodePort: 30080, LoadBalancerIP: "10.0.0.10"}))
+
+		service := NewService(NewResourceObjectMeta(jenkins), jenkins, false)
+
+		assert.Zero(t, service.Spec.Ports[0].NodePort)
+		assert.Empty(t, service.Spec.LoadBalancerIP)
+	})
+}
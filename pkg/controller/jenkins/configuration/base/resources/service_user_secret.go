@@ -0,0 +1,40 @@
+package resources
+
+import (
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ServiceUserSecretUserNameKey defines key of username in service user secret
+	ServiceUserSecretUserNameKey = "user"
+	// ServiceUserSecretTokenKey defines key of API token in service user secret
+	ServiceUserSecretTokenKey = "token"
+	// ServiceUserSecretTokenCreationKey defines key of API token creation time in service user secret
+	ServiceUserSecretTokenCreationKey = "tokenCreationTime"
+)
+
+// NewServiceUserSecret builds the Kubernetes secret used to publish API token of Jenkins service user
+func NewServiceUserSecret(meta metav1.ObjectMeta, serviceUser virtuslabv1alpha1.ServiceUser, token string) *corev1.Secret {
+	meta.Name = serviceUser.SecretName
+	now, _ := time.Now().UTC().MarshalText()
+	return &corev1.Secret{
+		TypeMeta:   buildSecretTypeMeta(),
+		ObjectMeta: meta,
+		Data: map[string][]byte{
+			ServiceUserSecretUserNameKey:      []byte(serviceUser.Name),
+			ServiceUserSecretTokenKey:         []byte(token),
+			ServiceUserSecretTokenCreationKey: now,
+		},
+	}
+}
+
+// NewServiceUserPassword returns random password of the service user account in Jenkins own user database,
+// the password isn't published, the account is used only with API token
+func NewServiceUserPassword() string {
+	return randomString(20)
+}
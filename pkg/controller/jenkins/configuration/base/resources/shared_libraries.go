@@ -0,0 +1,105 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const defaultSharedLibraryVersion = "master"
+
+// buildSharedLibrariesConfigurationAsCode renders configuration as code file with global and folder-scoped
+// Pipeline shared libraries, folder-scoped libraries are configured by Job DSL script
+func buildSharedLibrariesConfigurationAsCode(jenkins *virtuslabv1alpha1.Jenkins) string {
+	var globalLibraries []interface{}
+	folderLibraries := map[string][]virtuslabv1alpha1.SharedLibrary{}
+	for _, library := range jenkins.Spec.Configuration.SharedLibraries {
+		if len(library.Folder) == 0 {
+			globalLibraries = append(globalLibraries, buildSharedLibraryConfiguration(library))
+		} else {
+			folderLibraries[library.Folder] = append(folderLibraries[library.Folder], library)
+		}
+	}
+
+	configuration := map[string]interface{}{}
+	if len(globalLibraries) > 0 {
+		configuration["unclassified"] = map[string]interface{}{
+			"globalLibraries": map[string]interface{}{
+				"libraries": globalLibraries,
+			},
+		}
+	}
+
+	var folders []string
+	for folder := range folderLibraries {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+	var jobs []interface{}
+	for _, folder := range folders {
+		jobs = append(jobs, map[string]string{"script": buildFolderLibrariesJobDSL(folder, folderLibraries[folder])})
+	}
+	if len(jobs) > 0 {
+		configuration["jobs"] = jobs
+	}
+
+	if len(configuration) == 0 {
+		return ""
+	}
+
+	// JSON is valid YAML, marshalling maps of strings and bools never fails
+	output, _ := json.MarshalIndent(configuration, "", "  ")
+	return string(output)
+}
+
+func buildSharedLibraryConfiguration(library virtuslabv1alpha1.SharedLibrary) map[string]interface{} {
+	git := map[string]interface{}{"remote": library.RepositoryURL}
+	if len(library.CredentialsID) > 0 {
+		git["credentialsId"] = library.CredentialsID
+	}
+
+	return map[string]interface{}{
+		"name":                 library.Name,
+		"defaultVersion":       getSharedLibraryVersion(library),
+		"implicit":             library.Implicit,
+		"allowVersionOverride": library.AllowVersionOverride,
+		"retriever": map[string]interface{}{
+			"modernSCM": map[string]interface{}{
+				"scm": map[string]interface{}{
+					"git": git,
+				},
+			},
+		},
+	}
+}
+
+func buildFolderLibrariesJobDSL(folder string, libraries []virtuslabv1alpha1.SharedLibrary) string {
+	var script strings.Builder
+	fmt.Fprintf(&script, "folder('%s') {\n", escapeGroovyString(folder))
+	script.WriteString("  properties {\n    folderLibraries {\n      libraries {\n")
+	for _, library := range libraries {
+		script.WriteString("        libraryConfiguration {\n")
+		fmt.Fprintf(&script, "          name('%s')\n", escapeGroovyString(library.Name))
+		fmt.Fprintf(&script, "          defaultVersion('%s')\n", escapeGroovyString(getSharedLibraryVersion(library)))
+		fmt.Fprintf(&script, "          implicit(%t)\n", library.Implicit)
+		fmt.Fprintf(&script, "          allowVersionOverride(%t)\n", library.AllowVersionOverride)
+		script.WriteString("          retriever {\n            modernSCM {\n              scm {\n                git {\n")
+		fmt.Fprintf(&script, "                  remote('%s')\n", escapeGroovyString(library.RepositoryURL))
+		if len(library.CredentialsID) > 0 {
+			fmt.Fprintf(&script, "                  credentialsId('%s')\n", escapeGroovyString(library.CredentialsID))
+		}
+		script.WriteString("                }\n              }\n            }\n          }\n        }\n")
+	}
+	script.WriteString("      }\n    }\n  }\n}\n")
+	return script.String()
+}
+
+func getSharedLibraryVersion(library virtuslabv1alpha1.SharedLibrary) string {
+	if len(library.DefaultVersion) > 0 {
+		return library.DefaultVersion
+	}
+	return defaultSharedLibraryVersion
+}
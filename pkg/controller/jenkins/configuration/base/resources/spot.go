@@ -0,0 +1,175 @@
+package resources
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AWSSpotNodeLabelKey is the label of EKS managed nodes with capacity type
+	AWSSpotNodeLabelKey = "eks.amazonaws.com/capacityType"
+	// GCPSpotNodeLabelKey is the label and taint of GKE Spot VMs
+	GCPSpotNodeLabelKey = "cloud.google.com/gke-spot"
+	// AzureSpotNodeLabelKey is the label and taint of AKS node pools with priority
+	AzureSpotNodeLabelKey = "kubernetes.azure.com/scalesetpriority"
+)
+
+// spotNodeSelectors contains node selector of spot nodes per cloud provider
+var spotNodeSelectors = map[virtuslabv1alpha1.SpotProvider]map[string]string{
+	virtuslabv1alpha1.SpotProviderAWS:   {AWSSpotNodeLabelKey: "SPOT"},
+	virtuslabv1alpha1.SpotProviderGCP:   {GCPSpotNodeLabelKey: "true"},
+	virtuslabv1alpha1.SpotProviderAzure: {AzureSpotNodeLabelKey: "spot"},
+}
+
+// spotTolerations contains tolerations of spot node taints per cloud provider, EKS doesn't taint spot nodes
+var spotTolerations = map[virtuslabv1alpha1.SpotProvider][]corev1.Toleration{
+	virtuslabv1alpha1.SpotProviderGCP: {
+		{Key: GCPSpotNodeLabelKey, Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule},
+	},
+	virtuslabv1alpha1.SpotProviderAzure: {
+		{Key: AzureSpotNodeLabelKey, Operator: corev1.TolerationOpEqual, Value: "spot", Effect: corev1.TaintEffectNoSchedule},
+	},
+}
+
+// applySpotNodes adds node selector and tolerations of AgentPodTemplate.Spot to the agent pod, the explicit node selector
+// takes precedence over the cloud provider one
+func applySpotNodes(pod *corev1.Pod, spot *virtuslabv1alpha1.SpotNodes) {
+	if spot == nil {
+		return
+	}
+
+	for _, nodeSelector := range []map[string]string{spotNodeSelectors[spot.Provider], spot.NodeSelector} {
+		for key, value := range nodeSelector {
+			if pod.Spec.NodeSelector == nil {
+				pod.Spec.NodeSelector = map[string]string{}
+			}
+			pod.Spec.NodeSelector[key] = value
+		}
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, spotTolerations[spot.Provider]...)
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, spot.Tolerations...)
+}
+
+const configureSpotAgentsRetryFmt = `
+import hudson.ExtensionList
+import hudson.model.Action
+import hudson.model.Cause
+import hudson.model.CauseAction
+import hudson.model.Computer
+import hudson.model.ParametersAction
+import hudson.model.Result
+import hudson.model.Run
+import hudson.model.TaskListener
+import hudson.model.listeners.RunListener
+import hudson.slaves.ComputerListener
+import hudson.slaves.OfflineCause
+import jenkins.model.Jenkins
+
+import java.util.concurrent.ConcurrentHashMap
+
+// records builds running on removed spot agents, Kubernetes plugin names agent pods '<template name>-<random suffix>'
+class SpotComputerListener extends ComputerListener {
+    Map<String, Integer> retriesByAgentPattern
+    Map<String, Integer> interruptedRuns
+
+    void onOffline(Computer computer, OfflineCause cause) {
+        def retries = retriesByAgentPattern.find { pattern, retries -> computer.name ==~ pattern }?.value
+        if (retries == null) {
+            return
+        }
+        computer.executors.each { executor ->
+            def executable = executor.currentExecutable
+            def run = null
+            try {
+                // pipelines run node blocks as placeholder tasks of the build
+                run = executable instanceof Run ? executable : executable?.parent?.run()
+            } catch (Exception ignored) {
+            }
+            if (run instanceof Run) {
+                interruptedRuns.put(run.externalizableId, retries)
+            }
+        }
+    }
+}
+
+// schedules builds which failed after their spot agent was removed again, retries are builds caused by the previous
+// build of the same job
+class SpotRunListener extends RunListener<Run> {
+    Map<String, Integer> interruptedRuns
+
+    SpotRunListener() {
+        super(Run)
+    }
+
+    void onCompleted(Run run, TaskListener listener) {
+        def retries = interruptedRuns.remove(run.externalizableId)
+        if (retries == null || run.result == Result.SUCCESS || run.result == Result.NOT_BUILT) {
+            return
+        }
+
+        def job = run.parent
+        def attempt = 0
+        def cause = run.getCause(Cause.UpstreamCause)
+        while (cause != null && cause.upstreamProject == job.fullName && attempt < retries) {
+            attempt++
+            cause = cause.upstreamRun?.getCause(Cause.UpstreamCause)
+        }
+        if (attempt >= retries) {
+            return
+        }
+
+        listener.logger.println("Spot agent of the build was removed, scheduling retry ${attempt + 1} of ${retries}")
+        List<Action> actions = [new CauseAction(new Cause.UpstreamCause(run))]
+        def parameters = run.getAction(ParametersAction)
+        if (parameters != null) {
+            actions.add(parameters)
+        }
+        job.scheduleBuild2(0, actions as Action[])
+    }
+}
+
+// the listeners added by the previous run are replaced, builds interrupted meanwhile are kept
+def context = Jenkins.getInstance().servletContext
+def listenersAttribute = 'jenkins-operator.spotAgentsRetryListeners'
+def interruptedRuns = new ConcurrentHashMap<String, Integer>()
+def currentListeners = context.getAttribute(listenersAttribute)
+if (currentListeners != null) {
+    ExtensionList.lookup(ComputerListener).remove(currentListeners[0])
+    ExtensionList.lookup(RunListener).remove(currentListeners[1])
+    interruptedRuns.putAll(currentListeners[1].interruptedRuns)
+    context.removeAttribute(listenersAttribute)
+}
+
+def retriesByAgentPattern = %s
+if (!retriesByAgentPattern.isEmpty()) {
+    def computerListener = new SpotComputerListener(retriesByAgentPattern: retriesByAgentPattern, interruptedRuns: interruptedRuns)
+    def runListener = new SpotRunListener(interruptedRuns: interruptedRuns)
+    ExtensionList.lookup(ComputerListener).add(computerListener)
+    ExtensionList.lookup(RunListener).add(runListener)
+    context.setAttribute(listenersAttribute, [computerListener, runListener])
+}
+`
+
+// buildConfigureSpotAgentsRetryGroovyScript renders groovy script which retries builds of agent pod templates with
+// AgentPodTemplate.Spot.Retries when their agent is removed, the script is rendered even without such templates
+// to remove the listeners
+func buildConfigureSpotAgentsRetryGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	var entries []string
+	for _, template := range jenkins.Spec.Agents.Templates {
+		if template.Spot == nil || template.Spot.Retries <= 0 {
+			continue
+		}
+		// template names are DNS-1123 labels, so the pattern doesn't match pods of the templates with longer names
+		pattern := fmt.Sprintf("%s-[a-z0-9]+", regexp.QuoteMeta(template.Name))
+		entries = append(entries, fmt.Sprintf("'%s': %d", escapeGroovyString(pattern), template.Spot.Retries))
+	}
+	if len(entries) == 0 {
+		return fmt.Sprintf(configureSpotAgentsRetryFmt, "[:]")
+	}
+	return fmt.Sprintf(configureSpotAgentsRetryFmt, fmt.Sprintf("[%s]", strings.Join(entries, ", ")))
+}
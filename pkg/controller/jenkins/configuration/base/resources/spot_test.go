@@ -0,0 +1,31 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildConfigureSpotAgentsRetryGroovyScript(t *testing.T) {
+	t.Run("retries", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Templates = []virtuslabv1alpha1.AgentPodTemplate{
+			{Name: "maven", Spot: &virtuslabv1alpha1.SpotNodes{Provider: virtuslabv1alpha1.SpotProviderAWS, Retries: 2}},
+			{Name: "gradle", Spot: &virtuslabv1alpha1.SpotNodes{Provider: virtuslabv1alpha1.SpotProviderAWS}},
+			{Name: "node"},
+		}
+
+		script := buildConfigureSpotAgentsRetryGroovyScript(jenkins)
+
+		assert.Contains(t, script, "def retriesByAgentPattern = ['maven-[a-z0-9]+': 2]\n")
+	})
+	t.Run("no retries", func(t *testing.T) {
+		script := buildConfigureSpotAgentsRetryGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		// listeners of the previous configuration are still removed
+		assert.Contains(t, script, "def retriesByAgentPattern = [:]\n")
+		assert.Contains(t, script, "ExtensionList.lookup(RunListener).remove(currentListeners[1])")
+	})
+}
@@ -0,0 +1,85 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// DefaultSSHDPort is Jenkins SSH server port used when Jenkins.Spec.Master.Remoting.SSHD.Port isn't set
+	DefaultSSHDPort = int32(50022)
+
+	sshdPortName = "sshd"
+)
+
+// IsSSHDEnabled tells if Jenkins built-in SSH server is enabled
+func IsSSHDEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Master.Remoting.SSHD != nil
+}
+
+// GetSSHDPort returns Jenkins SSH server port, -1 disables the server
+func GetSSHDPort(jenkins *virtuslabv1alpha1.Jenkins) int32 {
+	if !IsSSHDEnabled(jenkins) {
+		return -1
+	}
+	if port := jenkins.Spec.Master.Remoting.SSHD.Port; port != 0 {
+		return port
+	}
+	return DefaultSSHDPort
+}
+
+// IsReservedPort tells if the port is already used by Jenkins master container
+func IsReservedPort(port int32) bool {
+	return port == httpPortInt32 || port == httpsPortInt32 || port == slavePortInt32
+}
+
+// IsSSHDServiceEnabled tells if the dedicated Service exposing Jenkins SSH server should exist
+func IsSSHDServiceEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return IsSSHDEnabled(jenkins) && jenkins.Spec.Master.Remoting.SSHD.ServiceEnabled
+}
+
+// GetSSHDServiceName returns name of the Service exposing Jenkins SSH server
+func GetSSHDServiceName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s-sshd-%s", constants.OperatorName, jenkins.ObjectMeta.Name)
+}
+
+// NewSSHDService builds the Kubernetes service resource exposing Jenkins SSH server
+func NewSSHDService(meta metav1.ObjectMeta, jenkins *virtuslabv1alpha1.Jenkins) *corev1.Service {
+	selector := meta.Labels
+	meta.Name = GetSSHDServiceName(jenkins)
+	port := GetSSHDPort(jenkins)
+
+	return &corev1.Service{
+		TypeMeta:   buildServiceTypeMeta(),
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: selector,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       GetServicePortNames(jenkins).SSHD,
+					Port:       port,
+					TargetPort: intstr.FromString(sshdPortName),
+				},
+			},
+		},
+	}
+}
+
+// buildSSHDContainerPort returns Jenkins master container port of the SSH server, returns nil when it's disabled
+func buildSSHDContainerPort(jenkins *virtuslabv1alpha1.Jenkins) *corev1.ContainerPort {
+	if !IsSSHDEnabled(jenkins) {
+		return nil
+	}
+
+	return &corev1.ContainerPort{
+		Name:          sshdPortName,
+		ContainerPort: GetSSHDPort(jenkins),
+	}
+}
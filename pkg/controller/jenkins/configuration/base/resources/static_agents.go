@@ -0,0 +1,161 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SSHAgentsPluginName is the name of plugin required by Jenkins.Spec.Agents.Static
+	SSHAgentsPluginName = "ssh-slaves"
+	// StaticAgentUserNameSecretKey is the static agent Secret key with SSH user name
+	StaticAgentUserNameSecretKey = "username"
+	// StaticAgentPrivateKeySecretKey is the static agent Secret key with SSH private key
+	StaticAgentPrivateKeySecretKey = "privateKey"
+
+	defaultStaticAgentPort      = 22
+	defaultStaticAgentExecutors = 1
+	defaultStaticAgentWorkDir   = "/home/jenkins/agent"
+
+	// staticAgentDescription marks the nodes managed by the operator, the other nodes are left untouched
+	staticAgentDescription = "Static agent managed by jenkins-operator"
+)
+
+// getStaticAgentEnvNamePrefix returns prefix of Jenkins master container environment variables with the static agent
+// credentials, agent names are DNS-1123 labels so they only need upper case and underscores
+func getStaticAgentEnvNamePrefix(agent virtuslabv1alpha1.StaticAgent) string {
+	return fmt.Sprintf("JENKINS_STATIC_AGENT_%s_", strings.ToUpper(strings.Replace(agent.Name, "-", "_", -1)))
+}
+
+// buildStaticAgentsEnvVars returns Jenkins master container environment variables with SSH credentials of
+// Jenkins.Spec.Agents.Static referenced from the Secrets
+func buildStaticAgentsEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+	for _, agent := range jenkins.Spec.Agents.Static {
+		prefix := getStaticAgentEnvNamePrefix(agent)
+		envs = append(envs,
+			buildSecretKeyEnvVar(prefix+"USERNAME", agent.CredentialsSecretName, StaticAgentUserNameSecretKey),
+			buildSecretKeyEnvVar(prefix+"PRIVATE_KEY", agent.CredentialsSecretName, StaticAgentPrivateKeySecretKey))
+	}
+	return envs
+}
+
+var configureStaticAgentsTemplate = template.Must(template.New("configure-static-agents").Parse(`
+import com.cloudbees.plugins.credentials.CredentialsScope
+import com.cloudbees.plugins.credentials.SystemCredentialsProvider
+import com.cloudbees.plugins.credentials.domains.Domain
+import hudson.model.Node
+import hudson.slaves.DumbSlave
+import hudson.slaves.RetentionStrategy
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+def env = System.getenv()
+def description = '{{ .Description }}'
+def agents = [{{ range .Agents }}
+        [name: '{{ .Name }}', host: '{{ .Host }}', port: {{ .Port }}, hostKey: '{{ .HostKey }}', labels: '{{ .Labels }}',
+         executors: {{ .Executors }}, workDir: '{{ .WorkDir }}', credentialsId: 'static-agent-{{ .Name }}',
+         userNameEnv: '{{ .EnvNamePrefix }}USERNAME', privateKeyEnv: '{{ .EnvNamePrefix }}PRIVATE_KEY'],{{ end }}
+]
+
+if (!agents.isEmpty()) {
+    // SSH plugin classes are loaded dynamically because the plugin is optional
+    def classLoader = jenkins.pluginManager.uberClassLoader
+    def store = SystemCredentialsProvider.getInstance().getStore()
+    agents.each { agent ->
+        def privateKeySource = classLoader.loadClass('com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey$DirectEntryPrivateKeySource')
+                .newInstance(env[agent.privateKeyEnv])
+        def credentials = classLoader.loadClass('com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey').newInstance(
+                CredentialsScope.SYSTEM, agent.credentialsId, env[agent.userNameEnv], privateKeySource, null, description)
+        def currentCredentials = store.getCredentials(Domain.global()).find { it.id == agent.credentialsId }
+        if (currentCredentials == null) {
+            store.addCredentials(Domain.global(), credentials)
+        } else {
+            store.updateCredentials(Domain.global(), currentCredentials, credentials)
+        }
+
+        def verificationStrategy = agent.hostKey.isEmpty() ?
+                classLoader.loadClass('hudson.plugins.sshslaves.verifiers.ManuallyTrustedKeyVerificationStrategy').newInstance(false) :
+                classLoader.loadClass('hudson.plugins.sshslaves.verifiers.ManuallyProvidedKeyVerificationStrategy').newInstance(agent.hostKey)
+        def launcher = classLoader.loadClass('hudson.plugins.sshslaves.SSHLauncher').newInstance(
+                agent.host, agent.port, agent.credentialsId, null, null, null, null, 60, 10, 15, verificationStrategy)
+
+        def node = new DumbSlave(agent.name, agent.workDir, launcher)
+        node.setNodeDescription(description)
+        node.setNumExecutors(agent.executors)
+        node.setLabelString(agent.labels)
+        node.setMode(Node.Mode.NORMAL)
+        node.setRetentionStrategy(RetentionStrategy.INSTANCE)
+        // replaces the node with the same name
+        jenkins.addNode(node)
+    }
+}
+
+// nodes of removed static agents are deleted
+def agentNames = agents.collect { it.name }
+jenkins.nodes.findAll { it.nodeDescription == description && !agentNames.contains(it.nodeName) }.each { node ->
+    jenkins.removeNode(node)
+    def store = SystemCredentialsProvider.getInstance().getStore()
+    def credentials = store.getCredentials(Domain.global()).find { it.id == "static-agent-${node.nodeName}".toString() }
+    if (credentials != null) {
+        store.removeCredentials(Domain.global(), credentials)
+    }
+}
+`))
+
+// buildConfigureStaticAgentsGroovyScript renders groovy script which registers Jenkins.Spec.Agents.Static as permanent
+// SSH nodes, the script is rendered even without the agents to remove nodes of the deleted ones
+func buildConfigureStaticAgentsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	type agentData struct {
+		Name          string
+		Host          string
+		Port          int
+		HostKey       string
+		Labels        string
+		Executors     int
+		WorkDir       string
+		EnvNamePrefix string
+	}
+	var agents []agentData
+	for _, agent := range jenkins.Spec.Agents.Static {
+		port := agent.Port
+		if port == 0 {
+			port = defaultStaticAgentPort
+		}
+		executors := agent.Executors
+		if executors == 0 {
+			executors = defaultStaticAgentExecutors
+		}
+		workDir := agent.WorkDir
+		if len(workDir) == 0 {
+			workDir = defaultStaticAgentWorkDir
+		}
+		agents = append(agents, agentData{
+			Name:          escapeGroovyString(agent.Name),
+			Host:          escapeGroovyString(agent.Host),
+			Port:          port,
+			HostKey:       escapeGroovyString(agent.HostKey),
+			Labels:        escapeGroovyString(strings.Join(agent.Labels, " ")),
+			Executors:     executors,
+			WorkDir:       escapeGroovyString(workDir),
+			EnvNamePrefix: getStaticAgentEnvNamePrefix(agent),
+		})
+	}
+
+	data := struct {
+		Description string
+		Agents      []agentData
+	}{
+		Description: staticAgentDescription,
+		Agents:      agents,
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureStaticAgentsTemplate, data)
+	return output
+}
@@ -0,0 +1,47 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildConfigureStaticAgentsGroovyScript(t *testing.T) {
+	t.Run("agents", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Static = []virtuslabv1alpha1.StaticAgent{
+			{Name: "mac-mini", Host: "10.0.0.5", CredentialsSecretName: "mac-ssh", Labels: []string{"macos", "xcode"}},
+			{Name: "gpu", Host: "gpu.example.com", Port: 2222, CredentialsSecretName: "gpu-ssh", Executors: 4, WorkDir: "/data/jenkins",
+				HostKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIB"},
+		}
+
+		script := buildConfigureStaticAgentsGroovyScript(jenkins)
+
+		assert.Contains(t, script, `[name: 'mac-mini', host: '10.0.0.5', port: 22, hostKey: '', labels: 'macos xcode',
+         executors: 1, workDir: '/home/jenkins/agent', credentialsId: 'static-agent-mac-mini',
+         userNameEnv: 'JENKINS_STATIC_AGENT_MAC_MINI_USERNAME', privateKeyEnv: 'JENKINS_STATIC_AGENT_MAC_MINI_PRIVATE_KEY'],`)
+		assert.Contains(t, script, `[name: 'gpu', host: 'gpu.example.com', port: 2222, hostKey: 'ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIB', labels: '',
+         executors: 4, workDir: '/data/jenkins'`)
+	})
+	t.Run("no agents", func(t *testing.T) {
+		script := buildConfigureStaticAgentsGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		// nodes of the removed agents are still deleted
+		assert.Contains(t, script, "def agents = [\n]")
+		assert.Contains(t, script, "jenkins.removeNode(node)")
+	})
+	t.Run("env vars", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.Static = []virtuslabv1alpha1.StaticAgent{{Name: "mac-mini", CredentialsSecretName: "mac-ssh"}}
+
+		envs := buildStaticAgentsEnvVars(jenkins)
+
+		assert.Len(t, envs, 2)
+		assert.Equal(t, "JENKINS_STATIC_AGENT_MAC_MINI_USERNAME", envs[0].Name)
+		assert.Equal(t, "mac-ssh", envs[0].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, StaticAgentUserNameSecretKey, envs[0].ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, StaticAgentPrivateKeySecretKey, envs[1].ValueFrom.SecretKeyRef.Key)
+	})
+}
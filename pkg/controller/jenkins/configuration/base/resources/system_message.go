@@ -0,0 +1,24 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const configureSystemMessageFmt = `
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+jenkins.setSystemMessage('%s')
+jenkins.save()
+`
+
+// buildConfigureSystemMessageGroovyScript renders groovy script which sets Jenkins system message
+// from Jenkins.Spec.Master.SystemMessage
+func buildConfigureSystemMessageGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if len(jenkins.Spec.Master.SystemMessage) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(configureSystemMessageFmt, escapeGroovyString(jenkins.Spec.Master.SystemMessage))
+}
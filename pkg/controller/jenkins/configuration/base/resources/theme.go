@@ -0,0 +1,85 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+const (
+	// loginDisclaimerCSSFmt displays the login disclaimer above the Jenkins login form
+	loginDisclaimerCSSFmt = `form[name="login"]::before { content: "%s"; display: block; white-space: pre-wrap; font-weight: bold; margin-bottom: 1em; }`
+	// displayNameCSSFmt replaces Jenkins logo text in the page header with the instance name
+	displayNameCSSFmt = `#jenkins-name-icon { display: none; } #jenkins-home-link::after { content: "%s"; color: #fff; font-size: 20px; font-weight: bold; vertical-align: middle; margin-left: 0.5em; }`
+	// logoCSSFmt replaces Jenkins logo in the page header with the image
+	logoCSSFmt = `#jenkins-head-icon { content: url("%s"); max-height: 40px; }`
+)
+
+var configureThemeTemplate = template.Must(template.New("configure-theme").Parse(`
+import jenkins.model.Jenkins
+import org.codefirst.SimpleThemeDecorator
+import org.jenkinsci.plugins.simpletheme.CssTextThemeElement
+import org.jenkinsci.plugins.simpletheme.CssUrlThemeElement
+import org.jenkinsci.plugins.simpletheme.FaviconUrlThemeElement
+import org.jenkinsci.plugins.simpletheme.JsUrlThemeElement
+
+def decorator = Jenkins.instance.getDescriptorByType(SimpleThemeDecorator.class)
+decorator.setElements([
+{{- range .Elements }}
+        {{ . }},
+{{- end }}
+])
+decorator.save()
+`))
+
+// escapeCSSString escapes value used in double quoted CSS string
+func escapeCSSString(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\A `).Replace(value)
+}
+
+// buildThemeElements returns groovy expressions creating simple-theme-plugin elements
+func buildThemeElements(jenkins *virtuslabv1alpha1.Jenkins) []string {
+	var elements []string
+	addElement := func(elementClass, value string) {
+		elements = append(elements, fmt.Sprintf("new %s('%s')", elementClass, escapeGroovyString(value)))
+	}
+
+	theme := jenkins.Spec.Master.Theme
+	if len(theme.CSSURL) > 0 {
+		addElement("CssUrlThemeElement", theme.CSSURL)
+	}
+	if len(theme.JSURL) > 0 {
+		addElement("JsUrlThemeElement", theme.JSURL)
+	}
+	if len(theme.FaviconURL) > 0 {
+		addElement("FaviconUrlThemeElement", theme.FaviconURL)
+	}
+	if len(theme.LogoURL) > 0 {
+		addElement("CssTextThemeElement", fmt.Sprintf(logoCSSFmt, escapeCSSString(theme.LogoURL)))
+	}
+	if len(theme.DisplayName) > 0 {
+		addElement("CssTextThemeElement", fmt.Sprintf(displayNameCSSFmt, escapeCSSString(theme.DisplayName)))
+	}
+	if len(theme.CSS) > 0 {
+		addElement("CssTextThemeElement", theme.CSS)
+	}
+	if len(jenkins.Spec.Master.LoginDisclaimer) > 0 {
+		addElement("CssTextThemeElement", fmt.Sprintf(loginDisclaimerCSSFmt, escapeCSSString(jenkins.Spec.Master.LoginDisclaimer)))
+	}
+
+	return elements
+}
+
+// buildConfigureThemeGroovyScript renders groovy script which replaces simple-theme-plugin elements
+func buildConfigureThemeGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	elements := buildThemeElements(jenkins)
+	if len(elements) == 0 {
+		return ""
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureThemeTemplate, struct{ Elements []string }{Elements: elements})
+	return output
+}
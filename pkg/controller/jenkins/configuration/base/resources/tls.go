@@ -0,0 +1,135 @@
+package resources
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TLSCertificateSecretKey is the Jenkins master TLS Secret key with PEM encoded certificate
+	TLSCertificateSecretKey = corev1.TLSCertKey
+	// TLSPrivateKeySecretKey is the Jenkins master TLS Secret key with PEM encoded private key
+	TLSPrivateKeySecretKey = corev1.TLSPrivateKeyKey
+	// TLSCASecretKey is the CA Secret key with PEM encoded certificates used to verify Jenkins master certificate
+	TLSCASecretKey = "ca.crt"
+	// DefaultHTTPSKeystoreSecretKey is the Jenkins master keystore Secret key used when
+	// Jenkins.Spec.Master.HTTPSKeystore.KeystoreKey isn't set
+	DefaultHTTPSKeystoreSecretKey = "keystore.jks"
+	// DefaultHTTPSKeystorePasswordSecretKey is the Jenkins master keystore Secret key with the keystore password used
+	// when Jenkins.Spec.Master.HTTPSKeystore.PasswordKey isn't set
+	DefaultHTTPSKeystorePasswordSecretKey = "password"
+
+	tlsVolumeName   = "tls"
+	tlsVolumePath   = "/var/jenkins/tls"
+	jenkinsOptsName = "JENKINS_OPTS"
+	// httpsKeystorePasswordEnvName is referenced by JENKINS_OPTS, so the password isn't rendered into the pod spec
+	httpsKeystorePasswordEnvName = "JENKINS_HTTPS_KEYSTORE_PASSWORD"
+	httpsKeystoreFileName        = "keystore"
+)
+
+// IsTLSEnabled tells if Jenkins master serves HTTPS used by the operator, with PEM certificate or Java keystore
+func IsTLSEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Master.TLS != nil || IsHTTPSKeystoreEnabled(jenkins)
+}
+
+// IsHTTPSKeystoreEnabled tells if Jenkins master serves HTTPS with the certificate from Java keystore
+func IsHTTPSKeystoreEnabled(jenkins *virtuslabv1alpha1.Jenkins) bool {
+	return jenkins.Spec.Master.HTTPSKeystore != nil
+}
+
+// GetHTTPSKeystoreSecretKey returns the Jenkins master keystore Secret key with the keystore
+func GetHTTPSKeystoreSecretKey(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if key := jenkins.Spec.Master.HTTPSKeystore.KeystoreKey; len(key) > 0 {
+		return key
+	}
+	return DefaultHTTPSKeystoreSecretKey
+}
+
+// GetHTTPSKeystorePasswordSecretKey returns the Jenkins master keystore Secret key with the keystore password
+func GetHTTPSKeystorePasswordSecretKey(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if key := jenkins.Spec.Master.HTTPSKeystore.PasswordKey; len(key) > 0 {
+		return key
+	}
+	return DefaultHTTPSKeystorePasswordSecretKey
+}
+
+// GetTLSSecretName returns name of the Secret with Jenkins master certificate or keystore, returns empty string when
+// TLS isn't enabled
+func GetTLSSecretName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if IsHTTPSKeystoreEnabled(jenkins) {
+		return jenkins.Spec.Master.HTTPSKeystore.SecretName
+	}
+	if jenkins.Spec.Master.TLS != nil {
+		return jenkins.Spec.Master.TLS.SecretName
+	}
+	return ""
+}
+
+// GetJenkinsMasterServiceDNSName returns DNS name of Jenkins master Service which has to be present
+// in Jenkins master certificate
+func GetJenkinsMasterServiceDNSName(jenkins *virtuslabv1alpha1.Jenkins) string {
+	return fmt.Sprintf("%s.%s.svc", GetResourceName(jenkins), jenkins.ObjectMeta.Namespace)
+}
+
+// buildTLSJenkinsOpts returns Winstone options which enable HTTPS listener with the certificate mounted from
+// the Secret, returns empty string when TLS isn't enabled
+func buildTLSJenkinsOpts(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if !IsTLSEnabled(jenkins) {
+		return ""
+	}
+	if IsHTTPSKeystoreEnabled(jenkins) {
+		// Kubernetes expands the variable defined before JENKINS_OPTS
+		return fmt.Sprintf("--httpsPort=%d --httpsKeyStore=%s/%s --httpsKeyStorePassword=$(%s)",
+			HTTPSPortInt, tlsVolumePath, httpsKeystoreFileName, httpsKeystorePasswordEnvName)
+	}
+
+	return fmt.Sprintf("--httpsPort=%d --httpsCertificate=%s/%s --httpsPrivateKey=%s/%s",
+		HTTPSPortInt, tlsVolumePath, TLSCertificateSecretKey, tlsVolumePath, TLSPrivateKeySecretKey)
+}
+
+// buildHTTPSKeystoreEnvVars returns Jenkins master container environment variable with the keystore password
+// referenced from the Secret, returns nil when the keystore isn't used
+func buildHTTPSKeystoreEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	if !IsHTTPSKeystoreEnabled(jenkins) {
+		return nil
+	}
+
+	return []corev1.EnvVar{
+		buildSecretKeyEnvVar(httpsKeystorePasswordEnvName, jenkins.Spec.Master.HTTPSKeystore.SecretName,
+			GetHTTPSKeystorePasswordSecretKey(jenkins)),
+	}
+}
+
+// buildTLSVolume returns volume and its mount with Jenkins master certificate and private key or the keystore,
+// returns nils when TLS isn't enabled
+func buildTLSVolume(jenkins *virtuslabv1alpha1.Jenkins) (*corev1.Volume, *corev1.VolumeMount) {
+	if !IsTLSEnabled(jenkins) {
+		return nil, nil
+	}
+
+	items := []corev1.KeyToPath{
+		{Key: TLSCertificateSecretKey, Path: TLSCertificateSecretKey},
+		{Key: TLSPrivateKeySecretKey, Path: TLSPrivateKeySecretKey},
+	}
+	if IsHTTPSKeystoreEnabled(jenkins) {
+		items = []corev1.KeyToPath{{Key: GetHTTPSKeystoreSecretKey(jenkins), Path: httpsKeystoreFileName}}
+	}
+	volume := &corev1.Volume{
+		Name: tlsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: GetTLSSecretName(jenkins),
+				Items:      items,
+			},
+		},
+	}
+	mount := &corev1.VolumeMount{
+		Name:      tlsVolumeName,
+		MountPath: tlsVolumePath,
+		ReadOnly:  true,
+	}
+	return volume, mount
+}
@@ -0,0 +1,92 @@
+package resources
+
+import (
+	"fmt"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+)
+
+// toolInstallation defines Jenkins classes used to configure the tool type
+type toolInstallation struct {
+	installationClass string
+	installerFmt      string
+}
+
+// toolInstallations maps tool types to Jenkins installation classes, installerFmt renders installer
+// constructor arguments with the tool version
+var toolInstallations = map[virtuslabv1alpha1.ToolType]toolInstallation{
+	virtuslabv1alpha1.ToolTypeJDK: {
+		installationClass: "hudson.model.JDK",
+		installerFmt:      "classLoader.loadClass('hudson.tools.JDKInstaller').newInstance('%s', true)",
+	},
+	virtuslabv1alpha1.ToolTypeMaven: {
+		installationClass: "hudson.tasks.Maven$MavenInstallation",
+		installerFmt:      "classLoader.loadClass('hudson.tasks.Maven$MavenInstaller').newInstance('%s')",
+	},
+	virtuslabv1alpha1.ToolTypeGradle: {
+		installationClass: "hudson.plugins.gradle.GradleInstallation",
+		installerFmt:      "classLoader.loadClass('hudson.plugins.gradle.GradleInstaller').newInstance('%s')",
+	},
+	virtuslabv1alpha1.ToolTypeNodeJS: {
+		installationClass: "jenkins.plugins.nodejs.tools.NodeJSInstallation",
+		installerFmt:      "classLoader.loadClass('jenkins.plugins.nodejs.tools.NodeJSInstaller').newInstance('%s', '', 72L)",
+	},
+}
+
+var configureToolsTemplate = template.Must(template.New("configure-tools").Parse(`
+import hudson.tools.InstallSourceProperty
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+// installation classes are loaded dynamically because some of them are provided by optional plugins
+def classLoader = jenkins.pluginManager.uberClassLoader
+
+def installations = [:]
+{{- range .Tools }}
+installations.get('{{ .InstallationClass }}', []) << classLoader.loadClass('{{ .InstallationClass }}').newInstance(
+        '{{ .Name }}', '{{ .Home }}', [{{ if .Installer }}new InstallSourceProperty([{{ .Installer }}]){{ end }}])
+{{- end }}
+
+installations.each { installationClassName, tools ->
+    def installationClass = classLoader.loadClass(installationClassName)
+    def descriptor = jenkins.getDescriptorByType(classLoader.loadClass(installationClassName + '$DescriptorImpl'))
+    descriptor.setInstallations(tools.toArray(java.lang.reflect.Array.newInstance(installationClass, 0)))
+    descriptor.save()
+}
+`))
+
+// buildConfigureToolsGroovyScript renders groovy script which configures tools from Jenkins.Spec.Master.Tools
+// in Jenkins global tool configuration, tool types not present in the spec aren't changed
+func buildConfigureToolsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if len(jenkins.Spec.Master.Tools) == 0 {
+		return ""
+	}
+
+	type tool struct {
+		InstallationClass string
+		Name              string
+		Home              string
+		Installer         string
+	}
+	var tools []tool
+	for _, t := range jenkins.Spec.Master.Tools {
+		installation, ok := toolInstallations[t.Type]
+		if !ok {
+			continue // rejected by validation
+		}
+		configuredTool := tool{
+			InstallationClass: installation.installationClass,
+			Name:              escapeGroovyString(t.Name),
+			Home:              escapeGroovyString(t.Home),
+		}
+		if len(t.Version) > 0 {
+			configuredTool.Installer = fmt.Sprintf(installation.installerFmt, escapeGroovyString(t.Version))
+		}
+		tools = append(tools, configuredTool)
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureToolsTemplate, struct{ Tools interface{} }{Tools: tools})
+	return output
+}
@@ -0,0 +1,172 @@
+package resources
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// VaultPluginName is the name of plugin required by Jenkins.Spec.Vault
+	VaultPluginName = "hashicorp-vault-plugin"
+	// VaultTokenSecretKey is the Vault Secret key with token used by token auth method
+	VaultTokenSecretKey = "token"
+	// VaultRoleIDSecretKey is the Vault Secret key with role ID used by appRole auth method
+	VaultRoleIDSecretKey = "roleId"
+	// VaultSecretIDSecretKey is the Vault Secret key with secret ID used by appRole auth method
+	VaultSecretIDSecretKey = "secretId"
+
+	defaultVaultEngineVersion = 2
+	vaultCredentialsID        = "jenkins-operator-vault"
+
+	// environment variables read by configuration as code plugin to resolve secrets from Vault,
+	// the configure-vault script reads them as well so the Vault credentials aren't stored in ConfigMaps
+	vaultURLEnvName           = "CASC_VAULT_URL"
+	vaultPathsEnvName         = "CASC_VAULT_PATHS"
+	vaultEngineVersionEnvName = "CASC_VAULT_ENGINE_VERSION"
+	vaultTokenEnvName         = "CASC_VAULT_TOKEN"
+	vaultAppRoleEnvName       = "CASC_VAULT_APPROLE"
+	vaultAppRoleSecretEnvName = "CASC_VAULT_APPROLE_SECRET"
+	vaultKubernetesRoleName   = "CASC_VAULT_KUBERNETES_ROLE"
+)
+
+// RequiredVaultSecretKeys returns keys of Vault Secret required by the auth method
+func RequiredVaultSecretKeys(authMethod virtuslabv1alpha1.VaultAuthMethod) []string {
+	switch authMethod {
+	case virtuslabv1alpha1.VaultAuthMethodToken:
+		return []string{VaultTokenSecretKey}
+	case virtuslabv1alpha1.VaultAuthMethodAppRole:
+		return []string{VaultRoleIDSecretKey, VaultSecretIDSecretKey}
+	default:
+		return nil
+	}
+}
+
+func getVaultEngineVersion(vault *virtuslabv1alpha1.Vault) int {
+	if vault.EngineVersion > 0 {
+		return vault.EngineVersion
+	}
+	return defaultVaultEngineVersion
+}
+
+func buildSecretKeyEnvVar(name, secretName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// buildVaultEnvVars returns Jenkins master container environment variables with Vault configuration,
+// the Vault credentials are referenced from the Secret so they are never rendered into the pod spec
+func buildVaultEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	vault := jenkins.Spec.Vault
+	if vault == nil {
+		return nil
+	}
+
+	envs := []corev1.EnvVar{
+		{Name: vaultURLEnvName, Value: vault.Address},
+		{Name: vaultEngineVersionEnvName, Value: strconv.Itoa(getVaultEngineVersion(vault))},
+	}
+	if len(vault.Paths) > 0 {
+		envs = append(envs, corev1.EnvVar{Name: vaultPathsEnvName, Value: strings.Join(vault.Paths, ",")})
+	}
+
+	switch vault.AuthMethod {
+	case virtuslabv1alpha1.VaultAuthMethodToken:
+		envs = append(envs, buildSecretKeyEnvVar(vaultTokenEnvName, vault.SecretName, VaultTokenSecretKey))
+	case virtuslabv1alpha1.VaultAuthMethodAppRole:
+		envs = append(envs,
+			buildSecretKeyEnvVar(vaultAppRoleEnvName, vault.SecretName, VaultRoleIDSecretKey),
+			buildSecretKeyEnvVar(vaultAppRoleSecretEnvName, vault.SecretName, VaultSecretIDSecretKey))
+	case virtuslabv1alpha1.VaultAuthMethodKubernetes:
+		envs = append(envs, corev1.EnvVar{Name: vaultKubernetesRoleName, Value: vault.Role})
+	}
+
+	return envs
+}
+
+var configureVaultTemplate = template.Must(template.New("configure-vault").Parse(`
+import com.cloudbees.plugins.credentials.CredentialsScope
+import com.cloudbees.plugins.credentials.SystemCredentialsProvider
+import com.cloudbees.plugins.credentials.domains.Domain
+import hudson.util.Secret
+import jenkins.model.GlobalConfiguration
+import jenkins.model.Jenkins
+
+def jenkins = Jenkins.instance
+// Vault plugin classes are loaded dynamically because the plugin is optional
+def classLoader = jenkins.pluginManager.uberClassLoader
+def env = System.getenv()
+def description = 'Vault credentials managed by jenkins-operator'
+
+{{ if eq .AuthMethod "token" -}}
+def credentials = classLoader.loadClass('com.datapipe.jenkins.vault.credentials.VaultTokenCredential').newInstance(
+        CredentialsScope.GLOBAL, '{{ .CredentialsID }}', description, Secret.fromString(env['{{ .TokenEnv }}']))
+{{- else if eq .AuthMethod "appRole" -}}
+def credentials = classLoader.loadClass('com.datapipe.jenkins.vault.credentials.VaultAppRoleCredential').newInstance(
+        CredentialsScope.GLOBAL, '{{ .CredentialsID }}', description, env['{{ .AppRoleEnv }}'],
+        Secret.fromString(env['{{ .AppRoleSecretEnv }}']), 'approle')
+{{- else -}}
+def credentials = classLoader.loadClass('com.datapipe.jenkins.vault.credentials.VaultKubernetesCredential').newInstance(
+        CredentialsScope.GLOBAL, '{{ .CredentialsID }}', description, env['{{ .KubernetesRoleEnv }}'])
+{{- end }}
+
+def store = SystemCredentialsProvider.getInstance().getStore()
+def existing = store.getCredentials(Domain.global()).find { it.id == '{{ .CredentialsID }}' }
+if (existing) {
+    store.updateCredentials(Domain.global(), existing, credentials)
+} else {
+    store.addCredentials(Domain.global(), credentials)
+}
+
+def configuration = classLoader.loadClass('com.datapipe.jenkins.vault.configuration.VaultConfiguration').newInstance(
+        env['{{ .URLEnv }}'], '{{ .CredentialsID }}')
+configuration.setEngineVersion(Integer.valueOf(env['{{ .EngineVersionEnv }}']))
+
+def globalConfiguration = GlobalConfiguration.all().get(
+        classLoader.loadClass('com.datapipe.jenkins.vault.configuration.GlobalVaultConfiguration'))
+globalConfiguration.setConfiguration(configuration)
+globalConfiguration.save()
+`))
+
+// buildConfigureVaultGroovyScript renders groovy script which configures Vault plugin and its credentials
+// from the Jenkins master container environment variables
+func buildConfigureVaultGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	if jenkins.Spec.Vault == nil {
+		return ""
+	}
+
+	data := struct {
+		AuthMethod        virtuslabv1alpha1.VaultAuthMethod
+		CredentialsID     string
+		URLEnv            string
+		EngineVersionEnv  string
+		TokenEnv          string
+		AppRoleEnv        string
+		AppRoleSecretEnv  string
+		KubernetesRoleEnv string
+	}{
+		AuthMethod:        jenkins.Spec.Vault.AuthMethod,
+		CredentialsID:     vaultCredentialsID,
+		URLEnv:            vaultURLEnvName,
+		EngineVersionEnv:  vaultEngineVersionEnvName,
+		TokenEnv:          vaultTokenEnvName,
+		AppRoleEnv:        vaultAppRoleEnvName,
+		AppRoleSecretEnv:  vaultAppRoleSecretEnvName,
+		KubernetesRoleEnv: vaultKubernetesRoleName,
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureVaultTemplate, data)
+	return output
+}
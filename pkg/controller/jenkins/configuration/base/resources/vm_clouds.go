@@ -0,0 +1,291 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// EC2PluginName is the name of plugin required by ec2 Jenkins.Spec.Agents.VMClouds
+	EC2PluginName = "ec2"
+	// GCEPluginName is the name of plugin required by gce Jenkins.Spec.Agents.VMClouds
+	GCEPluginName = "google-compute-engine"
+
+	// EC2AccessKeyIDSecretKey is the ec2 cloud Secret key with AWS access key ID
+	EC2AccessKeyIDSecretKey = "accessKeyId"
+	// EC2SecretAccessKeySecretKey is the ec2 cloud Secret key with AWS secret access key
+	EC2SecretAccessKeySecretKey = "secretAccessKey"
+	// EC2PrivateKeySecretKey is the ec2 cloud Secret key with private key of EC2 key pair used to connect to instances
+	EC2PrivateKeySecretKey = "privateKey"
+	// GCEServiceAccountKeySecretKey is the gce cloud Secret key with Google service account JSON key
+	GCEServiceAccountKeySecretKey = "serviceAccountKey"
+
+	defaultVMAgentExecutors   = 1
+	defaultVMAgentIdleMinutes = 30
+	defaultEC2RemoteUser      = "ec2-user"
+	defaultGCERemoteUser      = "jenkins"
+
+	// gceAPIProjectsURL is the prefix of Compute Engine resource URLs the plugin expects
+	gceAPIProjectsURL = "https://www.googleapis.com/compute/v1/projects/"
+
+	// vmCloudCredentialsIDPrefix is the prefix of Jenkins credentials of the virtual machine clouds, it marks the clouds
+	// managed by the operator
+	vmCloudCredentialsIDPrefix = "vm-cloud-"
+)
+
+// VMCloudPluginNames contains plugins required by virtual machine cloud providers
+var VMCloudPluginNames = map[virtuslabv1alpha1.VMCloudProvider]string{
+	virtuslabv1alpha1.VMCloudProviderEC2: EC2PluginName,
+	virtuslabv1alpha1.VMCloudProviderGCE: GCEPluginName,
+}
+
+// GetVMCloudSecretKeys returns keys of the credentials Secret required by the virtual machine cloud provider
+func GetVMCloudSecretKeys(provider virtuslabv1alpha1.VMCloudProvider) []string {
+	if provider == virtuslabv1alpha1.VMCloudProviderGCE {
+		return []string{GCEServiceAccountKeySecretKey}
+	}
+	return []string{EC2AccessKeyIDSecretKey, EC2SecretAccessKeySecretKey, EC2PrivateKeySecretKey}
+}
+
+// GetGCEImageProject returns the project of gce template image 'projects/<project>/global/images/<image>'
+func GetGCEImageProject(image string) string {
+	parts := strings.Split(image, "/")
+	if len(parts) < 2 || parts[0] != "projects" {
+		return ""
+	}
+	return parts[1]
+}
+
+// getGCERegion returns the region of the zone, e.g. 'europe-west1' of 'europe-west1-b'
+func getGCERegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
+
+// getVMCloudEnvNamePrefix returns prefix of Jenkins master container environment variables with the cloud credentials,
+// cloud names are DNS-1123 labels so they only need upper case and underscores
+func getVMCloudEnvNamePrefix(cloud virtuslabv1alpha1.VMCloud) string {
+	return fmt.Sprintf("JENKINS_VM_CLOUD_%s_", strings.ToUpper(strings.Replace(cloud.Name, "-", "_", -1)))
+}
+
+// buildVMCloudsEnvVars returns Jenkins master container environment variables with credentials of
+// Jenkins.Spec.Agents.VMClouds referenced from the Secrets
+func buildVMCloudsEnvVars(jenkins *virtuslabv1alpha1.Jenkins) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+	for _, cloud := range jenkins.Spec.Agents.VMClouds {
+		prefix := getVMCloudEnvNamePrefix(cloud)
+		switch cloud.Provider {
+		case virtuslabv1alpha1.VMCloudProviderEC2:
+			envs = append(envs,
+				buildSecretKeyEnvVar(prefix+"ACCESS_KEY_ID", cloud.CredentialsSecretName, EC2AccessKeyIDSecretKey),
+				buildSecretKeyEnvVar(prefix+"SECRET_ACCESS_KEY", cloud.CredentialsSecretName, EC2SecretAccessKeySecretKey),
+				buildSecretKeyEnvVar(prefix+"PRIVATE_KEY", cloud.CredentialsSecretName, EC2PrivateKeySecretKey))
+		case virtuslabv1alpha1.VMCloudProviderGCE:
+			envs = append(envs, buildSecretKeyEnvVar(prefix+"SERVICE_ACCOUNT_KEY", cloud.CredentialsSecretName, GCEServiceAccountKeySecretKey))
+		}
+	}
+	return envs
+}
+
+var configureVMCloudsTemplate = template.Must(template.New("configure-vm-clouds").Parse(`
+import com.cloudbees.plugins.credentials.CredentialsScope
+import com.cloudbees.plugins.credentials.SecretBytes
+import com.cloudbees.plugins.credentials.SystemCredentialsProvider
+import com.cloudbees.plugins.credentials.domains.Domain
+import jenkins.model.Jenkins
+import org.jenkinsci.plugins.structs.describable.DescribableModel
+
+def jenkins = Jenkins.instance
+def env = System.getenv()
+def credentialsIdPrefix = '{{ .CredentialsIDPrefix }}'
+def cloudNames = [{{ range .Clouds }}'{{ .Name }}', {{ end }}]
+def store = SystemCredentialsProvider.getInstance().getStore()
+// cloud plugin classes are loaded dynamically because the plugins are optional, they are instantiated from their
+// data bound constructors and setters like in pipelines
+def classLoader = jenkins.pluginManager.uberClassLoader
+def instantiate = { String className, Map arguments ->
+    new DescribableModel(classLoader.loadClass(className)).instantiate(arguments)
+}
+def updateCredentials = { credentials ->
+    def currentCredentials = store.getCredentials(Domain.global()).find { it.id == credentials.id }
+    if (currentCredentials == null) {
+        store.addCredentials(Domain.global(), credentials)
+    } else {
+        store.updateCredentials(Domain.global(), currentCredentials, credentials)
+    }
+}
+
+// the clouds are recreated, clouds and credentials of removed ones are deleted
+jenkins.clouds.findAll { cloud ->
+    cloud.hasProperty('credentialsId') && cloud.credentialsId?.startsWith(credentialsIdPrefix)
+}.each { cloud ->
+    jenkins.clouds.remove(cloud)
+}
+store.getCredentials(Domain.global()).findAll { credentials ->
+    credentials.id.startsWith(credentialsIdPrefix) &&
+            !cloudNames.any { credentials.id == credentialsIdPrefix + it || credentials.id == credentialsIdPrefix + it + '-ssh' }
+}.each { credentials ->
+    store.removeCredentials(Domain.global(), credentials)
+}
+{{ range .Clouds }}
+jenkins.clouds.remove(jenkins.clouds.getByName('{{ .Name }}'))
+{{ if .EC2 }}updateCredentials(instantiate('com.cloudbees.jenkins.plugins.awscredentials.AWSCredentialsImpl', [
+        scope: CredentialsScope.GLOBAL, id: credentialsIdPrefix + '{{ .Name }}', description: 'AWS credentials of VM cloud {{ .Name }}',
+        accessKey: env['{{ .EnvNamePrefix }}ACCESS_KEY_ID'], secretKey: env['{{ .EnvNamePrefix }}SECRET_ACCESS_KEY']]))
+updateCredentials(classLoader.loadClass('com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey').newInstance(
+        CredentialsScope.SYSTEM, credentialsIdPrefix + '{{ .Name }}-ssh', '{{ .DefaultRemoteUser }}',
+        classLoader.loadClass('com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey$DirectEntryPrivateKeySource')
+                .newInstance(env['{{ .EnvNamePrefix }}PRIVATE_KEY']),
+        null, 'EC2 key pair of VM cloud {{ .Name }}'))
+jenkins.clouds.add(instantiate('hudson.plugins.ec2.AmazonEC2Cloud', [
+        name: '{{ .Name }}', region: '{{ .Region }}', credentialsId: credentialsIdPrefix + '{{ .Name }}',
+        sshKeysCredentialsId: credentialsIdPrefix + '{{ .Name }}-ssh', useInstanceProfileForCredentials: false,
+        instanceCapStr: '{{ .MaxInstances }}', templates: [{{ range .Templates }}
+                [description: '{{ .Name }}', ami: '{{ .Image }}', type: '{{ .MachineType }}', zone: '{{ .Zone }}', subnetId: '{{ .Subnet }}',
+                 labelString: '{{ .Labels }}', mode: 'NORMAL', numExecutors: '{{ .Executors }}', instanceCapStr: '{{ .MaxInstances }}',
+                 idleTerminationMinutes: '{{ .IdleMinutes }}', remoteAdmin: '{{ .RemoteUser }}', remoteFS: '{{ .WorkDir }}'],{{ end }}
+        ]]))
+{{ else }}updateCredentials(instantiate('com.google.jenkins.plugins.credentials.oauth.GoogleRobotPrivateKeyCredentials', [
+        scope: CredentialsScope.GLOBAL, id: credentialsIdPrefix + '{{ .Name }}', projectId: '{{ .Project }}',
+        serviceAccountConfig: instantiate('com.google.jenkins.plugins.credentials.oauth.JsonServiceAccountConfig', [
+                secretJsonKey: SecretBytes.fromBytes(env['{{ .EnvNamePrefix }}SERVICE_ACCOUNT_KEY'].getBytes('UTF-8'))])]))
+jenkins.clouds.add(instantiate('com.google.jenkins.plugins.computeengine.ComputeEngineCloud', [
+        cloudName: '{{ .Name }}', projectId: '{{ .Project }}', credentialsId: credentialsIdPrefix + '{{ .Name }}',
+        instanceCapStr: '{{ .MaxInstances }}', configurations: [{{ range .Templates }}
+                [namePrefix: '{{ .Name }}', description: '{{ .Name }}', region: '{{ .Region }}', zone: '{{ .Zone }}',
+                 machineType: '{{ .MachineType }}', bootDiskSourceImageName: '{{ .Image }}', bootDiskSourceImageProject: '{{ .ImageProject }}',
+                 networkConfiguration: instantiate('com.google.jenkins.plugins.computeengine.AutofilledNetworkConfiguration', [
+                         network: '{{ .Network }}', subnetwork: '{{ .Subnet }}']),
+                 labelString: '{{ .Labels }}', mode: 'NORMAL', numExecutorsStr: '{{ .Executors }}',
+                 retentionTimeMinutesStr: '{{ .IdleMinutes }}', runAsUser: '{{ .RemoteUser }}', remoteFs: '{{ .WorkDir }}'],{{ end }}
+        ]]))
+{{ end }}{{ end }}
+jenkins.save()
+`))
+
+// buildConfigureVMCloudsGroovyScript renders groovy script which configures Jenkins.Spec.Agents.VMClouds with EC2 and
+// Google Compute Engine plugins, the script is rendered even without the clouds to remove the deleted ones
+func buildConfigureVMCloudsGroovyScript(jenkins *virtuslabv1alpha1.Jenkins) string {
+	type templateData struct {
+		Name         string
+		Image        string
+		ImageProject string
+		MachineType  string
+		Region       string
+		Zone         string
+		Network      string
+		Subnet       string
+		Labels       string
+		Executors    int
+		MaxInstances string
+		IdleMinutes  int
+		RemoteUser   string
+		WorkDir      string
+	}
+	type cloudData struct {
+		Name              string
+		EC2               bool
+		Region            string
+		Project           string
+		MaxInstances      string
+		DefaultRemoteUser string
+		EnvNamePrefix     string
+		Templates         []templateData
+	}
+
+	var clouds []cloudData
+	for _, cloud := range jenkins.Spec.Agents.VMClouds {
+		ec2 := cloud.Provider == virtuslabv1alpha1.VMCloudProviderEC2
+		defaultRemoteUser := defaultEC2RemoteUser
+		if !ec2 {
+			defaultRemoteUser = defaultGCERemoteUser
+		}
+
+		var templates []templateData
+		for _, vmTemplate := range cloud.Templates {
+			executors := vmTemplate.Executors
+			if executors == 0 {
+				executors = defaultVMAgentExecutors
+			}
+			idleMinutes := vmTemplate.IdleMinutes
+			if idleMinutes == 0 {
+				idleMinutes = defaultVMAgentIdleMinutes
+			}
+			remoteUser := vmTemplate.RemoteUser
+			if len(remoteUser) == 0 {
+				remoteUser = defaultRemoteUser
+			}
+			workDir := vmTemplate.WorkDir
+			if len(workDir) == 0 {
+				workDir = "/home/" + remoteUser
+			}
+			data := templateData{
+				Name:         escapeGroovyString(vmTemplate.Name),
+				Image:        escapeGroovyString(vmTemplate.Image),
+				MachineType:  escapeGroovyString(vmTemplate.MachineType),
+				Zone:         escapeGroovyString(vmTemplate.Zone),
+				Subnet:       escapeGroovyString(vmTemplate.Subnet),
+				Labels:       escapeGroovyString(strings.Join(vmTemplate.Labels, " ")),
+				Executors:    executors,
+				MaxInstances: getInstanceCap(vmTemplate.MaxInstances),
+				IdleMinutes:  idleMinutes,
+				RemoteUser:   escapeGroovyString(remoteUser),
+				WorkDir:      escapeGroovyString(workDir),
+			}
+			if !ec2 {
+				// Compute Engine plugin refers to the resources by their URLs
+				projectURL := gceAPIProjectsURL + cloud.Project
+				region := getGCERegion(vmTemplate.Zone)
+				subnet := vmTemplate.Subnet
+				if len(subnet) == 0 {
+					subnet = "default"
+				}
+				data.ImageProject = escapeGroovyString(GetGCEImageProject(vmTemplate.Image))
+				data.Region = escapeGroovyString(projectURL + "/regions/" + region)
+				data.Zone = escapeGroovyString(projectURL + "/zones/" + vmTemplate.Zone)
+				data.MachineType = escapeGroovyString(projectURL + "/zones/" + vmTemplate.Zone + "/machineTypes/" + vmTemplate.MachineType)
+				data.Network = escapeGroovyString(projectURL + "/global/networks/default")
+				data.Subnet = escapeGroovyString(projectURL + "/regions/" + region + "/subnetworks/" + subnet)
+			}
+			templates = append(templates, data)
+		}
+
+		clouds = append(clouds, cloudData{
+			Name:              escapeGroovyString(cloud.Name),
+			EC2:               ec2,
+			Region:            escapeGroovyString(cloud.Region),
+			Project:           escapeGroovyString(cloud.Project),
+			MaxInstances:      getInstanceCap(cloud.MaxInstances),
+			DefaultRemoteUser: defaultRemoteUser,
+			EnvNamePrefix:     getVMCloudEnvNamePrefix(cloud),
+			Templates:         templates,
+		})
+	}
+
+	data := struct {
+		CredentialsIDPrefix string
+		Clouds              []cloudData
+	}{
+		CredentialsIDPrefix: vmCloudCredentialsIDPrefix,
+		Clouds:              clouds,
+	}
+
+	// the template doesn't contain any calls which could fail
+	output, _ := render(configureVMCloudsTemplate, data)
+	return output
+}
+
+// getInstanceCap returns instance cap of the cloud plugins, the plugins don't limit instances when it's empty
+func getInstanceCap(maxInstances int) string {
+	if maxInstances <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d", maxInstances)
+}
@@ -0,0 +1,77 @@
+package resources
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildConfigureVMCloudsGroovyScript(t *testing.T) {
+	t.Run("ec2", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.VMClouds = []virtuslabv1alpha1.VMCloud{
+			{Name: "aws", Provider: virtuslabv1alpha1.VMCloudProviderEC2, Region: "eu-west-1", CredentialsSecretName: "aws", MaxInstances: 10,
+				Templates: []virtuslabv1alpha1.VMAgentTemplate{
+					{Name: "windows", Image: "ami-0abcdef1234567890", MachineType: "m5.xlarge", Labels: []string{"windows", "msbuild"},
+						RemoteUser: "Administrator", WorkDir: "C:\\jenkins"},
+				}},
+		}
+
+		script := buildConfigureVMCloudsGroovyScript(jenkins)
+
+		assert.Contains(t, script, "def cloudNames = ['aws', ]")
+		assert.Contains(t, script, "accessKey: env['JENKINS_VM_CLOUD_AWS_ACCESS_KEY_ID']")
+		assert.Contains(t, script, `name: 'aws', region: 'eu-west-1', credentialsId: credentialsIdPrefix + 'aws',`)
+		assert.Contains(t, script, "instanceCapStr: '10', templates: [")
+		assert.Contains(t, script, `[description: 'windows', ami: 'ami-0abcdef1234567890', type: 'm5.xlarge', zone: '', subnetId: '',
+                 labelString: 'windows msbuild', mode: 'NORMAL', numExecutors: '1', instanceCapStr: '',
+                 idleTerminationMinutes: '30', remoteAdmin: 'Administrator', remoteFS: 'C:\\jenkins'],`)
+		assert.NotContains(t, script, "ComputeEngineCloud")
+	})
+	t.Run("gce", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.VMClouds = []virtuslabv1alpha1.VMCloud{
+			{Name: "gcp", Provider: virtuslabv1alpha1.VMCloudProviderGCE, Project: "ci-builds", CredentialsSecretName: "gcp",
+				Templates: []virtuslabv1alpha1.VMAgentTemplate{
+					{Name: "android", Image: "projects/ci-builds/global/images/family/android", MachineType: "n2-standard-8",
+						Zone: "europe-west1-b", Executors: 2, IdleMinutes: 10},
+				}},
+		}
+
+		script := buildConfigureVMCloudsGroovyScript(jenkins)
+
+		projectURL := "https://www.googleapis.com/compute/v1/projects/ci-builds"
+		assert.Contains(t, script, "env['JENKINS_VM_CLOUD_GCP_SERVICE_ACCOUNT_KEY']")
+		assert.Contains(t, script, "cloudName: 'gcp', projectId: 'ci-builds', credentialsId: credentialsIdPrefix + 'gcp',")
+		assert.Contains(t, script, "region: '"+projectURL+"/regions/europe-west1', zone: '"+projectURL+"/zones/europe-west1-b',")
+		assert.Contains(t, script, "machineType: '"+projectURL+"/zones/europe-west1-b/machineTypes/n2-standard-8'")
+		assert.Contains(t, script, "bootDiskSourceImageName: 'projects/ci-builds/global/images/family/android', bootDiskSourceImageProject: 'ci-builds'")
+		assert.Contains(t, script, "subnetwork: '"+projectURL+"/regions/europe-west1/subnetworks/default'")
+		assert.Contains(t, script, "numExecutorsStr: '2',\n                 retentionTimeMinutesStr: '10', runAsUser: 'jenkins', remoteFs: '/home/jenkins']")
+		assert.NotContains(t, script, "AmazonEC2Cloud")
+	})
+	t.Run("no clouds", func(t *testing.T) {
+		script := buildConfigureVMCloudsGroovyScript(&virtuslabv1alpha1.Jenkins{})
+
+		// clouds of the removed ones are still deleted
+		assert.Contains(t, script, "def cloudNames = []")
+		assert.Contains(t, script, "jenkins.clouds.remove(cloud)")
+	})
+	t.Run("env vars", func(t *testing.T) {
+		jenkins := &virtuslabv1alpha1.Jenkins{}
+		jenkins.Spec.Agents.VMClouds = []virtuslabv1alpha1.VMCloud{
+			{Name: "aws-builds", Provider: virtuslabv1alpha1.VMCloudProviderEC2, CredentialsSecretName: "aws"},
+			{Name: "gcp", Provider: virtuslabv1alpha1.VMCloudProviderGCE, CredentialsSecretName: "gcp"},
+		}
+
+		envs := buildVMCloudsEnvVars(jenkins)
+
+		assert.Len(t, envs, 4)
+		assert.Equal(t, "JENKINS_VM_CLOUD_AWS_BUILDS_ACCESS_KEY_ID", envs[0].Name)
+		assert.Equal(t, EC2PrivateKeySecretKey, envs[2].ValueFrom.SecretKeyRef.Key)
+		assert.Equal(t, "gcp", envs[3].ValueFrom.SecretKeyRef.Name)
+		assert.Equal(t, GCEServiceAccountKeySecretKey, envs[3].ValueFrom.SecretKeyRef.Key)
+	})
+}
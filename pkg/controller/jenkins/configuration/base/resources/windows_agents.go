@@ -0,0 +1,55 @@
+package resources
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// OSNodeLabelKey is the node label with the operating system
+	OSNodeLabelKey = "kubernetes.io/os"
+	// OSNodeTaintKey is the taint some cloud providers, e.g. GKE, add to Windows nodes
+	OSNodeTaintKey = "node.kubernetes.io/os"
+
+	// DefaultWindowsAgentImage is the jnlp container image of Windows agent pod templates without image
+	DefaultWindowsAgentImage = "jenkins/inbound-agent:windowsservercore-ltsc2019"
+)
+
+// windowsKeepAliveCommand keeps Windows sidecar containers running, 'cat' with tty used by Linux containers isn't
+// available in Windows images
+var windowsKeepAliveCommand = []string{"powershell", "Start-Sleep", "2147483"}
+
+// applyAgentOS schedules the agent pod on nodes with AgentPodTemplate.OS, Windows pods get the default image, keep
+// alive command of the sidecar containers without command and toleration of the Windows node taint
+func applyAgentOS(pod *corev1.Pod, template virtuslabv1alpha1.AgentPodTemplate) {
+	if len(template.OS) == 0 {
+		return
+	}
+
+	if pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = map[string]string{}
+	}
+	pod.Spec.NodeSelector[OSNodeLabelKey] = string(template.OS)
+	if template.OS != virtuslabv1alpha1.AgentOSWindows {
+		return
+	}
+
+	for i, container := range pod.Spec.Containers {
+		if container.Name == AgentContainerName {
+			if len(container.Image) == 0 {
+				pod.Spec.Containers[i].Image = DefaultWindowsAgentImage
+			}
+			continue
+		}
+		if len(container.Command) == 0 && len(container.Args) == 0 {
+			pod.Spec.Containers[i].Command = windowsKeepAliveCommand
+		}
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+		Key:      OSNodeTaintKey,
+		Operator: corev1.TolerationOpEqual,
+		Value:    string(virtuslabv1alpha1.AgentOSWindows),
+		Effect:   corev1.TaintEffectNoSchedule,
+	})
+}
@@ -0,0 +1,77 @@
+package resources
+
+import (
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WorkspaceVolumeName is the name of the volume Kubernetes plugin mounts as the agent workspace, the volume defined
+// in the pod yaml is used instead of the plugin default
+const WorkspaceVolumeName = "workspace-volume"
+
+// applyWorkspaceVolume sets emptyDir or hostPath volume of AgentPodTemplate.WorkspaceVolume as the workspace volume of
+// the agent pod, ephemeral volume isn't available in the vendored pod API, see setAgentPodYamlWorkspaceVolume
+func applyWorkspaceVolume(pod *corev1.Pod, workspace *virtuslabv1alpha1.WorkspaceVolume) {
+	if workspace == nil {
+		return
+	}
+
+	var source corev1.VolumeSource
+	switch workspace.Type {
+	case virtuslabv1alpha1.WorkspaceVolumeTypeEmptyDir:
+		source.EmptyDir = &corev1.EmptyDirVolumeSource{SizeLimit: workspace.SizeLimit}
+		if workspace.Memory {
+			source.EmptyDir.Medium = corev1.StorageMediumMemory
+		}
+	case virtuslabv1alpha1.WorkspaceVolumeTypeHostPath:
+		// the directory is shared by agent pods on the node, it's created when it doesn't exist yet
+		hostPathType := corev1.HostPathDirectoryOrCreate
+		source.HostPath = &corev1.HostPathVolumeSource{Path: workspace.Path, Type: &hostPathType}
+	default:
+		return
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{Name: WorkspaceVolumeName, VolumeSource: source})
+}
+
+// setAgentPodYamlWorkspaceVolume adds generic ephemeral volume of AgentPodTemplate.WorkspaceVolume to the agent pod
+// yaml as the workspace volume, the PVC is created and deleted together with the agent pod
+func setAgentPodYamlWorkspaceVolume(podYAML []byte, workspace *virtuslabv1alpha1.WorkspaceVolume) ([]byte, error) {
+	if workspace == nil || workspace.Type != virtuslabv1alpha1.WorkspaceVolumeTypeEphemeral || workspace.Size == nil {
+		return podYAML, nil
+	}
+
+	accessMode := workspace.AccessMode
+	if len(accessMode) == 0 {
+		accessMode = corev1.ReadWriteOnce
+	}
+	claimSpec := map[string]interface{}{
+		"accessModes": []interface{}{string(accessMode)},
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{"storage": workspace.Size.String()},
+		},
+	}
+	if len(workspace.StorageClassName) > 0 {
+		claimSpec["storageClassName"] = workspace.StorageClassName
+	}
+
+	pod := map[string]interface{}{}
+	if err := yaml.Unmarshal(podYAML, &pod); err != nil {
+		return nil, err
+	}
+	spec, ok := pod["spec"].(map[string]interface{})
+	if !ok {
+		spec = map[string]interface{}{}
+		pod["spec"] = spec
+	}
+	volumes, _ := spec["volumes"].([]interface{})
+	spec["volumes"] = append(volumes, map[string]interface{}{
+		"name": WorkspaceVolumeName,
+		"ephemeral": map[string]interface{}{
+			"volumeClaimTemplate": map[string]interface{}{"spec": claimSpec},
+		},
+	})
+	return yaml.Marshal(pod)
+}
@@ -0,0 +1,155 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/metrics"
+	"github.com/VirtusLab/jenkins-operator/pkg/log"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// safeRestartTimeout is the maximum time the operator waits for running builds before Jenkins restart
+	safeRestartTimeout = 10 * time.Minute
+
+	restartReasonSpecChanged        = "SpecChanged"
+	restartReasonCertificateRenewed = "CertificateRenewed"
+	restartReasonCompleted          = "Completed"
+	// restartReasonCanceled is the reason of the finished Restarting condition when the restart isn't required anymore,
+	// e.g. the spec change was reverted, before the Jenkins master pod has been deleted
+	restartReasonCanceled = "Canceled"
+	// restartReasonInvalidPodPhase is the reason of Jenkins master pod recreation counted by metrics.JenkinsRestarts
+	restartReasonInvalidPodPhase = "InvalidPodPhase"
+)
+
+// safeRestartJenkins puts Jenkins into quiet mode, waits (bounded by safeRestartTimeout) until running builds finish
+// and then deletes the Jenkins master pod, the progress is exposed by the Restarting status condition
+func (r *ReconcileJenkinsBaseConfiguration) safeRestartJenkins(meta metav1.ObjectMeta, currentJenkinsMasterPod *corev1.Pod,
+	reason, message string) (reconcile.Result, error) {
+	if !conditions.IsTrue(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting) {
+		r.logger.Info(fmt.Sprintf("Starting safe restart of Jenkins, reason '%s': %s", reason, message))
+		conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, reason,
+			fmt.Sprintf("%s, putting Jenkins into quiet mode", message))
+		if err := r.updateStatus(); err != nil {
+			return reconcile.Result{}, err
+		}
+		metrics.JenkinsRestarts.WithLabelValues(r.jenkins.Namespace, r.jenkins.Name, reason).Inc()
+
+		if !isPodReady(currentJenkinsMasterPod) {
+			return r.terminateJenkinsMasterPod(currentJenkinsMasterPod)
+		}
+		jenkinsClient, err := r.ensureJenkinsClient(meta)
+		if err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Couldn't connect to Jenkins, restarting immediately: %s", err))
+			return r.terminateJenkinsMasterPod(currentJenkinsMasterPod)
+		}
+		if err = jenkinsClient.QuietDown(); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Couldn't put Jenkins into quiet mode, restarting immediately: %s", err))
+			return r.terminateJenkinsMasterPod(currentJenkinsMasterPod)
+		}
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 10}, nil
+	}
+
+	if !isPodReady(currentJenkinsMasterPod) {
+		return r.terminateJenkinsMasterPod(currentJenkinsMasterPod)
+	}
+
+	restartCondition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting)
+	if time.Since(restartCondition.LastTransitionTime.Time) > safeRestartTimeout {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Running builds haven't finished in %s, restarting Jenkins", safeRestartTimeout))
+		return r.terminateJenkinsMasterPod(currentJenkinsMasterPod)
+	}
+
+	jenkinsClient, err := r.ensureJenkinsClient(meta)
+	if err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Couldn't connect to Jenkins, restarting immediately: %s", err))
+		return r.terminateJenkinsMasterPod(currentJenkinsMasterPod)
+	}
+	busyExecutors, err := jenkinsClient.GetBusyExecutors()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if busyExecutors == 0 {
+		return r.terminateJenkinsMasterPod(currentJenkinsMasterPod)
+	}
+
+	r.logger.V(log.VDebug).Info(fmt.Sprintf("Waiting for %d running builds before Jenkins restart", busyExecutors))
+	conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, restartCondition.Reason,
+		fmt.Sprintf("Waiting for %d running builds", busyExecutors))
+	if err = r.updateStatus(); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 10}, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) terminateJenkinsMasterPod(currentJenkinsMasterPod *corev1.Pod) (reconcile.Result, error) {
+	r.logger.Info(fmt.Sprintf("Terminating Jenkins Master Pod %s/%s", currentJenkinsMasterPod.Namespace, currentJenkinsMasterPod.Name))
+	if err := r.k8sClient.Delete(context.TODO(), currentJenkinsMasterPod); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if restartCondition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting); restartCondition != nil &&
+		restartCondition.Status == corev1.ConditionTrue {
+		conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, restartCondition.Reason,
+			"Terminating Jenkins master pod")
+		if err := r.updateStatus(); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// completeRestart marks the Restarting condition as finished once the new Jenkins master pod is ready, when the Jenkins
+// master pod is older than the restart the pod hasn't been deleted because the restart isn't required anymore, so
+// the quiet mode is canceled unless Jenkins is in maintenance mode, then it's handed over to the maintenance mode
+func (r *ReconcileJenkinsBaseConfiguration) completeRestart(meta metav1.ObjectMeta, jenkinsClient jenkinsclient.Jenkins) error {
+	restartCondition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting)
+	if restartCondition == nil || restartCondition.Status != corev1.ConditionTrue {
+		return nil
+	}
+
+	currentJenkinsMasterPod, err := r.getJenkinsMasterPod(meta)
+	if err != nil {
+		return err
+	}
+	if currentJenkinsMasterPod.ObjectMeta.CreationTimestamp.Before(&restartCondition.LastTransitionTime) {
+		if r.jenkins.Spec.MaintenanceMode {
+			// the quiet mode is kept for the maintenance, so it's canceled when the maintenance mode is disabled
+			r.jenkins.Status.MaintenanceQuietDown = true
+		} else if _, err := jenkinsClient.ExecuteScript(cancelQuietDownScript); err != nil {
+			return err
+		}
+
+		r.logger.Info("Jenkins restart isn't required anymore, the restart has been canceled")
+		conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionFalse, restartReasonCanceled,
+			"Jenkins restart isn't required anymore, quiet mode has been canceled")
+		return r.updateStatus()
+	}
+
+	r.logger.Info("Jenkins has been restarted")
+	conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionFalse, restartReasonCompleted,
+		"Jenkins master pod has been restarted")
+	return r.updateStatus()
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if !containerStatus.Ready {
+			return false
+		}
+	}
+	return true
+}
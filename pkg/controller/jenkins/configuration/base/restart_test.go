@@ -0,0 +1,90 @@
+package base
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestReconcileJenkinsBaseConfiguration_completeRestart(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	restartStarted := metav1.NewTime(time.Now().Add(-time.Minute))
+	newReconciler := func(t *testing.T, maintenanceMode bool, podCreated metav1.Time) *ReconcileJenkinsBaseConfiguration {
+		jenkins := &virtuslabv1alpha1.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+			Spec:       virtuslabv1alpha1.JenkinsSpec{MaintenanceMode: maintenanceMode},
+		}
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting, corev1.ConditionTrue, restartReasonSpecChanged, "")
+		jenkins.Status.Conditions[0].LastTransitionTime = restartStarted
+		pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins, nil)
+		pod.ObjectMeta.CreationTimestamp = podCreated
+		fakeClient := fake.NewFakeClient()
+		if err := fakeClient.Create(context.TODO(), jenkins); err != nil {
+			t.Fatal(err)
+		}
+		if err := fakeClient.Create(context.TODO(), pod); err != nil {
+			t.Fatal(err)
+		}
+		return &ReconcileJenkinsBaseConfiguration{
+			k8sClient: fakeClient,
+			scheme:    scheme.Scheme,
+			logger:    logf.ZapLogger(false),
+			jenkins:   jenkins,
+		}
+	}
+
+	t.Run("Jenkins master pod restarted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		r := newReconciler(t, false, metav1.Now())
+
+		err := r.completeRestart(resources.NewResourceObjectMeta(r.jenkins), jenkinsClient)
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting)
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.Equal(t, restartReasonCompleted, condition.Reason)
+	})
+	t.Run("restart not required anymore", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkinsClient.EXPECT().ExecuteScript(cancelQuietDownScript).Return("", nil)
+		r := newReconciler(t, false, metav1.NewTime(restartStarted.Add(-time.Hour)))
+
+		err := r.completeRestart(resources.NewResourceObjectMeta(r.jenkins), jenkinsClient)
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting)
+		assert.Equal(t, corev1.ConditionFalse, condition.Status)
+		assert.Equal(t, restartReasonCanceled, condition.Reason)
+	})
+	t.Run("restart not required anymore in maintenance mode", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		r := newReconciler(t, true, metav1.NewTime(restartStarted.Add(-time.Hour)))
+
+		err := r.completeRestart(resources.NewResourceObjectMeta(r.jenkins), jenkinsClient)
+
+		assert.NoError(t, err)
+		condition := conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting)
+		assert.Equal(t, restartReasonCanceled, condition.Reason)
+		assert.True(t, r.jenkins.Status.MaintenanceQuietDown)
+	})
+}
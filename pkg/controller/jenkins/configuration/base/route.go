@@ -0,0 +1,100 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	routev1 "github.com/VirtusLab/jenkins-operator/pkg/apis/route/v1"
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ensureRoute creates or updates OpenShift Route of Jenkins UI, the Route is deleted when Jenkins.Spec.Service.Route
+// isn't set, nothing is done outside of OpenShift
+func (r *ReconcileJenkinsBaseConfiguration) ensureRoute(meta metav1.ObjectMeta) error {
+	if !r.openshift {
+		return nil
+	}
+	if !resources.IsRouteEnabled(r.jenkins) {
+		route := &routev1.Route{ObjectMeta: meta}
+		err := r.k8sClient.Delete(context.TODO(), route)
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err) {
+			return err
+		}
+		return nil
+	}
+
+	certificates, err := r.getRouteCertificates()
+	if err != nil {
+		return err
+	}
+	route := resources.NewRoute(meta, r.jenkins, certificates)
+	currentRoute := &routev1.Route{}
+	err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: route.Name, Namespace: route.Namespace}, currentRoute)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating OpenShift Route '%s'", route.Name))
+		return r.createResource(route)
+	} else if err != nil {
+		return err
+	}
+
+	if len(route.Spec.Host) == 0 {
+		// keep the host generated by OpenShift router
+		route.Spec.Host = currentRoute.Spec.Host
+	}
+	if reflect.DeepEqual(currentRoute.Spec, route.Spec) && reflect.DeepEqual(currentRoute.Annotations, route.Annotations) {
+		return nil
+	}
+	// custom resources can't be updated without resource version, so the current object is updated
+	currentRoute.Spec = route.Spec
+	currentRoute.Annotations = route.Annotations
+	return r.updateResource(currentRoute)
+}
+
+// getRouteCertificates reads the host certificate from Jenkins.Spec.Service.Route.SecretName Secret and the CA
+// verifying Jenkins master certificate with reencrypt termination
+func (r *ReconcileJenkinsBaseConfiguration) getRouteCertificates() (resources.RouteCertificates, error) {
+	certificates := resources.RouteCertificates{}
+	termination := resources.GetRouteTermination(r.jenkins)
+	if termination == virtuslabv1alpha1.RouteTerminationPassthrough {
+		return certificates, nil
+	}
+
+	if secretName := r.jenkins.Spec.Service.Route.SecretName; len(secretName) > 0 {
+		secret := &corev1.Secret{}
+		err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: r.jenkins.ObjectMeta.Namespace}, secret)
+		if err != nil {
+			return certificates, err
+		}
+		certificates.Certificate = string(secret.Data[resources.TLSCertificateSecretKey])
+		certificates.Key = string(secret.Data[resources.TLSPrivateKeySecretKey])
+	}
+
+	if termination == virtuslabv1alpha1.RouteTerminationReencrypt {
+		var caSecretName string
+		if keystore := r.jenkins.Spec.Master.HTTPSKeystore; keystore != nil {
+			caSecretName = keystore.CASecretName
+		} else if caSecretName = r.jenkins.Spec.Master.TLS.CASecretName; len(caSecretName) == 0 {
+			caSecretName = r.jenkins.Spec.Master.TLS.SecretName
+		}
+		if len(caSecretName) == 0 {
+			// the router uses the cluster service CA
+			return certificates, nil
+		}
+		caSecret := &corev1.Secret{}
+		err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: caSecretName, Namespace: r.jenkins.ObjectMeta.Namespace}, caSecret)
+		if err != nil {
+			return certificates, err
+		}
+		// the router uses the cluster service CA when the destination CA isn't set
+		certificates.DestinationCACertificate = string(caSecret.Data[resources.TLSCASecretKey])
+	}
+
+	return certificates, nil
+}
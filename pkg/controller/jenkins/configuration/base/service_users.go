@@ -0,0 +1,129 @@
+package base
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// serviceUserTokenPrefix marks the line with generated API token in the groovy script output
+const serviceUserTokenPrefix = "service-user-token="
+
+const generateServiceUserTokenFmt = `
+import hudson.model.User
+import hudson.security.HudsonPrivateSecurityRealm
+import jenkins.model.Jenkins
+import jenkins.security.ApiTokenProperty
+
+def decode(String value) {
+    return new String(Base64.getDecoder().decode(value), 'UTF-8')
+}
+
+def userName = decode('%s')
+def realm = Jenkins.instance.securityRealm
+if (realm instanceof HudsonPrivateSecurityRealm && realm.getUser(userName) == null) {
+    realm.createAccount(userName, decode('%s'))
+}
+def user = User.getById(userName, true)
+def tokenStore = user.getProperty(ApiTokenProperty).tokenStore
+def tokenName = decode('%s')
+tokenStore.tokenListSortedByName.findAll { it.name == tokenName }.each { tokenStore.revokeToken(it.uuid) }
+def token = tokenStore.generateNewToken(tokenName)
+user.save()
+println('%s' + token.plainValue)
+`
+
+// buildGenerateServiceUserTokenGroovyScript renders groovy script which creates the service user and replaces
+// its API token generated by the operator, values are base64 encoded so they don't need escaping
+func buildGenerateServiceUserTokenGroovyScript(userName, password string) string {
+	return fmt.Sprintf(generateServiceUserTokenFmt,
+		base64.StdEncoding.EncodeToString([]byte(userName)),
+		base64.StdEncoding.EncodeToString([]byte(password)),
+		base64.StdEncoding.EncodeToString([]byte(constants.OperatorName)),
+		serviceUserTokenPrefix)
+}
+
+// parseServiceUserToken returns API token printed by the generate service user token groovy script
+func parseServiceUserToken(output string) (string, error) {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, serviceUserTokenPrefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, serviceUserTokenPrefix)), nil
+		}
+	}
+	return "", fmt.Errorf("couldn't find API token in groovy script output")
+}
+
+// isServiceUserSecretValid tells if the Secret contains API token of the user which is still present in Jenkins,
+// Jenkins home isn't persistent, so tokens generated before Jenkins master pod start are lost
+func isServiceUserSecretValid(secret *corev1.Secret, userName string, jenkinsMasterPod *corev1.Pod) bool {
+	if string(secret.Data[resources.ServiceUserSecretUserNameKey]) != userName ||
+		len(secret.Data[resources.ServiceUserSecretTokenKey]) == 0 {
+		return false
+	}
+
+	tokenCreationTime := time.Time{}
+	if err := tokenCreationTime.UnmarshalText(secret.Data[resources.ServiceUserSecretTokenCreationKey]); err != nil {
+		return false
+	}
+	return !jenkinsMasterPod.ObjectMeta.CreationTimestamp.Time.UTC().After(tokenCreationTime.UTC())
+}
+
+// ensureServiceUsers creates Jenkins accounts of Jenkins.Spec.Security.ServiceUsers and publishes their API tokens
+// in Secrets, a new token is generated only when the Secret doesn't contain a valid one
+func (r *ReconcileJenkinsBaseConfiguration) ensureServiceUsers(meta metav1.ObjectMeta, jenkinsClient jenkinsclient.Jenkins) error {
+	if len(r.jenkins.Spec.Security.ServiceUsers) == 0 {
+		return nil
+	}
+
+	jenkinsMasterPod, err := r.getJenkinsMasterPod(meta)
+	if err != nil {
+		return err
+	}
+
+	for _, serviceUser := range r.jenkins.Spec.Security.ServiceUsers {
+		secret := &corev1.Secret{}
+		err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: serviceUser.SecretName, Namespace: r.jenkins.ObjectMeta.Namespace}, secret)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		secretFound := err == nil
+		if secretFound && isServiceUserSecretValid(secret, serviceUser.Name, jenkinsMasterPod) {
+			continue
+		}
+
+		r.logger.Info(fmt.Sprintf("Generating API token of service user '%s'", serviceUser.Name))
+		output, err := jenkinsClient.ExecuteScript(buildGenerateServiceUserTokenGroovyScript(serviceUser.Name, resources.NewServiceUserPassword()))
+		if err != nil {
+			return err
+		}
+		token, err := parseServiceUserToken(output)
+		if err != nil {
+			return err
+		}
+
+		serviceUserSecret := resources.NewServiceUserSecret(resources.NewResourceObjectMeta(r.jenkins), serviceUser, token)
+		if !secretFound {
+			err = r.createResource(serviceUserSecret)
+		} else {
+			secret.Data = serviceUserSecret.Data
+			err = r.updateResource(secret)
+		}
+		if err != nil {
+			return err
+		}
+		r.logger.Info(fmt.Sprintf("API token of service user '%s' has been published in Secret '%s'", serviceUser.Name, serviceUser.SecretName))
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package base
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildGenerateServiceUserTokenGroovyScript(t *testing.T) {
+	userName := "ci-bot'); Jenkins.instance.doSafeExit(null); ('"
+
+	script := buildGenerateServiceUserTokenGroovyScript(userName, "password")
+
+	assert.Contains(t, script, base64.StdEncoding.EncodeToString([]byte(userName)))
+	assert.NotContains(t, script, userName)
+	assert.Contains(t, script, serviceUserTokenPrefix)
+}
+
+func TestParseServiceUserToken(t *testing.T) {
+	t.Run("happy", func(t *testing.T) {
+		token, err := parseServiceUserToken("some output\n" + serviceUserTokenPrefix + "11abcdef\n")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "11abcdef", token)
+	})
+	t.Run("no token", func(t *testing.T) {
+		_, err := parseServiceUserToken("some output\n")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestIsServiceUserSecretValid(t *testing.T) {
+	podCreationTime := time.Now().UTC()
+	jenkinsMasterPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(podCreationTime)}}
+	newSecret := func(userName, token string, tokenCreationTime time.Time) *corev1.Secret {
+		creationTime, _ := tokenCreationTime.MarshalText()
+		return &corev1.Secret{
+			Data: map[string][]byte{
+				resources.ServiceUserSecretUserNameKey:      []byte(userName),
+				resources.ServiceUserSecretTokenKey:         []byte(token),
+				resources.ServiceUserSecretTokenCreationKey: creationTime,
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		secret *corev1.Secret
+		want   bool
+	}{
+		{
+			name:   "valid",
+			secret: newSecret("ci-bot", "token", podCreationTime.Add(time.Minute)),
+			want:   true,
+		},
+		{
+			name:   "empty secret",
+			secret: &corev1.Secret{},
+			want:   false,
+		},
+		{
+			name:   "different user",
+			secret: newSecret("deployer", "token", podCreationTime.Add(time.Minute)),
+			want:   false,
+		},
+		{
+			name:   "empty token",
+			secret: newSecret("ci-bot", "", podCreationTime.Add(time.Minute)),
+			want:   false,
+		},
+		{
+			name:   "token generated before Jenkins master pod start",
+			secret: newSecret("ci-bot", "token", podCreationTime.Add(-time.Minute)),
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isServiceUserSecretValid(tt.secret, "ci-bot", jenkinsMasterPod))
+		})
+	}
+}
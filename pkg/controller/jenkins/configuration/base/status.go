@@ -0,0 +1,52 @@
+package base
+
+import (
+	"fmt"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// reasonMasterPodCreated is the reason of the event and the conditions reset when a new Jenkins master pod is
+	// created
+	reasonMasterPodCreated = "MasterPodCreated"
+	// reasonJenkinsReachable is the reason of the event and the Provisioned condition when Jenkins API of the new
+	// Jenkins master pod is reachable
+	reasonJenkinsReachable = "JenkinsReachable"
+)
+
+// setProvisioningConditions resets the conditions of Jenkins lifecycle when a new Jenkins master pod is created,
+// the new pod is provisioned and configured from scratch
+func setProvisioningConditions(status *virtuslabv1alpha1.JenkinsStatus) {
+	conditions.Set(status, virtuslabv1alpha1.JenkinsConditionProvisioned, corev1.ConditionFalse, reasonMasterPodCreated,
+		"Waiting for Jenkins master pod")
+	conditions.Set(status, virtuslabv1alpha1.JenkinsConditionBaseConfigured, corev1.ConditionFalse, reasonMasterPodCreated,
+		"Waiting for base configuration")
+	conditions.Set(status, virtuslabv1alpha1.JenkinsConditionUserConfigured, corev1.ConditionFalse, reasonMasterPodCreated,
+		"Waiting for user configuration")
+	conditions.Set(status, virtuslabv1alpha1.JenkinsConditionReady, corev1.ConditionFalse, reasonMasterPodCreated,
+		"Jenkins master pod is being provisioned")
+}
+
+// ensureProvisionedCondition marks Jenkins as provisioned once Jenkins master pod is ready and Jenkins API is reachable,
+// Jenkins version is recorded in the status
+func (r *ReconcileJenkinsBaseConfiguration) ensureProvisionedCondition(jenkinsClient jenkinsclient.Jenkins) error {
+	if conditions.IsTrue(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionProvisioned) {
+		return nil
+	}
+
+	r.logger.Info("Jenkins master pod is provisioned")
+	r.jenkins.Status.JenkinsVersion = jenkinsClient.GetVersion()
+	conditions.Set(&r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionProvisioned, corev1.ConditionTrue, reasonJenkinsReachable,
+		"Jenkins master pod is ready and Jenkins API is reachable")
+	if err := r.updateStatus(); err != nil {
+		return err
+	}
+	r.recorder.Event(r.jenkins, corev1.EventTypeNormal, reasonJenkinsReachable,
+		fmt.Sprintf("Jenkins API is reachable, Jenkins version '%s'", r.jenkins.Status.JenkinsVersion))
+	return nil
+}
@@ -0,0 +1,177 @@
+package base
+
+import (
+	"context"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestSetProvisioningConditions(t *testing.T) {
+	status := virtuslabv1alpha1.JenkinsStatus{}
+	conditions.Set(&status, virtuslabv1alpha1.JenkinsConditionReady, corev1.ConditionTrue, "Reconciled", "")
+	conditions.Set(&status, virtuslabv1alpha1.JenkinsConditionMaintenance, corev1.ConditionTrue, maintenanceReasonEnabled, "")
+
+	setProvisioningConditions(&status)
+
+	for _, conditionType := range []virtuslabv1alpha1.JenkinsConditionType{
+		virtuslabv1alpha1.JenkinsConditionProvisioned,
+		virtuslabv1alpha1.JenkinsConditionBaseConfigured,
+		virtuslabv1alpha1.JenkinsConditionUserConfigured,
+		virtuslabv1alpha1.JenkinsConditionReady,
+	} {
+		condition := conditions.Get(status, conditionType)
+		if assert.NotNil(t, condition, string(conditionType)) {
+			assert.Equal(t, corev1.ConditionFalse, condition.Status, string(conditionType))
+			assert.Equal(t, reasonMasterPodCreated, condition.Reason, string(conditionType))
+		}
+	}
+	assert.True(t, conditions.IsTrue(status, virtuslabv1alpha1.JenkinsConditionMaintenance))
+}
+
+func TestReconcileJenkinsBaseConfiguration_ensureProvisionedCondition(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	jenkins := &virtuslabv1alpha1.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+	}
+	setProvisioningConditions(&jenkins.Status)
+	fakeClient := fake.NewFakeClient()
+	err = fakeClient.Create(context.TODO(), jenkins)
+	assert.NoError(t, err)
+	recorder := record.NewFakeRecorder(10)
+	r := &ReconcileJenkinsBaseConfiguration{
+		k8sClient: fakeClient,
+		scheme:    scheme.Scheme,
+		recorder:  recorder,
+		logger:    logf.ZapLogger(false),
+		jenkins:   jenkins,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jenkinsClient := client.NewMockJenkins(ctrl)
+	jenkinsClient.EXPECT().GetVersion().Return("2.176.2")
+
+	err = r.ensureProvisionedCondition(jenkinsClient)
+
+	assert.NoError(t, err)
+	current := &virtuslabv1alpha1.Jenkins{}
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}, current)
+	assert.NoError(t, err)
+	assert.True(t, conditions.IsTrue(current.Status, virtuslabv1alpha1.JenkinsConditionProvisioned))
+	assert.False(t, conditions.IsTrue(current.Status, virtuslabv1alpha1.JenkinsConditionReady))
+	assert.Equal(t, "2.176.2", current.Status.JenkinsVersion)
+	assert.Equal(t, virtuslabv1alpha1.JenkinsPhaseConfiguring, current.Status.Phase)
+	assert.Equal(t, "Normal JenkinsReachable Jenkins API is reachable, Jenkins version '2.176.2'", <-recorder.Events)
+
+	err = r.ensureProvisionedCondition(jenkinsClient)
+
+	assert.NoError(t, err)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestReconcileJenkinsBaseConfiguration_ensureJenkinsMasterPod_keepsSpecHistory(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	history := []virtuslabv1alpha1.SpecChange{
+		{Generation: 1, Hash: "first"},
+		{Generation: 2, Hash: "second", Changes: []string{"master.image"}, FieldHashes: map[string]string{"master.image": "image"}},
+	}
+	jenkins := &virtuslabv1alpha1.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+		Status: virtuslabv1alpha1.JenkinsStatus{
+			ObservedGeneration:                 2,
+			JenkinsVersion:                     "2.176.2",
+			OperatorCredentialsResourceVersion: "100",
+			SpecHistory:                        history,
+		},
+	}
+	fakeClient := fake.NewFakeClient()
+	err = fakeClient.Create(context.TODO(), jenkins)
+	assert.NoError(t, err)
+	err = fakeClient.Create(context.TODO(), newBaseConfigurationConfigMap(jenkins))
+	assert.NoError(t, err)
+	r := &ReconcileJenkinsBaseConfiguration{
+		k8sClient: fakeClient,
+		scheme:    scheme.Scheme,
+		recorder:  record.NewFakeRecorder(10),
+		logger:    logf.ZapLogger(false),
+		jenkins:   jenkins,
+	}
+
+	_, err = r.ensureJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins))
+
+	assert.NoError(t, err)
+	current := &virtuslabv1alpha1.Jenkins{}
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}, current)
+	assert.NoError(t, err)
+	assert.Equal(t, history, current.Status.SpecHistory)
+	assert.Equal(t, "100", current.Status.OperatorCredentialsResourceVersion)
+	assert.Equal(t, int64(2), current.Status.ObservedGeneration)
+	assert.Empty(t, current.Status.JenkinsVersion)
+	assert.NotNil(t, current.Status.ProvisionStartTime)
+	pod := &corev1.Pod{}
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: resources.GetResourceName(jenkins)}, pod)
+	assert.NoError(t, err)
+}
+
+func TestReconcileJenkinsBaseConfiguration_ensureJenkinsMasterPod_annotatesSpecHash(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	jenkins := &virtuslabv1alpha1.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+	}
+	baseConfiguration := newBaseConfigurationConfigMap(jenkins)
+	pod := resources.NewJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins), jenkins, baseConfiguration.Data)
+	requiredSpecHash := resources.GetJenkinsMasterPodSpecHash(pod)
+	delete(pod.ObjectMeta.Annotations, constants.AnnotationSpecHashKey)
+	fakeClient := fake.NewFakeClient()
+	err = fakeClient.Create(context.TODO(), jenkins)
+	assert.NoError(t, err)
+	err = fakeClient.Create(context.TODO(), baseConfiguration)
+	assert.NoError(t, err)
+	err = fakeClient.Create(context.TODO(), pod)
+	assert.NoError(t, err)
+	r := &ReconcileJenkinsBaseConfiguration{
+		k8sClient: fakeClient,
+		scheme:    scheme.Scheme,
+		recorder:  record.NewFakeRecorder(10),
+		logger:    logf.ZapLogger(false),
+		jenkins:   jenkins,
+	}
+
+	result, err := r.ensureJenkinsMasterPod(resources.NewResourceObjectMeta(jenkins))
+
+	assert.NoError(t, err)
+	assert.False(t, result.Requeue)
+	current := &corev1.Pod{}
+	err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, current)
+	if assert.NoError(t, err) {
+		assert.Nil(t, current.ObjectMeta.DeletionTimestamp)
+		assert.Equal(t, requiredSpecHash, resources.GetJenkinsMasterPodSpecHash(current))
+	}
+	assert.Equal(t, requiredSpecHash, r.jenkins.Status.MasterPodSpecHash)
+	assert.Nil(t, conditions.Get(r.jenkins.Status, virtuslabv1alpha1.JenkinsConditionRestarting))
+}
+
+func newBaseConfigurationConfigMap(jenkins *virtuslabv1alpha1.Jenkins) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: jenkins.Namespace, Name: resources.GetBaseConfigurationConfigMapName(jenkins)},
+		Data:       map[string]string{"1-basic-settings.groovy": "println 'basic settings'"},
+	}
+}
@@ -0,0 +1,116 @@
+package base
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"reflect"
+
+	certmanagerv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/certmanager/v1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// buildJenkinsClientTLSConfig returns TLS configuration used by the operator to verify Jenkins master certificate,
+// returns nil when TLS isn't enabled
+func (r *ReconcileJenkinsBaseConfiguration) buildJenkinsClientTLSConfig() (*tls.Config, error) {
+	if !resources.IsTLSEnabled(r.jenkins) {
+		return nil, nil
+	}
+
+	// the CA is optional when it's read from the certificate Secret
+	var caSecretName string
+	var caRequired, insecureSkipVerify bool
+	if keystore := r.jenkins.Spec.Master.HTTPSKeystore; keystore != nil {
+		caSecretName, caRequired, insecureSkipVerify = keystore.CASecretName, true, keystore.InsecureSkipVerify
+	} else {
+		masterTLS := r.jenkins.Spec.Master.TLS
+		caSecretName, caRequired, insecureSkipVerify = masterTLS.CASecretName, len(masterTLS.CASecretName) > 0, masterTLS.InsecureSkipVerify
+		if !caRequired {
+			caSecretName = masterTLS.SecretName
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		// the operator connects through the Service short name or port-forward, so the name is set explicitly
+		ServerName:         resources.GetJenkinsMasterServiceDNSName(r.jenkins),
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if resources.IsFIPSModeEnabled(r.jenkins) {
+		tlsConfig.MinVersion = tls.VersionTLS12
+		tlsConfig.CipherSuites = resources.FIPSCipherSuites
+	}
+	if insecureSkipVerify || len(caSecretName) == 0 {
+		return tlsConfig, nil
+	}
+
+	caSecret := &corev1.Secret{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: caSecretName, Namespace: r.jenkins.ObjectMeta.Namespace}, caSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	caCertificates, found := caSecret.Data[resources.TLSCASecretKey]
+	if !found && !caRequired {
+		// system root certificates are used
+		return tlsConfig, nil
+	}
+	tlsConfig.RootCAs = x509.NewCertPool()
+	if !tlsConfig.RootCAs.AppendCertsFromPEM(caCertificates) {
+		return nil, fmt.Errorf("couldn't parse CA certificates from '%s' key of Secret '%s'", resources.TLSCASecretKey, caSecretName)
+	}
+
+	return tlsConfig, nil
+}
+
+// ensureCertificate creates or updates cert-manager Certificate of Jenkins master when Jenkins.Spec.Master.TLS.IssuerRef is set
+func (r *ReconcileJenkinsBaseConfiguration) ensureCertificate(meta metav1.ObjectMeta) error {
+	if r.jenkins.Spec.Master.TLS == nil || r.jenkins.Spec.Master.TLS.IssuerRef == nil {
+		return nil
+	}
+
+	certificate := resources.NewCertificate(meta, r.jenkins)
+	currentCertificate := &certmanagerv1.Certificate{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: certificate.Name, Namespace: certificate.Namespace}, currentCertificate)
+	if err != nil && apierrors.IsNotFound(err) {
+		r.logger.Info(fmt.Sprintf("Creating cert-manager Certificate '%s'", certificate.Name))
+		return r.createResource(certificate)
+	} else if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(currentCertificate.Spec, certificate.Spec) {
+		return nil
+	}
+	// custom resources can't be updated without resource version, so the current object is updated
+	currentCertificate.Spec = certificate.Spec
+	return r.updateResource(currentCertificate)
+}
+
+// getTLSCertificateHash returns hash of Jenkins master certificate or keystore, Jenkins reads them only during start,
+// so it's restarted when the hash changes, returns empty string when TLS isn't enabled
+func (r *ReconcileJenkinsBaseConfiguration) getTLSCertificateHash() (string, error) {
+	if !resources.IsTLSEnabled(r.jenkins) {
+		return "", nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: resources.GetTLSSecretName(r.jenkins), Namespace: r.jenkins.ObjectMeta.Namespace}, secret)
+	if err != nil {
+		return "", err
+	}
+
+	key := resources.TLSCertificateSecretKey
+	if resources.IsHTTPSKeystoreEnabled(r.jenkins) {
+		key = resources.GetHTTPSKeystoreSecretKey(r.jenkins)
+	}
+	hash := sha256.Sum256(secret.Data[key])
+	return base64.URLEncoding.EncodeToString(hash[:]), nil
+}
@@ -2,83 +2,2984 @@ package base
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
 	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
 
+	certmanagerv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/certmanager/v1"
+	ingressclassv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/networking/v1"
 	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/plugins"
 	"github.com/VirtusLab/jenkins-operator/pkg/log"
 
 	docker "github.com/docker/distribution/reference"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	samlMetadataNamespace = "urn:oasis:names:tc:SAML:2.0:metadata"
+	// minAdminPasswordRotationPeriod prevents Jenkins user and Secret updates on every reconcile loop
+	minAdminPasswordRotationPeriod = time.Hour
+	// minOrphanedAgentPodsGracePeriod leaves Kubernetes plugin time to register agents of new pods
+	minOrphanedAgentPodsGracePeriod = 5 * time.Minute
 )
 
 var (
 	dockerImageRegexp = regexp.MustCompile(`^` + docker.TagRegexp.String() + `$`)
+	// script security signatures, e.g. 'method java.lang.String trim' or 'new java.io.File java.lang.String'
+	scriptSignatureRegexp = regexp.MustCompile(`^(method|staticMethod|new|field|staticField) \S+( \S+)*$`)
+	// script security hashes, SHA-1 used by older plugin versions or SHA-512 with prefix
+	scriptHashRegexp = regexp.MustCompile(`^([0-9a-f]{40}|SHA512:[0-9a-f]{128})$`)
+	envNameRegexp    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// DNS compatible S3 bucket names, required by artifact-manager-s3 plugin
+	s3BucketNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+	// GitHub user and organization names
+	gitHubNameRegexp = regexp.MustCompile(`^[A-Za-z0-9](-?[A-Za-z0-9])*$`)
+	// Jenkins permissions referenced by group and name, e.g. 'Overall/Read' or 'Job/Build'
+	permissionRegexp = regexp.MustCompile(`^[^/\s]+( [^/\s]+)*/[^/\s]+$`)
+	// Jenkins context path, e.g. '/jenkins' or '/ci/jenkins', without characters which need escaping in URLs
+	prefixRegexp = regexp.MustCompile(`^(/[A-Za-z0-9._~-]+)+/?$`)
+	// HTTP header field names, RFC 7230 tokens
+	headerNameRegexp = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+	// SSH public host keys in known_hosts format without host names, e.g. 'ssh-ed25519 AAAA...'
+	sshHostKeyRegexp = regexp.MustCompile(`^[a-z0-9@.-]+ [A-Za-z0-9+/]+={0,2}$`)
+	// AWS region, e.g. 'eu-west-1' or 'us-gov-west-1'
+	awsRegionRegexp = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]$`)
+	// Amazon Machine Image ID, e.g. 'ami-0abcdef1234567890'
+	amiIDRegexp = regexp.MustCompile(`^ami-[0-9a-f]{8}([0-9a-f]{9})?$`)
+	// Google Cloud project ID
+	gcpProjectIDRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+	// Compute Engine image or image family, e.g. 'projects/debian-cloud/global/images/family/debian-12'
+	gceImageRegexp = regexp.MustCompile(`^projects/[a-z][a-z0-9-]{4,28}[a-z0-9]/global/images/(family/)?[a-z]([a-z0-9-]*[a-z0-9])?$`)
+	// Compute Engine zone, e.g. 'europe-west1-b'
+	gceZoneRegexp = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+-[a-z]$`)
+
+	// prometheusDurationRegexp matches Prometheus durations, e.g. 1m30s
+	prometheusDurationRegexp = regexp.MustCompile(`^([0-9]+(ms|s|m|h|d|w|y))+$`)
+
+	// monitoringPathRegexp matches the prometheus plugin path without leading and trailing slash
+	monitoringPathRegexp = regexp.MustCompile(`^[A-Za-z0-9._~-]+(/[A-Za-z0-9._~-]+)*$`)
 )
 
-// Validate validates Jenkins CR Spec.master section
-func (r *ReconcileJenkinsBaseConfiguration) Validate(jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
-	if jenkins.Spec.Master.Image == "" {
-		r.logger.V(log.VWarn).Info("Image not set")
+// Validate validates Jenkins CR Spec.master section
+func (r *ReconcileJenkinsBaseConfiguration) Validate(ctx context.Context, jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
+	r.ctx = ctx
+	if jenkins.Spec.Master.Image == "" {
+		r.logger.V(log.VWarn).Info("Image not set")
+		return false, nil
+	}
+
+	if !dockerImageRegexp.MatchString(jenkins.Spec.Master.Image) && !docker.ReferenceRegexp.MatchString(jenkins.Spec.Master.Image) {
+		r.logger.V(log.VWarn).Info("Invalid image")
+		return false, nil
+
+	}
+
+	if !r.validatePlugins(jenkins.Spec.Master.Plugins) {
+		return false, nil
+	}
+
+	valid, err := r.validateBaseScripts()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	if !r.validateSharedLibraries() {
+		return false, nil
+	}
+
+	if !r.validateTools() {
+		return false, nil
+	}
+
+	if !r.validateScriptApprovals() {
+		return false, nil
+	}
+
+	if !r.validateGlobalEnv() {
+		return false, nil
+	}
+
+	if !r.validateExecutors() {
+		return false, nil
+	}
+
+	if !r.validateBuildSettings() {
+		return false, nil
+	}
+
+	if !r.validateRemoting() {
+		return false, nil
+	}
+
+	if !r.validateMarkupFormatter() {
+		return false, nil
+	}
+
+	if !r.validateTheme() {
+		return false, nil
+	}
+
+	if !r.validateContentSecurityPolicy() {
+		return false, nil
+	}
+
+	if !r.validateResponseHeaders() {
+		return false, nil
+	}
+
+	valid, err = r.validateTLS()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateHTTPSKeystore()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateRoute()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateVault()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateArtifactManager()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateLDAP()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateOIDC()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateSAML()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateGitHubOAuth()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	if !r.validateAuthorization() {
+		return false, nil
+	}
+
+	if !r.validateNetworkPolicy() {
+		return false, nil
+	}
+
+	if !r.validateService() {
+		return false, nil
+	}
+
+	if !r.validateAgentService() {
+		return false, nil
+	}
+
+	if !r.validateHeadlessService() {
+		return false, nil
+	}
+
+	if !r.validateJenkinsLocation() {
+		return false, nil
+	}
+
+	if !r.validatePrefix() {
+		return false, nil
+	}
+
+	if !r.validateSessionTimeout() {
+		return false, nil
+	}
+
+	if !r.validateIngress() {
+		return false, nil
+	}
+
+	valid, err = r.validateIngressClass()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	if !r.validateIstio() {
+		return false, nil
+	}
+
+	if !r.validateHTTPRoute() {
+		return false, nil
+	}
+
+	if !r.validateExternalDNS() {
+		return false, nil
+	}
+
+	if !r.validateExposure() {
+		return false, nil
+	}
+
+	if !r.validateMonitoring() {
+		return false, nil
+	}
+
+	if !r.validatePodSecurityProfile() {
+		return false, nil
+	}
+
+	if !r.validateAdminPasswordRotationPeriod() {
+		return false, nil
+	}
+
+	if !r.validateFIPSMode() {
+		return false, nil
+	}
+
+	if !r.validateServiceUsers() {
+		return false, nil
+	}
+
+	if !r.validateServiceAccountToken() {
+		return false, nil
+	}
+
+	if !r.validateLoginThrottling() {
+		return false, nil
+	}
+
+	if !r.validateSecurityProfile() {
+		return false, nil
+	}
+
+	if !r.validateScriptConsole() {
+		return false, nil
+	}
+
+	valid, err = r.validateAgentTemplates()
+	if err != nil {
+		return false, err
+	}
+	if err := r.ensureAgentTemplatesCondition(); err != nil {
+		return false, err
+	}
+	if !valid {
+		return false, nil
+	}
+
+	if !r.validateAgentDefaultImage() {
+		return false, nil
+	}
+
+	if !r.validateAgentsImagePullSecrets() {
+		return false, nil
+	}
+
+	if !r.validateAgentsCapacity() {
+		return false, nil
+	}
+
+	if !r.validateAgentsRetention() {
+		return false, nil
+	}
+
+	if !r.validateOrphanedAgentPodsGracePeriod() {
+		return false, nil
+	}
+
+	valid, err = r.validateAgentsNamespace()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateStaticAgents()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateAgentsClusters()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateVMClouds()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.verifyBackup()
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	if r.jenkins.Spec.Backup == virtuslabv1alpha1.JenkinsBackupTypeAmazonS3 && !r.verifyBackupAmazonS3() {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentTemplates() (bool, error) {
+	r.agentPodTemplateErrors = map[string][]string{}
+	valid := true
+	names := map[string]bool{}
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		if template.YamlConfigMap != nil {
+			resolved, resolvedValid, err := r.resolveAgentPodTemplateYamlConfigMap(template)
+			if err != nil {
+				return false, err
+			}
+			if !resolvedValid {
+				valid = false
+				continue
+			}
+			template = resolved
+		}
+		if errs := validation.IsDNS1123Label(template.Name); len(errs) > 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid agent pod template name '%s': %s", template.Name, strings.Join(errs, ", ")))
+			valid = false
+		}
+		if names[template.Name] {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Agent pod template name '%s' is used more than once", template.Name))
+			valid = false
+		}
+		names[template.Name] = true
+		if image := template.Image; len(image) > 0 && !dockerImageRegexp.MatchString(image) && !docker.ReferenceRegexp.MatchString(image) {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid image '%s' of agent pod template '%s'", image, template.Name))
+			valid = false
+		}
+		if strings.IndexFunc(template.Label, unicode.IsControl) >= 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Label of agent pod template '%s' can't contain control characters", template.Name))
+			valid = false
+		}
+		for _, env := range template.Env {
+			if !envNameRegexp.MatchString(env.Name) {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid environment variable name '%s' of agent pod template '%s'", env.Name, template.Name))
+				valid = false
+			}
+		}
+
+		pod, err := resources.NewAgentPod(template)
+		if err != nil {
+			r.warnAgentPodTemplate(template.Name, err.Error())
+			valid = false
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			// the image of jnlp container is already checked
+			if image := container.Image; len(image) > 0 && image != template.Image &&
+				!dockerImageRegexp.MatchString(image) && !docker.ReferenceRegexp.MatchString(image) {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid image '%s' of container '%s' of agent pod template '%s'",
+					image, container.Name, template.Name))
+				valid = false
+			}
+		}
+		volumes := map[string]bool{}
+		for _, volume := range pod.Spec.Volumes {
+			if errs := validation.IsDNS1123Label(volume.Name); len(errs) > 0 {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid volume name '%s' of agent pod template '%s': %s", volume.Name, template.Name,
+					strings.Join(errs, ", ")))
+				valid = false
+			}
+			if volumes[volume.Name] {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Volume name '%s' of agent pod template '%s' is used more than once", volume.Name, template.Name))
+				valid = false
+			}
+			volumes[volume.Name] = true
+		}
+		for _, volumeMount := range template.VolumeMounts {
+			if !volumes[volumeMount.Name] {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Volume mount '%s' of agent pod template '%s' doesn't reference any volume", volumeMount.Name, template.Name))
+				valid = false
+			}
+			if !strings.HasPrefix(volumeMount.MountPath, "/") &&
+				!(template.OS == virtuslabv1alpha1.AgentOSWindows && windowsAbsolutePathRegexp.MatchString(volumeMount.MountPath)) {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Volume mount '%s' of agent pod template '%s' requires absolute mount path", volumeMount.Name, template.Name))
+				valid = false
+			}
+		}
+		if len(template.Permissions) > 0 && len(pod.Spec.ServiceAccountName) > 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Agent pod template '%s' with permissions can't set service account '%s' in yaml",
+				template.Name, pod.Spec.ServiceAccountName))
+			valid = false
+		}
+		if !r.validateAgentPodTemplatePermissions(template) {
+			valid = false
+		}
+		if !r.validateAgentPodTemplateSpot(template) {
+			valid = false
+		}
+		if !r.validateAgentPodTemplateScheduling(template) {
+			valid = false
+		}
+		if !r.validateAgentPodTemplateOS(template, pod) {
+			valid = false
+		}
+		if !r.validateAgentPodTemplateImageBuilder(template, pod) {
+			valid = false
+		}
+		if !r.validateAgentPodTemplateResources(template) {
+			valid = false
+		}
+		if !r.validateAgentPodTemplateGPU(template, pod) {
+			valid = false
+		}
+		if !r.validateAgentPodTemplateWorkspaceVolume(template, pod) {
+			valid = false
+		}
+	}
+	if !r.validateAgentPodTemplateLabels() {
+		valid = false
+	}
+
+	return valid, nil
+}
+
+// reservedAgentLabels are labels of Jenkins master node, jobs restricted to them would run in agent pods instead
+var reservedAgentLabels = []string{"master", "built-in"}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplateLabels() bool {
+	valid := true
+	atoms := map[string]bool{}
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		for _, atom := range strings.Fields(template.Label) {
+			atoms[atom] = true
+		}
+	}
+	// templates of the additional namespaces and clusters get labels prefixed by their names
+	var prefixes []string
+	for _, namespace := range r.jenkins.Spec.Agents.AdditionalNamespaces {
+		prefixes = append(prefixes, namespace.Name+"-")
+	}
+	for _, cluster := range r.jenkins.Spec.Agents.Clusters {
+		prefixes = append(prefixes, cluster.Name+"-")
+	}
+
+	labels := map[string]string{}
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		fields := strings.Fields(template.Label)
+		for _, atom := range fields {
+			if containsString(reservedAgentLabels, atom) {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Label '%s' of agent pod template '%s' is reserved for Jenkins master", atom, template.Name))
+				valid = false
+			}
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(atom, prefix) && atoms[strings.TrimPrefix(atom, prefix)] {
+					r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Label '%s' of agent pod template '%s' collides with label of templates in '%s' cloud",
+						atom, template.Name, strings.TrimSuffix(prefix, "-")))
+					valid = false
+				}
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		// Kubernetes plugin picks any of the templates with the same labels
+		sort.Strings(fields)
+		label := strings.Join(fields, " ")
+		if other, found := labels[label]; found {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Agent pod template '%s' has the same label '%s' as agent pod template '%s'",
+				template.Name, label, other))
+			valid = false
+			continue
+		}
+		labels[label] = template.Name
+	}
+
+	return valid
+}
+
+// warnAgentPodTemplate logs validation error of the agent pod template and keeps it for the AgentTemplatesInvalid
+// status condition
+func (r *ReconcileJenkinsBaseConfiguration) warnAgentPodTemplate(templateName, message string) {
+	r.logger.V(log.VWarn).Info(message)
+	if r.agentPodTemplateErrors != nil {
+		r.agentPodTemplateErrors[templateName] = append(r.agentPodTemplateErrors[templateName], message)
+	}
+}
+
+// resolveAgentPodTemplateYamlConfigMap returns the agent pod template with Yaml combined with the pod from the ConfigMap,
+// so the other validations check the pod used by Kubernetes plugin
+func (r *ReconcileJenkinsBaseConfiguration) resolveAgentPodTemplateYamlConfigMap(template virtuslabv1alpha1.AgentPodTemplate) (virtuslabv1alpha1.AgentPodTemplate, bool, error) {
+	allowed := len(template.YamlMergeStrategy) == 0
+	for _, strategy := range virtuslabv1alpha1.AllowedYamlMergeStrategies {
+		if template.YamlMergeStrategy == strategy {
+			allowed = true
+		}
+	}
+	if !allowed {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid yaml merge strategy '%s' of agent pod template '%s', allowed '%+v'",
+			template.YamlMergeStrategy, template.Name, virtuslabv1alpha1.AllowedYamlMergeStrategies))
+		return template, false, nil
+	}
+	if template.YamlMergeStrategy == virtuslabv1alpha1.YamlMergeStrategyOverride && len(template.Yaml) > 0 {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Yaml of agent pod template '%s' is ignored by '%s' yaml merge strategy",
+			template.Name, virtuslabv1alpha1.YamlMergeStrategyOverride))
+		return template, false, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: r.jenkins.Namespace, Name: template.YamlConfigMap.Name}, configMap)
+	if err != nil && errors.IsNotFound(err) {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("ConfigMap '%s' of agent pod template '%s' not found", template.YamlConfigMap.Name, template.Name))
+		return template, false, nil
+	} else if err != nil {
+		return template, false, err
+	}
+
+	resolved, err := resources.ResolveAgentPodTemplateYaml(template, configMap)
+	if err != nil {
+		r.warnAgentPodTemplate(template.Name, err.Error())
+		return template, false, nil
+	}
+
+	return resolved, true, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentsCapacity() bool {
+	valid := true
+	if r.jenkins.Spec.Agents.MaxConcurrent < 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid maximum number of concurrent agents '%d'", r.jenkins.Spec.Agents.MaxConcurrent))
+		valid = false
+	}
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		if template.MaxConcurrent < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid maximum number of concurrent agents '%d' of agent pod template '%s'",
+				template.MaxConcurrent, template.Name))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentsImagePullSecrets() bool {
+	valid := true
+	names := map[string]bool{}
+	for _, secret := range r.jenkins.Spec.Agents.ImagePullSecrets {
+		if errs := validation.IsDNS1123Subdomain(secret.Name); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid agents image pull secret name '%s': %s", secret.Name, strings.Join(errs, ", ")))
+			valid = false
+		}
+		if names[secret.Name] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Agents image pull secret '%s' is used more than once", secret.Name))
+			valid = false
+		}
+		names[secret.Name] = true
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentsRetention() bool {
+	valid := true
+	for _, template := range r.jenkins.Spec.Agents.Templates {
+		if template.IdleMinutes < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Idle minutes of agent pod template '%s' can't be negative", template.Name))
+			valid = false
+		}
+		if template.ConnectionTimeoutSeconds < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Connection timeout of agent pod template '%s' can't be negative", template.Name))
+			valid = false
+		}
+		if len(template.PodRetention) == 0 {
+			continue
+		}
+		allowed := false
+		for _, retention := range virtuslabv1alpha1.AllowedPodRetentions {
+			if template.PodRetention == retention {
+				allowed = true
+			}
+		}
+		if !allowed {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid pod retention '%s' of agent pod template '%s', allowed '%+v'",
+				template.PodRetention, template.Name, virtuslabv1alpha1.AllowedPodRetentions))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateOrphanedAgentPodsGracePeriod() bool {
+	gracePeriod := r.jenkins.Spec.Agents.OrphanedPodsGracePeriod
+	if gracePeriod == nil {
+		return true
+	}
+
+	if gracePeriod.Duration < minOrphanedAgentPodsGracePeriod {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Orphaned agent pods grace period '%s' is shorter than '%s'",
+			gracePeriod.Duration, minOrphanedAgentPodsGracePeriod))
+		return false
+	}
+
+	return true
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentDefaultImage() bool {
+	image := r.jenkins.Spec.Agents.DefaultImage
+	if len(image) == 0 {
+		return true
+	}
+
+	reference, err := docker.Parse(image)
+	if err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid default agent image '%s': %s", image, err))
+		return false
+	}
+	// the image pins remoting version, so it can't float with the default 'latest' tag
+	_, tagged := reference.(docker.Tagged)
+	_, digested := reference.(docker.Digested)
+	if !tagged && !digested {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Default agent image '%s' requires a tag or a digest", image))
+		return false
+	}
+
+	return true
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplatePermissions(template virtuslabv1alpha1.AgentPodTemplate) bool {
+	valid := true
+	for _, rule := range template.Permissions {
+		if len(rule.Verbs) == 0 || len(rule.Resources) == 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Permissions of agent pod template '%s' require verbs and resources", template.Name))
+			valid = false
+		}
+		if len(rule.NonResourceURLs) > 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Permissions of agent pod template '%s' can't contain non-resource URLs", template.Name))
+			valid = false
+		}
+		// the permissions are minimal only when they are listed explicitly
+		if containsString(rule.Verbs, rbacv1.VerbAll) || containsString(rule.Resources, rbacv1.ResourceAll) ||
+			containsString(rule.APIGroups, rbacv1.APIGroupAll) {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Permissions of agent pod template '%s' can't contain wildcards", template.Name))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplateSpot(template virtuslabv1alpha1.AgentPodTemplate) bool {
+	spot := template.Spot
+	if spot == nil {
+		return true
+	}
+
+	valid := true
+	if len(spot.Provider) > 0 {
+		allowed := false
+		for _, provider := range virtuslabv1alpha1.AllowedSpotProviders {
+			if spot.Provider == provider {
+				allowed = true
+			}
+		}
+		if !allowed {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid spot provider '%s' of agent pod template '%s', allowed '%+v'",
+				spot.Provider, template.Name, virtuslabv1alpha1.AllowedSpotProviders))
+			valid = false
+		}
+	} else if len(spot.NodeSelector) == 0 {
+		// without the node selector the pods would be scheduled on any node
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Spot of agent pod template '%s' requires provider or node selector", template.Name))
+		valid = false
+	}
+	if !r.validateNodeSelector(template.Name, "spot node selector", spot.NodeSelector) {
+		valid = false
+	}
+	if !r.validateTolerations(template.Name, "spot toleration", spot.Tolerations) {
+		valid = false
+	}
+	if spot.Retries < 0 {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Spot retries of agent pod template '%s' can't be negative", template.Name))
+		valid = false
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplateScheduling(template virtuslabv1alpha1.AgentPodTemplate) bool {
+	valid := true
+	if !r.validateNodeSelector(template.Name, "node selector", template.NodeSelector) {
+		valid = false
+	}
+	if !r.validateTolerations(template.Name, "toleration", template.Tolerations) {
+		valid = false
+	}
+	if template.Affinity == nil {
+		return valid
+	}
+
+	if nodeAffinity := template.Affinity.NodeAffinity; nodeAffinity != nil {
+		var terms []corev1.NodeSelectorTerm
+		if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+			terms = append(terms, nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms...)
+		}
+		for _, preferred := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if preferred.Weight < 1 || preferred.Weight > 100 {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid node affinity weight '%d' of agent pod template '%s', must be between 1 and 100",
+					preferred.Weight, template.Name))
+				valid = false
+			}
+			terms = append(terms, preferred.Preference)
+		}
+		for _, term := range terms {
+			for _, requirement := range term.MatchExpressions {
+				if errs := validation.IsQualifiedName(requirement.Key); len(errs) > 0 {
+					r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid node affinity key '%s' of agent pod template '%s': %s",
+						requirement.Key, template.Name, strings.Join(errs, ", ")))
+					valid = false
+				}
+			}
+		}
+	}
+
+	var podAffinityTerms []corev1.PodAffinityTerm
+	if podAffinity := template.Affinity.PodAffinity; podAffinity != nil {
+		podAffinityTerms = append(podAffinityTerms, podAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, preferred := range podAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			podAffinityTerms = append(podAffinityTerms, preferred.PodAffinityTerm)
+		}
+	}
+	if podAntiAffinity := template.Affinity.PodAntiAffinity; podAntiAffinity != nil {
+		podAffinityTerms = append(podAffinityTerms, podAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+		for _, preferred := range podAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			podAffinityTerms = append(podAffinityTerms, preferred.PodAffinityTerm)
+		}
+	}
+	for _, term := range podAffinityTerms {
+		if len(term.TopologyKey) == 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Pod affinity of agent pod template '%s' requires topology key", template.Name))
+			valid = false
+		} else if errs := validation.IsQualifiedName(term.TopologyKey); len(errs) > 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid pod affinity topology key '%s' of agent pod template '%s': %s",
+				term.TopologyKey, template.Name, strings.Join(errs, ", ")))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateNodeSelector(templateName, description string, nodeSelector map[string]string) bool {
+	valid := true
+	for key, value := range nodeSelector {
+		errs := append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...)
+		if len(errs) > 0 {
+			r.warnAgentPodTemplate(templateName, fmt.Sprintf("Invalid %s '%s=%s' of agent pod template '%s': %s",
+				description, key, value, templateName, strings.Join(errs, ", ")))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateTolerations(templateName, description string, tolerations []corev1.Toleration) bool {
+	valid := true
+	for _, toleration := range tolerations {
+		if len(toleration.Key) > 0 {
+			if errs := validation.IsQualifiedName(toleration.Key); len(errs) > 0 {
+				r.warnAgentPodTemplate(templateName, fmt.Sprintf("Invalid %s key '%s' of agent pod template '%s': %s",
+					description, toleration.Key, templateName, strings.Join(errs, ", ")))
+				valid = false
+			}
+		} else if toleration.Operator != corev1.TolerationOpExists {
+			r.warnAgentPodTemplate(templateName, fmt.Sprintf("Invalid %s of agent pod template '%s', empty key requires '%s' operator",
+				description, templateName, corev1.TolerationOpExists))
+			valid = false
+		}
+		if toleration.Operator == corev1.TolerationOpExists && len(toleration.Value) > 0 {
+			r.warnAgentPodTemplate(templateName, fmt.Sprintf("Invalid %s '%s' of agent pod template '%s', '%s' operator requires empty value",
+				description, toleration.Key, templateName, corev1.TolerationOpExists))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+// windowsAbsolutePathRegexp matches absolute paths with drive letter, e.g. 'C:\workspace'
+var windowsAbsolutePathRegexp = regexp.MustCompile(`^[a-zA-Z]:\\`)
+
+// linuxShells are commands of Linux images which aren't available in Windows containers
+var linuxShells = []string{"sh", "bash", "cat", "/bin/sh", "/bin/bash", "/bin/cat"}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplateOS(template virtuslabv1alpha1.AgentPodTemplate, pod *corev1.Pod) bool {
+	if len(template.OS) == 0 {
+		return true
+	}
+
+	allowed := false
+	for _, agentOS := range virtuslabv1alpha1.AllowedAgentOSs {
+		if template.OS == agentOS {
+			allowed = true
+		}
+	}
+	if !allowed {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid OS '%s' of agent pod template '%s', allowed '%+v'",
+			template.OS, template.Name, virtuslabv1alpha1.AllowedAgentOSs))
+		return false
+	}
+	if template.OS != virtuslabv1alpha1.AgentOSWindows {
+		return true
+	}
+
+	valid := true
+	// Windows containers don't support host network nor privileged containers
+	if pod.Spec.HostNetwork {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Windows agent pod template '%s' can't use host network", template.Name))
+		valid = false
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.SecurityContext != nil && container.SecurityContext.Privileged != nil && *container.SecurityContext.Privileged {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Container '%s' of Windows agent pod template '%s' can't be privileged",
+				container.Name, template.Name))
+			valid = false
+		}
+		if len(container.Command) > 0 && containsString(linuxShells, container.Command[0]) {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Container '%s' of Windows agent pod template '%s' can't run Linux command '%s', use e.g. 'powershell'",
+				container.Name, template.Name, container.Command[0]))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplateImageBuilder(template virtuslabv1alpha1.AgentPodTemplate, pod *corev1.Pod) bool {
+	builder := template.ImageBuilder
+	if builder == nil {
+		return true
+	}
+
+	allowed := false
+	for _, builderType := range virtuslabv1alpha1.AllowedImageBuilderTypes {
+		if builder.Type == builderType {
+			allowed = true
+		}
+	}
+	if !allowed {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid image builder type '%s' of agent pod template '%s', allowed '%+v'",
+			builder.Type, template.Name, virtuslabv1alpha1.AllowedImageBuilderTypes))
+		return false
+	}
+
+	valid := true
+	if image := builder.Image; len(image) > 0 && !dockerImageRegexp.MatchString(image) && !docker.ReferenceRegexp.MatchString(image) {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid image builder image '%s' of agent pod template '%s'", image, template.Name))
+		valid = false
+	}
+	// the image builder containers are Linux only
+	if template.OS == virtuslabv1alpha1.AgentOSWindows {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Windows agent pod template '%s' can't use image builder", template.Name))
+		valid = false
+	}
+	containerName := resources.GetImageBuilderContainerName(builder.Type)
+	containers := 0
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			containers++
+		}
+	}
+	if containers > 1 {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Yaml of agent pod template '%s' can't contain '%s' container of the image builder",
+			template.Name, containerName))
+		valid = false
+	}
+
+	return valid
+}
+
+// standardResourceNames are the compute resources of containers which aren't extended resources
+var standardResourceNames = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage}
+
+// isExtendedResourceName returns true if the resource name is well-formed extended resource, e.g. 'nvidia.com/gpu', it
+// requires domain prefix outside of kubernetes.io reserved for Kubernetes
+func isExtendedResourceName(name corev1.ResourceName) bool {
+	if !strings.Contains(string(name), "/") || len(validation.IsQualifiedName(string(name))) > 0 {
+		return false
+	}
+	domain := strings.SplitN(string(name), "/", 2)[0]
+	return domain != "kubernetes.io" && !strings.HasSuffix(domain, ".kubernetes.io")
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplateResources(template virtuslabv1alpha1.AgentPodTemplate) bool {
+	valid := true
+	for _, resourceList := range []corev1.ResourceList{template.Resources.Requests, template.Resources.Limits} {
+		for name := range resourceList {
+			standard := false
+			for _, standardName := range standardResourceNames {
+				if name == standardName {
+					standard = true
+				}
+			}
+			if !standard && !strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) && !isExtendedResourceName(name) {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid resource name '%s' of agent pod template '%s'", name, template.Name))
+				valid = false
+			}
+		}
+	}
+	// extended resources can't be overcommitted, Kubernetes rejects pods with different request and limit
+	for name, request := range template.Resources.Requests {
+		if !isExtendedResourceName(name) {
+			continue
+		}
+		if limit, ok := template.Resources.Limits[name]; !ok || limit.Cmp(request) != 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Request of extended resource '%s' of agent pod template '%s' requires the same limit",
+				name, template.Name))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplateGPU(template virtuslabv1alpha1.AgentPodTemplate, pod *corev1.Pod) bool {
+	gpu := template.GPU
+	if gpu == nil {
+		return true
+	}
+
+	valid := true
+	resourceName := resources.GetAgentGPUResourceName(gpu)
+	if !isExtendedResourceName(resourceName) {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid GPU resource name '%s' of agent pod template '%s', it requires domain prefix, e.g. '%s'",
+			resourceName, template.Name, resources.DefaultGPUResourceName))
+		valid = false
+	}
+	if gpu.Count <= 0 {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("GPU count of agent pod template '%s' has to be positive", template.Name))
+		valid = false
+	}
+	if len(gpu.Container) > 0 {
+		found := false
+		for _, container := range pod.Spec.Containers {
+			if container.Name == gpu.Container {
+				found = true
+			}
+		}
+		if !found {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("GPU container '%s' of agent pod template '%s' not found", gpu.Container, template.Name))
+			valid = false
+		}
+	}
+	if len(gpu.RuntimeClassName) > 0 {
+		if errs := validation.IsDNS1123Subdomain(gpu.RuntimeClassName); len(errs) > 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid runtime class name '%s' of agent pod template '%s': %s",
+				gpu.RuntimeClassName, template.Name, strings.Join(errs, ", ")))
+			valid = false
+		}
+	}
+	if len(gpu.DriverCapabilities) > 0 && !resources.IsNVIDIAResource(resourceName) {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Driver capabilities of agent pod template '%s' require NVIDIA GPU resource", template.Name))
+		valid = false
+	}
+	for _, capability := range gpu.DriverCapabilities {
+		if !containsString(resources.NVIDIADriverCapabilities, capability) {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid driver capability '%s' of agent pod template '%s', allowed '%+v'",
+				capability, template.Name, resources.NVIDIADriverCapabilities))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+// agentsNamespacePermissions are the permissions the operator needs to manage RBAC in the separate agents namespace
+var agentsNamespacePermissions = []authorizationv1.ResourceAttributes{
+	{Verb: "create", Resource: "serviceaccounts"},
+	{Verb: "list", Resource: "serviceaccounts"},
+	{Verb: "delete", Resource: "serviceaccounts"},
+	{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "roles"},
+	{Verb: "update", Group: "rbac.authorization.k8s.io", Resource: "roles"},
+	{Verb: "list", Group: "rbac.authorization.k8s.io", Resource: "roles"},
+	{Verb: "delete", Group: "rbac.authorization.k8s.io", Resource: "roles"},
+	{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "rolebindings"},
+	{Verb: "update", Group: "rbac.authorization.k8s.io", Resource: "rolebindings"},
+	{Verb: "list", Group: "rbac.authorization.k8s.io", Resource: "rolebindings"},
+	{Verb: "delete", Group: "rbac.authorization.k8s.io", Resource: "rolebindings"},
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentsNamespace() (bool, error) {
+	valid := true
+	namespaces := map[string]bool{r.jenkins.ObjectMeta.Namespace: true, resources.GetAgentsNamespace(r.jenkins): true}
+	for _, additional := range r.jenkins.Spec.Agents.AdditionalNamespaces {
+		if namespaces[additional.Name] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Additional agents namespace '%s' is Jenkins namespace, agents namespace or is used more than once",
+				additional.Name))
+			valid = false
+		}
+		namespaces[additional.Name] = true
+		if additional.MaxConcurrent < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Max concurrent agents of additional agents namespace '%s' can't be negative", additional.Name))
+			valid = false
+		}
+	}
+	if !valid {
+		return false, nil
+	}
+
+	for _, namespace := range resources.GetAllAgentsNamespaces(r.jenkins) {
+		if namespace == r.jenkins.ObjectMeta.Namespace {
+			continue
+		}
+		namespaceValid, err := r.validateSeparateAgentsNamespace(namespace)
+		if !namespaceValid || err != nil {
+			return namespaceValid, err
+		}
+	}
+
+	return true, nil
+}
+
+// validateSeparateAgentsNamespace checks if the namespace exists and the operator can manage RBAC resources in it
+func (r *ReconcileJenkinsBaseConfiguration) validateSeparateAgentsNamespace(namespace string) (bool, error) {
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid agents namespace '%s': %s", namespace, strings.Join(errs, ", ")))
+		return false, nil
+	}
+
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Name: namespace}, &corev1.Namespace{})
+	if err != nil && errors.IsNotFound(err) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Agents namespace '%s' not found", namespace))
+		return false, nil
+	} else if err != nil && errors.IsForbidden(err) {
+		r.logger.V(log.VDebug).Info(fmt.Sprintf("Agents namespace '%s' can't be verified: %s", namespace, err))
+	} else if err != nil {
+		return false, err
+	}
+
+	permissions := append([]authorizationv1.ResourceAttributes{}, agentsNamespacePermissions...)
+	if r.jenkins.Spec.Agents.OrphanedPodsGracePeriod != nil {
+		permissions = append(permissions, orphanedAgentPodsPermissions...)
+	}
+	valid := true
+	for _, permission := range permissions {
+		permission.Namespace = namespace
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &permission},
+		}
+		if err := r.k8sClient.Create(context.TODO(), review); err != nil {
+			return false, err
+		}
+		if !review.Status.Allowed {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Operator is not allowed to %s %s in agents namespace '%s'",
+				permission.Verb, permission.Resource, namespace))
+			valid = false
+		}
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentsClusters() (bool, error) {
+	valid := true
+	names := map[string]bool{}
+	for _, cluster := range r.jenkins.Spec.Agents.Clusters {
+		if errs := validation.IsDNS1123Label(cluster.Name); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid agents cluster name '%s': %s", cluster.Name, strings.Join(errs, ", ")))
+			valid = false
+		}
+		// the names are used by Kubernetes clouds of Jenkins and of the additional agents namespaces
+		if names[cluster.Name] || cluster.Name == "kubernetes" || strings.HasPrefix(cluster.Name, "kubernetes-") {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Agents cluster name '%s' is used more than once or is reserved", cluster.Name))
+			valid = false
+		}
+		names[cluster.Name] = true
+		if errs := validation.IsDNS1123Label(cluster.Namespace); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid namespace '%s' of agents cluster '%s': %s", cluster.Namespace, cluster.Name,
+				strings.Join(errs, ", ")))
+			valid = false
+		}
+		if jenkinsURL, err := url.ParseRequestURI(cluster.JenkinsURL); err != nil || (jenkinsURL.Scheme != "http" && jenkinsURL.Scheme != "https") {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Jenkins URL '%s' of agents cluster '%s'", cluster.JenkinsURL, cluster.Name))
+			valid = false
+		}
+		if len(cluster.JenkinsTunnel) > 0 {
+			if _, _, err := net.SplitHostPort(cluster.JenkinsTunnel); err != nil {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Jenkins tunnel '%s' of agents cluster '%s', expected 'host:port'",
+					cluster.JenkinsTunnel, cluster.Name))
+				valid = false
+			}
+		}
+		if len(cluster.ServiceAccountName) > 0 {
+			if errs := validation.IsDNS1123Subdomain(cluster.ServiceAccountName); len(errs) > 0 {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid service account '%s' of agents cluster '%s': %s", cluster.ServiceAccountName,
+					cluster.Name, strings.Join(errs, ", ")))
+				valid = false
+			}
+		}
+		if cluster.MaxConcurrent < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Max concurrent agents of agents cluster '%s' can't be negative", cluster.Name))
+			valid = false
+		}
+		if !valid {
+			continue
+		}
+
+		secretValid, err := r.validateSecretKeys(fmt.Sprintf("Agents cluster '%s' kubeconfig", cluster.Name), cluster.KubeconfigSecretName,
+			resources.AgentsClusterKubeconfigSecretKey)
+		if err != nil {
+			return false, err
+		}
+		if !secretValid {
+			valid = false
+			continue
+		}
+		secret := &corev1.Secret{}
+		err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: r.jenkins.Namespace, Name: cluster.KubeconfigSecretName}, secret)
+		if err != nil {
+			return false, err
+		}
+		denied, err := verifyAgentsClusterAccess(cluster, secret.Data[resources.AgentsClusterKubeconfigSecretKey])
+		if err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Agents cluster '%s' isn't reachable: %s", cluster.Name, err))
+			valid = false
+			continue
+		}
+		for _, permission := range denied {
+			resource := permission.Resource
+			if len(permission.Subresource) > 0 {
+				resource += "/" + permission.Subresource
+			}
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Kubeconfig of agents cluster '%s' doesn't allow to %s %s in namespace '%s'",
+				cluster.Name, permission.Verb, resource, cluster.Namespace))
+			valid = false
+		}
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateVMClouds() (bool, error) {
+	valid := true
+	names := map[string]bool{}
+	for _, cluster := range r.jenkins.Spec.Agents.Clusters {
+		names[cluster.Name] = true
+	}
+	for _, cloud := range r.jenkins.Spec.Agents.VMClouds {
+		if errs := validation.IsDNS1123Label(cloud.Name); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid VM cloud name '%s': %s", cloud.Name, strings.Join(errs, ", ")))
+			valid = false
+		}
+		// the names are used by Kubernetes clouds as well
+		if names[cloud.Name] || cloud.Name == "kubernetes" || strings.HasPrefix(cloud.Name, "kubernetes-") {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("VM cloud name '%s' is used more than once or is reserved", cloud.Name))
+			valid = false
+		}
+		names[cloud.Name] = true
+
+		pluginName, allowed := resources.VMCloudPluginNames[cloud.Provider]
+		if !allowed {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid provider '%s' of VM cloud '%s', allowed '%+v'",
+				cloud.Provider, cloud.Name, virtuslabv1alpha1.AllowedVMCloudProviders))
+			valid = false
+			continue
+		}
+		if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, pluginName) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by VM cloud '%s', please add it to 'spec.master.plugins'",
+				pluginName, cloud.Name))
+			valid = false
+		}
+		switch cloud.Provider {
+		case virtuslabv1alpha1.VMCloudProviderEC2:
+			if !awsRegionRegexp.MatchString(cloud.Region) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid AWS region '%s' of VM cloud '%s'", cloud.Region, cloud.Name))
+				valid = false
+			}
+			if len(cloud.Project) > 0 {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Project of VM cloud '%s' requires '%s' provider", cloud.Name, virtuslabv1alpha1.VMCloudProviderGCE))
+				valid = false
+			}
+		case virtuslabv1alpha1.VMCloudProviderGCE:
+			if !gcpProjectIDRegexp.MatchString(cloud.Project) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Google Cloud project '%s' of VM cloud '%s'", cloud.Project, cloud.Name))
+				valid = false
+			}
+			// the region of gce clouds is given by zones of the templates
+			if len(cloud.Region) > 0 {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Region of VM cloud '%s' requires '%s' provider", cloud.Name, virtuslabv1alpha1.VMCloudProviderEC2))
+				valid = false
+			}
+		}
+		if cloud.MaxInstances < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Max instances of VM cloud '%s' can't be negative", cloud.Name))
+			valid = false
+		}
+		if len(cloud.Templates) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("VM cloud '%s' requires at least one template", cloud.Name))
+			valid = false
+		}
+		if !r.validateVMAgentTemplates(cloud) {
+			valid = false
+		}
+		if len(cloud.CredentialsSecretName) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("VM cloud '%s' requires credentials Secret", cloud.Name))
+			valid = false
+			continue
+		}
+
+		secretValid, err := r.validateSecretKeys(fmt.Sprintf("VM cloud '%s' credentials", cloud.Name), cloud.CredentialsSecretName,
+			resources.GetVMCloudSecretKeys(cloud.Provider)...)
+		if err != nil {
+			return false, err
+		}
+		valid = valid && secretValid
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateVMAgentTemplates(cloud virtuslabv1alpha1.VMCloud) bool {
+	valid := true
+	names := map[string]bool{}
+	for _, vmTemplate := range cloud.Templates {
+		if errs := validation.IsDNS1123Label(vmTemplate.Name); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid template name '%s' of VM cloud '%s': %s", vmTemplate.Name, cloud.Name,
+				strings.Join(errs, ", ")))
+			valid = false
+		}
+		if names[vmTemplate.Name] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Template name '%s' of VM cloud '%s' is used more than once", vmTemplate.Name, cloud.Name))
+			valid = false
+		}
+		names[vmTemplate.Name] = true
+
+		switch cloud.Provider {
+		case virtuslabv1alpha1.VMCloudProviderEC2:
+			if !amiIDRegexp.MatchString(vmTemplate.Image) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid AMI ID '%s' of template '%s' of VM cloud '%s'", vmTemplate.Image,
+					vmTemplate.Name, cloud.Name))
+				valid = false
+			}
+			if len(vmTemplate.Zone) > 0 && !strings.HasPrefix(vmTemplate.Zone, cloud.Region) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Availability zone '%s' of template '%s' isn't in region '%s' of VM cloud '%s'",
+					vmTemplate.Zone, vmTemplate.Name, cloud.Region, cloud.Name))
+				valid = false
+			}
+		case virtuslabv1alpha1.VMCloudProviderGCE:
+			if !gceImageRegexp.MatchString(vmTemplate.Image) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid image '%s' of template '%s' of VM cloud '%s', expected 'projects/<project>/global/images/<image>'",
+					vmTemplate.Image, vmTemplate.Name, cloud.Name))
+				valid = false
+			}
+			if !gceZoneRegexp.MatchString(vmTemplate.Zone) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid zone '%s' of template '%s' of VM cloud '%s'", vmTemplate.Zone,
+					vmTemplate.Name, cloud.Name))
+				valid = false
+			}
+			if len(vmTemplate.Subnet) > 0 {
+				if errs := validation.IsDNS1123Label(vmTemplate.Subnet); len(errs) > 0 {
+					r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid subnet '%s' of template '%s' of VM cloud '%s': %s", vmTemplate.Subnet,
+						vmTemplate.Name, cloud.Name, strings.Join(errs, ", ")))
+					valid = false
+				}
+			}
+		}
+		if len(vmTemplate.MachineType) == 0 || strings.IndexFunc(vmTemplate.MachineType, unicode.IsSpace) >= 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid machine type '%s' of template '%s' of VM cloud '%s'", vmTemplate.MachineType,
+				vmTemplate.Name, cloud.Name))
+			valid = false
+		}
+		for _, label := range vmTemplate.Labels {
+			if len(label) == 0 || strings.IndexFunc(label, unicode.IsSpace) >= 0 || strings.IndexFunc(label, unicode.IsControl) >= 0 {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid label '%s' of template '%s' of VM cloud '%s'", label, vmTemplate.Name, cloud.Name))
+				valid = false
+			}
+		}
+		if vmTemplate.Executors < 0 || vmTemplate.MaxInstances < 0 || vmTemplate.IdleMinutes < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Executors, max instances and idle minutes of template '%s' of VM cloud '%s' can't be negative",
+				vmTemplate.Name, cloud.Name))
+			valid = false
+		}
+		if strings.IndexFunc(vmTemplate.RemoteUser, unicode.IsSpace) >= 0 || strings.IndexFunc(vmTemplate.WorkDir, unicode.IsControl) >= 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid remote user or work directory of template '%s' of VM cloud '%s'",
+				vmTemplate.Name, cloud.Name))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateStaticAgents() (bool, error) {
+	agents := r.jenkins.Spec.Agents.Static
+	if len(agents) == 0 {
+		return true, nil
+	}
+
+	valid := true
+	if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.SSHAgentsPluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.agents.static', please add it to 'spec.master.plugins'",
+			resources.SSHAgentsPluginName))
+		valid = false
+	}
+	names := map[string]bool{}
+	for _, agent := range agents {
+		if errs := validation.IsDNS1123Label(agent.Name); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid static agent name '%s': %s", agent.Name, strings.Join(errs, ", ")))
+			valid = false
+		}
+		if names[agent.Name] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Static agent name '%s' is used more than once", agent.Name))
+			valid = false
+		}
+		names[agent.Name] = true
+		if net.ParseIP(agent.Host) == nil && len(validation.IsDNS1123Subdomain(agent.Host)) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid host '%s' of static agent '%s', expected host name or IP address", agent.Host, agent.Name))
+			valid = false
+		}
+		if agent.Port < 0 || agent.Port > 65535 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid port '%d' of static agent '%s'", agent.Port, agent.Name))
+			valid = false
+		}
+		if agent.Executors < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid number of executors '%d' of static agent '%s'", agent.Executors, agent.Name))
+			valid = false
+		}
+		if len(agent.HostKey) > 0 && !sshHostKeyRegexp.MatchString(agent.HostKey) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid host key of static agent '%s', expected '<type> <base64 key>'", agent.Name))
+			valid = false
+		}
+		for _, label := range agent.Labels {
+			if len(label) == 0 || strings.IndexFunc(label, unicode.IsSpace) >= 0 || strings.IndexFunc(label, unicode.IsControl) >= 0 {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid label '%s' of static agent '%s'", label, agent.Name))
+				valid = false
+			}
+		}
+		if strings.IndexFunc(agent.WorkDir, unicode.IsControl) >= 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Work directory of static agent '%s' can't contain control characters", agent.Name))
+			valid = false
+		}
+		if len(agent.CredentialsSecretName) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Static agent '%s' requires credentials Secret", agent.Name))
+			valid = false
+			continue
+		}
+
+		secretValid, err := r.validateSecretKeys(fmt.Sprintf("Static agent '%s' credentials", agent.Name), agent.CredentialsSecretName,
+			resources.StaticAgentUserNameSecretKey, resources.StaticAgentPrivateKeySecretKey)
+		if err != nil {
+			return false, err
+		}
+		valid = valid && secretValid
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validatePlugins(pluginsWithVersions map[string][]string) bool {
+	valid := true
+	allPlugins := map[string][]plugins.Plugin{}
+
+	for rootPluginName, dependentPluginNames := range pluginsWithVersions {
+		if _, err := plugins.New(rootPluginName); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid root plugin name '%s'", rootPluginName))
+			valid = false
+		}
+
+		dependentPlugins := []plugins.Plugin{}
+		for _, pluginName := range dependentPluginNames {
+			if p, err := plugins.New(pluginName); err != nil {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid dependent plugin name '%s' in root plugin '%s'", pluginName, rootPluginName))
+				valid = false
+			} else {
+				dependentPlugins = append(dependentPlugins, *p)
+			}
+		}
+
+		allPlugins[rootPluginName] = dependentPlugins
+	}
+
+	if valid {
+		return plugins.VerifyDependencies(allPlugins)
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateBaseScripts() (bool, error) {
+	valid := true
+	for _, name := range r.jenkins.Spec.Configuration.DisabledBaseScripts {
+		if !resources.IsBaseConfigurationScriptName(name) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Unknown base script '%s' in 'spec.configuration.disabledBaseScripts', allowed '%+v'",
+				name, resources.GetBaseConfigurationScriptNames()))
+			valid = false
+		}
+	}
+
+	reference := r.jenkins.Spec.Configuration.BaseScriptsOverride
+	if reference == nil {
+		return valid, nil
+	}
+
+	overrideConfigMap := &corev1.ConfigMap{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: r.jenkins.Namespace, Name: reference.Name}, overrideConfigMap)
+	if err != nil && errors.IsNotFound(err) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("ConfigMap '%s' from 'spec.configuration.baseScriptsOverride' not found", reference.Name))
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for name := range overrideConfigMap.Data {
+		if !resources.IsBaseConfigurationScriptName(name) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Unknown base script '%s' in ConfigMap '%s', allowed '%+v'",
+				name, reference.Name, resources.GetBaseConfigurationScriptNames()))
+			valid = false
+		}
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateSharedLibraries() bool {
+	sharedLibraries := r.jenkins.Spec.Configuration.SharedLibraries
+	if len(sharedLibraries) == 0 {
+		return true
+	}
+
+	valid := true
+	if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.ConfigurationAsCodePluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.configuration.sharedLibraries', please add it to 'spec.master.plugins'",
+			resources.ConfigurationAsCodePluginName))
+		valid = false
+	}
+
+	names := map[string]bool{}
+	for _, library := range sharedLibraries {
+		if len(library.Name) == 0 {
+			r.logger.V(log.VWarn).Info("Shared library name can't be empty")
+			valid = false
+		}
+		if len(library.RepositoryURL) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Shared library '%s' repository url can't be empty", library.Name))
+			valid = false
+		}
+		key := library.Folder + "/" + library.Name
+		if names[key] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Duplicated shared library '%s' in folder '%s'", library.Name, library.Folder))
+			valid = false
+		}
+		names[key] = true
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateTools() bool {
+	valid := true
+	names := map[string]bool{}
+	for _, tool := range r.jenkins.Spec.Master.Tools {
+		allowed := false
+		for _, toolType := range virtuslabv1alpha1.AllowedToolTypes {
+			if tool.Type == toolType {
+				allowed = true
+			}
+		}
+		if !allowed {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid tool type '%s', allowed '%+v'", tool.Type, virtuslabv1alpha1.AllowedToolTypes))
+			valid = false
+		}
+		if len(tool.Name) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Tool name can't be empty, type '%s'", tool.Type))
+			valid = false
+		}
+		if len(tool.Version) == 0 && len(tool.Home) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Tool '%s' requires version or home", tool.Name))
+			valid = false
+		}
+		key := string(tool.Type) + "/" + tool.Name
+		if names[key] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Duplicated tool '%s' of type '%s'", tool.Name, tool.Type))
+			valid = false
+		}
+		names[key] = true
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateScriptApprovals() bool {
+	valid := true
+	for _, signature := range r.jenkins.Spec.Master.ScriptApprovals.Signatures {
+		if !scriptSignatureRegexp.MatchString(signature) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid script approval signature '%s'", signature))
+			valid = false
+		}
+	}
+	for _, hash := range r.jenkins.Spec.Master.ScriptApprovals.ScriptHashes {
+		if !scriptHashRegexp.MatchString(hash) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid script approval hash '%s'", hash))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateGlobalEnv() bool {
+	valid := true
+	for name := range r.jenkins.Spec.Master.GlobalEnv {
+		if !envNameRegexp.MatchString(name) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid global environment variable name '%s'", name))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateExecutors() bool {
+	valid := true
+	master := r.jenkins.Spec.Master
+	if master.Executors != nil && *master.Executors < 1 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid number of master executors '%d', at least one is required by operator jobs, "+
+			"use 'exclusive' mode to keep other builds away from master", *master.Executors))
+		valid = false
+	}
+	if len(master.Mode) > 0 {
+		allowed := false
+		for _, mode := range virtuslabv1alpha1.AllowedMasterModes {
+			if master.Mode == mode {
+				allowed = true
+			}
+		}
+		if !allowed {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid master mode '%s', allowed '%+v'", master.Mode, virtuslabv1alpha1.AllowedMasterModes))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateBuildSettings() bool {
+	settings := r.jenkins.Spec.Master.BuildSettings
+	valid := true
+	if settings.QuietPeriod != nil && *settings.QuietPeriod < 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid quiet period '%d', it can't be negative", *settings.QuietPeriod))
+		valid = false
+	}
+	if settings.SCMCheckoutRetryCount != nil && *settings.SCMCheckoutRetryCount < 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid SCM checkout retry count '%d', it can't be negative", *settings.SCMCheckoutRetryCount))
+		valid = false
+	}
+	if discarder := settings.BuildDiscarder; discarder != nil {
+		if discarder.DaysToKeep < 0 || discarder.NumToKeep < 0 || discarder.ArtifactDaysToKeep < 0 || discarder.ArtifactNumToKeep < 0 {
+			r.logger.V(log.VWarn).Info("Invalid build discarder, limits can't be negative")
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateRemoting() bool {
+	remoting := r.jenkins.Spec.Master.Remoting
+	valid := true
+	if remoting.CLIRemotingEnabled && !remoting.CLIEnabled {
+		r.logger.V(log.VWarn).Info("CLI over remoting requires 'spec.master.remoting.cliEnabled'")
+		valid = false
+	}
+	for _, protocol := range remoting.AgentProtocols {
+		allowed := false
+		for _, allowedProtocol := range virtuslabv1alpha1.AllowedAgentProtocols {
+			if protocol == allowedProtocol {
+				allowed = true
+			}
+		}
+		if !allowed {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid agent protocol '%s', allowed '%+v'", protocol, virtuslabv1alpha1.AllowedAgentProtocols))
+			valid = false
+		}
+	}
+	if remoting.SSHD != nil && remoting.SSHD.Port != 0 {
+		// Jenkins master container doesn't run as root, so it can't bind privileged ports
+		if remoting.SSHD.Port < 1024 || remoting.SSHD.Port > 65535 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.master.remoting.sshd.port' %d, must be between 1024 and 65535", remoting.SSHD.Port))
+			valid = false
+		} else if resources.IsReservedPort(remoting.SSHD.Port) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.master.remoting.sshd.port' %d, the port is already used by Jenkins master", remoting.SSHD.Port))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateMarkupFormatter() bool {
+	formatter := r.jenkins.Spec.Master.MarkupFormatter
+	if len(formatter) == 0 {
+		return true
+	}
+
+	allowed := false
+	for _, allowedFormatter := range virtuslabv1alpha1.AllowedMarkupFormatters {
+		if formatter == allowedFormatter {
+			allowed = true
+		}
+	}
+	if !allowed {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid markup formatter '%s', allowed '%+v'", formatter, virtuslabv1alpha1.AllowedMarkupFormatters))
+		return false
+	}
+
+	if formatter == virtuslabv1alpha1.MarkupFormatterSafeHTML &&
+		!isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.AntisamyMarkupFormatterPluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by '%s' markup formatter, please add it to 'spec.master.plugins'",
+			resources.AntisamyMarkupFormatterPluginName, formatter))
+		return false
+	}
+
+	return true
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateTheme() bool {
+	theme := r.jenkins.Spec.Master.Theme
+	valid := true
+	for name, value := range map[string]string{
+		"logoURL":    theme.LogoURL,
+		"faviconURL": theme.FaviconURL,
+		"cssURL":     theme.CSSURL,
+		"jsURL":      theme.JSURL,
+	} {
+		if len(value) == 0 {
+			continue
+		}
+		if _, err := url.ParseRequestURI(value); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid theme %s '%s': %s", name, value, err))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateResponseHeaders() bool {
+	valid := true
+	headers := map[string]map[string]string{"spec.master.responseHeaders": r.jenkins.Spec.Master.ResponseHeaders}
+	if ingress := r.jenkins.Spec.Service.Ingress; ingress != nil {
+		headers["spec.service.ingress.responseHeaders"] = ingress.ResponseHeaders
+	}
+	for field, fieldHeaders := range headers {
+		for name, value := range fieldHeaders {
+			if !headerNameRegexp.MatchString(name) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid '%s' header name '%s'", field, name))
+				valid = false
+			}
+			if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("'%s' header '%s' value can't contain control characters, e.g. new lines", field, name))
+				valid = false
+			}
+		}
+	}
+	if ingress := r.jenkins.Spec.Service.Ingress; ingress != nil {
+		for name, value := range ingress.ResponseHeaders {
+			// the value is quoted in nginx configuration
+			if strings.ContainsAny(value, `"\`) {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.ingress.responseHeaders' header '%s' value can't contain '\"' and '\\'", name))
+				valid = false
+			}
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateContentSecurityPolicy() bool {
+	valid := true
+	if contentSecurityPolicy := r.jenkins.Spec.Master.ContentSecurityPolicy; contentSecurityPolicy != nil &&
+		strings.IndexFunc(*contentSecurityPolicy, unicode.IsControl) >= 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Content security policy '%s' can't contain control characters, e.g. new lines", *contentSecurityPolicy))
+		valid = false
+	}
+
+	resourceRootURL := r.jenkins.Spec.Master.ResourceRootURL
+	if len(resourceRootURL) == 0 {
+		return valid
+	}
+	parsedURL, err := url.Parse(resourceRootURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || len(parsedURL.Host) == 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid resource root URL '%s', expected absolute http or https URL", resourceRootURL))
+		valid = false
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateVault() (bool, error) {
+	vault := r.jenkins.Spec.Vault
+	if vault == nil {
+		return true, nil
+	}
+
+	valid := true
+	if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.VaultPluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.vault', please add it to 'spec.master.plugins'",
+			resources.VaultPluginName))
+		valid = false
+	}
+	if len(vault.Paths) > 0 && !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.ConfigurationAsCodePluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.vault.paths', please add it to 'spec.master.plugins'",
+			resources.ConfigurationAsCodePluginName))
+		valid = false
+	}
+	if len(vault.Address) == 0 {
+		r.logger.V(log.VWarn).Info("Vault address can't be empty")
+		valid = false
+	}
+	if vault.EngineVersion < 0 || vault.EngineVersion > 2 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Vault KV engine version '%d', allowed 1 or 2", vault.EngineVersion))
+		valid = false
+	}
+
+	switch vault.AuthMethod {
+	case virtuslabv1alpha1.VaultAuthMethodToken, virtuslabv1alpha1.VaultAuthMethodAppRole:
+		if len(vault.SecretName) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Vault secretName is required by '%s' auth method", vault.AuthMethod))
+			return false, nil
+		}
+	case virtuslabv1alpha1.VaultAuthMethodKubernetes:
+		if len(vault.Role) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Vault role is required by '%s' auth method", vault.AuthMethod))
+			valid = false
+		}
+		return valid, nil
+	default:
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Vault auth method '%s', allowed '%+v'",
+			vault.AuthMethod, virtuslabv1alpha1.AllowedVaultAuthMethods))
+		return false, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: r.jenkins.Namespace, Name: vault.SecretName}, secret)
+	if err != nil && errors.IsNotFound(err) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Vault Secret '%s' not found", vault.SecretName))
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	for _, key := range resources.RequiredVaultSecretKeys(vault.AuthMethod) {
+		if len(secret.Data[key]) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Vault Secret '%s' doesn't contain '%s' key required by '%s' auth method",
+				vault.SecretName, key, vault.AuthMethod))
+			valid = false
+		}
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateTLS() (bool, error) {
+	masterTLS := r.jenkins.Spec.Master.TLS
+	if masterTLS == nil {
+		return true, nil
+	}
+
+	if len(masterTLS.SecretName) == 0 {
+		r.logger.V(log.VWarn).Info("Jenkins master TLS Secret name not set")
+		return false, nil
+	}
+	valid := true
+	if masterTLS.InsecureSkipVerify && len(masterTLS.CASecretName) > 0 {
+		r.logger.V(log.VWarn).Info("'spec.master.tls.caSecretName' can't be used with 'spec.master.tls.insecureSkipVerify'")
+		valid = false
+	}
+	if len(masterTLS.CASecretName) > 0 {
+		caValid, err := r.validateSecretKeys("Jenkins master TLS CA", masterTLS.CASecretName, resources.TLSCASecretKey)
+		if err != nil {
+			return false, err
+		}
+		valid = valid && caValid
+	}
+	if masterTLS.IssuerRef != nil {
+		// the Secret is created by cert-manager with the certificate valid for the Jenkins master Service DNS names
+		return r.validateCertManagerIssuerRef(masterTLS.IssuerRef) && valid, nil
+	}
+
+	secretValid, err := r.validateSecretKeys("Jenkins master TLS", masterTLS.SecretName,
+		resources.TLSCertificateSecretKey, resources.TLSPrivateKeySecretKey)
+	if !secretValid || err != nil {
+		return false, err
+	}
+
+	secret := &corev1.Secret{}
+	err = r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: r.jenkins.Namespace, Name: masterTLS.SecretName}, secret)
+	if err != nil {
+		return false, err
+	}
+	keyPair, err := tls.X509KeyPair(secret.Data[resources.TLSCertificateSecretKey], secret.Data[resources.TLSPrivateKeySecretKey])
+	if err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Jenkins master TLS Secret '%s': %s", masterTLS.SecretName, err))
+		return false, nil
+	}
+	certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Jenkins master TLS Secret '%s' certificate: %s", masterTLS.SecretName, err))
+		return false, nil
+	}
+	if resources.IsFIPSModeEnabled(r.jenkins) && !resources.IsFIPSApprovedPublicKey(certificate.PublicKey) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Jenkins master TLS Secret '%s' certificate key isn't FIPS approved, "+
+			"RSA key of at least %d bits or ECDSA key on P-256, P-384 or P-521 curve is required", masterTLS.SecretName, resources.FIPSMinRSAKeySize))
+		valid = false
+	}
+	dnsName := resources.GetJenkinsMasterServiceDNSName(r.jenkins)
+	if err = certificate.VerifyHostname(dnsName); err != nil && !masterTLS.InsecureSkipVerify {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Jenkins master TLS Secret '%s' certificate isn't valid for '%s': %s",
+			masterTLS.SecretName, dnsName, err))
+		valid = false
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateHTTPSKeystore() (bool, error) {
+	keystore := r.jenkins.Spec.Master.HTTPSKeystore
+	if keystore == nil {
+		return true, nil
+	}
+
+	if len(keystore.SecretName) == 0 {
+		r.logger.V(log.VWarn).Info("Jenkins master HTTPS keystore Secret name not set")
+		return false, nil
+	}
+	valid := true
+	if r.jenkins.Spec.Master.TLS != nil {
+		r.logger.V(log.VWarn).Info("'spec.master.httpsKeystore' can't be used together with 'spec.master.tls'")
+		valid = false
+	}
+	if keystore.InsecureSkipVerify && len(keystore.CASecretName) > 0 {
+		r.logger.V(log.VWarn).Info("'spec.master.httpsKeystore.caSecretName' can't be used with 'spec.master.httpsKeystore.insecureSkipVerify'")
+		valid = false
+	}
+	if len(keystore.CASecretName) > 0 {
+		caValid, err := r.validateSecretKeys("Jenkins master HTTPS keystore CA", keystore.CASecretName, resources.TLSCASecretKey)
+		if err != nil {
+			return false, err
+		}
+		valid = valid && caValid
+	}
+
+	// the keystore format and the certificate can't be verified without the password, Jenkins fails to start then
+	secretValid, err := r.validateSecretKeys("Jenkins master HTTPS keystore", keystore.SecretName,
+		resources.GetHTTPSKeystoreSecretKey(r.jenkins), resources.GetHTTPSKeystorePasswordSecretKey(r.jenkins))
+	return valid && secretValid, err
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateRoute() (bool, error) {
+	route := r.jenkins.Spec.Service.Route
+	if route == nil {
+		return true, nil
+	}
+	if !r.openshift {
+		r.logger.V(log.VWarn).Info("'spec.service.route' requires OpenShift, use 'spec.service.ingress' instead")
+		return false, nil
+	}
+
+	valid := true
+	if r.jenkins.Spec.Service.Ingress != nil {
+		r.logger.V(log.VWarn).Info("'spec.service.route' and 'spec.service.ingress' can't be used together")
+		valid = false
+	}
+	if len(route.Host) > 0 {
+		if errs := validation.IsDNS1123Subdomain(route.Host); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Route host '%s': %s", route.Host, strings.Join(errs, ", ")))
+			valid = false
+		}
+		if resourceRootURL, err := url.Parse(r.jenkins.Spec.Master.ResourceRootURL); err == nil && resourceRootURL.Hostname() == route.Host {
+			r.logger.V(log.VWarn).Info("Resource root URL has to use different host than Route, otherwise it isn't isolated from Jenkins UI")
+			valid = false
+		}
+	}
+
+	termination := resources.GetRouteTermination(r.jenkins)
+	allowed := false
+	for _, allowedTermination := range virtuslabv1alpha1.AllowedRouteTerminations {
+		if termination == allowedTermination {
+			allowed = true
+		}
+	}
+	if !allowed {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Route termination '%s', allowed '%+v'", termination, virtuslabv1alpha1.AllowedRouteTerminations))
+		return false, nil
+	}
+	if termination != virtuslabv1alpha1.RouteTerminationEdge && !resources.IsTLSEnabled(r.jenkins) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Route termination '%s' requires 'spec.master.tls'", termination))
+		valid = false
+	}
+	if len(route.SecretName) == 0 {
+		return valid, nil
+	}
+	if termination == virtuslabv1alpha1.RouteTerminationPassthrough {
+		r.logger.V(log.VWarn).Info("Route Secret can't be used with passthrough termination, Jenkins master certificate is served")
+		return false, nil
+	}
+
+	secretValid, err := r.validateSecretKeys("Route TLS", route.SecretName,
+		resources.TLSCertificateSecretKey, resources.TLSPrivateKeySecretKey)
+	return valid && secretValid, err
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateCertManagerIssuerRef(issuerRef *virtuslabv1alpha1.CertManagerIssuerReference) bool {
+	if len(issuerRef.Name) == 0 {
+		r.logger.V(log.VWarn).Info("cert-manager issuer name can't be empty")
+		return false
+	}
+	if len(issuerRef.Group) > 0 && issuerRef.Group != certmanagerv1.SchemeGroupVersion.Group {
+		// external issuers define their own kinds
+		return true
+	}
+	if len(issuerRef.Kind) > 0 && issuerRef.Kind != certmanagerv1.IssuerKind && issuerRef.Kind != certmanagerv1.ClusterIssuerKind {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid cert-manager issuer kind '%s', allowed '%s' and '%s'",
+			issuerRef.Kind, certmanagerv1.IssuerKind, certmanagerv1.ClusterIssuerKind))
+		return false
+	}
+
+	return true
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateArtifactManager() (bool, error) {
+	if r.jenkins.Spec.ArtifactManager == nil || r.jenkins.Spec.ArtifactManager.AmazonS3 == nil {
+		return true, nil
+	}
+
+	s3 := r.jenkins.Spec.ArtifactManager.AmazonS3
+	valid := true
+	if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.ArtifactManagerS3PluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.artifactManager.amazonS3', please add it to 'spec.master.plugins'",
+			resources.ArtifactManagerS3PluginName))
+		valid = false
+	}
+	if !s3BucketNameRegexp.MatchString(s3.BucketName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid artifact manager S3 bucket name '%s'", s3.BucketName))
+		valid = false
+	}
+	if len(s3.Prefix) > 0 && !strings.HasSuffix(s3.Prefix, "/") {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Artifact manager S3 prefix '%s' has to end with '/'", s3.Prefix))
+		valid = false
+	}
+	if len(s3.CredentialsSecretName) == 0 {
+		return valid, nil
+	}
+
+	secretValid, err := r.validateSecretKeys("Artifact manager", s3.CredentialsSecretName,
+		resources.ArtifactManagerS3AccessKeyIDSecretKey, resources.ArtifactManagerS3SecretAccessKeySecretKey)
+	return valid && secretValid, err
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateLDAP() (bool, error) {
+	ldap := r.jenkins.Spec.Security.LDAP
+	if ldap == nil {
+		return true, nil
+	}
+
+	valid := true
+	if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.LDAPPluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.security.ldap', please add it to 'spec.master.plugins'",
+			resources.LDAPPluginName))
+		valid = false
+	}
+	if server, err := url.Parse(ldap.Server); err != nil || (server.Scheme != "ldap" && server.Scheme != "ldaps") || len(server.Host) == 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid LDAP server '%s', expected ldap://host[:port] or ldaps://host[:port]", ldap.Server))
+		valid = false
+	}
+	if len(ldap.BindSecretName) == 0 {
+		return valid, nil
+	}
+
+	secretValid, err := r.validateSecretKeys("LDAP bind", ldap.BindSecretName,
+		resources.LDAPBindDNSecretKey, resources.LDAPBindPasswordSecretKey)
+	return valid && secretValid, err
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateOIDC() (bool, error) {
+	oidc := r.jenkins.Spec.Security.OIDC
+	if oidc == nil {
+		return true, nil
+	}
+
+	valid := true
+	if r.jenkins.Spec.Security.LDAP != nil {
+		r.logger.V(log.VWarn).Info("Only one of 'spec.security.ldap' and 'spec.security.oidc' can be set")
+		valid = false
+	}
+	for _, plugin := range []string{resources.OIDCPluginName, resources.ConfigurationAsCodePluginName} {
+		if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, plugin) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.security.oidc', please add it to 'spec.master.plugins'", plugin))
+			valid = false
+		}
+	}
+	if issuer, err := url.ParseRequestURI(oidc.IssuerURL); err != nil || (issuer.Scheme != "https" && issuer.Scheme != "http") {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid OpenID Connect issuer URL '%s'", oidc.IssuerURL))
+		valid = false
+	}
+	if len(oidc.ClientSecretName) == 0 {
+		r.logger.V(log.VWarn).Info("OpenID Connect clientSecretName can't be empty")
+		return false, nil
+	}
+
+	secretValid, err := r.validateSecretKeys("OpenID Connect client", oidc.ClientSecretName,
+		resources.OIDCClientIDSecretKey, resources.OIDCClientSecretSecretKey)
+	if err != nil {
+		return false, err
+	}
+	valid = valid && secretValid
+	if len(oidc.BreakGlassSecretName) == 0 {
+		return valid, nil
+	}
+
+	secretValid, err = r.validateSecretKeys("OpenID Connect break-glass", oidc.BreakGlassSecretName,
+		resources.OIDCBreakGlassUserNameSecretKey, resources.OIDCBreakGlassPasswordSecretKey)
+	return valid && secretValid, err
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateSAML() (bool, error) {
+	saml := r.jenkins.Spec.Security.SAML
+	if saml == nil {
+		return true, nil
+	}
+
+	valid := true
+	if r.jenkins.Spec.Security.LDAP != nil || r.jenkins.Spec.Security.OIDC != nil {
+		r.logger.V(log.VWarn).Info("Only one of 'spec.security.ldap', 'spec.security.oidc' and 'spec.security.saml' can be set")
+		valid = false
+	}
+	for _, plugin := range []string{resources.SAMLPluginName, resources.ConfigurationAsCodePluginName} {
+		if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, plugin) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.security.saml', please add it to 'spec.master.plugins'", plugin))
+			valid = false
+		}
+	}
+	if len(saml.KeystoreSecretName) > 0 && len(saml.PrivateKeyAlias) == 0 {
+		r.logger.V(log.VWarn).Info("SAML privateKeyAlias is required by keystoreSecretName")
+		valid = false
+	}
+
+	metadataValid, err := r.validateSAMLIdPMetadata(saml.IdPMetadata)
+	if err != nil {
+		return false, err
+	}
+	valid = valid && metadataValid
+	if len(saml.KeystoreSecretName) == 0 {
+		return valid, nil
+	}
+
+	secretValid, err := r.validateSecretKeys("SAML keystore", saml.KeystoreSecretName,
+		resources.SAMLKeystoreSecretKey, resources.SAMLKeystorePasswordSecretKey, resources.SAMLPrivateKeyPasswordSecretKey)
+	return valid && secretValid, err
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateSAMLIdPMetadata(metadata virtuslabv1alpha1.SAMLIdPMetadata) (bool, error) {
+	sources := 0
+	for _, set := range []bool{len(metadata.XML) > 0, len(metadata.URL) > 0, metadata.ConfigMap != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		r.logger.V(log.VWarn).Info("Exactly one of SAML idpMetadata 'xml', 'url' and 'configMap' has to be set")
+		return false, nil
+	}
+
+	if len(metadata.URL) > 0 {
+		if _, err := url.ParseRequestURI(metadata.URL); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid SAML idpMetadata URL '%s'", metadata.URL))
+			return false, nil
+		}
+		return true, nil
+	}
+
+	xmlMetadata := metadata.XML
+	if metadata.ConfigMap != nil {
+		configMap := &corev1.ConfigMap{}
+		err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: r.jenkins.Namespace, Name: metadata.ConfigMap.Name}, configMap)
+		if err != nil && errors.IsNotFound(err) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("SAML idpMetadata ConfigMap '%s' not found", metadata.ConfigMap.Name))
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		xmlMetadata = configMap.Data[resources.SAMLIdPMetadataConfigMapKey]
+	}
+
+	if err := validateSAMLIdPMetadataXML(xmlMetadata); err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid SAML idpMetadata: %s", err))
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// validateSAMLIdPMetadataXML checks if the metadata is well-formed SAML 2.0 metadata document
+func validateSAMLIdPMetadataXML(metadata string) error {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.NewDecoder(strings.NewReader(metadata)).Decode(&root); err != nil {
+		return err
+	}
+	if root.XMLName.Space != samlMetadataNamespace ||
+		(root.XMLName.Local != "EntityDescriptor" && root.XMLName.Local != "EntitiesDescriptor") {
+		return fmt.Errorf("root element has to be EntityDescriptor or EntitiesDescriptor from '%s' namespace", samlMetadataNamespace)
+	}
+	return nil
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateGitHubOAuth() (bool, error) {
+	github := r.jenkins.Spec.Security.GitHubOAuth
+	if github == nil {
+		return true, nil
+	}
+
+	valid := true
+	security := r.jenkins.Spec.Security
+	if security.LDAP != nil || security.OIDC != nil || security.SAML != nil {
+		r.logger.V(log.VWarn).Info("Only one of 'spec.security.ldap', 'spec.security.oidc', 'spec.security.saml' and 'spec.security.githubOAuth' can be set")
+		valid = false
+	}
+	if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.GitHubOAuthPluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.security.githubOAuth', please add it to 'spec.master.plugins'",
+			resources.GitHubOAuthPluginName))
+		valid = false
+	}
+	for _, value := range []string{github.WebURL, github.APIURL} {
+		if len(value) == 0 {
+			continue
+		}
+		if _, err := url.ParseRequestURI(value); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid GitHub URL '%s'", value))
+			valid = false
+		}
+	}
+	for _, organization := range github.Organizations {
+		if !gitHubNameRegexp.MatchString(organization) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid GitHub organization '%s'", organization))
+			valid = false
+		}
+	}
+	for _, team := range github.Teams {
+		parts := strings.Split(team, "/")
+		if len(parts) != 2 || !gitHubNameRegexp.MatchString(parts[0]) || len(parts[1]) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid GitHub team '%s', expected 'organization/team' format", team))
+			valid = false
+		}
+	}
+	for _, admin := range github.Admins {
+		if !gitHubNameRegexp.MatchString(admin) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid GitHub user name '%s'", admin))
+			valid = false
+		}
+	}
+	if len(github.ClientSecretName) == 0 {
+		r.logger.V(log.VWarn).Info("GitHub OAuth clientSecretName can't be empty")
+		return false, nil
+	}
+
+	secretValid, err := r.validateSecretKeys("GitHub OAuth", github.ClientSecretName,
+		resources.GitHubOAuthClientIDSecretKey, resources.GitHubOAuthClientSecretSecretKey)
+	return valid && secretValid, err
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAuthorization() bool {
+	authorization := r.jenkins.Spec.Security.Authorization
+	if authorization == nil || (authorization.Matrix == nil && authorization.RoleBased == nil) {
+		return true
+	}
+
+	valid := true
+	if r.jenkins.Spec.Security.GitHubOAuth != nil {
+		r.logger.V(log.VWarn).Info("'spec.security.authorization' can't be set together with 'spec.security.githubOAuth' which configures authorization")
+		valid = false
+	}
+	if authorization.Matrix != nil && authorization.RoleBased != nil {
+		r.logger.V(log.VWarn).Info("Only one of 'spec.security.authorization.matrix' and 'spec.security.authorization.roleBased' can be set")
+		return false
+	}
+
+	if authorization.Matrix != nil {
+		return r.validateMatrixAuthorization(authorization.Matrix) && valid
+	}
+	return r.validateRoleBasedAuthorization(authorization.RoleBased) && valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateMatrixAuthorization(matrix *virtuslabv1alpha1.MatrixAuthorization) bool {
+	valid := true
+	if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.MatrixAuthPluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.security.authorization.matrix', please add it to 'spec.master.plugins'",
+			resources.MatrixAuthPluginName))
+		valid = false
+	}
+	for _, grant := range matrix.Grants {
+		if len(grant.Sid) == 0 {
+			r.logger.V(log.VWarn).Info("Matrix authorization grant sid can't be empty")
+			valid = false
+		}
+		if len(grant.Permissions) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Matrix authorization grant for '%s' doesn't contain any permission", grant.Sid))
+			valid = false
+		}
+		if !r.validatePermissions(fmt.Sprintf("granted to '%s'", grant.Sid), grant.Permissions) {
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateRoleBasedAuthorization(roleBased *virtuslabv1alpha1.RoleBasedAuthorization) bool {
+	valid := true
+	if !isPluginConfigured(r.jenkins.Spec.Master.Plugins, resources.RoleStrategyPluginName) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Plugin '%s' is required by 'spec.security.authorization.roleBased', please add it to 'spec.master.plugins'",
+			resources.RoleStrategyPluginName))
+		valid = false
+	}
+
+	globalRoles := map[string]bool{resources.OperatorRoleName: true}
+	for _, role := range roleBased.GlobalRoles {
+		if globalRoles[role.Name] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Global role name '%s' is duplicated or reserved", role.Name))
+			valid = false
+		}
+		globalRoles[role.Name] = true
+		if len(role.Pattern) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Global role '%s' can't have pattern", role.Name))
+			valid = false
+		}
+		if !r.validateRole(role) {
+			valid = false
+		}
+	}
+
+	itemRoles := map[string]bool{}
+	for _, role := range roleBased.ItemRoles {
+		if itemRoles[role.Name] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Item role name '%s' is duplicated", role.Name))
+			valid = false
+		}
+		itemRoles[role.Name] = true
+		if _, err := regexp.Compile(role.Pattern); err != nil || len(role.Pattern) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Item role '%s' has invalid pattern '%s'", role.Name, role.Pattern))
+			valid = false
+		}
+		if !r.validateRole(role) {
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateRole(role virtuslabv1alpha1.Role) bool {
+	valid := true
+	if len(role.Name) == 0 {
+		r.logger.V(log.VWarn).Info("Role name can't be empty")
+		valid = false
+	}
+	if len(role.Permissions) == 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Role '%s' doesn't contain any permission", role.Name))
+		valid = false
+	}
+	for _, sid := range role.Assignments {
+		if len(sid) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Role '%s' assignment can't be empty", role.Name))
+			valid = false
+		}
+	}
+
+	return r.validatePermissions(fmt.Sprintf("of role '%s'", role.Name), role.Permissions) && valid
+}
+
+// validatePermissions checks format of permissions, the permissions existence is verified by Jenkins
+func (r *ReconcileJenkinsBaseConfiguration) validatePermissions(owner string, permissions []string) bool {
+	valid := true
+	for _, permission := range permissions {
+		if !permissionRegexp.MatchString(permission) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid permission '%s' %s, expected 'group/name' format, e.g. 'Overall/Read'",
+				permission, owner))
+			valid = false
+		}
+	}
+	return valid
+}
+
+// validateSecretKeys checks if the Secret in the Jenkins CR namespace exists and contains all the keys,
+// description is used in log messages
+func (r *ReconcileJenkinsBaseConfiguration) validateNetworkPolicy() bool {
+	networkPolicy := r.jenkins.Spec.NetworkPolicy
+	if networkPolicy == nil {
+		return true
+	}
+
+	valid := true
+	for i, peer := range networkPolicy.IngressControllers {
+		if peer.PodSelector == nil && peer.NamespaceSelector == nil && peer.IPBlock == nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Network policy ingress controller %d has to set podSelector, namespaceSelector or ipBlock", i))
+			valid = false
+		}
+	}
+	for i, rule := range networkPolicy.AdditionalRules {
+		for _, peer := range rule.From {
+			if peer.PodSelector == nil && peer.NamespaceSelector == nil && peer.IPBlock == nil {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Network policy additional rule %d has to set podSelector, namespaceSelector or ipBlock in all peers", i))
+				valid = false
+			}
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateService() bool {
+	service := r.jenkins.Spec.Service
+	serviceType := resources.GetServiceType(r.jenkins, r.minikube)
+	valid := true
+	if serviceType != corev1.ServiceTypeClusterIP && serviceType != corev1.ServiceTypeNodePort && serviceType != corev1.ServiceTypeLoadBalancer {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Service type '%s', allowed '%s', '%s' and '%s'", serviceType,
+			corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer))
+		valid = false
+	}
+
+	nodePorts := map[string]int32{
+		"nodePort":              service.NodePort,
+		"httpsNodePort":         service.HTTPSNodePort,
+		"agentListenerNodePort": service.AgentListenerNodePort,
+	}
+	usedNodePorts := map[int32]bool{}
+	for name, nodePort := range nodePorts {
+		if nodePort == 0 {
+			continue
+		}
+		if serviceType == corev1.ServiceTypeClusterIP {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.%s' can't be used with '%s' Service type", name, serviceType))
+			valid = false
+		}
+		if nodePort < 1 || nodePort > 65535 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.service.%s' %d", name, nodePort))
+			valid = false
+		}
+		if usedNodePorts[nodePort] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Node port %d is used more than once", nodePort))
+			valid = false
+		}
+		usedNodePorts[nodePort] = true
+	}
+	if affinity := service.SessionAffinity; len(affinity) > 0 && affinity != corev1.ServiceAffinityNone && affinity != corev1.ServiceAffinityClientIP {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Service session affinity '%s', allowed '%s' and '%s'", affinity,
+			corev1.ServiceAffinityNone, corev1.ServiceAffinityClientIP))
+		valid = false
+	}
+	if timeoutSeconds := service.SessionAffinityTimeoutSeconds; timeoutSeconds != 0 {
+		if service.SessionAffinity != corev1.ServiceAffinityClientIP {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.sessionAffinityTimeoutSeconds' requires '%s' session affinity", corev1.ServiceAffinityClientIP))
+			valid = false
+		}
+		if timeoutSeconds < 1 || timeoutSeconds > corev1.MaxClientIPServiceAffinitySeconds {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.service.sessionAffinityTimeoutSeconds' %d, allowed 1-%d", timeoutSeconds,
+				corev1.MaxClientIPServiceAffinitySeconds))
+			valid = false
+		}
+	}
+	if policy := service.ExternalTrafficPolicy; len(policy) > 0 {
+		if policy != corev1.ServiceExternalTrafficPolicyTypeCluster && policy != corev1.ServiceExternalTrafficPolicyTypeLocal {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Service external traffic policy '%s', allowed '%s' and '%s'", policy,
+				corev1.ServiceExternalTrafficPolicyTypeCluster, corev1.ServiceExternalTrafficPolicyTypeLocal))
+			valid = false
+		}
+		if serviceType == corev1.ServiceTypeClusterIP {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.externalTrafficPolicy' can't be used with '%s' Service type", serviceType))
+			valid = false
+		}
+	}
+	portNames := resources.GetServicePortNames(r.jenkins)
+	usedPortNames := map[string]bool{}
+	for _, portName := range []string{portNames.HTTP, portNames.HTTPS, portNames.AgentListener, portNames.SSHD} {
+		if errs := validation.IsValidPortName(portName); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Service port name '%s': %s", portName, strings.Join(errs, ", ")))
+			valid = false
+		}
+		if usedPortNames[portName] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Service port name '%s' is used more than once", portName))
+			valid = false
+		}
+		usedPortNames[portName] = true
+	}
+	if service.AgentListenerNodePort != 0 && !resources.IsAgentListenerExposed(r.jenkins) {
+		r.logger.V(log.VWarn).Info("'spec.service.agentListenerNodePort' can't be used when TCP agent listener is disabled or agents use WebSocket")
+		valid = false
+	}
+
+	if len(service.LoadBalancerIP) > 0 {
+		if serviceType != corev1.ServiceTypeLoadBalancer {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.loadBalancerIP' requires '%s' Service type", corev1.ServiceTypeLoadBalancer))
+			valid = false
+		}
+		if net.ParseIP(service.LoadBalancerIP) == nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.service.loadBalancerIP' '%s'", service.LoadBalancerIP))
+			valid = false
+		}
+	}
+	if len(service.LoadBalancerSourceRanges) > 0 && serviceType != corev1.ServiceTypeLoadBalancer {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.loadBalancerSourceRanges' requires '%s' Service type", corev1.ServiceTypeLoadBalancer))
+		valid = false
+	}
+	for _, sourceRange := range service.LoadBalancerSourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.service.loadBalancerSourceRanges' CIDR '%s'", sourceRange))
+			valid = false
+		}
+	}
+	if provider := service.InternalLoadBalancer; len(provider) > 0 {
+		if serviceType != corev1.ServiceTypeLoadBalancer {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.internalLoadBalancer' requires '%s' Service type", corev1.ServiceTypeLoadBalancer))
+			valid = false
+		}
+		allowed := false
+		for _, allowedProvider := range virtuslabv1alpha1.AllowedInternalLoadBalancerProviders {
+			if provider == allowedProvider {
+				allowed = true
+			}
+		}
+		if !allowed {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.service.internalLoadBalancer' '%s', allowed '%+v'", provider,
+				virtuslabv1alpha1.AllowedInternalLoadBalancerProviders))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentService() bool {
+	agent := r.jenkins.Spec.Service.Agent
+	if agent == nil {
+		return true
+	}
+
+	valid := true
+	if !resources.IsAgentListenerExposed(r.jenkins) {
+		r.logger.V(log.VWarn).Info("'spec.service.agent' requires TCP agent listener, 'spec.master.remoting.agentListenerDisabled' and 'spec.master.remoting.webSocket' can't be set")
+		valid = false
+	}
+	serviceType := resources.GetAgentServiceType(r.jenkins)
+	if serviceType != corev1.ServiceTypeClusterIP && serviceType != corev1.ServiceTypeNodePort && serviceType != corev1.ServiceTypeLoadBalancer {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid agent Service type '%s', allowed '%s', '%s' and '%s'", serviceType,
+			corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer))
+		valid = false
+	}
+
+	if agent.NodePort != 0 {
+		if serviceType == corev1.ServiceTypeClusterIP {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.agent.nodePort' can't be used with '%s' Service type", serviceType))
+			valid = false
+		}
+		if agent.NodePort < 1 || agent.NodePort > 65535 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.service.agent.nodePort' %d", agent.NodePort))
+			valid = false
+		}
+		service := r.jenkins.Spec.Service
+		for _, nodePort := range []int32{service.NodePort, service.HTTPSNodePort, service.AgentListenerNodePort} {
+			if nodePort == agent.NodePort {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Node port %d is used more than once", nodePort))
+				valid = false
+			}
+		}
+	}
+
+	if len(agent.LoadBalancerIP) > 0 {
+		if serviceType != corev1.ServiceTypeLoadBalancer {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.agent.loadBalancerIP' requires '%s' Service type", corev1.ServiceTypeLoadBalancer))
+			valid = false
+		}
+		if net.ParseIP(agent.LoadBalancerIP) == nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.service.agent.loadBalancerIP' '%s'", agent.LoadBalancerIP))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateHeadlessService() bool {
+	if !resources.IsHeadlessServiceEnabled(r.jenkins) {
+		return true
+	}
+
+	valid := true
+	// the Service name and the pod hostname are DNS labels of the pod address
+	for _, name := range []string{resources.GetHeadlessServiceName(r.jenkins), resources.GetResourceName(r.jenkins)} {
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.headless' requires shorter Jenkins CR name, '%s' isn't valid DNS label: %s",
+				name, strings.Join(errs, ", ")))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validatePrefix() bool {
+	prefix := r.jenkins.Spec.Master.Prefix
+	if len(prefix) == 0 {
+		return true
+	}
+
+	if !prefixRegexp.MatchString(prefix) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Jenkins prefix '%s', expected path like '/jenkins'", prefix))
+		return false
+	}
+	if route := r.jenkins.Spec.Service.Route; route != nil && route.Termination == virtuslabv1alpha1.RouteTerminationPassthrough {
+		r.logger.V(log.VWarn).Info("Jenkins prefix can't be routed by Route with passthrough termination")
+		return false
+	}
+
+	return true
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateSessionTimeout() bool {
+	if sessionTimeout := r.jenkins.Spec.Master.SessionTimeout; sessionTimeout < 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Jenkins session timeout %d minutes, it can't be negative", sessionTimeout))
+		return false
+	}
+	return true
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateJenkinsLocation() bool {
+	valid := true
+	if externalURL := r.jenkins.Spec.ExternalURL; len(externalURL) > 0 {
+		parsedURL, err := url.Parse(externalURL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || len(parsedURL.Host) == 0 ||
+			len(parsedURL.RawQuery) > 0 || len(parsedURL.Fragment) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid external URL '%s', expected absolute http or https URL without query", externalURL))
+			valid = false
+		} else if resourceRootURL, err := url.Parse(r.jenkins.Spec.Master.ResourceRootURL); err == nil && resourceRootURL.Host == parsedURL.Host {
+			r.logger.V(log.VWarn).Info("Resource root URL has to use different host than external URL, otherwise it isn't isolated from Jenkins UI")
+			valid = false
+		}
+	}
+	if adminEmail := r.jenkins.Spec.Master.AdminEmail; len(adminEmail) > 0 {
+		if _, err := mail.ParseAddress(adminEmail); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid admin e-mail address '%s': %s", adminEmail, err))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateIngress() bool {
+	ingress := r.jenkins.Spec.Service.Ingress
+	if ingress == nil {
+		return true
+	}
+
+	valid := true
+	if errs := validation.IsDNS1123Subdomain(ingress.Host); len(errs) > 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Ingress host '%s': %s", ingress.Host, strings.Join(errs, ", ")))
+		valid = false
+	}
+	if resourceRootURL, err := url.Parse(r.jenkins.Spec.Master.ResourceRootURL); err == nil && resourceRootURL.Hostname() == ingress.Host {
+		r.logger.V(log.VWarn).Info("Resource root URL has to use different host than Ingress, otherwise it isn't isolated from Jenkins UI")
+		valid = false
+	}
+	if ingress.TLS != nil {
+		if len(ingress.TLS.SecretName) == 0 {
+			r.logger.V(log.VWarn).Info("Ingress TLS Secret name not set")
+			valid = false
+		}
+		if ingress.TLS.IssuerRef != nil {
+			valid = r.validateCertManagerIssuerRef(ingress.TLS.IssuerRef) && valid
+		}
+	}
+	for _, sourceRange := range ingress.AllowSourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Ingress allowed source range '%s', expected CIDR", sourceRange))
+			valid = false
+		}
+	}
+	if ingress.Auth != nil {
+		valid = r.validateIngressAuth(ingress.Auth) && valid
+	}
+
+	return valid
+}
+
+// validateIngressClass verifies that IngressClass selected by Jenkins.Spec.Service.Ingress.IngressClassName exists,
+// the check is skipped on clusters without IngressClass API and when the operator isn't allowed to read IngressClasses
+func (r *ReconcileJenkinsBaseConfiguration) validateIngressClass() (bool, error) {
+	ingress := r.jenkins.Spec.Service.Ingress
+	if ingress == nil || len(ingress.IngressClassName) == 0 {
+		return true, nil
+	}
+	if errs := validation.IsDNS1123Subdomain(ingress.IngressClassName); len(errs) > 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Ingress class name '%s': %s", ingress.IngressClassName, strings.Join(errs, ", ")))
 		return false, nil
 	}
 
-	if !dockerImageRegexp.MatchString(jenkins.Spec.Master.Image) && !docker.ReferenceRegexp.MatchString(jenkins.Spec.Master.Image) {
-		r.logger.V(log.VWarn).Info("Invalid image")
+	ingressClass := &ingressclassv1.IngressClass{}
+	err := r.apiClient.Get(context.TODO(), types.NamespacedName{Name: ingress.IngressClassName}, ingressClass)
+	if err != nil && errors.IsNotFound(err) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("IngressClass '%s' not found", ingress.IngressClassName))
 		return false, nil
+	} else if err != nil && (apimeta.IsNoMatchError(err) || errors.IsForbidden(err)) {
+		r.logger.V(log.VDebug).Info(fmt.Sprintf("IngressClass '%s' can't be verified: %s", ingress.IngressClassName, err))
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
 
+func (r *ReconcileJenkinsBaseConfiguration) validateIngressAuth(auth *virtuslabv1alpha1.IngressAuth) bool {
+	oauth2Proxy := len(auth.OAuth2ProxyURL) > 0
+	basicAuth := len(auth.BasicAuthSecretName) > 0
+	if oauth2Proxy == basicAuth {
+		r.logger.V(log.VWarn).Info("Exactly one of Ingress 'auth.oauth2ProxyURL' and 'auth.basicAuthSecretName' has to be set")
+		return false
 	}
 
-	if !r.validatePlugins(jenkins.Spec.Master.Plugins) {
-		return false, nil
+	valid := true
+	if oauth2Proxy {
+		proxyURL, err := url.Parse(auth.OAuth2ProxyURL)
+		if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") || len(proxyURL.Host) == 0 || len(proxyURL.RawQuery) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid oauth2-proxy URL '%s', expected absolute http or https URL without query", auth.OAuth2ProxyURL))
+			valid = false
+		}
+		if len(auth.BasicAuthRealm) > 0 {
+			r.logger.V(log.VWarn).Info("Ingress 'auth.basicAuthRealm' requires 'auth.basicAuthSecretName'")
+			valid = false
+		}
+	}
+	if basicAuth {
+		if errs := validation.IsDNS1123Subdomain(auth.BasicAuthSecretName); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Ingress basic authentication Secret name '%s': %s", auth.BasicAuthSecretName, strings.Join(errs, ", ")))
+			valid = false
+		}
 	}
 
-	valid, err := r.verifyBackup()
-	if !valid || err != nil {
-		return valid, err
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateIstio() bool {
+	istio := r.jenkins.Spec.Service.Istio
+	if istio == nil {
+		return true
 	}
 
-	if r.jenkins.Spec.Backup == virtuslabv1alpha1.JenkinsBackupTypeAmazonS3 && !r.verifyBackupAmazonS3() {
-		return false, nil
+	valid := true
+	if r.jenkins.Spec.Service.Ingress != nil || r.jenkins.Spec.Service.Route != nil {
+		r.logger.V(log.VWarn).Info("'spec.service.istio' can't be used together with 'spec.service.ingress' or 'spec.service.route'")
+		valid = false
+	}
+	if errs := validation.IsDNS1123Subdomain(istio.Host); len(errs) > 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Istio host '%s': %s", istio.Host, strings.Join(errs, ", ")))
+		valid = false
+	}
+	if resourceRootURL, err := url.Parse(r.jenkins.Spec.Master.ResourceRootURL); err == nil && resourceRootURL.Hostname() == istio.Host {
+		r.logger.V(log.VWarn).Info("Resource root URL has to use different host than Istio, otherwise it isn't isolated from Jenkins UI")
+		valid = false
 	}
 
-	return true, nil
+	if len(istio.Gateway) == 0 {
+		return valid
+	}
+	gatewayNamespace, gatewayName := r.jenkins.ObjectMeta.Namespace, istio.Gateway
+	if parts := strings.Split(istio.Gateway, "/"); len(parts) == 2 {
+		gatewayNamespace, gatewayName = parts[0], parts[1]
+	}
+	namespaceErrs := validation.IsDNS1123Label(gatewayNamespace)
+	nameErrs := validation.IsDNS1123Subdomain(gatewayName)
+	if len(namespaceErrs) > 0 || len(nameErrs) > 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid Istio Gateway '%s', expected '<namespace>/<name>' or '<name>': %s",
+			istio.Gateway, strings.Join(append(namespaceErrs, nameErrs...), ", ")))
+		valid = false
+	}
+	if len(istio.GatewaySelector) > 0 || len(istio.TLSSecretName) > 0 {
+		r.logger.V(log.VWarn).Info("Istio gateway selector and TLS Secret name are used only when the operator creates the Gateway, 'spec.service.istio.gateway' can't be set")
+		valid = false
+	}
+
+	return valid
 }
 
-func (r *ReconcileJenkinsBaseConfiguration) validatePlugins(pluginsWithVersions map[string][]string) bool {
+func (r *ReconcileJenkinsBaseConfiguration) validateMonitoring() bool {
+	monitoring := r.jenkins.Spec.Monitoring
+	if monitoring == nil {
+		return true
+	}
+
 	valid := true
-	allPlugins := map[string][]plugins.Plugin{}
+	if len(monitoring.Path) > 0 && !monitoringPathRegexp.MatchString(strings.Trim(monitoring.Path, "/")) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid prometheus plugin path '%s', expected path segments, e.g. 'prometheus'", monitoring.Path))
+		valid = false
+	}
+	if len(monitoring.Interval) > 0 && !prometheusDurationRegexp.MatchString(monitoring.Interval) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid PodMonitor interval '%s', expected Prometheus duration, e.g. '30s'", monitoring.Interval))
+		valid = false
+	}
+	valid = r.validateMonitoringLabels("PodMonitor", monitoring.Labels) && valid
+	if monitoring.GrafanaDashboard != nil {
+		valid = r.validateMonitoringLabels("Grafana dashboard ConfigMap", monitoring.GrafanaDashboard.Labels) && valid
+	}
+	if prometheusRule := monitoring.PrometheusRule; prometheusRule != nil {
+		valid = r.validateMonitoringLabels("PrometheusRule", prometheusRule.Labels) && valid
+		if errs := validation.IsValidLabelValue(prometheusRule.Severity); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid alert severity '%s': %s", prometheusRule.Severity, strings.Join(errs, ", ")))
+			valid = false
+		}
+		if prometheusRule.QueueLength < 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid alert queue length '%d', expected positive number", prometheusRule.QueueLength))
+			valid = false
+		}
+	}
 
-	for rootPluginName, dependentPluginNames := range pluginsWithVersions {
-		if _, err := plugins.New(rootPluginName); err != nil {
-			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid root plugin name '%s'", rootPluginName))
+	return valid
+}
+
+// validateMonitoringLabels validates labels of a monitoring resource, the labels can't override labels selecting
+// Jenkins master pod
+func (r *ReconcileJenkinsBaseConfiguration) validateMonitoringLabels(kind string, labels map[string]string) bool {
+	valid := true
+	selector := resources.BuildResourceLabels(r.jenkins)
+	for key, value := range labels {
+		if _, found := selector[key]; found {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("%s label '%s' is reserved by the operator", kind, key))
+			valid = false
+			continue
+		}
+		if errs := append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid %s label '%s=%s': %s", kind, key, value, strings.Join(errs, ", ")))
+			valid = false
+		}
+	}
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateHTTPRoute() bool {
+	httpRoute := r.jenkins.Spec.Service.HTTPRoute
+	if httpRoute == nil {
+		return true
+	}
+
+	valid := true
+	service := r.jenkins.Spec.Service
+	if service.Ingress != nil || service.Route != nil || service.Istio != nil {
+		r.logger.V(log.VWarn).Info("'spec.service.httpRoute' can't be used together with 'spec.service.ingress', 'spec.service.route' or 'spec.service.istio'")
+		valid = false
+	}
+	if errs := validation.IsDNS1123Subdomain(httpRoute.Host); len(errs) > 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid HTTPRoute host '%s': %s", httpRoute.Host, strings.Join(errs, ", ")))
+		valid = false
+	}
+	if resourceRootURL, err := url.Parse(r.jenkins.Spec.Master.ResourceRootURL); err == nil && resourceRootURL.Hostname() == httpRoute.Host {
+		r.logger.V(log.VWarn).Info("Resource root URL has to use different host than HTTPRoute, otherwise it isn't isolated from Jenkins UI")
+		valid = false
+	}
+
+	gatewayNamespace, gatewayName := resources.GetHTTPRouteGateway(r.jenkins)
+	namespaceErrs := validation.IsDNS1123Label(gatewayNamespace)
+	nameErrs := validation.IsDNS1123Subdomain(gatewayName)
+	if len(namespaceErrs) > 0 || len(nameErrs) > 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid HTTPRoute Gateway '%s', expected '<namespace>/<name>' or '<name>': %s",
+			httpRoute.Gateway, strings.Join(append(namespaceErrs, nameErrs...), ", ")))
+		valid = false
+	}
+	if len(httpRoute.SectionName) > 0 {
+		if errs := validation.IsDNS1123Subdomain(httpRoute.SectionName); len(errs) > 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid HTTPRoute section name '%s': %s", httpRoute.SectionName, strings.Join(errs, ", ")))
 			valid = false
 		}
+	}
 
-		dependentPlugins := []plugins.Plugin{}
-		for _, pluginName := range dependentPluginNames {
-			if p, err := plugins.New(pluginName); err != nil {
-				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid dependent plugin name '%s' in root plugin '%s'", pluginName, rootPluginName))
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateExposure() bool {
+	exposure := r.jenkins.Spec.Exposure
+	if len(exposure) == 0 {
+		return true
+	}
+
+	allowed := false
+	for _, allowedExposure := range virtuslabv1alpha1.AllowedExposures {
+		if exposure == allowedExposure {
+			allowed = true
+		}
+	}
+	if !allowed {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid 'spec.exposure' '%s', allowed '%+v'", exposure, virtuslabv1alpha1.AllowedExposures))
+		return false
+	}
+	if !resources.IsInternalExposure(r.jenkins) {
+		return true
+	}
+
+	valid := true
+	service := r.jenkins.Spec.Service
+	if serviceType := service.Type; len(serviceType) > 0 && serviceType != corev1.ServiceTypeClusterIP {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.type' '%s' can't be used with '%s' exposure", serviceType, exposure))
+		valid = false
+	}
+	if resources.IsAgentServiceEnabled(r.jenkins) && resources.GetAgentServiceType(r.jenkins) != corev1.ServiceTypeClusterIP {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("'spec.service.agent.type' '%s' can't be used with '%s' exposure",
+			resources.GetAgentServiceType(r.jenkins), exposure))
+		valid = false
+	}
+	externalPaths := map[string]bool{
+		"spec.service.ingress":   service.Ingress != nil,
+		"spec.service.route":     service.Route != nil,
+		"spec.service.istio":     service.Istio != nil,
+		"spec.service.httpRoute": service.HTTPRoute != nil,
+	}
+	for name, enabled := range externalPaths {
+		if enabled {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("'%s' can't be used with '%s' exposure", name, exposure))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateExternalDNS() bool {
+	externalDNS := r.jenkins.Spec.Service.ExternalDNS
+	if externalDNS == nil {
+		return true
+	}
+
+	valid := true
+	if len(externalDNS.Hostname) > 0 {
+		for _, hostname := range strings.Split(externalDNS.Hostname, ",") {
+			hostname = strings.TrimPrefix(strings.TrimSpace(hostname), "*.")
+			if errs := validation.IsDNS1123Subdomain(hostname); len(errs) > 0 {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid external-dns hostname '%s': %s", hostname, strings.Join(errs, ", ")))
 				valid = false
-			} else {
-				dependentPlugins = append(dependentPlugins, *p)
 			}
 		}
+	}
+	if externalDNS.TTL < 0 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid external-dns TTL '%d', it can't be negative", externalDNS.TTL))
+		valid = false
+	}
 
-		allPlugins[rootPluginName] = dependentPlugins
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validatePodSecurityProfile() bool {
+	profile := r.jenkins.Spec.Security.PodSecurityProfile
+	if len(profile) == 0 {
+		return true
 	}
 
-	if valid {
-		return plugins.VerifyDependencies(allPlugins)
+	allowed := false
+	for _, allowedProfile := range virtuslabv1alpha1.AllowedPodSecurityProfiles {
+		if profile == allowedProfile {
+			allowed = true
+		}
+	}
+	if !allowed {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid pod security profile '%s', allowed '%+v'", profile, virtuslabv1alpha1.AllowedPodSecurityProfiles))
+		return false
+	}
+	if profile == virtuslabv1alpha1.PodSecurityProfilePrivileged {
+		return true
+	}
+
+	// annotations are the only part of the generated pods which can be changed by users
+	valid := true
+	for key, value := range r.jenkins.Spec.Master.Annotations {
+		seccomp := key == resources.SeccompPodAnnotationKey || strings.HasPrefix(key, resources.SeccompContainerAnnotationKeyPrefix)
+		appArmor := strings.HasPrefix(key, resources.AppArmorContainerAnnotationKeyPrefix)
+		localhost := strings.HasPrefix(value, resources.LocalhostProfilePrefix)
+		switch {
+		case seccomp && value == resources.UnconfinedProfile:
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Annotation '%s' can't be '%s' with '%s' pod security profile", key, value, profile))
+			valid = false
+		case seccomp && profile == virtuslabv1alpha1.PodSecurityProfileRestricted && !localhost &&
+			value != resources.SeccompProfileRuntimeDefault && value != resources.SeccompProfileDockerDefault:
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Annotation '%s' has to be '%s' or '%s*' with '%s' pod security profile",
+				key, resources.SeccompProfileRuntimeDefault, resources.LocalhostProfilePrefix, profile))
+			valid = false
+		case appArmor && !localhost && value != resources.AppArmorProfileRuntimeDefault:
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Annotation '%s' has to be '%s' or '%s*' with '%s' pod security profile",
+				key, resources.AppArmorProfileRuntimeDefault, resources.LocalhostProfilePrefix, profile))
+			valid = false
+		}
 	}
 
 	return valid
 }
 
+func (r *ReconcileJenkinsBaseConfiguration) validateSecretKeys(description, secretName string, keys ...string) (bool, error) {
+	secret := &corev1.Secret{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: r.jenkins.Namespace, Name: secretName}, secret)
+	if err != nil && errors.IsNotFound(err) {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("%s Secret '%s' not found", description, secretName))
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	valid := true
+	for _, key := range keys {
+		if len(secret.Data[key]) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("%s Secret '%s' doesn't contain '%s' key", description, secretName, key))
+			valid = false
+		}
+	}
+
+	return valid, nil
+}
+
+func isPluginConfigured(pluginsWithVersions map[string][]string, pluginName string) bool {
+	for rootPluginName, dependentPluginNames := range pluginsWithVersions {
+		for _, name := range append([]string{rootPluginName}, dependentPluginNames...) {
+			if p, err := plugins.New(name); err == nil && p.Name == pluginName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *ReconcileJenkinsBaseConfiguration) verifyBackup() (bool, error) {
 	if r.jenkins.Spec.Backup == "" {
 		r.logger.V(log.VWarn).Info("Backup strategy not set in 'spec.backup'")
@@ -133,3 +3034,253 @@ func (r *ReconcileJenkinsBaseConfiguration) verifyBackupAmazonS3() bool {
 
 	return true
 }
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAdminPasswordRotationPeriod() bool {
+	rotationPeriod := r.jenkins.Spec.Master.AdminPasswordRotationPeriod
+	if rotationPeriod == nil {
+		return true
+	}
+
+	if rotationPeriod.Duration < minAdminPasswordRotationPeriod {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Admin password rotation period '%s' is shorter than '%s'",
+			rotationPeriod.Duration, minAdminPasswordRotationPeriod))
+		return false
+	}
+
+	return true
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateFIPSMode() bool {
+	if !resources.IsFIPSModeEnabled(r.jenkins) {
+		return true
+	}
+
+	valid := true
+	masterTLS := r.jenkins.Spec.Master.TLS
+	if masterTLS == nil {
+		r.logger.V(log.VWarn).Info("'spec.master.tls' is required by 'spec.security.fipsMode'")
+		valid = false
+	} else if masterTLS.InsecureSkipVerify {
+		r.logger.V(log.VWarn).Info("'spec.master.tls.insecureSkipVerify' can't be used with 'spec.security.fipsMode'")
+		valid = false
+	}
+	if vault := r.jenkins.Spec.Vault; vault != nil && !strings.HasPrefix(vault.Address, "https://") {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Vault address '%s' has to use https with 'spec.security.fipsMode'", vault.Address))
+		valid = false
+	}
+	if ldap := r.jenkins.Spec.Security.LDAP; ldap != nil && !strings.HasPrefix(ldap.Server, "ldaps://") {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("LDAP server '%s' has to use ldaps with 'spec.security.fipsMode'", ldap.Server))
+		valid = false
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateServiceUsers() bool {
+	valid := true
+	userNames := map[string]bool{}
+	secretNames := map[string]bool{}
+	for _, serviceUser := range r.jenkins.Spec.Security.ServiceUsers {
+		if len(serviceUser.Name) == 0 {
+			r.logger.V(log.VWarn).Info("Service user name is empty")
+			valid = false
+		} else if serviceUser.Name == resources.OperatorUserName {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Service user name '%s' is reserved for the operator", serviceUser.Name))
+			valid = false
+		} else if userNames[serviceUser.Name] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Service user '%s' is defined more than once", serviceUser.Name))
+			valid = false
+		}
+		userNames[serviceUser.Name] = true
+
+		if len(serviceUser.SecretName) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Secret name of service user '%s' is empty", serviceUser.Name))
+			valid = false
+		} else if serviceUser.SecretName == resources.GetOperatorCredentialsSecretName(r.jenkins) ||
+			serviceUser.SecretName == resources.GetJenkinsSecretsSecretName(r.jenkins) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Secret '%s' of service user '%s' is managed by the operator", serviceUser.SecretName, serviceUser.Name))
+			valid = false
+		} else if secretNames[serviceUser.SecretName] {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Secret '%s' is used by more than one service user", serviceUser.SecretName))
+			valid = false
+		}
+		secretNames[serviceUser.SecretName] = true
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateServiceAccountToken() bool {
+	serviceAccountToken := r.jenkins.Spec.Master.ServiceAccountToken
+	if serviceAccountToken == nil || serviceAccountToken.ExpirationSeconds == nil {
+		return true
+	}
+
+	// Kubernetes API server limits
+	expirationSeconds := *serviceAccountToken.ExpirationSeconds
+	if expirationSeconds < resources.MinServiceAccountTokenExpirationSeconds || expirationSeconds > 1<<32 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Service account token expiration '%d' seconds isn't between '%d' and '%d' seconds",
+			expirationSeconds, resources.MinServiceAccountTokenExpirationSeconds, int64(1<<32)))
+		return false
+	}
+
+	return true
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateLoginThrottling() bool {
+	loginThrottling := r.jenkins.Spec.Security.LoginThrottling
+	if loginThrottling == nil {
+		return true
+	}
+
+	valid := true
+	if loginThrottling.MaxFailedAttempts < 1 {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Login throttling max failed attempts '%d' is lower than 1", loginThrottling.MaxFailedAttempts))
+		valid = false
+	}
+	if loginThrottling.Window.Duration < time.Second {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Login throttling window '%s' is shorter than 1s", loginThrottling.Window.Duration))
+		valid = false
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateSecurityProfile() bool {
+	profile := r.jenkins.Spec.Security.Profile
+	if len(profile) == 0 {
+		return true
+	}
+
+	for _, allowedProfile := range virtuslabv1alpha1.AllowedSecurityProfiles {
+		if profile == allowedProfile {
+			return true
+		}
+	}
+	r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid security profile '%s', allowed '%+v'", profile, virtuslabv1alpha1.AllowedSecurityProfiles))
+	return false
+}
+
+// validateScriptConsole checks that only the operator user can run scripts when the script console is disabled,
+// Jenkins administrators can always run scripts, so the authorization strategy can't grant administration to others
+func (r *ReconcileJenkinsBaseConfiguration) validateScriptConsole() bool {
+	if resources.IsScriptConsoleEnabled(r.jenkins) {
+		return true
+	}
+	authorization := r.jenkins.Spec.Security.Authorization
+	if authorization == nil || (authorization.Matrix == nil && authorization.RoleBased == nil) {
+		r.logger.V(log.VWarn).Info("Script console can be disabled only with matrix-based or role-based authorization " +
+			"in spec.security.authorization, otherwise every logged in user is administrator")
+		return false
+	}
+
+	owners := map[string][]string{}
+	if authorization.Matrix != nil {
+		for _, grant := range authorization.Matrix.Grants {
+			owners[fmt.Sprintf("sid '%s'", grant.Sid)] = grant.Permissions
+		}
+	}
+	if authorization.RoleBased != nil {
+		for _, role := range append(authorization.RoleBased.GlobalRoles, authorization.RoleBased.ItemRoles...) {
+			owners[fmt.Sprintf("role '%s'", role.Name)] = role.Permissions
+		}
+	}
+
+	valid := true
+	for owner, permissions := range owners {
+		for _, permission := range permissions {
+			if permission == resources.RunScriptsPermission || permission == resources.AdministerPermission {
+				r.logger.V(log.VWarn).Info(fmt.Sprintf("Permission '%s' can't be granted to %s when script console is disabled",
+					permission, owner))
+				valid = false
+			}
+		}
+	}
+
+	return valid
+}
+
+func (r *ReconcileJenkinsBaseConfiguration) validateAgentPodTemplateWorkspaceVolume(template virtuslabv1alpha1.AgentPodTemplate, pod *corev1.Pod) bool {
+	workspace := template.WorkspaceVolume
+	if workspace == nil {
+		return true
+	}
+
+	valid := true
+	allowed := false
+	for _, volumeType := range virtuslabv1alpha1.AllowedWorkspaceVolumeTypes {
+		if workspace.Type == volumeType {
+			allowed = true
+		}
+	}
+	if !allowed {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid workspace volume type '%s' of agent pod template '%s', allowed '%+v'",
+			workspace.Type, template.Name, virtuslabv1alpha1.AllowedWorkspaceVolumeTypes))
+		return false
+	}
+
+	if workspace.Type != virtuslabv1alpha1.WorkspaceVolumeTypeEmptyDir && (workspace.Memory || workspace.SizeLimit != nil) {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Workspace volume memory and size limit of agent pod template '%s' require '%s' type",
+			template.Name, virtuslabv1alpha1.WorkspaceVolumeTypeEmptyDir))
+		valid = false
+	}
+	if workspace.Type != virtuslabv1alpha1.WorkspaceVolumeTypeEphemeral &&
+		(len(workspace.StorageClassName) > 0 || workspace.Size != nil || len(workspace.AccessMode) > 0) {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Workspace volume storage class, size and access mode of agent pod template '%s' require '%s' type",
+			template.Name, virtuslabv1alpha1.WorkspaceVolumeTypeEphemeral))
+		valid = false
+	}
+	if workspace.Type != virtuslabv1alpha1.WorkspaceVolumeTypeHostPath && len(workspace.Path) > 0 {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Workspace volume path of agent pod template '%s' requires '%s' type",
+			template.Name, virtuslabv1alpha1.WorkspaceVolumeTypeHostPath))
+		valid = false
+	}
+
+	switch workspace.Type {
+	case virtuslabv1alpha1.WorkspaceVolumeTypeEmptyDir:
+		if workspace.SizeLimit != nil && workspace.SizeLimit.Sign() <= 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Workspace volume size limit of agent pod template '%s' has to be positive", template.Name))
+			valid = false
+		}
+	case virtuslabv1alpha1.WorkspaceVolumeTypeEphemeral:
+		if workspace.Size == nil || workspace.Size.Sign() <= 0 {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Workspace volume of agent pod template '%s' requires positive size", template.Name))
+			valid = false
+		}
+		if len(workspace.StorageClassName) > 0 {
+			if errs := validation.IsDNS1123Subdomain(workspace.StorageClassName); len(errs) > 0 {
+				r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid workspace volume storage class '%s' of agent pod template '%s': %s",
+					workspace.StorageClassName, template.Name, strings.Join(errs, ", ")))
+				valid = false
+			}
+		}
+		// the workspace has to be writable
+		if len(workspace.AccessMode) > 0 && workspace.AccessMode != corev1.ReadWriteOnce && workspace.AccessMode != corev1.ReadWriteMany {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Invalid workspace volume access mode '%s' of agent pod template '%s', allowed '%+v'",
+				workspace.AccessMode, template.Name, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce, corev1.ReadWriteMany}))
+			valid = false
+		}
+	case virtuslabv1alpha1.WorkspaceVolumeTypeHostPath:
+		if !strings.HasPrefix(workspace.Path, "/") &&
+			!(template.OS == virtuslabv1alpha1.AgentOSWindows && windowsAbsolutePathRegexp.MatchString(workspace.Path)) {
+			r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Workspace volume path '%s' of agent pod template '%s' has to be absolute",
+				workspace.Path, template.Name))
+			valid = false
+		}
+	}
+
+	// emptyDir and hostPath workspace volumes are already added to the pod
+	defined := 0
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == resources.WorkspaceVolumeName {
+			defined++
+		}
+	}
+	if defined > 1 || (workspace.Type == virtuslabv1alpha1.WorkspaceVolumeTypeEphemeral && defined > 0) {
+		r.warnAgentPodTemplate(template.Name, fmt.Sprintf("Volume '%s' of agent pod template '%s' conflicts with the workspace volume",
+			resources.WorkspaceVolumeName, template.Name))
+		valid = false
+	}
+
+	return valid
+}
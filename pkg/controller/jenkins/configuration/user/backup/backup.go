@@ -0,0 +1,218 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// JobName is the name of Jenkins job which backups Jenkins jobs to Amazon S3
+	JobName = constants.OperatorName + "-backup"
+
+	// CheckPeriod is how often the last successful backup is looked for when backup is enabled
+	CheckPeriod = 5 * time.Minute
+
+	// backupSchedule is the schedule of the backup job, every 6 hours
+	backupSchedule = "H */6 * * *"
+)
+
+// Backup defines API for backup of Jenkins jobs
+type Backup struct {
+	jenkinsClient jenkinsclient.Jenkins
+	k8sClient     k8s.Client
+	logger        logr.Logger
+}
+
+// New creates Backup object
+func New(jenkinsClient jenkinsclient.Jenkins, k8sClient k8s.Client, logger logr.Logger) *Backup {
+	return &Backup{
+		jenkinsClient: jenkinsClient,
+		k8sClient:     k8sClient,
+		logger:        logger,
+	}
+}
+
+// EnsureBackup configures Jenkins job which periodically uploads Jenkins jobs to Amazon S3 when
+// Jenkins.Spec.Backup is AmazonS3, the completion time of the last successful build of the job is recorded
+// in Jenkins.Status.LastBackupTime
+func (b *Backup) EnsureBackup(jenkins *virtuslabv1alpha1.Jenkins) error {
+	if jenkins.Spec.Backup != virtuslabv1alpha1.JenkinsBackupTypeAmazonS3 {
+		return nil
+	}
+
+	jobXML, err := buildJobXML(jenkins.Spec.BackupAmazonS3)
+	if err != nil {
+		return err
+	}
+	_, created, err := b.jenkinsClient.CreateOrUpdateJob(jobXML, JobName)
+	if err != nil {
+		return err
+	}
+	if created {
+		b.logger.Info(fmt.Sprintf("'%s' job has been created", JobName))
+	}
+
+	return b.ensureLastBackupTime(jenkins)
+}
+
+// ensureLastBackupTime sets Jenkins.Status.LastBackupTime to the completion time of the last successful build
+// of the backup job, the job can run also when the operator isn't running, so the time is read from Jenkins
+func (b *Backup) ensureLastBackupTime(jenkins *virtuslabv1alpha1.Jenkins) error {
+	job, err := b.jenkinsClient.GetJob(JobName)
+	if err != nil {
+		return err
+	}
+	buildNumber := job.GetDetails().LastSuccessfulBuild.Number
+	if buildNumber == 0 {
+		return nil
+	}
+
+	build, err := b.jenkinsClient.GetBuild(JobName, buildNumber)
+	if err != nil {
+		return err
+	}
+	// the status keeps the time with the precision of seconds
+	completed := build.GetTimestamp().Add(time.Duration(build.GetDuration()) * time.Millisecond).Truncate(time.Second)
+	if jenkins.Status.LastBackupTime != nil && !completed.After(jenkins.Status.LastBackupTime.Time) {
+		return nil
+	}
+
+	lastBackupTime := metav1.NewTime(completed)
+	jenkins.Status.LastBackupTime = &lastBackupTime
+	if err := b.k8sClient.Status().Update(context.TODO(), jenkins); err != nil {
+		return err
+	}
+	b.logger.Info(fmt.Sprintf("Jenkins jobs have been backed up by build #%d", buildNumber))
+	return nil
+}
+
+func buildJobXML(backup virtuslabv1alpha1.JenkinsBackupAmazonS3) (string, error) {
+	data := struct {
+		BucketName      string
+		BucketPath      string
+		Region          string
+		CredentialsPath string
+	}{
+		BucketName:      escapeGroovyString(backup.BucketName),
+		BucketPath:      escapeGroovyString(strings.Trim(backup.BucketPath, "/")),
+		Region:          escapeGroovyString(backup.Region),
+		CredentialsPath: resources.JenkinsBackupCredentialsVolumePath,
+	}
+
+	var script bytes.Buffer
+	if err := backupPipelineTemplate.Execute(&script, data); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(backupJobXMLFmt, xmlEscaper.Replace(script.String())), nil
+}
+
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "'", "&apos;", `"`, "&quot;")
+
+// escapeGroovyString escapes the value to be used inside single-quoted groovy string
+func escapeGroovyString(value string) string {
+	return strings.Replace(strings.Replace(value, `\`, `\\`, -1), `'`, `\'`, -1)
+}
+
+// backupPipelineTemplate archives Jenkins jobs without workspaces and uploads the archive to Amazon S3 by a request
+// signed with AWS Signature Version 4, so no plugin or AWS CLI is required
+var backupPipelineTemplate = template.Must(template.New(JobName).Parse(`def bucketName = '{{ .BucketName }}'
+def bucketPath = '{{ .BucketPath }}'
+def region = '{{ .Region }}'
+def credentialsPath = '{{ .CredentialsPath }}'
+
+node('master') {
+    def archive = "${pwd()}/jenkins-jobs.tar.gz"
+    stage('Archive jobs') {
+        sh "tar -czf '${archive}' --exclude='*/workspace' -C \"\${JENKINS_HOME}\" jobs"
+    }
+
+    stage('Upload to Amazon S3') {
+        def fileName = "jenkins-jobs-${new Date().format('yyyyMMddHHmmss', TimeZone.getTimeZone('UTC'))}.tar.gz"
+        def key = bucketPath ? "${bucketPath}/${fileName}" : fileName
+        uploadToAmazonS3(archive, bucketName, key, region, credentialsPath)
+        println "Jenkins jobs have been uploaded to s3://${bucketName}/${key}"
+    }
+}
+
+@NonCPS
+def uploadToAmazonS3(String path, String bucketName, String key, String region, String credentialsPath) {
+    def accessKey = new File("${credentialsPath}/access-key").text.trim()
+    def secretKey = new File("${credentialsPath}/secret-key").text.trim()
+    def body = new File(path).bytes
+
+    def amzDate = new Date().format("yyyyMMdd'T'HHmmss'Z'", TimeZone.getTimeZone('UTC'))
+    def date = amzDate.substring(0, 8)
+    def host = "${bucketName}.s3.${region}.amazonaws.com"
+    def canonicalURI = '/' + key.split('/').collect { java.net.URLEncoder.encode(it, 'UTF-8').replace('+', '%20') }.join('/')
+    def payloadHash = sha256Hex(body)
+    def signedHeaders = 'host;x-amz-content-sha256;x-amz-date'
+    def canonicalRequest = "PUT\n${canonicalURI}\n\nhost:${host}\nx-amz-content-sha256:${payloadHash}\nx-amz-date:${amzDate}\n\n${signedHeaders}\n${payloadHash}"
+    def scope = "${date}/${region}/s3/aws4_request"
+    def stringToSign = "AWS4-HMAC-SHA256\n${amzDate}\n${scope}\n${sha256Hex(canonicalRequest.toString().getBytes('UTF-8'))}"
+    def signingKey = hmacSHA256(hmacSHA256(hmacSHA256(hmacSHA256("AWS4${secretKey}".toString().getBytes('UTF-8'), date), region), 's3'), 'aws4_request')
+    def signature = hmacSHA256(signingKey, stringToSign.toString()).encodeHex().toString()
+
+    def connection = new URL("https://${host}${canonicalURI}").openConnection()
+    connection.setRequestMethod('PUT')
+    connection.setDoOutput(true)
+    connection.setFixedLengthStreamingMode(body.length)
+    connection.setRequestProperty('x-amz-date', amzDate)
+    connection.setRequestProperty('x-amz-content-sha256', payloadHash)
+    connection.setRequestProperty('Authorization', "AWS4-HMAC-SHA256 Credential=${accessKey}/${scope}, SignedHeaders=${signedHeaders}, Signature=${signature}".toString())
+    connection.outputStream.withStream { it.write(body) }
+    def responseCode = connection.responseCode
+    if (responseCode != 200) {
+        throw new IllegalStateException("Amazon S3 responded with ${responseCode}: ${connection.errorStream?.text}")
+    }
+}
+
+@NonCPS
+def sha256Hex(byte[] data) {
+    return java.security.MessageDigest.getInstance('SHA-256').digest(data).encodeHex().toString()
+}
+
+@NonCPS
+def hmacSHA256(byte[] key, String data) {
+    def mac = javax.crypto.Mac.getInstance('HmacSHA256')
+    mac.init(new javax.crypto.spec.SecretKeySpec(key, 'HmacSHA256'))
+    return mac.doFinal(data.getBytes('UTF-8'))
+}
+`))
+
+const backupJobXMLFmt = `<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job@2.31">
+  <actions/>
+  <description>Backup Jenkins jobs to Amazon S3</description>
+  <keepDependencies>false</keepDependencies>
+  <properties>
+    <org.jenkinsci.plugins.workflow.job.properties.DisableConcurrentBuildsJobProperty/>
+    <org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+      <triggers>
+        <hudson.triggers.TimerTrigger>
+          <spec>` + backupSchedule + `</spec>
+        </hudson.triggers.TimerTrigger>
+      </triggers>
+    </org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+  </properties>
+  <definition class="org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition" plugin="workflow-cps@2.61">
+    <script>%s</script>
+    <sandbox>false</sandbox>
+  </definition>
+  <triggers/>
+  <disabled>false</disabled>
+</flow-definition>
+`
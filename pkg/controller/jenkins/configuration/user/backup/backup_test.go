@@ -0,0 +1,133 @@
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+
+	"github.com/bndr/gojenkins"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestBackup_EnsureBackup(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	buildStarted := time.Date(2019, time.January, 10, 12, 0, 0, 0, time.UTC)
+	buildCompleted := metav1.NewTime(buildStarted.Add(90 * time.Second))
+	newJenkins := func(backup virtuslabv1alpha1.JenkinsBackup, lastBackupTime *metav1.Time) *virtuslabv1alpha1.Jenkins {
+		return &virtuslabv1alpha1.Jenkins{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+			Spec: virtuslabv1alpha1.JenkinsSpec{
+				Backup: backup,
+				BackupAmazonS3: virtuslabv1alpha1.JenkinsBackupAmazonS3{
+					BucketName: "jenkins-backup",
+					BucketPath: "/example/",
+					Region:     "eu-west-1",
+				},
+			},
+			Status: virtuslabv1alpha1.JenkinsStatus{LastBackupTime: lastBackupTime},
+		}
+	}
+	expectBackupJob := func(jenkinsClient *client.MockJenkins, lastSuccessfulBuild int64) {
+		jenkinsClient.EXPECT().CreateOrUpdateJob(gomock.Any(), JobName).Return(nil, false, nil)
+		jenkinsClient.EXPECT().GetJob(JobName).Return(&gojenkins.Job{
+			Raw: &gojenkins.JobResponse{LastSuccessfulBuild: gojenkins.JobBuild{Number: lastSuccessfulBuild}},
+		}, nil)
+		if lastSuccessfulBuild > 0 {
+			jenkinsClient.EXPECT().GetBuild(JobName, lastSuccessfulBuild).Return(&gojenkins.Build{
+				Raw: &gojenkins.BuildResponse{
+					Result:    string(virtuslabv1alpha1.BuildSuccessStatus),
+					Timestamp: buildStarted.UnixNano() / int64(time.Millisecond),
+					Duration:  int64(90 * time.Second / time.Millisecond),
+				},
+			}, nil)
+		}
+	}
+	ensureBackup := func(t *testing.T, jenkinsClient *client.MockJenkins, jenkins *virtuslabv1alpha1.Jenkins) *virtuslabv1alpha1.Jenkins {
+		fakeClient := fake.NewFakeClient()
+		if err := fakeClient.Create(context.TODO(), jenkins); err != nil {
+			t.Fatal(err)
+		}
+
+		err := New(jenkinsClient, fakeClient, logf.ZapLogger(false)).EnsureBackup(jenkins)
+
+		assert.NoError(t, err)
+		current := &virtuslabv1alpha1.Jenkins{}
+		err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}, current)
+		assert.NoError(t, err)
+		return current
+	}
+
+	t.Run("backup completed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		expectBackupJob(jenkinsClient, 3)
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsBackupTypeAmazonS3, nil)
+
+		current := ensureBackup(t, jenkinsClient, jenkins)
+
+		if assert.NotNil(t, current.Status.LastBackupTime) {
+			assert.True(t, buildCompleted.Equal(current.Status.LastBackupTime))
+		}
+	})
+	t.Run("newer backup completed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		expectBackupJob(jenkinsClient, 4)
+		previousBackupTime := metav1.NewTime(buildStarted.Add(-6 * time.Hour))
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsBackupTypeAmazonS3, &previousBackupTime)
+
+		current := ensureBackup(t, jenkinsClient, jenkins)
+
+		if assert.NotNil(t, current.Status.LastBackupTime) {
+			assert.True(t, buildCompleted.Equal(current.Status.LastBackupTime))
+		}
+	})
+	t.Run("no successful backup yet", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		expectBackupJob(jenkinsClient, 0)
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsBackupTypeAmazonS3, nil)
+
+		current := ensureBackup(t, jenkinsClient, jenkins)
+
+		assert.Nil(t, current.Status.LastBackupTime)
+	})
+	t.Run("backup disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		jenkinsClient := client.NewMockJenkins(ctrl)
+		jenkins := newJenkins(virtuslabv1alpha1.JenkinsBackupTypeNoBackup, nil)
+
+		current := ensureBackup(t, jenkinsClient, jenkins)
+
+		assert.Nil(t, current.Status.LastBackupTime)
+	})
+}
+
+func TestBuildJobXML(t *testing.T) {
+	jobXML, err := buildJobXML(virtuslabv1alpha1.JenkinsBackupAmazonS3{
+		BucketName: "jenkins-backup",
+		BucketPath: "/example/",
+		Region:     "eu-west-1",
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, jobXML, "def bucketName = &apos;jenkins-backup&apos;")
+	assert.Contains(t, jobXML, "def bucketPath = &apos;example&apos;")
+	assert.Contains(t, jobXML, "def region = &apos;eu-west-1&apos;")
+	assert.Contains(t, jobXML, "def credentialsPath = &apos;/var/jenkins/backup-credentials&apos;")
+	assert.Contains(t, jobXML, "<spec>"+backupSchedule+"</spec>")
+}
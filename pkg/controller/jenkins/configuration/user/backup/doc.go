@@ -0,0 +1,2 @@
+// Package backup implements backup of Jenkins jobs to Amazon S3 by a Jenkins job
+package backup
@@ -0,0 +1,422 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+	"github.com/VirtusLab/jenkins-operator/pkg/log"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// UsernamePasswordType is type of credentials with username and password
+	UsernamePasswordType = "usernamePassword"
+	// SecretTextType is type of credentials with secret text
+	SecretTextType = "secretText"
+	// SSHUserPrivateKeyType is type of credentials with username and SSH private key
+	SSHUserPrivateKeyType = "sshUserPrivateKey"
+	// CertificateType is type of credentials with PKCS#12 certificate
+	CertificateType = "certificate"
+
+	// UsernameSecretKey is the Secret data key with username
+	UsernameSecretKey = "username"
+	// PasswordSecretKey is the Secret data key with password, also used as certificate password
+	PasswordSecretKey = "password"
+	// SecretTextSecretKey is the Secret data key with secret text
+	SecretTextSecretKey = "secret"
+	// PrivateKeySecretKey is the Secret data key with SSH private key
+	PrivateKeySecretKey = "privateKey"
+	// PassphraseSecretKey is the Secret data key with optional SSH private key passphrase
+	PassphraseSecretKey = "passphrase"
+	// CertificateSecretKey is the Secret data key with PKCS#12 certificate
+	CertificateSecretKey = "certificate"
+
+	// managedDescriptionSuffix marks Jenkins credentials managed by the operator
+	managedDescriptionSuffix = " (managed by " + constants.OperatorName + ")"
+)
+
+// AllowedTypes consists allowed types of Jenkins credentials
+var AllowedTypes = []string{UsernamePasswordType, SecretTextType, SSHUserPrivateKeyType, CertificateType}
+
+// requiredSecretKeys contains Secret data keys required by every credentials type
+var requiredSecretKeys = map[string][]string{
+	UsernamePasswordType:  {UsernameSecretKey, PasswordSecretKey},
+	SecretTextType:        {SecretTextSecretKey},
+	SSHUserPrivateKeyType: {UsernameSecretKey, PrivateKeySecretKey},
+	CertificateType:       {CertificateSecretKey},
+}
+
+// Credentials defines API for synchronizing Kubernetes Secrets to Jenkins credentials
+type Credentials struct {
+	jenkinsClient jenkinsclient.Jenkins
+	k8sClient     k8s.Client
+	logger        logr.Logger
+}
+
+// New creates Credentials object
+func New(jenkinsClient jenkinsclient.Jenkins, k8sClient k8s.Client, logger logr.Logger) *Credentials {
+	return &Credentials{
+		jenkinsClient: jenkinsClient,
+		k8sClient:     k8sClient,
+		logger:        logger,
+	}
+}
+
+// EnsureCredentials creates, updates or deletes Jenkins credentials according to the labeled Kubernetes Secrets
+// and JenkinsCredential CRs, the Jenkins credentials ID is the Secret or JenkinsCredential name
+func (c *Credentials) EnsureCredentials(jenkins *virtuslabv1alpha1.Jenkins) error {
+	credentials, err := c.GetCredentials(jenkins)
+	if err != nil {
+		return err
+	}
+
+	hash := calculateHash(credentials)
+	if hash == jenkins.Status.CredentialsHash {
+		return nil
+	}
+
+	script, err := buildSynchronizeCredentialsGroovyScript(credentials)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info(fmt.Sprintf("Synchronizing %d Jenkins credentials", len(credentials)))
+	output, err := c.jenkinsClient.ExecuteScript(script)
+	if err != nil {
+		return err
+	}
+	c.logger.V(log.VDebug).Info(fmt.Sprintf("Credentials synchronization output: %s", output))
+
+	jenkins.Status.CredentialsHash = hash
+	return c.k8sClient.Status().Update(context.TODO(), jenkins)
+}
+
+// Credential defines Jenkins credentials with the data from Kubernetes Secret
+type Credential struct {
+	// Source is the Kubernetes resource which defines the credentials, used in logs
+	Source      string
+	ID          string
+	Type        string
+	Scope       virtuslabv1alpha1.JenkinsCredentialScope
+	Folder      string
+	Description string
+	Data        map[string][]byte
+	// SecretResourceVersion is the resource version of the Secret with the data, used to detect data changes
+	SecretResourceVersion string
+}
+
+// GetCredentials returns all credentials configured for the Jenkins CR, from labeled Secrets and JenkinsCredential CRs
+func (c *Credentials) GetCredentials(jenkins *virtuslabv1alpha1.Jenkins) ([]Credential, error) {
+	secrets, err := c.GetCredentialsSecrets(jenkins)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials []Credential
+	for _, secret := range secrets {
+		credentials = append(credentials, Credential{
+			Source:                fmt.Sprintf("Secret '%s'", secret.Name),
+			ID:                    secret.Name,
+			Type:                  secret.ObjectMeta.Labels[constants.LabelCredentialsTypeKey],
+			Scope:                 virtuslabv1alpha1.JenkinsCredentialScopeGlobal,
+			Description:           fmt.Sprintf("Secret %s", secret.Name),
+			Data:                  secret.Data,
+			SecretResourceVersion: secret.ResourceVersion,
+		})
+	}
+
+	jenkinsCredentials, err := c.GetJenkinsCredentials(jenkins)
+	if err != nil {
+		return nil, err
+	}
+	for _, jenkinsCredential := range jenkinsCredentials {
+		secret := &corev1.Secret{}
+		namespaceName := types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkinsCredential.Spec.SecretName}
+		if err := c.k8sClient.Get(context.TODO(), namespaceName, secret); err != nil {
+			return nil, err
+		}
+
+		credential := Credential{
+			Source:                fmt.Sprintf("JenkinsCredential '%s'", jenkinsCredential.Name),
+			ID:                    jenkinsCredential.Name,
+			Type:                  jenkinsCredential.Spec.Type,
+			Scope:                 jenkinsCredential.Spec.Scope,
+			Folder:                jenkinsCredential.Spec.Folder,
+			Description:           jenkinsCredential.Spec.Description,
+			Data:                  secret.Data,
+			SecretResourceVersion: secret.ResourceVersion,
+		}
+		if len(credential.Scope) == 0 {
+			credential.Scope = virtuslabv1alpha1.JenkinsCredentialScopeGlobal
+		}
+		if len(credential.Description) == 0 {
+			credential.Description = fmt.Sprintf("JenkinsCredential %s", jenkinsCredential.Name)
+		}
+		credentials = append(credentials, credential)
+	}
+
+	return credentials, nil
+}
+
+// GetCredentialsSecrets returns Secrets with credentials type label sorted by name
+func (c *Credentials) GetCredentialsSecrets(jenkins *virtuslabv1alpha1.Jenkins) ([]corev1.Secret, error) {
+	secretList := &corev1.SecretList{}
+	listOptions := k8s.InNamespace(jenkins.Namespace).MatchingLabels(resources.BuildLabelsForWatchedResources(jenkins))
+	if err := c.k8sClient.List(context.TODO(), listOptions, secretList); err != nil {
+		return nil, err
+	}
+
+	var secrets []corev1.Secret
+	for _, secret := range secretList.Items {
+		if _, ok := secret.ObjectMeta.Labels[constants.LabelCredentialsTypeKey]; ok {
+			secrets = append(secrets, secret)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Name < secrets[j].Name
+	})
+
+	return secrets, nil
+}
+
+// GetJenkinsCredentials returns JenkinsCredential CRs which refer to the Jenkins CR sorted by name
+func (c *Credentials) GetJenkinsCredentials(jenkins *virtuslabv1alpha1.Jenkins) ([]virtuslabv1alpha1.JenkinsCredential, error) {
+	jenkinsCredentialList := &virtuslabv1alpha1.JenkinsCredentialList{}
+	if err := c.k8sClient.List(context.TODO(), k8s.InNamespace(jenkins.Namespace), jenkinsCredentialList); err != nil {
+		return nil, err
+	}
+
+	var jenkinsCredentials []virtuslabv1alpha1.JenkinsCredential
+	for _, jenkinsCredential := range jenkinsCredentialList.Items {
+		for _, name := range jenkinsCredential.Spec.Jenkins {
+			if name == jenkins.Name {
+				jenkinsCredentials = append(jenkinsCredentials, jenkinsCredential)
+				break
+			}
+		}
+	}
+	sort.Slice(jenkinsCredentials, func(i, j int) bool {
+		return jenkinsCredentials[i].Name < jenkinsCredentials[j].Name
+	})
+
+	return jenkinsCredentials, nil
+}
+
+// Validate returns an error when the credentials can't be configured in Jenkins
+func Validate(credential Credential) error {
+	keys, ok := requiredSecretKeys[credential.Type]
+	if !ok {
+		return fmt.Errorf("invalid credentials type '%s', allowed '%+v'", credential.Type, AllowedTypes)
+	}
+	for _, key := range keys {
+		if len(credential.Data[key]) == 0 {
+			return fmt.Errorf("missing '%s' Secret key required by '%s' credentials type", key, credential.Type)
+		}
+	}
+
+	validScope := false
+	for _, scope := range virtuslabv1alpha1.AllowedJenkinsCredentialScopes {
+		if credential.Scope == scope {
+			validScope = true
+		}
+	}
+	if !validScope {
+		return fmt.Errorf("invalid scope '%s', allowed '%+v'", credential.Scope, virtuslabv1alpha1.AllowedJenkinsCredentialScopes)
+	}
+	if len(credential.Folder) > 0 && credential.Scope != virtuslabv1alpha1.JenkinsCredentialScopeGlobal {
+		return fmt.Errorf("folder credentials support only '%s' scope", virtuslabv1alpha1.JenkinsCredentialScopeGlobal)
+	}
+
+	return nil
+}
+
+// ValidateUniqueIDs returns an error when more credentials have the same ID in the same folder
+func ValidateUniqueIDs(credentials []Credential) error {
+	sources := map[string]string{}
+	for _, credential := range credentials {
+		key := credential.Folder + "/" + credential.ID
+		if source, exists := sources[key]; exists {
+			return fmt.Errorf("%s and %s define the same credentials ID '%s'", source, credential.Source, credential.ID)
+		}
+		sources[key] = credential.Source
+	}
+	return nil
+}
+
+// calculateHash returns hash of the credentials without their data, so no secret is stored in the status,
+// the data changes are detected by the Secret resource version
+func calculateHash(credentials []Credential) string {
+	hash := sha256.New()
+	encoder := json.NewEncoder(hash)
+	for _, credential := range credentials {
+		credential.Data = nil
+		_ = encoder.Encode(credential)
+	}
+	return base64.URLEncoding.EncodeToString(hash.Sum(nil))
+}
+
+type credentialsData struct {
+	Type        string
+	ID          string
+	Scope       string
+	Folder      string
+	Description string
+	Username    string
+	Password    string
+	Secret      string
+	PrivateKey  string
+	Passphrase  string
+	Certificate string
+}
+
+// buildSynchronizeCredentialsGroovyScript renders groovy script which synchronizes Jenkins credentials,
+// all values are base64 encoded so they don't need escaping
+func buildSynchronizeCredentialsGroovyScript(credentials []Credential) (string, error) {
+	if err := ValidateUniqueIDs(credentials); err != nil {
+		return "", err
+	}
+
+	var data []credentialsData
+	for _, credential := range credentials {
+		if err := Validate(credential); err != nil {
+			return "", fmt.Errorf("%s: %s", credential.Source, err)
+		}
+		data = append(data, credentialsData{
+			Type:        credential.Type,
+			ID:          encode([]byte(credential.ID)),
+			Scope:       strings.ToUpper(string(credential.Scope)),
+			Folder:      encode([]byte(credential.Folder)),
+			Description: encode([]byte(credential.Description + managedDescriptionSuffix)),
+			Username:    encode(credential.Data[UsernameSecretKey]),
+			Password:    encode(credential.Data[PasswordSecretKey]),
+			Secret:      encode(credential.Data[SecretTextSecretKey]),
+			PrivateKey:  encode(credential.Data[PrivateKeySecretKey]),
+			Passphrase:  encode(credential.Data[PassphraseSecretKey]),
+			Certificate: encode(credential.Data[CertificateSecretKey]),
+		})
+	}
+
+	templateData := struct {
+		Credentials              []credentialsData
+		ManagedDescriptionSuffix string
+	}{
+		Credentials:              data,
+		ManagedDescriptionSuffix: managedDescriptionSuffix,
+	}
+
+	var buffer bytes.Buffer
+	if err := synchronizeCredentialsTemplate.Execute(&buffer, templateData); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+func encode(value []byte) string {
+	return base64.StdEncoding.EncodeToString(value)
+}
+
+var synchronizeCredentialsTemplate = template.Must(template.New("synchronize-credentials").Parse(`
+import com.cloudbees.hudson.plugins.folder.AbstractFolder
+import com.cloudbees.hudson.plugins.folder.properties.FolderCredentialsProvider.FolderCredentialsProperty
+import com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey
+import com.cloudbees.plugins.credentials.CredentialsScope
+import com.cloudbees.plugins.credentials.SecretBytes
+import com.cloudbees.plugins.credentials.SystemCredentialsProvider
+import com.cloudbees.plugins.credentials.domains.Domain
+import com.cloudbees.plugins.credentials.domains.DomainCredentials
+import com.cloudbees.plugins.credentials.impl.CertificateCredentialsImpl
+import com.cloudbees.plugins.credentials.impl.UsernamePasswordCredentialsImpl
+import hudson.util.Secret
+import jenkins.model.Jenkins
+import org.jenkinsci.plugins.plaincredentials.impl.StringCredentialsImpl
+
+def decode(String value) {
+    return new String(Base64.getDecoder().decode(value), 'UTF-8')
+}
+
+// folder full name to credentials, empty folder name means system store
+def credentials = [:]
+{{- range .Credentials }}
+credentials.get(decode('{{ .Folder }}'), []) <<
+{{- if eq .Type "usernamePassword" }}
+        new UsernamePasswordCredentialsImpl(CredentialsScope.{{ .Scope }}, decode('{{ .ID }}'), decode('{{ .Description }}'),
+                decode('{{ .Username }}'), decode('{{ .Password }}'))
+{{- else if eq .Type "secretText" }}
+        new StringCredentialsImpl(CredentialsScope.{{ .Scope }}, decode('{{ .ID }}'), decode('{{ .Description }}'),
+                Secret.fromString(decode('{{ .Secret }}')))
+{{- else if eq .Type "sshUserPrivateKey" }}
+        new BasicSSHUserPrivateKey(CredentialsScope.{{ .Scope }}, decode('{{ .ID }}'), decode('{{ .Username }}'),
+                new BasicSSHUserPrivateKey.DirectEntryPrivateKeySource(decode('{{ .PrivateKey }}')), decode('{{ .Passphrase }}'),
+                decode('{{ .Description }}'))
+{{- else if eq .Type "certificate" }}
+        new CertificateCredentialsImpl(CredentialsScope.{{ .Scope }}, decode('{{ .ID }}'), decode('{{ .Description }}'),
+                decode('{{ .Password }}'),
+                new CertificateCredentialsImpl.UploadedKeyStoreSource(SecretBytes.fromBytes(Base64.getDecoder().decode('{{ .Certificate }}'))))
+{{- end }}
+{{- end }}
+
+def jenkins = Jenkins.getInstance()
+def stores = ['': SystemCredentialsProvider.getInstance().getStore()]
+jenkins.getAllItems(AbstractFolder.class).each { folder ->
+    def property = folder.getProperties().get(FolderCredentialsProperty.class)
+    if (property != null) {
+        stores[folder.getFullName()] = property.getStore()
+    }
+}
+credentials.keySet().findAll { !stores.containsKey(it) }.each { folderName ->
+    def folder = jenkins.getItemByFullName(folderName, AbstractFolder.class)
+    if (folder == null) {
+        throw new IllegalStateException("Folder '${folderName}' not found")
+    }
+    def property = new FolderCredentialsProperty(new DomainCredentials[0])
+    folder.addProperty(property)
+    stores[folderName] = property.getStore()
+}
+
+def isManaged = { it.hasProperty('description') && it.description?.endsWith('{{ .ManagedDescriptionSuffix }}') }
+
+// credentials created in Jenkins are never overwritten
+stores.each { folderName, store ->
+    credentials.get(folderName, []).each { newCredentials ->
+        def existing = store.getCredentials(Domain.global()).find { it.id == newCredentials.id }
+        if (existing && !isManaged(existing)) {
+            throw new IllegalStateException("Credentials '${newCredentials.id}' in '${folderName}' already exist and aren't managed by the operator")
+        }
+    }
+}
+
+stores.each { folderName, store ->
+    def desiredCredentials = credentials.get(folderName, [])
+    def ids = desiredCredentials.collect { it.id }
+
+    store.getCredentials(Domain.global()).findAll { isManaged(it) && !ids.contains(it.id) }.each {
+        println "Removing credentials '${it.id}' from '${folderName}'"
+        store.removeCredentials(Domain.global(), it)
+    }
+
+    desiredCredentials.each { newCredentials ->
+        def existing = store.getCredentials(Domain.global()).find { it.id == newCredentials.id }
+        if (existing) {
+            store.updateCredentials(Domain.global(), existing, newCredentials)
+        } else {
+            println "Adding credentials '${newCredentials.id}' to '${folderName}'"
+            store.addCredentials(Domain.global(), newCredentials)
+        }
+    }
+}
+`))
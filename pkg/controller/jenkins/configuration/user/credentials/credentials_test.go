@@ -0,0 +1,175 @@
+package credentials
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func newCredential(id, credentialsType string, data map[string][]byte) Credential {
+	return Credential{
+		Source: "Secret '" + id + "'",
+		ID:     id,
+		Type:   credentialsType,
+		Scope:  virtuslabv1alpha1.JenkinsCredentialScopeGlobal,
+		Data:   data,
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("valid username password", func(t *testing.T) {
+		credential := newCredential("user", UsernamePasswordType, map[string][]byte{
+			UsernameSecretKey: []byte("user"),
+			PasswordSecretKey: []byte("password"),
+		})
+		assert.NoError(t, Validate(credential))
+	})
+	t.Run("missing key", func(t *testing.T) {
+		credential := newCredential("user", UsernamePasswordType, map[string][]byte{UsernameSecretKey: []byte("user")})
+		assert.Error(t, Validate(credential))
+	})
+	t.Run("invalid type", func(t *testing.T) {
+		credential := newCredential("token", "token", map[string][]byte{SecretTextSecretKey: []byte("token")})
+		assert.Error(t, Validate(credential))
+	})
+	t.Run("invalid scope", func(t *testing.T) {
+		credential := newCredential("token", SecretTextType, map[string][]byte{SecretTextSecretKey: []byte("token")})
+		credential.Scope = "user"
+		assert.Error(t, Validate(credential))
+	})
+	t.Run("system scope in folder", func(t *testing.T) {
+		credential := newCredential("token", SecretTextType, map[string][]byte{SecretTextSecretKey: []byte("token")})
+		credential.Scope = virtuslabv1alpha1.JenkinsCredentialScopeSystem
+		credential.Folder = "team"
+		assert.Error(t, Validate(credential))
+	})
+}
+
+func TestValidateUniqueIDs(t *testing.T) {
+	t.Run("different folders", func(t *testing.T) {
+		first := newCredential("token", SecretTextType, nil)
+		second := newCredential("token", SecretTextType, nil)
+		second.Folder = "team"
+		assert.NoError(t, ValidateUniqueIDs([]Credential{first, second}))
+	})
+	t.Run("duplicated ID", func(t *testing.T) {
+		first := newCredential("token", SecretTextType, nil)
+		second := newCredential("token", SecretTextType, nil)
+		second.Source = "JenkinsCredential 'token'"
+		assert.Error(t, ValidateUniqueIDs([]Credential{first, second}))
+	})
+}
+
+func TestBuildSynchronizeCredentialsGroovyScript(t *testing.T) {
+	t.Run("global credentials", func(t *testing.T) {
+		credentials := []Credential{
+			newCredential("token", SecretTextType, map[string][]byte{SecretTextSecretKey: []byte("it's secret")}),
+		}
+
+		script, err := buildSynchronizeCredentialsGroovyScript(credentials)
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, "new StringCredentialsImpl(CredentialsScope.GLOBAL, decode('"+
+			base64.StdEncoding.EncodeToString([]byte("token"))+"')")
+		assert.Contains(t, script, base64.StdEncoding.EncodeToString([]byte("it's secret")))
+		assert.NotContains(t, script, "it's secret")
+		assert.Contains(t, script, "already exist and aren't managed by the operator")
+	})
+	t.Run("system scope folder credentials", func(t *testing.T) {
+		credential := newCredential("token", SecretTextType, map[string][]byte{SecretTextSecretKey: []byte("token")})
+		credential.Scope = virtuslabv1alpha1.JenkinsCredentialScopeSystem
+		credential.Folder = "team"
+
+		_, err := buildSynchronizeCredentialsGroovyScript([]Credential{credential})
+
+		assert.Error(t, err)
+	})
+	t.Run("folder credentials", func(t *testing.T) {
+		credential := newCredential("token", SecretTextType, map[string][]byte{SecretTextSecretKey: []byte("token")})
+		credential.Folder = "team/project"
+
+		script, err := buildSynchronizeCredentialsGroovyScript([]Credential{credential})
+
+		assert.NoError(t, err)
+		assert.Contains(t, script, "credentials.get(decode('"+base64.StdEncoding.EncodeToString([]byte("team/project"))+"'), [])")
+	})
+}
+
+func TestCalculateHash(t *testing.T) {
+	newCredentials := func(secretResourceVersion string, secret string) []Credential {
+		credential := newCredential("token", SecretTextType, map[string][]byte{SecretTextSecretKey: []byte(secret)})
+		credential.SecretResourceVersion = secretResourceVersion
+		return []Credential{credential}
+	}
+
+	t.Run("the same credentials", func(t *testing.T) {
+		assert.Equal(t, calculateHash(newCredentials("1", "token")), calculateHash(newCredentials("1", "token")))
+	})
+	t.Run("Secret changed", func(t *testing.T) {
+		assert.NotEqual(t, calculateHash(newCredentials("1", "token")), calculateHash(newCredentials("2", "token")))
+	})
+	t.Run("data isn't hashed", func(t *testing.T) {
+		assert.Equal(t, calculateHash(newCredentials("1", "token")), calculateHash(newCredentials("1", "other-token")))
+	})
+}
+
+func TestCredentials_EnsureCredentials(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	jenkins := &virtuslabv1alpha1.Jenkins{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+	}
+	jenkinsCredential := &virtuslabv1alpha1.JenkinsCredential{
+		ObjectMeta: metav1.ObjectMeta{Namespace: jenkins.Namespace, Name: "token"},
+		Spec: virtuslabv1alpha1.JenkinsCredentialSpec{
+			Jenkins:    []string{jenkins.Name},
+			Type:       SecretTextType,
+			SecretName: "token-secret",
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: jenkins.Namespace, Name: "token-secret", ResourceVersion: "1"},
+		Data:       map[string][]byte{SecretTextSecretKey: []byte("token")},
+	}
+	fakeClient := fake.NewFakeClient()
+	for _, object := range []runtime.Object{jenkins, jenkinsCredential, secret} {
+		if err := fakeClient.Create(context.TODO(), object); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	jenkinsClient := client.NewMockJenkins(ctrl)
+	credentials := New(jenkinsClient, fakeClient, logf.ZapLogger(false))
+
+	jenkinsClient.EXPECT().ExecuteScript(gomock.Any()).Return("", nil).Times(1)
+	err = credentials.EnsureCredentials(jenkins)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, jenkins.Status.CredentialsHash)
+
+	// nothing has changed so the credentials aren't applied again
+	err = credentials.EnsureCredentials(jenkins)
+	assert.NoError(t, err)
+
+	secret.Data[SecretTextSecretKey] = []byte("rotated-token")
+	secret.ResourceVersion = "2"
+	err = fakeClient.Update(context.TODO(), secret)
+	assert.NoError(t, err)
+	jenkinsClient.EXPECT().ExecuteScript(gomock.Any()).Return("", nil).Do(func(script string) {
+		assert.Contains(t, script, base64.StdEncoding.EncodeToString([]byte("rotated-token")))
+	}).Times(1)
+	err = credentials.EnsureCredentials(jenkins)
+	assert.NoError(t, err)
+}
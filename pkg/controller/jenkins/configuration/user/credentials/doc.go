@@ -0,0 +1,2 @@
+// Package credentials implements synchronization of Kubernetes Secrets to Jenkins credentials
+package credentials
@@ -8,39 +8,59 @@ import (
 	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
 	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user/backup"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user/credentials"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user/repository"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user/seedjobs"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user/xmljobs"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/groovy"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/jobs"
+	"github.com/VirtusLab/jenkins-operator/pkg/log"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	k8s "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// reasonSeedJobsCompleted is the reason of event emitted when seed jobs of the current Jenkins master pod are built
+const reasonSeedJobsCompleted = "SeedJobsCompleted"
+
 // ReconcileUserConfiguration defines values required for Jenkins user configuration
 type ReconcileUserConfiguration struct {
 	k8sClient     k8s.Client
 	jenkinsClient jenkinsclient.Jenkins
+	recorder      record.EventRecorder
 	logger        logr.Logger
 	jenkins       *virtuslabv1alpha1.Jenkins
 }
 
 // New create structure which takes care of user configuration
-func New(k8sClient k8s.Client, jenkinsClient jenkinsclient.Jenkins, logger logr.Logger,
+func New(k8sClient k8s.Client, jenkinsClient jenkinsclient.Jenkins, recorder record.EventRecorder, logger logr.Logger,
 	jenkins *virtuslabv1alpha1.Jenkins) *ReconcileUserConfiguration {
 	return &ReconcileUserConfiguration{
 		k8sClient:     k8sClient,
 		jenkinsClient: jenkinsClient,
-		logger:        logger,
+		recorder:      recorder,
+		logger:        log.ForComponent(logger, log.ComponentUser),
 		jenkins:       jenkins,
 	}
 }
 
-// Reconcile it's a main reconciliation loop for user supplied configuration
-func (r *ReconcileUserConfiguration) Reconcile() (reconcile.Result, error) {
+// Reconcile it's a main reconciliation loop for user supplied configuration, spans of Jenkins API requests are children
+// of the span of ctx
+func (r *ReconcileUserConfiguration) Reconcile(ctx context.Context) (reconcile.Result, error) {
+	r.jenkinsClient = jenkinsclient.WithContext(ctx, r.jenkinsClient)
+	// credentials are synchronized first because seed jobs and user configuration can use them
+	err := credentials.New(r.jenkinsClient, r.k8sClient, r.logger).EnsureCredentials(r.jenkins)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// reconcile seed jobs
 	result, err := r.ensureSeedJobs()
 	if err != nil {
@@ -50,7 +70,46 @@ func (r *ReconcileUserConfiguration) Reconcile() (reconcile.Result, error) {
 		return result, nil
 	}
 
-	return r.ensureUserConfiguration(r.jenkinsClient)
+	result, err = r.ensureUserConfiguration(r.jenkinsClient)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if result.Requeue {
+		return result, nil
+	}
+
+	result, err = r.ensureRepositoryConfiguration()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if result.Requeue {
+		return result, nil
+	}
+
+	if err = xmljobs.New(r.jenkinsClient, r.k8sClient, r.logger).EnsureJobs(r.jenkins); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, backup.New(r.jenkinsClient, r.k8sClient, r.logger).EnsureBackup(r.jenkins)
+}
+
+func (r *ReconcileUserConfiguration) ensureRepositoryConfiguration() (reconcile.Result, error) {
+	done, err := repository.New(r.jenkinsClient, r.k8sClient, r.logger).EnsureConfiguration(r.jenkins)
+	if err != nil {
+		// build failed and can be recovered - retry build and requeue reconciliation loop with timeout
+		if err == jobs.ErrorBuildFailed {
+			return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 10}, nil
+		}
+		// build failed and cannot be recovered
+		if err == jobs.ErrorUnrecoverableBuildFailed {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if !done {
+		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 10}, nil
+	}
+	return reconcile.Result{}, nil
 }
 
 func (r *ReconcileUserConfiguration) ensureSeedJobs() (reconcile.Result, error) {
@@ -72,7 +131,25 @@ func (r *ReconcileUserConfiguration) ensureSeedJobs() (reconcile.Result, error)
 	if !done {
 		return reconcile.Result{Requeue: true, RequeueAfter: time.Second * 10}, nil
 	}
-	return reconcile.Result{}, nil
+	return reconcile.Result{}, r.ensureSeedJobsCompletedTime()
+}
+
+// ensureSeedJobsCompletedTime records the completion of seed jobs in the status and by an event once per
+// Jenkins master pod
+func (r *ReconcileUserConfiguration) ensureSeedJobsCompletedTime() error {
+	if len(r.jenkins.Spec.SeedJobs) == 0 || r.jenkins.Status.SeedJobsCompletedTime != nil {
+		return nil
+	}
+
+	now := metav1.Now()
+	r.jenkins.Status.SeedJobsCompletedTime = &now
+	if err := r.k8sClient.Status().Update(context.TODO(), r.jenkins); err != nil {
+		return err
+	}
+	r.logger.Info("Seed jobs are completed")
+	r.recorder.Eventf(r.jenkins, corev1.EventTypeNormal, reasonSeedJobsCompleted, "%d seed jobs have been built",
+		len(r.jenkins.Spec.SeedJobs))
+	return nil
 }
 
 func (r *ReconcileUserConfiguration) ensureUserConfiguration(jenkinsClient jenkinsclient.Jenkins) (reconcile.Result, error) {
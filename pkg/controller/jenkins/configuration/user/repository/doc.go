@@ -0,0 +1,2 @@
+// Package repository implements Jenkins configuration pulled from a Git repository
+package repository
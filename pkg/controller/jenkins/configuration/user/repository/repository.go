@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"text/template"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/jobs"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConfigurationJobName is the name of Jenkins job which applies configuration from the Git repository
+	ConfigurationJobName = constants.OperatorName + "-repository-configuration"
+	// CredentialsID is the ID of Jenkins credentials used to check out the Git repository
+	CredentialsID = constants.OperatorName + "-repository-configuration"
+
+	defaultRef  = "master"
+	defaultPath = "."
+
+	privateKeyParameterName = "PRIVATE_KEY"
+
+	// pollSCMSchedule is the schedule of checking the Git repository for changes
+	pollSCMSchedule = "H/5 * * * *"
+)
+
+// Repository defines API for applying Jenkins configuration from the Git repository
+type Repository struct {
+	jenkinsClient jenkinsclient.Jenkins
+	k8sClient     k8s.Client
+	logger        logr.Logger
+}
+
+// New creates Repository object
+func New(jenkinsClient jenkinsclient.Jenkins, k8sClient k8s.Client, logger logr.Logger) *Repository {
+	return &Repository{
+		jenkinsClient: jenkinsClient,
+		k8sClient:     k8sClient,
+		logger:        logger,
+	}
+}
+
+// EnsureConfiguration configures Jenkins job which checks out the Git repository and applies groovy scripts
+// and configuration as code files from it, the job polls the repository and runs again on every change
+func (r *Repository) EnsureConfiguration(jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
+	repository := jenkins.Spec.Configuration.Repository
+	if repository == nil {
+		return true, nil
+	}
+
+	privateKey, err := r.privateKeyFromSecret(jenkins.Namespace, repository.PrivateKey)
+	if err != nil {
+		return false, err
+	}
+
+	jobXML, err := buildJobXML(*repository, len(privateKey) > 0)
+	if err != nil {
+		return false, err
+	}
+	_, created, err := r.jenkinsClient.CreateOrUpdateJob(jobXML, ConfigurationJobName)
+	if err != nil {
+		return false, err
+	}
+	if created {
+		r.logger.Info(fmt.Sprintf("'%s' job has been created", ConfigurationJobName))
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(jobXML))
+	hash.Write([]byte(privateKey))
+	encodedHash := base64.URLEncoding.EncodeToString(hash.Sum(nil))
+
+	jobsClient := jobs.New(r.jenkinsClient, r.k8sClient, r.logger)
+	return jobsClient.EnsureBuildJob(ConfigurationJobName, encodedHash, map[string]string{privateKeyParameterName: privateKey}, jenkins, true)
+}
+
+// privateKeyFromSecret extracts the Git repository private key from the Kubernetes secret
+func (r *Repository) privateKeyFromSecret(namespace string, privateKey virtuslabv1alpha1.PrivateKey) (string, error) {
+	if privateKey.SecretKeyRef == nil {
+		return "", nil
+	}
+
+	secret := &corev1.Secret{}
+	namespaceName := types.NamespacedName{Namespace: namespace, Name: privateKey.SecretKeyRef.Name}
+	if err := r.k8sClient.Get(context.TODO(), namespaceName, secret); err != nil {
+		return "", err
+	}
+	return string(secret.Data[privateKey.SecretKeyRef.Key]), nil
+}
+
+func buildJobXML(repository virtuslabv1alpha1.ConfigurationRepository, withCredentials bool) (string, error) {
+	data := struct {
+		URL           string
+		Ref           string
+		Path          string
+		CredentialsID string
+	}{
+		URL:  escapeGroovyString(repository.URL),
+		Ref:  escapeGroovyString(defaultRef),
+		Path: escapeGroovyString(defaultPath),
+	}
+	if len(repository.Ref) > 0 {
+		data.Ref = escapeGroovyString(repository.Ref)
+	}
+	if len(repository.Path) > 0 {
+		data.Path = escapeGroovyString(repository.Path)
+	}
+	if withCredentials {
+		data.CredentialsID = CredentialsID
+	}
+
+	var script bytes.Buffer
+	if err := configurationPipelineTemplate.Execute(&script, data); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(configurationJobXMLFmt, xmlEscaper.Replace(script.String())), nil
+}
+
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "'", "&apos;", `"`, "&quot;")
+
+// escapeGroovyString escapes the value to be used inside single-quoted groovy string
+func escapeGroovyString(value string) string {
+	return strings.Replace(strings.Replace(value, `\`, `\\`, -1), `'`, `\'`, -1)
+}
+
+var configurationPipelineTemplate = template.Must(template.New(ConfigurationJobName).Parse(`import com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey
+import com.cloudbees.jenkins.plugins.sshcredentials.impl.BasicSSHUserPrivateKey.DirectEntryPrivateKeySource
+import com.cloudbees.plugins.credentials.CredentialsScope
+import com.cloudbees.plugins.credentials.SystemCredentialsProvider
+import com.cloudbees.plugins.credentials.domains.Domain
+
+def repositoryURL = '{{ .URL }}'
+def repositoryRef = '{{ .Ref }}'
+def configurationPath = '{{ .Path }}'
+def credentialsID = '{{ .CredentialsID }}'
+
+// builds triggered by SCM polling don't have the private key, the credentials are already configured then
+if (credentialsID && params.` + privateKeyParameterName + `) {
+    configureCredentials(credentialsID, params.` + privateKeyParameterName + `)
+}
+
+node('master') {
+    stage('Checkout') {
+        def remoteConfig = [url: repositoryURL]
+        if (credentialsID) {
+            remoteConfig.credentialsId = credentialsID
+        }
+        checkout([$class: 'GitSCM', branches: [[name: repositoryRef]], userRemoteConfigs: [remoteConfig]])
+    }
+
+    def workspace = pwd()
+    def scriptsText = sh(script: "ls ${configurationPath} | sort", returnStdout: true).trim()
+    def scripts = []
+    scripts.addAll(scriptsText.tokenize('\n'))
+
+    for(script in scripts) {
+        stage(script) {
+            if(script.endsWith(".groovy")) {
+                load "${configurationPath}/${script}"
+            } else if(script.endsWith(".yaml") || script.endsWith(".yml")) {
+                applyConfigurationAsCode("${workspace}/${configurationPath}/${script}")
+            } else {
+                println "Skipping unsupported file '${script}'"
+            }
+        }
+    }
+}
+
+@NonCPS
+def configureCredentials(String id, String privateKey) {
+    def credentials = new BasicSSHUserPrivateKey(
+            CredentialsScope.GLOBAL,
+            id,
+            "git",
+            new DirectEntryPrivateKeySource(privateKey),
+            "",
+            id
+    )
+    def store = SystemCredentialsProvider.getInstance().getStore()
+    def existing = store.getCredentials(Domain.global()).find { it.id == id }
+    if (existing) {
+        store.updateCredentials(Domain.global(), existing, credentials)
+    } else {
+        store.addCredentials(Domain.global(), credentials)
+    }
+}
+
+@NonCPS
+def applyConfigurationAsCode(String path) {
+    def pluginManager = jenkins.model.Jenkins.getInstance().getPluginManager()
+    if(pluginManager.getPlugin('configuration-as-code') == null) {
+        throw new IllegalStateException("Plugin 'configuration-as-code' is required to apply '${path}'")
+    }
+    def configurationAsCode = pluginManager.uberClassLoader.loadClass('io.jenkins.plugins.casc.ConfigurationAsCode')
+    configurationAsCode.get().configure(path)
+}
+`))
+
+const configurationJobXMLFmt = `<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job@2.31">
+  <actions/>
+  <description>Apply Jenkins configuration from Git repository</description>
+  <keepDependencies>false</keepDependencies>
+  <properties>
+    <org.jenkinsci.plugins.workflow.job.properties.DisableConcurrentBuildsJobProperty/>
+    <hudson.model.ParametersDefinitionProperty>
+      <parameterDefinitions>
+        <hudson.model.StringParameterDefinition>
+          <name>` + privateKeyParameterName + `</name>
+          <description></description>
+          <defaultValue></defaultValue>
+          <trim>false</trim>
+        </hudson.model.StringParameterDefinition>
+      </parameterDefinitions>
+    </hudson.model.ParametersDefinitionProperty>
+    <org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+      <triggers>
+        <hudson.triggers.SCMTrigger>
+          <spec>` + pollSCMSchedule + `</spec>
+          <ignorePostCommitHooks>false</ignorePostCommitHooks>
+        </hudson.triggers.SCMTrigger>
+      </triggers>
+    </org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+  </properties>
+  <definition class="org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition" plugin="workflow-cps@2.61">
+    <script>%s</script>
+    <sandbox>false</sandbox>
+  </definition>
+  <triggers/>
+  <disabled>false</disabled>
+</flow-definition>
+`
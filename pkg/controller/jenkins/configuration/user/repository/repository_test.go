@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildJobXML(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		jobXML, err := buildJobXML(virtuslabv1alpha1.ConfigurationRepository{URL: "https://github.com/VirtusLab/jenkins-configuration.git"}, false)
+
+		assert.NoError(t, err)
+		assert.Contains(t, jobXML, "def repositoryURL = &apos;https://github.com/VirtusLab/jenkins-configuration.git&apos;")
+		assert.Contains(t, jobXML, "def repositoryRef = &apos;master&apos;")
+		assert.Contains(t, jobXML, "def configurationPath = &apos;.&apos;")
+		assert.Contains(t, jobXML, "def credentialsID = &apos;&apos;")
+	})
+	t.Run("with credentials", func(t *testing.T) {
+		jobXML, err := buildJobXML(virtuslabv1alpha1.ConfigurationRepository{
+			URL:  "git@github.com:VirtusLab/jenkins-configuration.git",
+			Ref:  "v1.0",
+			Path: "jenkins",
+		}, true)
+
+		assert.NoError(t, err)
+		assert.Contains(t, jobXML, "def repositoryRef = &apos;v1.0&apos;")
+		assert.Contains(t, jobXML, "def configurationPath = &apos;jenkins&apos;")
+		assert.Contains(t, jobXML, "def credentialsID = &apos;"+CredentialsID+"&apos;")
+	})
+	t.Run("escaping", func(t *testing.T) {
+		jobXML, err := buildJobXML(virtuslabv1alpha1.ConfigurationRepository{URL: "https://example.com/repo.git", Path: `it's<dir>`}, false)
+
+		assert.NoError(t, err)
+		assert.Contains(t, jobXML, `def configurationPath = &apos;it\&apos;s&lt;dir&gt;&apos;`)
+	})
+}
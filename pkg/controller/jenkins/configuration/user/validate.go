@@ -9,8 +9,12 @@ import (
 	"strings"
 
 	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user/credentials"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user/xmljobs"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/groovy"
 	"github.com/VirtusLab/jenkins-operator/pkg/log"
 
 	"k8s.io/api/core/v1"
@@ -19,16 +23,179 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 )
 
-// Validate validates Jenkins CR Spec section
-func (r *ReconcileUserConfiguration) Validate(jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
+// Validate validates Jenkins CR Spec section, spans of Jenkins API requests are children of the span of ctx
+func (r *ReconcileUserConfiguration) Validate(ctx context.Context, jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
+	r.jenkinsClient = jenkinsclient.WithContext(ctx, r.jenkinsClient)
 	valid, err := r.validateSeedJobs(jenkins)
 	if !valid || err != nil {
 		return valid, err
 	}
 
+	valid, err = r.validateConfigurationRepository(jenkins)
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateXMLJobs(jenkins)
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateUserConfigurationConfigMap(jenkins)
+	if !valid || err != nil {
+		return valid, err
+	}
+
+	valid, err = r.validateCredentialsSecrets(jenkins)
+	if !valid || err != nil {
+		return valid, err
+	}
+
 	return r.verifyBackup()
 }
 
+func (r *ReconcileUserConfiguration) validateCredentialsSecrets(jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
+	credentialsClient := credentials.New(r.jenkinsClient, r.k8sClient, r.logger)
+	jenkinsCredentials, err := credentialsClient.GetJenkinsCredentials(jenkins)
+	if err != nil {
+		return false, err
+	}
+
+	valid := true
+	for _, jenkinsCredential := range jenkinsCredentials {
+		if len(jenkinsCredential.Spec.SecretName) == 0 {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid JenkinsCredential '%s': secretName can't be empty", jenkinsCredential.Name))
+			valid = false
+			continue
+		}
+
+		secret := &corev1.Secret{}
+		namespaceName := types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkinsCredential.Spec.SecretName}
+		err := r.k8sClient.Get(context.TODO(), namespaceName, secret)
+		if err != nil && apierrors.IsNotFound(err) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid JenkinsCredential '%s': Secret '%s' not found",
+				jenkinsCredential.Name, jenkinsCredential.Spec.SecretName))
+			valid = false
+		} else if err != nil {
+			return false, err
+		}
+	}
+	if !valid {
+		return false, nil
+	}
+
+	allCredentials, err := credentialsClient.GetCredentials(jenkins)
+	if err != nil {
+		return false, err
+	}
+
+	for _, credential := range allCredentials {
+		if err := credentials.Validate(credential); err != nil {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid credentials %s: %s", credential.Source, err))
+			valid = false
+		}
+	}
+	if err := credentials.ValidateUniqueIDs(allCredentials); err != nil {
+		r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid credentials: %s", err))
+		valid = false
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileUserConfiguration) validateXMLJobs(jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
+	valid := true
+	for _, reference := range jenkins.Spec.Configuration.Jobs {
+		logger := r.logger.WithValues("jobsConfigMap", reference.Name).V(log.VWarn)
+
+		if len(reference.Name) == 0 {
+			logger.Info("jobs ConfigMap name can't be empty")
+			valid = false
+			continue
+		}
+
+		configMap := &corev1.ConfigMap{}
+		err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: reference.Name}, configMap)
+		if err != nil && apierrors.IsNotFound(err) {
+			logger.Info("jobs ConfigMap not found")
+			valid = false
+			continue
+		} else if err != nil {
+			return false, err
+		}
+
+		for key := range configMap.Data {
+			if !strings.HasSuffix(key, xmljobs.JobFileSuffix) || len(xmljobs.GetJobName(key)) == 0 {
+				logger.Info(fmt.Sprintf("invalid key '%s', expected format <job-name>%s", key, xmljobs.JobFileSuffix))
+				valid = false
+			}
+		}
+	}
+
+	return valid, nil
+}
+
+// validateUserConfigurationConfigMap rejects keys of the user configuration ConfigMap which the configuration job
+// doesn't apply, the ConfigMap is created by the base configuration, so it's valid when it doesn't exist yet
+func (r *ReconcileUserConfiguration) validateUserConfigurationConfigMap(jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
+	configMapName := resources.GetUserConfigurationConfigMapName(jenkins)
+	configMap := &corev1.ConfigMap{}
+	err := r.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: configMapName}, configMap)
+	if err != nil && apierrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	valid := true
+	for key := range configMap.Data {
+		if !groovy.IsSupportedFile(key) {
+			r.logger.V(log.VWarn).Info(fmt.Sprintf("Invalid key '%s' in ConfigMap '%s', expected *.groovy, *.yaml or *.yml file",
+				key, configMapName))
+			valid = false
+		}
+	}
+
+	return valid, nil
+}
+
+func (r *ReconcileUserConfiguration) validateConfigurationRepository(jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
+	repository := jenkins.Spec.Configuration.Repository
+	if repository == nil {
+		return true, nil
+	}
+	logger := r.logger.WithValues("configurationRepository", fmt.Sprintf("%+v", *repository)).V(log.VWarn)
+
+	if len(repository.URL) == 0 {
+		logger.Info("repository url can't be empty")
+		return false, nil
+	}
+
+	if strings.Contains(repository.URL, "git@") && repository.PrivateKey.SecretKeyRef == nil {
+		logger.Info("private key can't be empty while using ssh repository url")
+		return false, nil
+	}
+
+	if repository.PrivateKey.SecretKeyRef != nil {
+		secret := &corev1.Secret{}
+		namespaceName := types.NamespacedName{Namespace: jenkins.Namespace, Name: repository.PrivateKey.SecretKeyRef.Name}
+		err := r.k8sClient.Get(context.TODO(), namespaceName, secret)
+		if err != nil && apierrors.IsNotFound(err) {
+			logger.Info("secret not found")
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+
+		if err := validatePrivateKey(string(secret.Data[repository.PrivateKey.SecretKeyRef.Key]), resources.IsFIPSModeEnabled(jenkins)); err != nil {
+			logger.Info(fmt.Sprintf("private key is invalid: %s", err))
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (r *ReconcileUserConfiguration) validateSeedJobs(jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
 	valid := true
 	if jenkins.Spec.SeedJobs != nil {
@@ -67,7 +234,7 @@ func (r *ReconcileUserConfiguration) validateSeedJobs(jenkins *virtuslabv1alpha1
 					valid = false
 				}
 
-				if err := validatePrivateKey(privateKey); err != nil {
+				if err := validatePrivateKey(privateKey, resources.IsFIPSModeEnabled(jenkins)); err != nil {
 					logger.Info(fmt.Sprintf("private key is invalid: %s", err))
 					valid = false
 				}
@@ -77,7 +244,8 @@ func (r *ReconcileUserConfiguration) validateSeedJobs(jenkins *virtuslabv1alpha1
 	return valid, nil
 }
 
-func validatePrivateKey(privateKey string) error {
+// validatePrivateKey checks if the deploy key is valid PEM encoded RSA key, in FIPS mode the key size has to be FIPS approved
+func validatePrivateKey(privateKey string, fipsMode bool) error {
 	block, _ := pem.Decode([]byte(privateKey))
 	if block == nil {
 		return errors.New("failed to decode PEM block")
@@ -93,6 +261,11 @@ func validatePrivateKey(privateKey string) error {
 		return err
 	}
 
+	if fipsMode && !resources.IsFIPSApprovedPublicKey(&priv.PublicKey) {
+		return fmt.Errorf("%d bits RSA key isn't FIPS approved, at least %d bits are required",
+			priv.N.BitLen(), resources.FIPSMinRSAKeySize)
+	}
+
 	return nil
 }
 
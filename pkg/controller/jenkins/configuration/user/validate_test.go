@@ -2,10 +2,15 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"testing"
 
 	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
 
 	"github.com/stretchr/testify/assert"
@@ -230,7 +235,7 @@ func TestValidateSeedJobs(t *testing.T) {
 				err := fakeClient.Create(context.TODO(), testingData.secret)
 				assert.NoError(t, err)
 			}
-			userReconcileLoop := New(fakeClient, nil, logf.ZapLogger(false), nil)
+			userReconcileLoop := New(fakeClient, nil, nil, logf.ZapLogger(false), nil)
 			result, err := userReconcileLoop.validateSeedJobs(testingData.jenkins)
 			assert.NoError(t, err)
 			assert.Equal(t, testingData.expectedResult, result)
@@ -238,6 +243,195 @@ func TestValidateSeedJobs(t *testing.T) {
 	}
 }
 
+func TestValidatePrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shortPrivateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	t.Run("valid key", func(t *testing.T) {
+		assert.NoError(t, validatePrivateKey(fakePrivateKey, false))
+	})
+	t.Run("valid key in FIPS mode", func(t *testing.T) {
+		assert.NoError(t, validatePrivateKey(fakePrivateKey, true))
+	})
+	t.Run("invalid key", func(t *testing.T) {
+		assert.Error(t, validatePrivateKey(fakeInvalidPrivateKey, false))
+	})
+	t.Run("short key", func(t *testing.T) {
+		assert.NoError(t, validatePrivateKey(shortPrivateKey, false))
+	})
+	t.Run("short key in FIPS mode", func(t *testing.T) {
+		assert.Error(t, validatePrivateKey(shortPrivateKey, true))
+	})
+}
+
+func TestValidateConfigurationRepository(t *testing.T) {
+	privateKeySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "deploy-keys"},
+		Data: map[string][]byte{
+			"configuration": []byte(fakePrivateKey),
+		},
+	}
+	privateKey := virtuslabv1alpha1.PrivateKey{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "deploy-keys"},
+			Key:                  "configuration",
+		},
+	}
+	data := []struct {
+		description    string
+		repository     *virtuslabv1alpha1.ConfigurationRepository
+		secret         *corev1.Secret
+		expectedResult bool
+	}{
+		{
+			description:    "Valid without repository",
+			expectedResult: true,
+		},
+		{
+			description:    "Valid with public repository",
+			repository:     &virtuslabv1alpha1.ConfigurationRepository{URL: "https://github.com/VirtusLab/jenkins-configuration.git"},
+			expectedResult: true,
+		},
+		{
+			description:    "Invalid without url",
+			repository:     &virtuslabv1alpha1.ConfigurationRepository{Ref: "master"},
+			expectedResult: false,
+		},
+		{
+			description:    "Invalid with ssh url and empty private key",
+			repository:     &virtuslabv1alpha1.ConfigurationRepository{URL: "git@github.com:VirtusLab/jenkins-configuration.git"},
+			expectedResult: false,
+		},
+		{
+			description:    "Invalid with missing secret",
+			repository:     &virtuslabv1alpha1.ConfigurationRepository{URL: "git@github.com:VirtusLab/jenkins-configuration.git", PrivateKey: privateKey},
+			expectedResult: false,
+		},
+		{
+			description:    "Valid with private key and secret",
+			repository:     &virtuslabv1alpha1.ConfigurationRepository{URL: "git@github.com:VirtusLab/jenkins-configuration.git", PrivateKey: privateKey},
+			secret:         privateKeySecret,
+			expectedResult: true,
+		},
+	}
+
+	for _, testingData := range data {
+		t.Run(fmt.Sprintf("Testing '%s'", testingData.description), func(t *testing.T) {
+			fakeClient := fake.NewFakeClient()
+			if testingData.secret != nil {
+				err := fakeClient.Create(context.TODO(), testingData.secret)
+				assert.NoError(t, err)
+			}
+			jenkins := &virtuslabv1alpha1.Jenkins{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+				Spec: virtuslabv1alpha1.JenkinsSpec{
+					Configuration: virtuslabv1alpha1.JenkinsConfiguration{Repository: testingData.repository},
+				},
+			}
+			userReconcileLoop := New(fakeClient, nil, nil, logf.ZapLogger(false), nil)
+			result, err := userReconcileLoop.validateConfigurationRepository(jenkins)
+			assert.NoError(t, err)
+			assert.Equal(t, testingData.expectedResult, result)
+		})
+	}
+}
+
+func TestValidateXMLJobs(t *testing.T) {
+	data := []struct {
+		description    string
+		configMap      *corev1.ConfigMap
+		expectedResult bool
+	}{
+		{
+			description: "Valid job files",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jobs"},
+				Data:       map[string]string{"build.xml": "<project/>"},
+			},
+			expectedResult: true,
+		},
+		{
+			description: "Invalid key without xml suffix",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jobs"},
+				Data:       map[string]string{"build": "<project/>"},
+			},
+			expectedResult: false,
+		},
+		{
+			description:    "Invalid with missing ConfigMap",
+			expectedResult: false,
+		},
+	}
+
+	for _, testingData := range data {
+		t.Run(fmt.Sprintf("Testing '%s'", testingData.description), func(t *testing.T) {
+			fakeClient := fake.NewFakeClient()
+			if testingData.configMap != nil {
+				err := fakeClient.Create(context.TODO(), testingData.configMap)
+				assert.NoError(t, err)
+			}
+			jenkins := &virtuslabv1alpha1.Jenkins{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+				Spec: virtuslabv1alpha1.JenkinsSpec{
+					Configuration: virtuslabv1alpha1.JenkinsConfiguration{
+						Jobs: []virtuslabv1alpha1.ConfigMapReference{{Name: "jobs"}},
+					},
+				},
+			}
+			userReconcileLoop := New(fakeClient, nil, nil, logf.ZapLogger(false), nil)
+			result, err := userReconcileLoop.validateXMLJobs(jenkins)
+			assert.NoError(t, err)
+			assert.Equal(t, testingData.expectedResult, result)
+		})
+	}
+}
+
+func TestValidateUserConfigurationConfigMap(t *testing.T) {
+	data := []struct {
+		description    string
+		configMapData  map[string]string
+		expectedResult bool
+	}{
+		{
+			description:    "Valid groovy script and configuration as code files",
+			configMapData:  map[string]string{"1-configure-theme.groovy": "", "2-casc.yaml": "", "3-casc.yml": ""},
+			expectedResult: true,
+		},
+		{
+			description:    "Invalid key of unsupported file",
+			configMapData:  map[string]string{"1-configure-theme.groovy": "", "2-casc.json": ""},
+			expectedResult: false,
+		},
+		{
+			description:    "Valid without ConfigMap",
+			expectedResult: true,
+		},
+	}
+
+	for _, testingData := range data {
+		t.Run(fmt.Sprintf("Testing '%s'", testingData.description), func(t *testing.T) {
+			jenkins := &virtuslabv1alpha1.Jenkins{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+			}
+			fakeClient := fake.NewFakeClient()
+			if testingData.configMapData != nil {
+				configMap := resources.NewUserConfigurationConfigMap(jenkins)
+				configMap.Data = testingData.configMapData
+				err := fakeClient.Create(context.TODO(), configMap)
+				assert.NoError(t, err)
+			}
+			userReconcileLoop := New(fakeClient, nil, nil, logf.ZapLogger(false), nil)
+			result, err := userReconcileLoop.validateUserConfigurationConfigMap(jenkins)
+			assert.NoError(t, err)
+			assert.Equal(t, testingData.expectedResult, result)
+		})
+	}
+}
+
 func TestReconcileUserConfiguration_verifyBackupAmazonS3(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,2 @@
+// Package xmljobs implements import of Jenkins jobs from config.xml files stored in ConfigMaps
+package xmljobs
@@ -0,0 +1,126 @@
+package xmljobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+	"github.com/VirtusLab/jenkins-operator/pkg/log"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8s "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// JobFileSuffix is the required suffix of ConfigMap keys which contain Jenkins job config.xml
+const JobFileSuffix = ".xml"
+
+// XMLJobs defines API for importing Jenkins jobs from config.xml files
+type XMLJobs struct {
+	jenkinsClient jenkinsclient.Jenkins
+	k8sClient     k8s.Client
+	logger        logr.Logger
+}
+
+// New creates XMLJobs object
+func New(jenkinsClient jenkinsclient.Jenkins, k8sClient k8s.Client, logger logr.Logger) *XMLJobs {
+	return &XMLJobs{
+		jenkinsClient: jenkinsClient,
+		k8sClient:     k8sClient,
+		logger:        logger,
+	}
+}
+
+// EnsureJobs creates or updates Jenkins jobs from every ConfigMap referenced in Jenkins.Spec.Configuration.Jobs,
+// the jobs are posted to Jenkins only when the hash of the ConfigMaps data differs from Jenkins.Status.JobsHash
+func (x *XMLJobs) EnsureJobs(jenkins *virtuslabv1alpha1.Jenkins) error {
+	var configMaps []corev1.ConfigMap
+	for _, reference := range jenkins.Spec.Configuration.Jobs {
+		configMap := &corev1.ConfigMap{}
+		err := x.k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: reference.Name}, configMap)
+		if err != nil {
+			return err
+		}
+
+		if err = x.ensureWatchLabels(jenkins, configMap); err != nil {
+			return err
+		}
+		configMaps = append(configMaps, *configMap)
+	}
+
+	hash := calculateHash(configMaps)
+	if hash == jenkins.Status.JobsHash {
+		return nil
+	}
+
+	for _, configMap := range configMaps {
+		for _, key := range getSortedKeys(configMap.Data) {
+			jobName := GetJobName(key)
+			_, created, err := x.jenkinsClient.CreateOrUpdateJob(configMap.Data[key], jobName)
+			if err != nil {
+				return err
+			}
+			if created {
+				x.logger.Info(fmt.Sprintf("'%s' job has been created from ConfigMap '%s'", jobName, configMap.Name))
+			} else {
+				x.logger.V(log.VDebug).Info(fmt.Sprintf("'%s' job has been updated from ConfigMap '%s'", jobName, configMap.Name))
+			}
+		}
+	}
+
+	jenkins.Status.JobsHash = hash
+	return x.k8sClient.Status().Update(context.TODO(), jenkins)
+}
+
+// ensureWatchLabels labels the ConfigMap so its changes trigger the reconciliation loop
+func (x *XMLJobs) ensureWatchLabels(jenkins *virtuslabv1alpha1.Jenkins, configMap *corev1.ConfigMap) error {
+	requiredLabels := resources.BuildLabelsForWatchedResources(jenkins)
+	changed := false
+	if configMap.ObjectMeta.Labels == nil {
+		configMap.ObjectMeta.Labels = map[string]string{}
+	}
+	for key, value := range requiredLabels {
+		if configMap.ObjectMeta.Labels[key] != value {
+			configMap.ObjectMeta.Labels[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	return x.k8sClient.Update(context.TODO(), configMap)
+}
+
+// GetJobName returns Jenkins job name for the ConfigMap key
+func GetJobName(key string) string {
+	return strings.TrimSuffix(key, JobFileSuffix)
+}
+
+func getSortedKeys(data map[string]string) []string {
+	var keys []string
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func calculateHash(configMaps []corev1.ConfigMap) string {
+	hash := sha256.New()
+	for _, configMap := range configMaps {
+		hash.Write([]byte(configMap.Name + "\x00"))
+		for _, key := range getSortedKeys(configMap.Data) {
+			hash.Write([]byte(key + "\x00"))
+			hash.Write([]byte(configMap.Data[key] + "\x00"))
+		}
+	}
+	return base64.URLEncoding.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,76 @@
+package xmljobs
+
+import (
+	"context"
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+const jobConfig = "<flow-definition/>"
+
+func TestXMLJobs_EnsureJobs(t *testing.T) {
+	err := virtuslabv1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "legacy-jobs"},
+		Data:       map[string]string{"build.xml": jobConfig},
+	}
+	currentHash := calculateHash([]corev1.ConfigMap{configMap})
+
+	tests := []struct {
+		name         string
+		jobsHash     string
+		expectedCall bool
+		created      bool
+	}{
+		{name: "job created", jobsHash: "", expectedCall: true, created: true},
+		{name: "job updated", jobsHash: "previous-hash", expectedCall: true, created: false},
+		{name: "jobs unchanged", jobsHash: currentHash, expectedCall: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			jenkinsClient := client.NewMockJenkins(ctrl)
+			if test.expectedCall {
+				jenkinsClient.EXPECT().CreateOrUpdateJob(jobConfig, "build").Return(nil, test.created, nil)
+			}
+			jenkins := &virtuslabv1alpha1.Jenkins{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-name", Name: "jenkins-cr-name"},
+				Spec: virtuslabv1alpha1.JenkinsSpec{
+					Configuration: virtuslabv1alpha1.JenkinsConfiguration{
+						Jobs: []virtuslabv1alpha1.ConfigMapReference{{Name: configMap.Name}},
+					},
+				},
+				Status: virtuslabv1alpha1.JenkinsStatus{JobsHash: test.jobsHash},
+			}
+			fakeClient := fake.NewFakeClient()
+			if err := fakeClient.Create(context.TODO(), jenkins); err != nil {
+				t.Fatal(err)
+			}
+			if err := fakeClient.Create(context.TODO(), configMap.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+
+			err := New(jenkinsClient, fakeClient, logf.ZapLogger(false)).EnsureJobs(jenkins)
+
+			assert.NoError(t, err)
+			assert.Equal(t, currentHash, jenkins.Status.JobsHash)
+			current := &virtuslabv1alpha1.Jenkins{}
+			err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: jenkins.Namespace, Name: jenkins.Name}, current)
+			assert.NoError(t, err)
+			assert.Equal(t, currentHash, current.Status.JobsHash)
+		})
+	}
+}
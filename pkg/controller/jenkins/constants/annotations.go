@@ -0,0 +1,11 @@
+package constants
+
+const (
+	// AnnotationSpecHashKey Kubernetes annotation name which contains hash of the Jenkins master pod spec
+	AnnotationSpecHashKey = OperatorName + "/spec-hash"
+	// AnnotationPausedKey Jenkins custom resource annotation name, reconciliation is paused when it's set to "true"
+	AnnotationPausedKey = OperatorName + "/paused"
+	// AnnotationTLSCertificateHashKey Kubernetes annotation name which contains hash of the Jenkins master certificate
+	// the Jenkins master pod has been started with
+	AnnotationTLSCertificateHashKey = OperatorName + "/tls-certificate-hash"
+)
@@ -13,4 +13,18 @@ const (
 
 	// LabelJenkinsCRKey Kubernetes label name which contains Jenkins CR name
 	LabelJenkinsCRKey = "jenkins-cr"
+
+	// LabelCredentialsTypeKey Kubernetes label name which marks Secret to synchronize as Jenkins credentials,
+	// the value is type of the credentials
+	LabelCredentialsTypeKey = OperatorName + "/credentials-type"
+
+	// LabelAgentPodTemplateKey Kubernetes label name which contains agent pod template name of the dedicated agent
+	// service account and its RBAC resources
+	LabelAgentPodTemplateKey = OperatorName + "/agent-pod-template"
+
+	// LabelAgentJenkinsCRKey Kubernetes label name of agent pods which contains name of their Jenkins CR
+	LabelAgentJenkinsCRKey = OperatorName + "/agent-jenkins-cr"
+	// LabelAgentJenkinsNamespaceKey Kubernetes label name of agent pods which contains namespace of their Jenkins CR,
+	// the agents namespace can be shared by Jenkins instances from different namespaces
+	LabelAgentJenkinsNamespaceKey = OperatorName + "/agent-jenkins-namespace"
 )
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"sort"
+	"strings"
 
 	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
 	jenkinsclient "github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/client"
@@ -50,7 +51,9 @@ func (g *Groovy) ConfigureGroovyJob() error {
 	return nil
 }
 
-// EnsureGroovyJob executes groovy script and verifies jenkins job status according to reconciliation loop lifecycle
+// EnsureGroovyJob executes groovy scripts and applies configuration as code files (*.yaml, *.yml) in lexical order,
+// it verifies jenkins job status according to reconciliation loop lifecycle. The job is triggered again every time
+// the data changes, so such configuration is applied without Jenkins restart
 func (g *Groovy) EnsureGroovyJob(secretOrConfigMapData map[string]string, jenkins *virtuslabv1alpha1.Jenkins) (bool, error) {
 	jobsClient := jobs.New(g.jenkinsClient, g.k8sClient, g.logger)
 
@@ -77,6 +80,12 @@ func (g *Groovy) calculateHash(secretOrConfigMapData map[string]string) string {
 	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
 }
 
+// IsSupportedFile tells whether the configuration job applies the file, groovy scripts are executed and YAML files
+// are applied by the configuration as code plugin
+func IsSupportedFile(fileName string) bool {
+	return strings.HasSuffix(fileName, ".groovy") || strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml")
+}
+
 const configurationJobXMLFmt = `<?xml version='1.1' encoding='UTF-8'?>
 <flow-definition plugin="workflow-job@2.31">
   <actions/>
@@ -122,11 +131,27 @@ node(&apos;master&apos;) {
     
     for(script in scripts) {
         stage(script) {
-            load &quot;${scriptsPath}/${script}&quot;
+            if(script.endsWith(&quot;.groovy&quot;)) {
+                load &quot;${scriptsPath}/${script}&quot;
+            } else if(script.endsWith(&quot;.yaml&quot;) || script.endsWith(&quot;.yml&quot;)) {
+                applyConfigurationAsCode(&quot;${scriptsPath}/${script}&quot;)
+            } else {
+                println &quot;Skipping unsupported file &apos;${script}&apos;&quot;
+            }
         }
     }
 }
 
+@NonCPS
+def applyConfigurationAsCode(String path) {
+    def pluginManager = jenkins.model.Jenkins.getInstance().getPluginManager()
+    if(pluginManager.getPlugin(&apos;configuration-as-code&apos;) == null) {
+        throw new IllegalStateException(&quot;Plugin &apos;configuration-as-code&apos; is required to apply &apos;${path}&apos;&quot;)
+    }
+    def configurationAsCode = pluginManager.uberClassLoader.loadClass(&apos;io.jenkins.plugins.casc.ConfigurationAsCode&apos;)
+    configurationAsCode.get().configure(path)
+}
+
 @NonCPS
 def calculateHash(String[] scripts, String scriptsPath) {
     def hash = java.security.MessageDigest.getInstance(&quot;SHA-256&quot;)
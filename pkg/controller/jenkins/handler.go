@@ -1,11 +1,16 @@
 package jenkins
 
 import (
+	"context"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -52,3 +57,73 @@ func (e *enqueueRequestForJenkins) getOwnerReconcileRequests(object metav1.Objec
 
 	return nil
 }
+
+// enqueueRequestForJenkinsCredential enqueues Requests for all Jenkins CRs referenced by JenkinsCredential.
+type enqueueRequestForJenkinsCredential struct{}
+
+func (e *enqueueRequestForJenkinsCredential) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.addReconcileRequests(evt.Object, q)
+}
+
+func (e *enqueueRequestForJenkinsCredential) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.addReconcileRequests(evt.ObjectOld, q)
+	e.addReconcileRequests(evt.ObjectNew, q)
+}
+
+func (e *enqueueRequestForJenkinsCredential) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.addReconcileRequests(evt.Object, q)
+}
+
+func (e *enqueueRequestForJenkinsCredential) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.addReconcileRequests(evt.Object, q)
+}
+
+func (e *enqueueRequestForJenkinsCredential) addReconcileRequests(object runtime.Object, q workqueue.RateLimitingInterface) {
+	jenkinsCredential, ok := object.(*virtuslabv1alpha1.JenkinsCredential)
+	if !ok {
+		return
+	}
+
+	for _, name := range jenkinsCredential.Spec.Jenkins {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: jenkinsCredential.Namespace,
+			Name:      name,
+		}})
+	}
+}
+
+// enqueueRequestForJenkinsCredentialSecret enqueues Requests for all Jenkins CRs which use the secret through JenkinsCredential.
+type enqueueRequestForJenkinsCredentialSecret struct {
+	client client.Client
+}
+
+func (e *enqueueRequestForJenkinsCredentialSecret) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.addReconcileRequests(evt.Meta, q)
+}
+
+func (e *enqueueRequestForJenkinsCredentialSecret) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.addReconcileRequests(evt.MetaNew, q)
+}
+
+func (e *enqueueRequestForJenkinsCredentialSecret) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.addReconcileRequests(evt.Meta, q)
+}
+
+func (e *enqueueRequestForJenkinsCredentialSecret) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.addReconcileRequests(evt.Meta, q)
+}
+
+func (e *enqueueRequestForJenkinsCredentialSecret) addReconcileRequests(object metav1.Object, q workqueue.RateLimitingInterface) {
+	jenkinsCredentialList := &virtuslabv1alpha1.JenkinsCredentialList{}
+	err := e.client.List(context.TODO(), client.InNamespace(object.GetNamespace()), jenkinsCredentialList)
+	if err != nil {
+		return
+	}
+
+	jenkinsHandler := &enqueueRequestForJenkinsCredential{}
+	for i, jenkinsCredential := range jenkinsCredentialList.Items {
+		if jenkinsCredential.Spec.SecretName == object.GetName() {
+			jenkinsHandler.addReconcileRequests(&jenkinsCredentialList.Items[i], q)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package health contains liveness and readiness endpoints of the operator process
+package health
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultAddress is the default address of the health endpoints
+	DefaultAddress = ":8081"
+	// LivenessPath is the path of the liveness endpoint
+	LivenessPath = "/healthz"
+	// ReadinessPath is the path of the readiness endpoint
+	ReadinessPath = "/readyz"
+
+	// CheckLeader passes when the operator pod becomes the leader, the other operator pods aren't ready
+	CheckLeader = "leader"
+	// CheckManager passes when the controller manager is started
+	CheckManager = "manager"
+	// CheckCaches passes when the informer caches of the controller manager are synced
+	CheckCaches = "caches"
+)
+
+// Checks are readiness checks of the operator process, the process is ready when all checks pass
+type Checks struct {
+	sync.RWMutex
+	names  []string
+	passed map[string]bool
+}
+
+// NewChecks creates readiness checks with the names, none of them passes
+func NewChecks(names ...string) *Checks {
+	return &Checks{names: names, passed: map[string]bool{}}
+}
+
+// Pass marks the check as passed, checks don't fail once they pass because the operator process exits when
+// the controller manager stops
+func (c *Checks) Pass(name string) {
+	c.Lock()
+	defer c.Unlock()
+	c.passed[name] = true
+}
+
+// IsReady tells if all checks pass
+func (c *Checks) IsReady() bool {
+	c.RLock()
+	defer c.RUnlock()
+	for _, name := range c.names {
+		if !c.passed[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// ServeHTTP responds 200 when all checks pass and 503 otherwise, the body lists the checks, e.g. '[+]leader ok'
+func (c *Checks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.RLock()
+	var lines []string
+	for _, name := range c.names {
+		if c.passed[name] {
+			lines = append(lines, fmt.Sprintf("[+]%s ok", name))
+		} else {
+			lines = append(lines, fmt.Sprintf("[-]%s failed", name))
+		}
+	}
+	c.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if c.IsReady() {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(lines, "\n"))
+}
+
+// livenessHandler responds 200 while the process serves requests, the operator pods waiting for the leadership are
+// alive, so the leadership and the controller manager aren't checked
+func livenessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// NewServeMux returns handler of the liveness and readiness endpoints
+func NewServeMux(readiness *Checks) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivenessPath, livenessHandler)
+	mux.Handle(ReadinessPath, readiness)
+	return mux
+}
+
+// Serve exposes the liveness and readiness endpoints on the address, it blocks until the server fails
+func Serve(address string, readiness *Checks) error {
+	return http.ListenAndServe(address, NewServeMux(readiness))
+}
@@ -0,0 +1,43 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServeMux(t *testing.T) {
+	readiness := NewChecks(CheckLeader, CheckManager, CheckCaches)
+	mux := NewServeMux(readiness)
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	t.Run("alive while waiting for the leadership", func(t *testing.T) {
+		response := get(LivenessPath)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "ok\n", response.Body.String())
+	})
+	t.Run("not ready until all checks pass", func(t *testing.T) {
+		readiness.Pass(CheckLeader)
+		readiness.Pass(CheckManager)
+
+		response := get(ReadinessPath)
+
+		assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+		assert.Equal(t, "[+]leader ok\n[+]manager ok\n[-]caches failed\n", response.Body.String())
+	})
+	t.Run("ready", func(t *testing.T) {
+		readiness.Pass(CheckCaches)
+
+		response := get(ReadinessPath)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.True(t, readiness.IsReady())
+	})
+}
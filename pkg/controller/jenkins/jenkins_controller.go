@@ -3,12 +3,17 @@ package jenkins
 import (
 	"context"
 	"fmt"
+	"time"
 
 	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/user/backup"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/constants"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/metrics"
 	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/plugins"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/tracing"
 	"github.com/VirtusLab/jenkins-operator/pkg/log"
 
 	"github.com/go-logr/logr"
@@ -17,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -27,18 +33,31 @@ import (
 
 // Add creates a new Jenkins Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
-func Add(mgr manager.Manager, local, minikube bool) error {
-	return add(mgr, newReconciler(mgr, local, minikube))
+func Add(mgr manager.Manager, local, minikube, openshift bool) error {
+	reconciler, err := newReconciler(mgr, local, minikube, openshift)
+	if err != nil {
+		return err
+	}
+	return add(mgr, reconciler)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, local, minikube bool) reconcile.Reconciler {
-	return &ReconcileJenkins{
-		client:   mgr.GetClient(),
-		scheme:   mgr.GetScheme(),
-		local:    local,
-		minikube: minikube,
+func newReconciler(mgr manager.Manager, local, minikube, openshift bool) (reconcile.Reconciler, error) {
+	// the manager cache is restricted to the operator namespace, cluster scoped resources and agents namespaces are
+	// read directly
+	apiClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return nil, err
 	}
+	return &ReconcileJenkins{
+		client:    mgr.GetClient(),
+		apiClient: apiClient,
+		scheme:    mgr.GetScheme(),
+		recorder:  mgr.GetRecorder(constants.OperatorName),
+		local:     local,
+		minikube:  minikube,
+		openshift: openshift,
+	}, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -64,6 +83,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to Secrets owned by Jenkins, e.g. rotated operator credentials
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &virtuslabv1alpha1.Jenkins{},
+	})
+	if err != nil {
+		return err
+	}
+
 	jenkinsHandler := &enqueueRequestForJenkins{}
 	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, jenkinsHandler)
 	if err != nil {
@@ -75,6 +103,16 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	err = c.Watch(&source.Kind{Type: &virtuslabv1alpha1.JenkinsCredential{}}, &enqueueRequestForJenkinsCredential{})
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &enqueueRequestForJenkinsCredentialSecret{client: mgr.GetClient()})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -82,9 +120,11 @@ var _ reconcile.Reconciler = &ReconcileJenkins{}
 
 // ReconcileJenkins reconciles a Jenkins object
 type ReconcileJenkins struct {
-	client          client.Client
-	scheme          *runtime.Scheme
-	local, minikube bool
+	client                     client.Client
+	apiClient                  client.Client
+	scheme                     *runtime.Scheme
+	recorder                   record.EventRecorder
+	local, minikube, openshift bool
 }
 
 // Reconcile it's a main reconciliation loop which maintain desired state based on Jenkins.Spec
@@ -92,7 +132,14 @@ func (r *ReconcileJenkins) Reconcile(request reconcile.Request) (reconcile.Resul
 	logger := r.buildLogger(request.Name)
 	logger.Info("Reconciling Jenkins")
 
-	result, err := r.reconcile(request, logger)
+	ctx, span := tracing.Start(context.Background(), "Reconcile Jenkins", tracing.JenkinsAttributes(request.Namespace, request.Name)...)
+	start := time.Now()
+	result, err := r.reconcile(ctx, request, logger)
+	tracing.End(span, err)
+	metrics.ReconcileDuration.WithLabelValues(request.Namespace, request.Name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.ReconcileErrors.WithLabelValues(request.Namespace, request.Name, metrics.ErrorReason(err)).Inc()
+	}
 	if err != nil && errors.IsConflict(err) {
 		logger.V(log.VWarn).Info(err.Error())
 		return reconcile.Result{Requeue: true}, nil
@@ -103,7 +150,7 @@ func (r *ReconcileJenkins) Reconcile(request reconcile.Request) (reconcile.Resul
 	return result, nil
 }
 
-func (r *ReconcileJenkins) reconcile(request reconcile.Request, logger logr.Logger) (reconcile.Result, error) {
+func (r *ReconcileJenkins) reconcile(ctx context.Context, request reconcile.Request, logger logr.Logger) (reconcile.Result, error) {
 	// Fetch the Jenkins instance
 	jenkins := &virtuslabv1alpha1.Jenkins{}
 	err := r.client.Get(context.TODO(), request.NamespacedName, jenkins)
@@ -118,24 +165,37 @@ func (r *ReconcileJenkins) reconcile(request reconcile.Request, logger logr.Logg
 		return reconcile.Result{}, err
 	}
 
+	paused, err := r.ensurePausedCondition(jenkins, logger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if paused {
+		return reconcile.Result{}, nil // don't requeue, annotation change triggers reconciliation
+	}
+
 	err = r.setDefaults(jenkins, logger)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
 	// Reconcile base configuration
-	baseConfiguration := base.New(r.client, r.scheme, logger, jenkins, r.local, r.minikube)
+	baseConfiguration := base.New(r.client, r.apiClient, r.scheme, r.recorder, logger, jenkins, r.local, r.minikube, r.openshift)
 
-	valid, err := baseConfiguration.Validate(jenkins)
+	phaseCtx, span := tracing.Start(ctx, "Validate base configuration")
+	valid, err := baseConfiguration.Validate(phaseCtx, jenkins)
+	tracing.End(span, err)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 	if !valid {
 		logger.V(log.VWarn).Info("Validation of user configuration failed, please correct Jenkins CR")
-		return reconcile.Result{}, nil // don't requeue
+		return reconcile.Result{}, r.ensureNotReadyCondition(jenkins, conditions.ReasonValidationFailed,
+			"Validation of base configuration failed, please correct Jenkins CR") // don't requeue
 	}
 
-	result, jenkinsClient, err := baseConfiguration.Reconcile()
+	phaseCtx, span = tracing.Start(ctx, "Reconcile base configuration")
+	result, jenkinsClient, err := baseConfiguration.Reconcile(phaseCtx)
+	tracing.End(span, err)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -143,30 +203,53 @@ func (r *ReconcileJenkins) reconcile(request reconcile.Request, logger logr.Logg
 		return result, nil
 	}
 
-	if jenkins.Status.BaseConfigurationCompletedTime == nil {
+	if jenkins.Spec.MaintenanceMode {
+		logger.V(log.VDebug).Info("Jenkins is in maintenance mode, skipping user configuration")
+		// base and user configuration aren't applied in maintenance mode, so Jenkins is ready only when it has been
+		// configured before, e.g. it isn't when Jenkins master pod has been recreated during maintenance
+		if conditions.IsTrue(jenkins.Status, virtuslabv1alpha1.JenkinsConditionBaseConfigured) &&
+			conditions.IsTrue(jenkins.Status, virtuslabv1alpha1.JenkinsConditionUserConfigured) {
+			if err = r.ensureReconciledStatus(jenkins); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return periodicCheckResult(jenkins), nil
+	}
+
+	if !conditions.IsTrue(jenkins.Status, virtuslabv1alpha1.JenkinsConditionBaseConfigured) {
 		logger.Info("Base configuration phase is complete")
-		now := metav1.Now()
-		jenkins.Status.BaseConfigurationCompletedTime = &now
-		err = r.client.Update(context.TODO(), jenkins)
+		if jenkins.Status.BaseConfigurationCompletedTime == nil {
+			now := metav1.Now()
+			jenkins.Status.BaseConfigurationCompletedTime = &now
+		}
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionBaseConfigured, corev1.ConditionTrue,
+			reasonBaseConfigurationCompleted, "Base configuration is completed")
+		err = r.client.Status().Update(context.TODO(), jenkins)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 		logger.Info("Base configuration completed time has been updated")
+		r.recorder.Event(jenkins, corev1.EventTypeNormal, reasonBaseConfigurationCompleted, "Base configuration is completed")
 	}
 
 	// Reconcile user configuration
-	userConfiguration := user.New(r.client, jenkinsClient, logger, jenkins)
+	userConfiguration := user.New(r.client, jenkinsClient, r.recorder, logger, jenkins)
 
-	valid, err = userConfiguration.Validate(jenkins)
+	phaseCtx, span = tracing.Start(ctx, "Validate user configuration")
+	valid, err = userConfiguration.Validate(phaseCtx, jenkins)
+	tracing.End(span, err)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 	if !valid {
 		logger.V(log.VWarn).Info("Validation of user configuration failed, please correct Jenkins CR")
-		return reconcile.Result{}, nil // don't requeue
+		return reconcile.Result{}, r.ensureNotReadyCondition(jenkins, conditions.ReasonValidationFailed,
+			"Validation of user configuration failed, please correct Jenkins CR") // don't requeue
 	}
 
-	result, err = userConfiguration.Reconcile()
+	phaseCtx, span = tracing.Start(ctx, "Reconcile user configuration")
+	result, err = userConfiguration.Reconcile(phaseCtx)
+	tracing.End(span, err)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -174,24 +257,69 @@ func (r *ReconcileJenkins) reconcile(request reconcile.Request, logger logr.Logg
 		return result, nil
 	}
 
-	if jenkins.Status.UserConfigurationCompletedTime == nil {
+	if !conditions.IsTrue(jenkins.Status, virtuslabv1alpha1.JenkinsConditionUserConfigured) {
 		logger.Info("User configuration phase is complete")
-		now := metav1.Now()
-		jenkins.Status.UserConfigurationCompletedTime = &now
-		err = r.client.Update(context.TODO(), jenkins)
+		if jenkins.Status.UserConfigurationCompletedTime == nil {
+			now := metav1.Now()
+			jenkins.Status.UserConfigurationCompletedTime = &now
+		}
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionUserConfigured, corev1.ConditionTrue,
+			reasonUserConfigurationCompleted, "Seed jobs and user configuration are completed")
+		err = r.client.Status().Update(context.TODO(), jenkins)
 		if err != nil {
 			return reconcile.Result{}, err
 		}
 		logger.Info("User configuration completed time has been updated")
+		r.recorder.Event(jenkins, corev1.EventTypeNormal, reasonUserConfigurationCompleted,
+			"Seed jobs and user configuration are completed")
 	}
 
-	return reconcile.Result{}, nil
+	if err = r.ensureReconciledStatus(jenkins); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return periodicCheckResult(jenkins), nil
+}
+
+// periodicCheckResult requeues the reconciliation to look for orphaned agent pods when
+// Jenkins.Spec.Agents.OrphanedPodsGracePeriod is set and for the last successful backup when backup is enabled
+func periodicCheckResult(jenkins *virtuslabv1alpha1.Jenkins) reconcile.Result {
+	result := reconcile.Result{}
+	if jenkins.Spec.Agents.OrphanedPodsGracePeriod != nil {
+		result.RequeueAfter = base.OrphanedAgentPodsCheckPeriod
+	}
+	if jenkins.Spec.Backup == virtuslabv1alpha1.JenkinsBackupTypeAmazonS3 &&
+		(result.RequeueAfter == 0 || backup.CheckPeriod < result.RequeueAfter) {
+		result.RequeueAfter = backup.CheckPeriod
+	}
+	return result
 }
 
 func (r *ReconcileJenkins) buildLogger(jenkinsName string) logr.Logger {
 	return log.Log.WithValues("cr", jenkinsName)
 }
 
+// ensurePausedCondition reflects the jenkins-operator/paused annotation in the status conditions,
+// when reconciliation is paused the operator doesn't mutate anything except the Jenkins CR status
+func (r *ReconcileJenkins) ensurePausedCondition(jenkins *virtuslabv1alpha1.Jenkins, logger logr.Logger) (bool, error) {
+	paused := jenkins.ObjectMeta.Annotations[constants.AnnotationPausedKey] == "true"
+	if paused == conditions.IsTrue(jenkins.Status, virtuslabv1alpha1.JenkinsConditionPaused) {
+		return paused, nil
+	}
+
+	if paused {
+		logger.Info(fmt.Sprintf("Reconciliation is paused by '%s' annotation", constants.AnnotationPausedKey))
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionPaused, corev1.ConditionTrue, "Paused",
+			fmt.Sprintf("Reconciliation is paused by '%s' annotation", constants.AnnotationPausedKey))
+	} else {
+		logger.Info("Reconciliation is resumed")
+		conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionPaused, corev1.ConditionFalse, "Resumed",
+			"Reconciliation is resumed")
+	}
+
+	return paused, r.client.Status().Update(context.TODO(), jenkins)
+}
+
 func (r *ReconcileJenkins) setDefaults(jenkins *virtuslabv1alpha1.Jenkins, logger logr.Logger) error {
 	changed := false
 	if len(jenkins.Spec.Master.Image) == 0 {
@@ -225,7 +225,7 @@ func (jobs *Jobs) removeBuildFromStatus(build virtuslabv1alpha1.Build, jenkins *
 		}
 	}
 	jenkins.Status.Builds = builds
-	err := jobs.k8sClient.Update(context.TODO(), jenkins)
+	err := jobs.k8sClient.Status().Update(context.TODO(), jenkins)
 	if err != nil {
 		return err
 	}
@@ -279,7 +279,7 @@ func (jobs *Jobs) updateBuildStatus(build virtuslabv1alpha1.Build, jenkins *virt
 		build.CreateTime = &now
 		jenkins.Status.Builds = append(jenkins.Status.Builds, build)
 	}
-	err := jobs.k8sClient.Update(context.TODO(), jenkins)
+	err := jobs.k8sClient.Status().Update(context.TODO(), jenkins)
 	if err != nil {
 		return err
 	}
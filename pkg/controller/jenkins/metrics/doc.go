@@ -0,0 +1,2 @@
+// Package metrics contains Prometheus metrics exposed by the operator
+package metrics
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// DefaultAddress is the address the metrics are served on by default, it's the metrics port of the operator
+	// deployment
+	DefaultAddress = ":60000"
+	// Path is the HTTP path of the metrics
+	Path = "/metrics"
+
+	metricsNamespace = "jenkins_operator"
+)
+
+var (
+	// OrphanedAgentPods is the number of agent pods of the Jenkins instance without Jenkins agent which are still in
+	// the grace period of the garbage collection
+	OrphanedAgentPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "orphaned_agent_pods",
+		Help:      "Number of agent pods without Jenkins agent waiting for the end of the grace period",
+	}, []string{"namespace", "jenkins"})
+
+	// DeletedOrphanedAgentPods is the number of agent pods of the Jenkins instance deleted by the garbage collection
+	DeletedOrphanedAgentPods = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "orphaned_agent_pods_deleted_total",
+		Help:      "Number of agent pods without Jenkins agent deleted after the grace period",
+	}, []string{"namespace", "jenkins"})
+
+	// ReconcileDuration is the duration of reconciliation loops of the Jenkins instance
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of reconciliation loops in seconds",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"namespace", "jenkins"})
+
+	// ReconcileErrors is the number of failed reconciliation loops of the Jenkins instance by the Kubernetes API reason
+	// of the error, see ErrorReason
+	ReconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Number of failed reconciliation loops",
+	}, []string{"namespace", "jenkins", "reason"})
+
+	// JenkinsAPIRequests is the number of requests sent to Jenkins API by the HTTP method and status code
+	JenkinsAPIRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "jenkins_api_requests_total",
+		Help:      "Number of requests sent to Jenkins API",
+	}, []string{"method", "code"})
+
+	// JenkinsAPIRequestDuration is the latency of requests sent to Jenkins API by the HTTP method
+	JenkinsAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "jenkins_api_request_duration_seconds",
+		Help:      "Latency of requests sent to Jenkins API in seconds",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	// JenkinsRestarts is the number of Jenkins master pod restarts of the Jenkins instance by the reason, e.g.
+	// SpecChanged
+	JenkinsRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "jenkins_restarts_total",
+		Help:      "Number of Jenkins master pod restarts",
+	}, []string{"namespace", "jenkins", "reason"})
+)
+
+func init() {
+	prometheus.MustRegister(OrphanedAgentPods, DeletedOrphanedAgentPods, ReconcileDuration, ReconcileErrors,
+		JenkinsAPIRequests, JenkinsAPIRequestDuration, JenkinsRestarts)
+}
+
+// ErrorReason returns the Kubernetes API reason of the error, e.g. Conflict, or Unknown for errors which don't come
+// from Kubernetes API
+func ErrorReason(err error) string {
+	if reason := errors.ReasonForError(err); len(reason) > 0 {
+		return string(reason)
+	}
+	return "Unknown"
+}
+
+// InstrumentJenkinsAPI wraps the transport of Jenkins API client to measure the requests
+func InstrumentJenkinsAPI(transport http.RoundTripper) http.RoundTripper {
+	return promhttp.InstrumentRoundTripperCounter(JenkinsAPIRequests,
+		promhttp.InstrumentRoundTripperDuration(JenkinsAPIRequestDuration, transport))
+}
+
+// Serve exposes the metrics on the address, it blocks until the server fails
+func Serve(address string) error {
+	mux := http.NewServeMux()
+	mux.Handle(Path, promhttp.Handler())
+	return http.ListenAndServe(address, mux)
+}
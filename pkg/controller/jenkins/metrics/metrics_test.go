@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestErrorReason(t *testing.T) {
+	resource := schema.GroupResource{Resource: "jenkins"}
+
+	assert.Equal(t, "Conflict", ErrorReason(apierrors.NewConflict(resource, "example", errors.New("modified"))))
+	assert.Equal(t, "NotFound", ErrorReason(apierrors.NewNotFound(resource, "example")))
+	assert.Equal(t, "Unknown", ErrorReason(errors.New("couldn't connect to Jenkins")))
+}
+
+func TestInstrumentJenkinsAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: InstrumentJenkinsAPI(http.DefaultTransport)}
+
+	response, err := client.Get(server.URL + "/api/json")
+	assert.NoError(t, err)
+	_ = response.Body.Close()
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(JenkinsAPIRequests.WithLabelValues("get", "403")))
+}
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strconv"
+
+	monitoringv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/monitoring/v1"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// servicePortName is the name of the metrics port of the operator Service and the operator deployment
+const servicePortName = "metrics"
+
+// GetServiceName returns the name of the Service exposing metrics of the operator pods
+func GetServiceName(operatorName string) string {
+	return operatorName + "-metrics"
+}
+
+// NewService builds the Service exposing the metrics port of the operator pods, the pods are selected by
+// 'name: <operator name>' label of the operator deployment
+func NewService(namespace, operatorName string, port int32) *corev1.Service {
+	labels := map[string]string{"name": operatorName}
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{Kind: "Service", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetServiceName(operatorName),
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: labels,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       servicePortName,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(port)),
+				},
+			},
+		},
+	}
+}
+
+// NewServiceMonitor builds Prometheus Operator ServiceMonitor scraping the Service built by NewService
+func NewServiceMonitor(namespace, operatorName string) *monitoringv1.ServiceMonitor {
+	labels := map[string]string{"name": operatorName}
+	return &monitoringv1.ServiceMonitor{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       monitoringv1.ServiceMonitorKind,
+			APIVersion: monitoringv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      operatorName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Selector: metav1.LabelSelector{MatchLabels: labels},
+			// the namespace label of the metrics is the namespace of the Jenkins CR, not of the operator
+			Endpoints: []monitoringv1.Endpoint{{Port: servicePortName, Path: Path, HonorLabels: true}},
+		},
+	}
+}
+
+// GetPort returns the port of the metrics address, e.g. 60000 of ':60000'
+func GetPort(address string) (int32, error) {
+	_, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid metrics address '%s'", address)
+	}
+	port, err := strconv.ParseInt(portString, 10, 32)
+	if err != nil || port <= 0 {
+		return 0, errors.Errorf("invalid port of metrics address '%s'", address)
+	}
+	return int32(port), nil
+}
+
+// EnsureServiceMonitor creates or updates the Service and Prometheus Operator ServiceMonitor of the operator metrics,
+// they aren't deleted together with the operator
+func EnsureServiceMonitor(k8sClient client.Client, namespace, operatorName string, port int32) error {
+	service := NewService(namespace, operatorName, port)
+	currentService := &corev1.Service{}
+	err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: service.Name, Namespace: namespace}, currentService)
+	if err != nil && apierrors.IsNotFound(err) {
+		if err := k8sClient.Create(context.TODO(), service); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else if !reflect.DeepEqual(currentService.Spec.Selector, service.Spec.Selector) ||
+		!reflect.DeepEqual(currentService.Spec.Ports, service.Spec.Ports) {
+		// cluster IP is immutable, so the current Service is updated
+		currentService.Spec.Selector = service.Spec.Selector
+		currentService.Spec.Ports = service.Spec.Ports
+		if err := k8sClient.Update(context.TODO(), currentService); err != nil {
+			return err
+		}
+	}
+
+	serviceMonitor := NewServiceMonitor(namespace, operatorName)
+	currentServiceMonitor := &monitoringv1.ServiceMonitor{}
+	err = k8sClient.Get(context.TODO(), types.NamespacedName{Name: serviceMonitor.Name, Namespace: namespace}, currentServiceMonitor)
+	if err != nil && apierrors.IsNotFound(err) {
+		return k8sClient.Create(context.TODO(), serviceMonitor)
+	} else if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(currentServiceMonitor.Spec, serviceMonitor.Spec) {
+		return nil
+	}
+	// custom resources can't be updated without resource version, so the current object is updated
+	currentServiceMonitor.Spec = serviceMonitor.Spec
+	return k8sClient.Update(context.TODO(), currentServiceMonitor)
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	monitoringv1 "github.com/VirtusLab/jenkins-operator/pkg/apis/monitoring/v1"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetPort(t *testing.T) {
+	port, err := GetPort(DefaultAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(60000), port)
+
+	port, err = GetPort("127.0.0.1:8383")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(8383), port)
+
+	_, err = GetPort("60000")
+	assert.Error(t, err)
+	_, err = GetPort(":metrics")
+	assert.Error(t, err)
+}
+
+func TestEnsureServiceMonitor(t *testing.T) {
+	err := monitoringv1.SchemeBuilder.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+	k8sClient := fake.NewFakeClient()
+
+	err = EnsureServiceMonitor(k8sClient, "operator", "jenkins-operator", 60000)
+	assert.NoError(t, err)
+	// the port has changed
+	err = EnsureServiceMonitor(k8sClient, "operator", "jenkins-operator", 8383)
+	assert.NoError(t, err)
+
+	service := &corev1.Service{}
+	err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "operator", Name: "jenkins-operator-metrics"}, service)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "jenkins-operator"}, service.Spec.Selector)
+	if assert.Len(t, service.Spec.Ports, 1) {
+		assert.Equal(t, int32(8383), service.Spec.Ports[0].Port)
+	}
+
+	serviceMonitor := &monitoringv1.ServiceMonitor{}
+	err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: "operator", Name: "jenkins-operator"}, serviceMonitor)
+	assert.NoError(t, err)
+	assert.Equal(t, []monitoringv1.Endpoint{{Port: "metrics", Path: "/metrics", HonorLabels: true}}, serviceMonitor.Spec.Endpoints)
+}
@@ -78,8 +78,8 @@ func TestVerifyDependencies(t *testing.T) {
 		},
 	}
 
-	debug := false
-	log.SetupLogger(&debug)
+	err := log.SetupLogger(false, "")
+	assert.NoError(t, err)
 
 	for index, testingData := range data {
 		t.Run(fmt.Sprintf("Testing %d data", index), func(t *testing.T) {
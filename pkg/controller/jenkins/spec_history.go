@@ -0,0 +1,132 @@
+package jenkins
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// specHistoryLimit is the number of spec generations kept in the status
+	specHistoryLimit = 10
+	// masterSpecField is the JSON name of Jenkins.Spec.Master, its image and plugins are reported separately
+	masterSpecField = "master"
+)
+
+// masterSpecFields are fields of Jenkins.Spec.Master reported as separate changes, e.g. master.image
+var masterSpecFields = []string{"image", "plugins"}
+
+// appendSpecHistory records the applied generation of the spec in the status, the changes are found by comparing
+// field hashes with the previous generation, the oldest generations are dropped above the limit
+func appendSpecHistory(status *virtuslabv1alpha1.JenkinsStatus, spec virtuslabv1alpha1.JenkinsSpec, generation int64) error {
+	hash, err := calculateJSONHash(spec)
+	if err != nil {
+		return err
+	}
+	fieldHashes, err := calculateSpecFieldHashes(spec)
+	if err != nil {
+		return err
+	}
+
+	change := virtuslabv1alpha1.SpecChange{
+		Generation:  generation,
+		Hash:        hash,
+		Time:        metav1.Now(),
+		FieldHashes: fieldHashes,
+	}
+	if count := len(status.SpecHistory); count > 0 {
+		previous := &status.SpecHistory[count-1]
+		change.Changes = getChangedFields(previous.FieldHashes, fieldHashes)
+		previous.FieldHashes = nil
+	}
+
+	status.SpecHistory = append(status.SpecHistory, change)
+	if len(status.SpecHistory) > specHistoryLimit {
+		status.SpecHistory = status.SpecHistory[len(status.SpecHistory)-specHistoryLimit:]
+	}
+	return nil
+}
+
+// calculateSpecFieldHashes returns hashes of the spec fields by their JSON names, the image and plugins of the master
+// are hashed separately from the rest of the master, e.g. master.image and master
+func calculateSpecFieldHashes(spec virtuslabv1alpha1.JenkinsSpec) (map[string]string, error) {
+	fields, err := toJSONFields(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if master, found := fields[masterSpecField]; found {
+		delete(fields, masterSpecField)
+		masterFields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(master, &masterFields); err != nil {
+			return nil, err
+		}
+		for _, name := range masterSpecFields {
+			if value, found := masterFields[name]; found {
+				fields[masterSpecField+"."+name] = value
+				delete(masterFields, name)
+			}
+		}
+		if len(masterFields) > 0 {
+			if fields[masterSpecField], err = json.Marshal(masterFields); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	hashes := map[string]string{}
+	for name, value := range fields {
+		hashes[name] = calculateHash(value)
+	}
+	return hashes, nil
+}
+
+// getChangedFields returns sorted names of the fields which are changed, added or removed
+func getChangedFields(previous, current map[string]string) []string {
+	changed := map[string]bool{}
+	for name, hash := range current {
+		if previous[name] != hash {
+			changed[name] = true
+		}
+	}
+	for name := range previous {
+		if _, found := current[name]; !found {
+			changed[name] = true
+		}
+	}
+
+	var names []string
+	for name := range changed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func toJSONFields(value interface{}) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	return fields, json.Unmarshal(data, &fields)
+}
+
+func calculateJSONHash(value interface{}) (string, error) {
+	// json encoder sorts map keys so the hash is stable
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return calculateHash(data), nil
+}
+
+func calculateHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return base64.URLEncoding.EncodeToString(hash[:])
+}
@@ -0,0 +1,74 @@
+package jenkins
+
+import (
+	"testing"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendSpecHistory(t *testing.T) {
+	spec := virtuslabv1alpha1.JenkinsSpec{
+		Master: virtuslabv1alpha1.JenkinsMaster{
+			Image:   "jenkins/jenkins:2.176.2",
+			Plugins: map[string][]string{"job-dsl:1.74": {}},
+		},
+	}
+
+	t.Run("first generation", func(t *testing.T) {
+		status := &virtuslabv1alpha1.JenkinsStatus{}
+
+		err := appendSpecHistory(status, spec, 1)
+
+		assert.NoError(t, err)
+		if assert.Len(t, status.SpecHistory, 1) {
+			assert.Equal(t, int64(1), status.SpecHistory[0].Generation)
+			assert.NotEmpty(t, status.SpecHistory[0].Hash)
+			assert.False(t, status.SpecHistory[0].Time.IsZero())
+			assert.Empty(t, status.SpecHistory[0].Changes)
+			assert.Contains(t, status.SpecHistory[0].FieldHashes, "master.image")
+		}
+	})
+	t.Run("changes since the previous generation", func(t *testing.T) {
+		status := &virtuslabv1alpha1.JenkinsStatus{}
+		err := appendSpecHistory(status, spec, 1)
+		assert.NoError(t, err)
+		changed := *spec.DeepCopy()
+		changed.Master.Image = "jenkins/jenkins:2.190.1"
+		changed.Master.Executors = new(int32)
+		changed.SeedJobs = []virtuslabv1alpha1.SeedJob{{ID: "jenkins-operator"}}
+
+		err = appendSpecHistory(status, changed, 2)
+
+		assert.NoError(t, err)
+		if assert.Len(t, status.SpecHistory, 2) {
+			assert.Nil(t, status.SpecHistory[0].FieldHashes)
+			assert.Equal(t, []string{"master", "master.image", "seedJobs"}, status.SpecHistory[1].Changes)
+			assert.NotEqual(t, status.SpecHistory[0].Hash, status.SpecHistory[1].Hash)
+			assert.NotEmpty(t, status.SpecHistory[1].FieldHashes)
+		}
+
+		err = appendSpecHistory(status, spec, 3)
+
+		assert.NoError(t, err)
+		if assert.Len(t, status.SpecHistory, 3) {
+			assert.Equal(t, []string{"master", "master.image", "seedJobs"}, status.SpecHistory[2].Changes)
+			assert.Equal(t, status.SpecHistory[0].Hash, status.SpecHistory[2].Hash)
+		}
+	})
+	t.Run("the oldest generations are dropped", func(t *testing.T) {
+		status := &virtuslabv1alpha1.JenkinsStatus{}
+
+		for generation := int64(1); generation <= specHistoryLimit+2; generation++ {
+			err := appendSpecHistory(status, spec, generation)
+			assert.NoError(t, err)
+		}
+
+		if assert.Len(t, status.SpecHistory, specHistoryLimit) {
+			assert.Equal(t, int64(3), status.SpecHistory[0].Generation)
+			assert.Equal(t, int64(specHistoryLimit+2), status.SpecHistory[specHistoryLimit-1].Generation)
+			assert.Empty(t, status.SpecHistory[specHistoryLimit-1].Changes)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	virtuslabv1alpha1 "github.com/VirtusLab/jenkins-operator/pkg/apis/virtuslab/v1alpha1"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/conditions"
+	"github.com/VirtusLab/jenkins-operator/pkg/controller/jenkins/configuration/base/resources"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	reasonBaseConfigurationCompleted = "BaseConfigurationCompleted"
+	reasonUserConfigurationCompleted = "UserConfigurationCompleted"
+	reasonReconciled                 = "Reconciled"
+	reasonBackupDisabled             = "BackupDisabled"
+	reasonBackupConfigured           = "BackupConfigured"
+)
+
+// ensureNotReadyCondition sets the Ready condition to false, e.g. when the spec is rejected by the validation
+func (r *ReconcileJenkins) ensureNotReadyCondition(jenkins *virtuslabv1alpha1.Jenkins, reason, message string) error {
+	condition := conditions.Get(jenkins.Status, virtuslabv1alpha1.JenkinsConditionReady)
+	if condition != nil && condition.Status == corev1.ConditionFalse && condition.Reason == reason && condition.Message == message {
+		return nil
+	}
+
+	conditions.Set(&jenkins.Status, virtuslabv1alpha1.JenkinsConditionReady, corev1.ConditionFalse, reason, message)
+	return r.client.Status().Update(context.TODO(), jenkins)
+}
+
+// ensureReconciledStatus sets the BackupHealthy and Ready conditions, Jenkins URL and the observed generation once
+// the spec is completely reconciled, a new generation is recorded in the spec history, the status is updated only
+// when it changes
+func (r *ReconcileJenkins) ensureReconciledStatus(jenkins *virtuslabv1alpha1.Jenkins) error {
+	status := jenkins.Status.DeepCopy()
+	status.URL = resources.GetJenkinsURL(jenkins)
+	setBackupHealthyCondition(status, jenkins.Spec)
+	conditions.Set(status, virtuslabv1alpha1.JenkinsConditionReady, corev1.ConditionTrue, reasonReconciled,
+		"Jenkins is provisioned and configured")
+	if status.ObservedGeneration != jenkins.ObjectMeta.Generation {
+		if err := appendSpecHistory(status, jenkins.Spec, jenkins.ObjectMeta.Generation); err != nil {
+			return err
+		}
+	}
+	status.ObservedGeneration = jenkins.ObjectMeta.Generation
+	if reflect.DeepEqual(*status, jenkins.Status) {
+		return nil
+	}
+
+	jenkins.Status = *status
+	return r.client.Status().Update(context.TODO(), jenkins)
+}
+
+// setBackupHealthyCondition reflects the backup strategy of the spec in the BackupHealthy condition, the backup
+// credentials are verified by the user configuration validation
+func setBackupHealthyCondition(status *virtuslabv1alpha1.JenkinsStatus, spec virtuslabv1alpha1.JenkinsSpec) {
+	if spec.Backup == virtuslabv1alpha1.JenkinsBackupTypeAmazonS3 {
+		conditions.Set(status, virtuslabv1alpha1.JenkinsConditionBackupHealthy, corev1.ConditionTrue, reasonBackupConfigured,
+			fmt.Sprintf("Backup to Amazon S3 bucket '%s' is configured", spec.BackupAmazonS3.BucketName))
+		return
+	}
+
+	conditions.Set(status, virtuslabv1alpha1.JenkinsConditionBackupHealthy, corev1.ConditionUnknown, reasonBackupDisabled,
+		"Backup is disabled")
+}
@@ -0,0 +1,3 @@
+// Package tracing contains OpenTelemetry tracing of reconciliation loops and Jenkins API requests, the spans are
+// exported over OTLP/HTTP
+package tracing
@@ -0,0 +1,277 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/VirtusLab/jenkins-operator/pkg/log"
+	"github.com/VirtusLab/jenkins-operator/version"
+)
+
+const (
+	// TracesPath is the HTTP path of OTLP traces endpoint of the collector
+	TracesPath = "/v1/traces"
+
+	exportInterval  = 5 * time.Second
+	exportTimeout   = 10 * time.Second
+	maxExportSpans  = 512
+	maxQueuedSpans  = 2048
+	statusCodeError = 2
+)
+
+// Setup exports spans in OTLP/HTTP JSON format to the endpoint, e.g. 'otel-collector.monitoring:4318', a fraction of
+// traces set by sampleRatio is recorded, spans aren't recorded when it isn't called, the returned function flushes
+// and stops the exporter
+func Setup(endpoint string, insecure bool, sampleRatio float64) (func(context.Context) error, error) {
+	scheme := "https"
+	if insecure {
+		scheme = "http"
+	}
+	tracesURL, err := url.Parse(fmt.Sprintf("%s://%s%s", scheme, endpoint, TracesPath))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint '%s': %s", endpoint, err)
+	}
+
+	exporter := &otlpExporter{
+		url:    tracesURL.String(),
+		client: &http.Client{Timeout: exportTimeout},
+		queue:  make(chan *Span, maxQueuedSpans),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		resource: []Attribute{
+			String("service.name", ServiceName),
+			String("service.version", version.Version),
+		},
+	}
+	go exporter.run()
+	setProcessor(exporter, sampleRatio)
+	return exporter.shutdown, nil
+}
+
+// otlpExporter sends ended spans in batches to OTLP/HTTP endpoint, spans are dropped when the queue is full
+type otlpExporter struct {
+	url      string
+	client   *http.Client
+	resource []Attribute
+	queue    chan *Span
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func (e *otlpExporter) onEnd(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+	}
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	var spans []*Span
+	for {
+		select {
+		case span := <-e.queue:
+			spans = append(spans, span)
+			if len(spans) >= maxExportSpans {
+				e.exportAndLog(spans)
+				spans = nil
+			}
+		case <-ticker.C:
+			e.exportAndLog(spans)
+			spans = nil
+		case <-e.stop:
+			for {
+				select {
+				case span := <-e.queue:
+					spans = append(spans, span)
+				default:
+					e.exportAndLog(spans)
+					return
+				}
+			}
+		}
+	}
+}
+
+// shutdown stops the span processing and waits until the queued spans are exported
+func (e *otlpExporter) shutdown(ctx context.Context) error {
+	setProcessor(nil, 0)
+	e.stopOnce.Do(func() {
+		close(e.stop)
+	})
+	select {
+	case <-e.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *otlpExporter) exportAndLog(spans []*Span) {
+	if err := e.export(spans); err != nil {
+		log.Log.V(log.VWarn).Info(fmt.Sprintf("Couldn't export %d spans: %s", len(spans), err))
+	}
+}
+
+func (e *otlpExporter) export(spans []*Span) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(newExportRequest(e.resource, spans))
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := e.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("OTLP endpoint responded with '%s'", response.Status)
+	}
+	return nil
+}
+
+// exportRequest is OTLP ExportTraceServiceRequest in the JSON encoding of OTLP/HTTP
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanData `json:"spans"`
+}
+
+type scope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type spanData struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []keyValue  `json:"attributes,omitempty"`
+	Events            []eventData `json:"events,omitempty"`
+	Status            status      `json:"status"`
+}
+
+type eventData struct {
+	TimeUnixNano string     `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+type status struct {
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// anyValue is OTLP AnyValue, 64-bit integers are strings in the JSON encoding
+type anyValue struct {
+	StringValue *string `json:"stringValue,omitempty"`
+	IntValue    *string `json:"intValue,omitempty"`
+}
+
+func newExportRequest(resourceAttributes []Attribute, spans []*Span) exportRequest {
+	var data []spanData
+	for _, span := range spans {
+		data = append(data, newSpanData(span))
+	}
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{Attributes: newKeyValues(resourceAttributes)},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: tracerName, Version: version.Version},
+				Spans: data,
+			}},
+		}},
+	}
+}
+
+func newSpanData(span *Span) spanData {
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+
+	data := spanData{
+		TraceID:           hex.EncodeToString(span.traceID[:]),
+		SpanID:            hex.EncodeToString(span.spanID[:]),
+		Name:              span.name,
+		Kind:              int(span.kind),
+		StartTimeUnixNano: unixNano(span.start),
+		EndTimeUnixNano:   unixNano(span.end),
+		Attributes:        newKeyValues(span.attributes),
+	}
+	if span.parentID != [8]byte{} {
+		data.ParentSpanID = hex.EncodeToString(span.parentID[:])
+	}
+	for _, event := range span.events {
+		data.Events = append(data.Events, eventData{
+			TimeUnixNano: unixNano(event.time),
+			Name:         event.name,
+			Attributes:   newKeyValues(event.attributes),
+		})
+	}
+	if span.failed {
+		data.Status = status{Message: span.statusMessage, Code: statusCodeError}
+	}
+	return data
+}
+
+func newKeyValues(attributes []Attribute) []keyValue {
+	var keyValues []keyValue
+	for _, attribute := range attributes {
+		var value string
+		switch typed := attribute.Value.(type) {
+		case string:
+			value = typed
+			keyValues = append(keyValues, keyValue{Key: attribute.Key, Value: anyValue{StringValue: &value}})
+		case int:
+			value = strconv.Itoa(typed)
+			keyValues = append(keyValues, keyValue{Key: attribute.Key, Value: anyValue{IntValue: &value}})
+		}
+	}
+	return keyValues
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	var requests []exportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, TracesPath, request.URL.Path)
+		assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+		var exported exportRequest
+		assert.NoError(t, json.NewDecoder(request.Body).Decode(&exported))
+		requests = append(requests, exported)
+	}))
+	defer server.Close()
+
+	shutdown, err := Setup(strings.TrimPrefix(server.URL, "http://"), true, 1)
+	assert.NoError(t, err)
+	ctx, parent := Start(context.Background(), "Reconcile Jenkins", JenkinsAttributes("namespace-name", "example")...)
+	_, child := Start(ctx, "Validate base configuration")
+	End(child, errors.New("invalid plugin"))
+	End(parent, nil)
+	err = shutdown(context.Background())
+	assert.NoError(t, err)
+
+	_, span := Start(context.Background(), "Reconcile Jenkins")
+	assert.Nil(t, span)
+	if assert.Len(t, requests, 1) && assert.Len(t, requests[0].ResourceSpans, 1) {
+		resourceSpans := requests[0].ResourceSpans[0]
+		assert.Equal(t, "service.name", resourceSpans.Resource.Attributes[0].Key)
+		assert.Equal(t, ServiceName, *resourceSpans.Resource.Attributes[0].Value.StringValue)
+		spans := resourceSpans.ScopeSpans[0].Spans
+		if assert.Len(t, spans, 2) {
+			assert.Equal(t, "Validate base configuration", spans[0].Name)
+			assert.Equal(t, spans[1].SpanID, spans[0].ParentSpanID)
+			assert.Equal(t, spans[1].TraceID, spans[0].TraceID)
+			assert.Len(t, spans[0].TraceID, 32)
+			assert.Equal(t, status{Message: "invalid plugin", Code: statusCodeError}, spans[0].Status)
+			assert.Equal(t, "exception", spans[0].Events[0].Name)
+
+			assert.Equal(t, "Reconcile Jenkins", spans[1].Name)
+			assert.Empty(t, spans[1].ParentSpanID)
+			assert.Equal(t, "jenkins.namespace", spans[1].Attributes[0].Key)
+			assert.Equal(t, "namespace-name", *spans[1].Attributes[0].Value.StringValue)
+		}
+	}
+}
+
+func TestNewKeyValues(t *testing.T) {
+	keyValues := newKeyValues([]Attribute{String("http.method", "GET"), Int("http.status_code", http.StatusOK)})
+
+	body, err := json.Marshal(keyValues)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"key":"http.method","value":{"stringValue":"GET"}},`+
+		`{"key":"http.status_code","value":{"intValue":"200"}}]`, string(body))
+}
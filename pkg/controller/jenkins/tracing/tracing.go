@@ -0,0 +1,237 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// ServiceName is the service.name resource attribute of the operator spans
+	ServiceName = "jenkins-operator"
+
+	tracerName = "github.com/VirtusLab/jenkins-operator"
+)
+
+// spanKind is the OTLP kind of span
+type spanKind int
+
+const (
+	spanKindInternal spanKind = 1
+	spanKindClient   spanKind = 3
+)
+
+// spanProcessor receives sampled spans when they end
+type spanProcessor interface {
+	onEnd(span *Span)
+}
+
+var (
+	mutex       sync.RWMutex
+	processor   spanProcessor
+	sampleRatio float64
+)
+
+// setProcessor sets processor of the spans and the fraction of traces which are sampled, spans aren't created when
+// processor is nil
+func setProcessor(newProcessor spanProcessor, newSampleRatio float64) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	processor = newProcessor
+	sampleRatio = newSampleRatio
+}
+
+func getProcessor() (spanProcessor, float64) {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return processor, sampleRatio
+}
+
+// Attribute is key-value attribute of span, the value is string or int
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns attribute with string value
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns attribute with int value
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+type event struct {
+	name       string
+	time       time.Time
+	attributes []Attribute
+}
+
+// Span is operation of a trace, nil Span is a no-op span returned when tracing isn't set up
+type Span struct {
+	processor spanProcessor
+	sampled   bool
+	traceID   [16]byte
+	spanID    [8]byte
+	parentID  [8]byte
+	name      string
+	kind      spanKind
+	start     time.Time
+
+	mutex         sync.Mutex
+	end           time.Time
+	attributes    []Attribute
+	events        []event
+	failed        bool
+	statusMessage string
+}
+
+func (s *Span) setAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.attributes = append(s.attributes, attributes...)
+}
+
+func (s *Span) setError(message string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failed = true
+	s.statusMessage = message
+}
+
+func (s *Span) recordError(err error) {
+	s.setError(err.Error())
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.events = append(s.events, event{
+		name:       "exception",
+		time:       time.Now(),
+		attributes: []Attribute{String("exception.message", err.Error())},
+	})
+}
+
+type spanContextKey struct{}
+
+func spanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// Start starts span of the operation, the span is a child of the span of ctx, the span is nil when tracing isn't
+// set up
+func Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, *Span) {
+	return start(ctx, name, spanKindInternal, attributes)
+}
+
+func start(ctx context.Context, name string, kind spanKind, attributes []Attribute) (context.Context, *Span) {
+	currentProcessor, currentSampleRatio := getProcessor()
+	if currentProcessor == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		processor:  currentProcessor,
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: attributes,
+	}
+	if parent := spanFromContext(ctx); parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+		span.sampled = parent.sampled
+	} else {
+		_, _ = rand.Read(span.traceID[:])
+		span.sampled = isSampled(span.traceID, currentSampleRatio)
+	}
+	_, _ = rand.Read(span.spanID[:])
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// isSampled samples traces by trace ID, so the decision is the same in every process which sees the trace
+func isSampled(traceID [16]byte, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	if ratio <= 0 {
+		return false
+	}
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < uint64(ratio*(1<<63))
+}
+
+// End records the error of the operation in the span and ends the span
+func End(span *Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.recordError(err)
+	}
+
+	span.mutex.Lock()
+	ended := !span.end.IsZero()
+	if !ended {
+		span.end = time.Now()
+	}
+	span.mutex.Unlock()
+	if !ended && span.sampled {
+		span.processor.onEnd(span)
+	}
+}
+
+// JenkinsAttributes returns span attributes of the Jenkins CR
+func JenkinsAttributes(namespace, name string) []Attribute {
+	return []Attribute{
+		String("jenkins.namespace", namespace),
+		String("jenkins.name", name),
+	}
+}
+
+// InstrumentJenkinsAPI returns transport creating a span of each Jenkins API request, requests of the Jenkins API
+// client don't carry context, so the spans are children of the span of ctx, e.g. of the reconciliation loop which
+// created the client
+func InstrumentJenkinsAPI(ctx context.Context, transport http.RoundTripper) http.RoundTripper {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &tracingTransport{ctx: ctx, transport: transport}
+}
+
+type tracingTransport struct {
+	ctx       context.Context
+	transport http.RoundTripper
+}
+
+// RoundTrip sends the request within a client span, the query isn't recorded because it can contain credentials
+func (t *tracingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	if spanFromContext(ctx) == nil {
+		ctx = t.ctx
+	}
+	_, span := start(ctx, fmt.Sprintf("Jenkins API %s", request.Method), spanKindClient, []Attribute{
+		String("http.method", request.Method),
+		String("http.target", request.URL.Path),
+	})
+
+	response, err := t.transport.RoundTrip(request)
+	if err == nil {
+		span.setAttributes(Int("http.status_code", response.StatusCode))
+		if response.StatusCode >= http.StatusInternalServerError {
+			span.setError(response.Status)
+		}
+	}
+	End(span, err)
+	return response, err
+}
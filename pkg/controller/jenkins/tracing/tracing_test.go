@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+type spanRecorder struct {
+	mutex sync.Mutex
+	spans []*Span
+}
+
+func (r *spanRecorder) onEnd(span *Span) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func TestInstrumentJenkinsAPI(t *testing.T) {
+	recorder := &spanRecorder{}
+	setProcessor(recorder, 1)
+	defer setProcessor(nil, 0)
+
+	ctx, parent := Start(context.Background(), "Reconcile Jenkins", JenkinsAttributes("namespace-name", "example")...)
+	transport := InstrumentJenkinsAPI(ctx, roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		if request.URL.Path == "/api/json" {
+			return &http.Response{StatusCode: http.StatusOK, Status: "200 OK"}, nil
+		}
+		return nil, errors.New("connection refused")
+	}))
+
+	request, err := http.NewRequest(http.MethodGet, "http://jenkins:8080/api/json?token=secret", nil)
+	assert.NoError(t, err)
+	_, err = transport.RoundTrip(request)
+	assert.NoError(t, err)
+	request, err = http.NewRequest(http.MethodPost, "http://jenkins:8080/scriptText", nil)
+	assert.NoError(t, err)
+	_, err = transport.RoundTrip(request)
+	assert.Error(t, err)
+	End(parent, nil)
+
+	spans := recorder.spans
+	if assert.Len(t, spans, 3) {
+		assert.Equal(t, "Jenkins API GET", spans[0].name)
+		assert.Equal(t, parent.traceID, spans[0].traceID)
+		assert.Equal(t, parent.spanID, spans[0].parentID)
+		assert.Contains(t, spans[0].attributes, String("http.target", "/api/json"))
+		assert.Contains(t, spans[0].attributes, Int("http.status_code", http.StatusOK))
+		assert.False(t, spans[0].failed)
+
+		assert.Equal(t, "Jenkins API POST", spans[1].name)
+		assert.True(t, spans[1].failed)
+		assert.Equal(t, "connection refused", spans[1].statusMessage)
+
+		assert.Equal(t, "Reconcile Jenkins", spans[2].name)
+		assert.Equal(t, [8]byte{}, spans[2].parentID)
+		assert.Contains(t, spans[2].attributes, String("jenkins.name", "example"))
+	}
+}
+
+func TestStart(t *testing.T) {
+	t.Run("tracing isn't set up", func(t *testing.T) {
+		ctx, span := Start(context.Background(), "Reconcile Jenkins")
+
+		assert.Nil(t, span)
+		assert.Equal(t, context.Background(), ctx)
+		End(span, errors.New("failed"))
+	})
+	t.Run("trace isn't sampled", func(t *testing.T) {
+		recorder := &spanRecorder{}
+		setProcessor(recorder, 0)
+		defer setProcessor(nil, 0)
+
+		ctx, parent := Start(context.Background(), "Reconcile Jenkins")
+		setProcessor(recorder, 1)
+		_, child := Start(ctx, "Validate base configuration")
+		End(child, nil)
+		End(parent, nil)
+
+		assert.Empty(t, recorder.spans)
+	})
+}
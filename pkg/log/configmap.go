@@ -0,0 +1,46 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LevelsConfigMapPeriod is how often the log levels ConfigMap is read
+const LevelsConfigMapPeriod = 30 * time.Second
+
+// WatchLevelsConfigMap reads log levels of components from the ConfigMap every period until stop is closed,
+// the ConfigMap keys are the components and the values are the log levels, e.g. 'base: debug', the levels set by
+// SetupLogger are used when the ConfigMap doesn't exist and invalid levels are ignored
+func WatchLevelsConfigMap(k8sClient client.Client, namespace, name string, period time.Duration, stop <-chan struct{}) {
+	var applied map[string]string
+	wait.Until(func() {
+		configMap := &corev1.ConfigMap{}
+		err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, configMap)
+		if err != nil && !apierrors.IsNotFound(err) {
+			Log.V(VWarn).Info(fmt.Sprintf("Couldn't read log levels ConfigMap '%s': %s", name, err))
+			return
+		}
+
+		componentLevels := configMap.Data
+		if componentLevels == nil {
+			componentLevels = map[string]string{}
+		}
+		if applied != nil && reflect.DeepEqual(componentLevels, applied) {
+			return
+		}
+		if err := SetLevels(componentLevels); err != nil {
+			Log.V(VWarn).Info(fmt.Sprintf("Invalid log levels ConfigMap '%s': %s", name, err))
+		} else {
+			Log.Info(fmt.Sprintf("Log levels of ConfigMap '%s' are applied: %v", name, componentLevels))
+		}
+		applied = componentLevels
+	}, period, stop)
+}
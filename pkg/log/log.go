@@ -1,6 +1,16 @@
 package log
 
 import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
@@ -13,10 +23,201 @@ const (
 	VWarn = -1
 	// VDebug defines debug log level
 	VDebug = 1
+	// vError defines error log level, only errors are logged
+	vError = -2
 )
 
-// SetupLogger setups global logger
-func SetupLogger(development *bool) {
-	logf.SetLogger(logf.ZapLogger(*development))
+const (
+	// ComponentDefault is the log level key of loggers which don't belong to any component, e.g. the controller and
+	// controller-runtime
+	ComponentDefault = "default"
+	// ComponentBase is the component of Jenkins base configuration
+	ComponentBase = "base"
+	// ComponentUser is the component of Jenkins user configuration
+	ComponentUser = "user"
+	// ComponentClient is the component of Jenkins API client
+	ComponentClient = "client"
+)
+
+// levelNames maps log level names to logr verbosity, messages of higher verbosity aren't logged
+var levelNames = map[string]int{
+	"debug": VDebug,
+	"info":  0,
+	"warn":  VWarn,
+	"error": vError,
+}
+
+var components = []string{ComponentDefault, ComponentBase, ComponentUser, ComponentClient}
+
+// levels holds verbosity of components, initial is the verbosity set by SetupLogger and current is changed at runtime
+// by SetLevels
+var levels = struct {
+	sync.RWMutex
+	initial map[string]int
+	current map[string]int
+}{initial: map[string]int{}, current: map[string]int{}}
+
+// SetupLogger setups global logger writing JSON lines to stderr with the log levels of components parsed by
+// ParseLevels, the default log level is debug when debug is set and it isn't in the log levels, the logger is set up
+// even if the log levels are invalid
+func SetupLogger(debug bool, componentLevelsValue string) error {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	// verbosity is filtered by leveledLogger, so the core logs everything down to the debug level
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.Lock(os.Stderr), zapcore.Level(-VDebug))
+	zapLogger := zap.New(core, zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr)))
+
+	logf.SetLogger(&leveledLogger{logger: zapr.NewLogger(zapLogger), component: ComponentDefault})
 	Log = log.Log.WithName("controller-jenkins")
+
+	componentLevels, err := ParseLevels(componentLevelsValue)
+	if err != nil {
+		return err
+	}
+	if _, found := componentLevels[ComponentDefault]; debug && !found {
+		componentLevels[ComponentDefault] = "debug"
+	}
+	verbosity, err := parseVerbosity(componentLevels)
+	if err != nil {
+		return err
+	}
+	levels.Lock()
+	defer levels.Unlock()
+	levels.initial = verbosity
+	levels.current = verbosity
+	return nil
+}
+
+// ParseLevels parses log levels of components, e.g. 'default=info,base=debug,client=warn'
+func ParseLevels(value string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid log level '%s', expected '<component>=<level>'", item)
+		}
+		result[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return result, nil
+}
+
+// SetLevels overrides the log levels set by SetupLogger, e.g. by the log levels ConfigMap, the previous overrides are
+// discarded, components without level use the level of the default component
+func SetLevels(componentLevels map[string]string) error {
+	overrides, err := parseVerbosity(componentLevels)
+	if err != nil {
+		return err
+	}
+
+	levels.Lock()
+	defer levels.Unlock()
+	verbosity := map[string]int{}
+	for component, value := range levels.initial {
+		verbosity[component] = value
+	}
+	for component, value := range overrides {
+		verbosity[component] = value
+	}
+	levels.current = verbosity
+	return nil
+}
+
+// parseVerbosity returns logr verbosity of the log levels of components
+func parseVerbosity(componentLevels map[string]string) (map[string]int, error) {
+	verbosity := map[string]int{}
+	for component, level := range componentLevels {
+		if !isComponent(component) {
+			return nil, fmt.Errorf("unknown log component '%s', expected one of: %s", component, strings.Join(components, ", "))
+		}
+		value, found := levelNames[strings.ToLower(level)]
+		if !found {
+			return nil, fmt.Errorf("invalid log level '%s' of component '%s', expected one of: %s", level, component,
+				strings.Join(getLevelNames(), ", "))
+		}
+		verbosity[component] = value
+	}
+	return verbosity, nil
+}
+
+// ForComponent returns logger of the component, its messages are filtered by the log level of the component and
+// labeled with the component name, loggers not created by SetupLogger, e.g. in tests, are returned as they are
+func ForComponent(logger logr.Logger, component string) logr.Logger {
+	leveled, ok := logger.(*leveledLogger)
+	if !ok {
+		return logger
+	}
+	return &leveledLogger{
+		logger:    leveled.logger.WithValues("component", component),
+		component: component,
+		verbosity: leveled.verbosity,
+	}
+}
+
+func isComponent(component string) bool {
+	for _, name := range components {
+		if name == component {
+			return true
+		}
+	}
+	return false
+}
+
+func getLevelNames() []string {
+	var names []string
+	for name := range levelNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isEnabled tells if messages of the verbosity are logged by the component
+func isEnabled(component string, verbosity int) bool {
+	levels.RLock()
+	defer levels.RUnlock()
+	maxVerbosity, found := levels.current[component]
+	if !found {
+		// info when the default component isn't set
+		maxVerbosity = levels.current[ComponentDefault]
+	}
+	return verbosity <= maxVerbosity
+}
+
+// leveledLogger filters messages by the log level of its component, the level is checked on every message, so
+// the level changes apply to existing loggers
+type leveledLogger struct {
+	logger    logr.Logger
+	component string
+	verbosity int
+}
+
+func (l *leveledLogger) Enabled() bool {
+	return isEnabled(l.component, l.verbosity)
+}
+
+func (l *leveledLogger) Info(msg string, keysAndValues ...interface{}) {
+	if l.Enabled() {
+		l.logger.V(l.verbosity).Info(msg, keysAndValues...)
+	}
+}
+
+// Error messages are logged regardless of the log level
+func (l *leveledLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.logger.Error(err, msg, keysAndValues...)
+}
+
+func (l *leveledLogger) V(level int) logr.InfoLogger {
+	return &leveledLogger{logger: l.logger, component: l.component, verbosity: l.verbosity + level}
+}
+
+func (l *leveledLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &leveledLogger{logger: l.logger.WithValues(keysAndValues...), component: l.component, verbosity: l.verbosity}
+}
+
+func (l *leveledLogger) WithName(name string) logr.Logger {
+	return &leveledLogger{logger: l.logger.WithName(name), component: l.component, verbosity: l.verbosity}
 }
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevels(t *testing.T) {
+	t.Run("happy", func(t *testing.T) {
+		got, err := ParseLevels(" default=info, base=debug,client=WARN,")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"default": "info", "base": "debug", "client": "WARN"}, got)
+	})
+	t.Run("empty", func(t *testing.T) {
+		got, err := ParseLevels("")
+
+		assert.NoError(t, err)
+		assert.Empty(t, got)
+	})
+	t.Run("fail, missing level", func(t *testing.T) {
+		_, err := ParseLevels("base")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSetupLogger(t *testing.T) {
+	t.Run("debug", func(t *testing.T) {
+		err := SetupLogger(true, "user=warn")
+
+		assert.NoError(t, err)
+		assert.True(t, isEnabled(ComponentDefault, VDebug))
+		assert.True(t, isEnabled(ComponentBase, VDebug))
+		assert.False(t, isEnabled(ComponentUser, 0))
+		assert.True(t, isEnabled(ComponentUser, VWarn))
+	})
+	t.Run("debug doesn't override the default level", func(t *testing.T) {
+		err := SetupLogger(true, "default=error")
+
+		assert.NoError(t, err)
+		assert.False(t, isEnabled(ComponentClient, VWarn))
+	})
+	t.Run("fail, unknown component", func(t *testing.T) {
+		err := SetupLogger(false, "backup=debug")
+
+		assert.Error(t, err)
+	})
+	t.Run("fail, invalid level", func(t *testing.T) {
+		err := SetupLogger(false, "base=trace")
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSetLevels(t *testing.T) {
+	err := SetupLogger(false, "base=debug")
+	assert.NoError(t, err)
+	logger := ForComponent(Log, ComponentClient)
+
+	err = SetLevels(map[string]string{ComponentClient: "debug"})
+
+	assert.NoError(t, err)
+	assert.True(t, logger.V(VDebug).Enabled())
+	assert.True(t, isEnabled(ComponentBase, VDebug))
+	assert.False(t, isEnabled(ComponentUser, VDebug))
+
+	err = SetLevels(map[string]string{})
+
+	assert.NoError(t, err)
+	assert.False(t, logger.V(VDebug).Enabled())
+	assert.True(t, logger.Enabled())
+	assert.True(t, isEnabled(ComponentBase, VDebug))
+
+	err = SetLevels(map[string]string{ComponentClient: "verbose"})
+
+	assert.Error(t, err)
+	assert.False(t, logger.V(VDebug).Enabled())
+}